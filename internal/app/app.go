@@ -6,9 +6,27 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"payment-service/internal/domain/apikey"
+	"payment-service/internal/domain/fx"
+	"payment-service/internal/domain/outbox"
+	"payment-service/internal/domain/product"
+	"payment-service/internal/provider"
+	"payment-service/internal/publisher"
+	"payment-service/internal/service/access"
+	"payment-service/internal/service/accounting"
 	"payment-service/internal/service/catalogue"
+	"payment-service/internal/service/checkout"
+	"payment-service/internal/service/compliance"
+	"payment-service/internal/service/exchange"
 	"payment-service/internal/service/payment"
+	"payment-service/internal/service/reconciliation"
+	"payment-service/internal/service/stock"
+	"payment-service/internal/storage"
+	"payment-service/internal/worker"
+	"payment-service/pkg/auth"
 	"payment-service/pkg/epay"
+	"payment-service/pkg/ratelimit"
+	"payment-service/pkg/store"
 	"syscall"
 	"time"
 
@@ -40,18 +58,43 @@ func Run() {
 
 	repositories, err := repository.New(
 		//repository.WithPostgresStore(schema, configs.POSTGRES.DSN))
-		repository.WithMemoryStore())
+		repository.WithMemoryStore(),
+		repository.WithRedisCache(configs.REDIS.URL, configs.Cache.TTL, configs.Cache.Encoding),
+		repository.WithPIIEncryption(configs.PII.KeyFile))
 	if err != nil {
 		logger.Error("ERR_INIT_REPOSITORY", zap.Error(err))
 		return
 	}
 	defer repositories.Close()
 
+	var imageStorage product.Storage
+	switch configs.Storage.Driver {
+	case "s3":
+		imageStorage = storage.NewS3(storage.S3Config{
+			Endpoint:  configs.Storage.Endpoint,
+			Region:    configs.Storage.Region,
+			Bucket:    configs.Storage.Bucket,
+			AccessKey: configs.Storage.AccessKey,
+			SecretKey: configs.Storage.SecretKey,
+			PublicURL: configs.Storage.PublicURL,
+		})
+	default:
+		imageStorage = storage.NewLocal(configs.Storage.Path, configs.Storage.BaseURL)
+	}
+
 	catalogueService, err := catalogue.New(
 		catalogue.WithCategoryRepository(repositories.Category),
+		catalogue.WithAttributeRepository(repositories.Attribute),
 		catalogue.WithProductRepository(repositories.Product),
-		catalogue.WithCategoryCache(repositories.Category),
-		catalogue.WithProductCache(repositories.Product),
+		catalogue.WithPriceRepository(repositories.Price),
+		catalogue.WithCategoryCache(repositories.CategoryCache),
+		catalogue.WithProductCache(repositories.ProductCache),
+		catalogue.WithTransactor(repositories.Transactor),
+		catalogue.WithImportRepository(repositories.Import),
+		catalogue.WithImageRepository(repositories.Image),
+		catalogue.WithStorage(imageStorage),
+		catalogue.WithMaxImageSize(configs.Image.MaxSize),
+		catalogue.WithThumbnailSize(configs.Image.ThumbnailSize),
 	)
 
 	if err != nil {
@@ -59,13 +102,46 @@ func Run() {
 		return
 	}
 
-	paymentService, err := payment.New(
-		payment.WithBillingRepository(repositories.Billing),
-		payment.WithBillingCache(repositories.Billing),
+	accountingService, err := accounting.New(
+		accounting.WithLedgerRepository(repositories.Ledger),
+		accounting.WithTransactor(repositories.Transactor),
+		accounting.WithFeeRate(configs.Ledger.FeeRate),
 	)
 
 	if err != nil {
-		logger.Error("ERR_INIT_PAYMENT_SERVICE", zap.Error(err))
+		logger.Error("ERR_INIT_ACCOUNTING_SERVICE", zap.Error(err))
+		return
+	}
+
+	var rateProvider fx.Provider
+	switch {
+	case configs.FX.URL == "":
+	case configs.FX.Format == "csv":
+		rateProvider = provider.NewRatesFeed(configs.FX.URL)
+	default:
+		rateProvider = provider.NewRatesAPI(configs.FX.URL)
+	}
+
+	exchangeService, err := exchange.New(
+		exchange.WithRateRepository(repositories.FX),
+		exchange.WithProvider(rateProvider),
+		exchange.WithBaseCurrency(configs.FX.BaseCurrency),
+	)
+
+	if err != nil {
+		logger.Error("ERR_INIT_EXCHANGE_SERVICE", zap.Error(err))
+		return
+	}
+
+	stockService, err := stock.New(
+		stock.WithInventoryRepository(repositories.Inventory),
+		stock.WithProductRepository(repositories.Product),
+		stock.WithTransactor(repositories.Transactor),
+		stock.WithReservationTTL(configs.Inventory.ReservationTTL),
+	)
+
+	if err != nil {
+		logger.Error("ERR_INIT_STOCK_SERVICE", zap.Error(err))
 		return
 	}
 
@@ -81,12 +157,139 @@ func Run() {
 		Amount:        configs.EPay.Amount,
 	})
 
+	paymentService, err := payment.New(
+		payment.WithBillingRepository(repositories.Billing),
+		payment.WithBillingCache(repositories.BillingCache),
+		payment.WithOutboxRepository(repositories.Outbox),
+		payment.WithDisputeRepository(repositories.Dispute),
+		payment.WithTenantRepository(repositories.Tenant),
+		payment.WithGateway(provider.NewEPay(ePayClient)),
+		payment.WithTransactor(repositories.Transactor),
+		payment.WithSubjectIndex(repositories.BillingIndex),
+		payment.WithAccountingService(accountingService),
+		payment.WithExchangeService(exchangeService),
+		payment.WithObserver(checkout.NewBillingObserver(repositories.Order)),
+		payment.WithObserver(stockService),
+	)
+
+	if err != nil {
+		logger.Error("ERR_INIT_PAYMENT_SERVICE", zap.Error(err))
+		return
+	}
+
+	checkoutService, err := checkout.New(
+		checkout.WithOrderRepository(repositories.Order),
+		checkout.WithCatalogueService(catalogueService),
+		checkout.WithPaymentService(paymentService),
+		checkout.WithStockService(stockService),
+		checkout.WithTransactor(repositories.Transactor),
+		checkout.WithTax(configs.Order.TaxRate, configs.Order.TaxIncluded),
+	)
+
+	if err != nil {
+		logger.Error("ERR_INIT_CHECKOUT_SERVICE", zap.Error(err))
+		return
+	}
+
+	reconciliationService, err := reconciliation.New(
+		reconciliation.WithSettlementRepository(repositories.Settlement),
+		reconciliation.WithBillingRepository(repositories.Billing),
+		reconciliation.WithTransactor(repositories.Transactor),
+	)
+
+	if err != nil {
+		logger.Error("ERR_INIT_RECONCILIATION_SERVICE", zap.Error(err))
+		return
+	}
+
+	complianceService, err := compliance.New(
+		compliance.WithAuditRepository(repositories.Audit),
+		compliance.WithBillingRepository(repositories.Billing),
+		compliance.WithOrderRepository(repositories.Order),
+		compliance.WithTransactor(repositories.Transactor),
+		compliance.WithSubjectIndex(repositories.BillingIndex),
+	)
+
+	if err != nil {
+		logger.Error("ERR_INIT_COMPLIANCE_SERVICE", zap.Error(err))
+		return
+	}
+
+	accessService, err := access.New(
+		access.WithAPIKeyRepository(repositories.APIKey),
+		access.WithTenantRepository(repositories.Tenant),
+	)
+
+	if err != nil {
+		logger.Error("ERR_INIT_ACCESS_SERVICE", zap.Error(err))
+		return
+	}
+
+	if configs.Auth.AdminKey != "" {
+		if err = accessService.EnsureKey(store.WithOperator(context.Background()), "admin", configs.Auth.AdminKey, apikey.Scopes); err != nil {
+			logger.Error("ERR_ENSURE_ADMIN_KEY", zap.Error(err))
+			return
+		}
+	}
+
+	authenticators := []auth.Authenticator{auth.NewAPIKey(accessService)}
+	if configs.Auth.JWKS != "" {
+		keys := auth.NewJWKS(configs.Auth.JWKS, configs.Auth.JWKSRefresh)
+		authenticators = append(authenticators, auth.NewJWT(keys, configs.Auth.Issuer, configs.Auth.Audience, configs.Auth.OperatorIssuers...))
+	}
+
+	publishers := []outbox.Publisher{publisher.NewTenantWebhook(repositories.Tenant)}
+	if configs.Outbox.WebhookURL != "" {
+		publishers = append(publishers, publisher.NewWebhook(configs.Outbox.WebhookURL, configs.Outbox.WebhookSecret))
+	}
+
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemory()
+	if configs.REDIS.URL != "" {
+		redis, err := store.NewRedis(configs.REDIS.URL)
+		if err != nil {
+			logger.Error("ERR_INIT_REDIS", zap.Error(err))
+			return
+		}
+		defer redis.Client.Close()
+
+		publishers = append(publishers, publisher.NewRedisStream(redis.Client, configs.Outbox.Stream))
+		rateLimitStore = ratelimit.NewRedis(redis.Client, configs.RateLimit.Prefix)
+	}
+
+	relay := worker.NewRelay(repositories.Outbox, publishers, configs.Outbox.Interval, configs.Outbox.BatchSize)
+	relay.Run(logger)
+
+	rateLoader := worker.NewRateLoader(exchangeService, configs.FX.Interval)
+	if rateProvider != nil {
+		rateLoader.Run(logger)
+	}
+
+	reservationReaper := worker.NewReservationReaper(stockService, configs.Inventory.Interval)
+	reservationReaper.Run(logger)
+
+	trashPurger := worker.NewTrashPurger(catalogueService, configs.Trash.Retention, configs.Trash.Interval)
+	trashPurger.Run(logger)
+
+	piiRekeyer := worker.NewPIIRekeyer(repositories.BillingRekeyer, configs.PII.RekeyInterval)
+	if repositories.BillingRekeyer != nil {
+		piiRekeyer.Run(logger)
+	}
+
 	handlers, err := handler.New(
 		handler.Dependencies{
-			Configs:          configs,
-			CatalogueService: catalogueService,
-			EPayClient:       ePayClient,
-			PaymentService:   paymentService,
+			Configs:               configs,
+			CatalogueService:      catalogueService,
+			EPayClient:            ePayClient,
+			PaymentService:        paymentService,
+			AccountingService:     accountingService,
+			ExchangeService:       exchangeService,
+			CheckoutService:       checkoutService,
+			ReconciliationService: reconciliationService,
+			StockService:          stockService,
+			ComplianceService:     complianceService,
+			AccessService:         accessService,
+			Authenticators:        authenticators,
+			RateLimitStore:        rateLimitStore,
 		},
 		handler.WithHTTPHandler())
 	if err != nil {
@@ -133,6 +336,29 @@ func Run() {
 
 	fmt.Println("Running cleanup tasks...")
 	// Your cleanup tasks go here
+	if err = relay.Stop(ctx); err != nil {
+		logger.Error("ERR_STOP_RELAY", zap.Error(err))
+	}
+
+	if err = rateLoader.Stop(ctx); err != nil {
+		logger.Error("ERR_STOP_RATE_LOADER", zap.Error(err))
+	}
+
+	if err = reservationReaper.Stop(ctx); err != nil {
+		logger.Error("ERR_STOP_RESERVATION_REAPER", zap.Error(err))
+	}
+
+	if err = trashPurger.Stop(ctx); err != nil {
+		logger.Error("ERR_STOP_TRASH_PURGER", zap.Error(err))
+	}
+
+	if err = piiRekeyer.Stop(ctx); err != nil {
+		logger.Error("ERR_STOP_PII_REKEYER", zap.Error(err))
+	}
+
+	if err = catalogueService.WaitImports(ctx); err != nil {
+		logger.Error("ERR_WAIT_PRODUCT_IMPORTS", zap.Error(err))
+	}
 
 	fmt.Println("Server was successful shutdown.")
 }
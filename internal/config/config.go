@@ -17,13 +17,176 @@ const (
 	defaultHTTPWriteTimeout       = 15 * time.Second
 	defaultHTTPIdleTimeout        = 60 * time.Second
 	defaultHTTPMaxHeaderMegabytes = 1
+	defaultHTTPAllowedOrigin      = "*"
+
+	defaultOutboxInterval  = 5 * time.Second
+	defaultOutboxBatchSize = 100
+	defaultOutboxStream    = "billing-events"
+
+	defaultFXBaseCurrency = "KZT"
+	defaultFXFormat       = "json"
+	defaultFXInterval     = time.Hour
+
+	defaultInventoryReservationTTL = 30 * time.Minute
+	defaultInventoryInterval       = time.Minute
+
+	defaultStorageDriver  = "local"
+	defaultStoragePath    = "media"
+	defaultStorageBaseURL = "http://localhost/media"
+	defaultStorageRegion  = "us-east-1"
+
+	defaultImageMaxSize       = 5 << 20
+	defaultImageThumbnailSize = 256
+
+	defaultCacheTTL      = 5 * time.Minute
+	defaultCacheEncoding = "json"
+
+	defaultTrashRetention = 30 * 24 * time.Hour
+	defaultTrashInterval  = time.Hour
+
+	defaultAuthJWKSRefresh = time.Hour
+
+	defaultPIIRekeyInterval = 24 * time.Hour
+
+	defaultRateLimitDefault = "600/1m"
+	defaultRateLimitIP      = "1200/1m"
+	defaultRateLimitPrefix  = "ratelimit:"
 )
 
+// defaultRateLimitRoutes keeps billings from being created, and the payment results from being posted, in floods.
+var defaultRateLimitRoutes = map[string]string{
+	"POST /api/v1/billings":          "60/1m",
+	"POST /api/v1/billings/postlink": "120/1m",
+}
+
 type (
 	Configs struct {
-		HTTP     HTTPConfig
-		POSTGRES DatabaseConfig
-		EPay     EPayConfig
+		HTTP      HTTPConfig
+		POSTGRES  DatabaseConfig
+		REDIS     RedisConfig
+		EPay      EPayConfig
+		Outbox    OutboxConfig
+		Ledger    LedgerConfig
+		FX        FXConfig
+		Order     OrderConfig
+		Inventory InventoryConfig
+		Storage   StorageConfig
+		Image     ImageConfig
+		Cache     CacheConfig
+		Trash     TrashConfig
+		Auth      AuthConfig
+		RateLimit RateLimitConfig
+		PII       PIIConfig
+	}
+
+	// PIIConfig describes how the personal data of payers is kept. It is encrypted with the keys of KeyFile,
+	// a JSON file of base64 AES-256 keys by id, the id of the primary one and the key of the blind indexes the payers
+	// are looked up by, and left as it is when KeyFile is empty.
+	// The data encrypted under a retired key is encrypted under the primary one every RekeyInterval.
+	PIIConfig struct {
+		KeyFile       string
+		RekeyInterval time.Duration
+	}
+
+	// RateLimitConfig limits the API requests of every caller, an API key or token, or the IP of an anonymous
+	// request. IP is count/period across every request of an IP, taken before its credentials are checked so keys
+	// can't be guessed faster, empty turns it off. Default is count/period across the routes without a limit of
+	// their own, e.g. 600/1m, empty turns it off. Routes limits the routes keyed by [METHOD ]/path,
+	// e.g. POST /api/v1/billings:30/1m, where {name} matches a path segment and a trailing * the rest.
+	// The buckets are kept under Prefix in REDIS.URL when set, shared by the replicas, and in memory otherwise.
+	RateLimitConfig struct {
+		IP      string
+		Default string
+		Routes  map[string]string
+		Prefix  string
+	}
+
+	// AuthConfig describes how API callers are authenticated. Source services send the API keys issued to them,
+	// AdminKey is stored with every scope at startup so the first keys can be issued. Bearer tokens are accepted
+	// when JWKS is set, a JSON Web Key Set file path or URL reloaded every JWKSRefresh; Issuer and Audience
+	// are checked when set. Tokens without a tenant_id claim act for every tenant and are only accepted
+	// from the comma-separated OperatorIssuers.
+	AuthConfig struct {
+		AdminKey        string
+		JWKS            string
+		JWKSRefresh     time.Duration
+		Issuer          string
+		Audience        string
+		OperatorIssuers []string
+	}
+
+	// TrashConfig holds how long deleted products and categories can be restored before they are purged
+	// and how often the trash is checked.
+	TrashConfig struct {
+		Retention time.Duration
+		Interval  time.Duration
+	}
+
+	// CacheConfig holds how long products, categories and billings stay cached in REDIS.URL and how they are encoded,
+	// json or msgpack. The cache is enabled when REDIS.URL is set and TTL is positive.
+	CacheConfig struct {
+		TTL      time.Duration
+		Encoding string
+	}
+
+	// StorageConfig describes where product images are kept. Driver local writes them under Path and serves them
+	// at BaseURL, which has to end with /media for the service to serve them itself. Driver s3 puts them into
+	// Bucket of an S3-compatible Endpoint, PublicURL replaces Endpoint/Bucket in image URLs when set.
+	StorageConfig struct {
+		Driver    string
+		Path      string
+		BaseURL   string
+		Endpoint  string
+		Region    string
+		Bucket    string
+		AccessKey string
+		SecretKey string
+		PublicURL string
+	}
+
+	// ImageConfig holds the largest image file accepted in bytes and the side of the box thumbnails fit in pixels.
+	ImageConfig struct {
+		MaxSize       int64
+		ThumbnailSize int
+	}
+
+	// InventoryConfig holds how long stock stays reserved for an unpaid order and how often expired reservations
+	// are released.
+	InventoryConfig struct {
+		ReservationTTL time.Duration
+		Interval       time.Duration
+	}
+
+	// OrderConfig holds the tax charged on order items, e.g. 0.12 for 12%. TaxIncluded tells catalogue prices
+	// already contain it.
+	OrderConfig struct {
+		TaxRate     float64
+		TaxIncluded bool
+	}
+
+	// FXConfig describes the base currency reports are converted to and where exchange rates are loaded from.
+	// The provider is enabled when URL is set, Format is json for a rates API or csv for a feed, URL may be
+	// a file path for csv.
+	FXConfig struct {
+		BaseCurrency string
+		URL          string
+		Format       string
+		Interval     time.Duration
+	}
+
+	// LedgerConfig holds the share of a captured amount the gateway keeps as a fee, e.g. 0.025 for 2.5%.
+	LedgerConfig struct {
+		FeeRate float64
+	}
+
+	// OutboxConfig describes where the outbox relay delivers billing events.
+	// A publisher is enabled when its target is set: WebhookURL for HTTP, REDIS.URL for Redis Streams.
+	OutboxConfig struct {
+		Interval      time.Duration
+		BatchSize     int
+		WebhookURL    string
+		WebhookSecret string
+		Stream        string
 	}
 
 	EPayConfig struct {
@@ -38,6 +201,9 @@ type (
 		Amount        string `mapstructure:"amount"`
 	}
 
+	// HTTPConfig describes the HTTP server, AllowedOrigins lists the origins browsers may call the API from,
+	// * allows any. TrustedProxies lists the addresses or CIDR ranges of the proxies in front of it, the client IP
+	// is only taken from X-Forwarded-For or X-Real-IP of the requests they pass on.
 	HTTPConfig struct {
 		Port               string
 		Host               string
@@ -46,6 +212,8 @@ type (
 		WriteTimeout       time.Duration
 		IdleTimeout        time.Duration
 		MaxHeaderMegabytes int
+		AllowedOrigins     []string
+		TrustedProxies     []string
 	}
 
 	ClientConfig struct {
@@ -57,6 +225,10 @@ type (
 	DatabaseConfig struct {
 		DSN string
 	}
+
+	RedisConfig struct {
+		URL string
+	}
 )
 
 // New populates Configs struct with values from config file
@@ -76,6 +248,7 @@ func New() (cfg Configs, err error) {
 		WriteTimeout:       defaultHTTPWriteTimeout,
 		IdleTimeout:        defaultHTTPIdleTimeout,
 		MaxHeaderMegabytes: defaultHTTPMaxHeaderMegabytes,
+		AllowedOrigins:     []string{defaultHTTPAllowedOrigin},
 	}
 
 	err = envconfig.Process("HTTP", &cfg.HTTP)
@@ -88,5 +261,124 @@ func New() (cfg Configs, err error) {
 		return
 	}
 
+	err = envconfig.Process("REDIS", &cfg.REDIS)
+	if err != nil {
+		return
+	}
+
+	cfg.Outbox = OutboxConfig{
+		Interval:  defaultOutboxInterval,
+		BatchSize: defaultOutboxBatchSize,
+		Stream:    defaultOutboxStream,
+	}
+
+	err = envconfig.Process("OUTBOX", &cfg.Outbox)
+	if err != nil {
+		return
+	}
+
+	err = envconfig.Process("LEDGER", &cfg.Ledger)
+	if err != nil {
+		return
+	}
+
+	cfg.FX = FXConfig{
+		BaseCurrency: defaultFXBaseCurrency,
+		Format:       defaultFXFormat,
+		Interval:     defaultFXInterval,
+	}
+
+	err = envconfig.Process("FX", &cfg.FX)
+	if err != nil {
+		return
+	}
+
+	err = envconfig.Process("ORDER", &cfg.Order)
+	if err != nil {
+		return
+	}
+
+	cfg.Inventory = InventoryConfig{
+		ReservationTTL: defaultInventoryReservationTTL,
+		Interval:       defaultInventoryInterval,
+	}
+
+	err = envconfig.Process("INVENTORY", &cfg.Inventory)
+	if err != nil {
+		return
+	}
+
+	cfg.Storage = StorageConfig{
+		Driver:  defaultStorageDriver,
+		Path:    defaultStoragePath,
+		BaseURL: defaultStorageBaseURL,
+		Region:  defaultStorageRegion,
+	}
+
+	err = envconfig.Process("STORAGE", &cfg.Storage)
+	if err != nil {
+		return
+	}
+
+	cfg.Image = ImageConfig{
+		MaxSize:       defaultImageMaxSize,
+		ThumbnailSize: defaultImageThumbnailSize,
+	}
+
+	err = envconfig.Process("IMAGE", &cfg.Image)
+	if err != nil {
+		return
+	}
+
+	cfg.Cache = CacheConfig{
+		TTL:      defaultCacheTTL,
+		Encoding: defaultCacheEncoding,
+	}
+
+	err = envconfig.Process("CACHE", &cfg.Cache)
+	if err != nil {
+		return
+	}
+
+	cfg.Trash = TrashConfig{
+		Retention: defaultTrashRetention,
+		Interval:  defaultTrashInterval,
+	}
+
+	err = envconfig.Process("TRASH", &cfg.Trash)
+	if err != nil {
+		return
+	}
+
+	cfg.Auth = AuthConfig{
+		JWKSRefresh: defaultAuthJWKSRefresh,
+	}
+
+	err = envconfig.Process("AUTH", &cfg.Auth)
+	if err != nil {
+		return
+	}
+
+	cfg.PII = PIIConfig{
+		RekeyInterval: defaultPIIRekeyInterval,
+	}
+
+	err = envconfig.Process("PII", &cfg.PII)
+	if err != nil {
+		return
+	}
+
+	cfg.RateLimit = RateLimitConfig{
+		IP:      defaultRateLimitIP,
+		Default: defaultRateLimitDefault,
+		Routes:  defaultRateLimitRoutes,
+		Prefix:  defaultRateLimitPrefix,
+	}
+
+	err = envconfig.Process("RATELIMIT", &cfg.RateLimit)
+	if err != nil {
+		return
+	}
+
 	return
 }
@@ -0,0 +1,74 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Request issues a key to the source service of the name with the scopes. The key acts for the tenant,
+// only admin keys leave it empty to act for every tenant and admin keys act for no single tenant.
+type Request struct {
+	TenantID string   `json:"tenant_id"`
+	Name     string   `json:"name"`
+	Scopes   []string `json:"scopes"`
+}
+
+func (s *Request) Bind(r *http.Request) error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" {
+		return errors.New("name: cannot be blank")
+	}
+
+	if len(s.Scopes) == 0 {
+		return errors.New("scopes: cannot be blank")
+	}
+
+	for _, scope := range s.Scopes {
+		if !IsScope(scope) {
+			return errors.New("scopes: must be any of " + strings.Join(Scopes, ", "))
+		}
+	}
+
+	s.TenantID = strings.TrimSpace(s.TenantID)
+	if s.TenantID == "" && !HasScope(s.Scopes, ScopeAdmin) {
+		return errors.New("tenant_id: cannot be blank")
+	}
+	if s.TenantID != "" && HasScope(s.Scopes, ScopeAdmin) {
+		return ErrTenantAdmin
+	}
+
+	return nil
+}
+
+// Response holds the key itself only when it has just been issued.
+type Response struct {
+	ID        string     `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	TenantID  string     `json:"tenant_id,omitempty"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	Key       string     `json:"key,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		ID:        data.ID,
+		CreatedAt: data.CreatedAt,
+		TenantID:  data.TenantID,
+		Name:      data.Name,
+		Scopes:    data.Scopes,
+		RevokedAt: data.RevokedAt,
+	}
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
@@ -0,0 +1,70 @@
+package apikey
+
+import (
+	"errors"
+	"time"
+)
+
+// Scopes are granted to API keys and read from the scope claim of tokens alike. Reads only require the caller
+// to be authenticated, writes require the scope of the route. The personal data of payers is masked
+// for the callers without ScopePIIRead.
+const (
+	ScopeCatalogueWrite = "catalogue:write"
+	ScopeBillingCreate  = "billing:create"
+	ScopeBillingUpdate  = "billing:update"
+	ScopeBillingRefund  = "billing:refund"
+	ScopeOrderCreate    = "order:create"
+	ScopeInventoryWrite = "inventory:write"
+	ScopeDisputeWrite   = "dispute:write"
+	ScopePIIRead        = "pii:read"
+	ScopeAdmin          = "admin"
+)
+
+// Scopes lists every scope in the order they are documented.
+var Scopes = []string{
+	ScopeCatalogueWrite,
+	ScopeBillingCreate,
+	ScopeBillingUpdate,
+	ScopeBillingRefund,
+	ScopeOrderCreate,
+	ScopeInventoryWrite,
+	ScopeDisputeWrite,
+	ScopePIIRead,
+	ScopeAdmin,
+}
+
+// ErrTenantAdmin rejects a key granted the admin scope for a tenant, the admin routes see every tenant
+// and are reserved to the keys of operators.
+var ErrTenantAdmin = errors.New("scopes: admin cannot be granted to a key of a tenant")
+
+// Entity is an API key issued to a source service. Only the SHA-256 hash of the key is kept,
+// the key itself is shown once when it is issued. RevokedAt is set once the key can no longer be used.
+type Entity struct {
+	CreatedAt time.Time  `db:"created_at"`
+	ID        string     `db:"id"`
+	TenantID  string     `db:"tenant_id"`
+	Name      string     `db:"name"`
+	Hash      string     `db:"hash"`
+	Scopes    []string   `db:"-"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+func IsScope(scope string) bool {
+	for _, known := range Scopes {
+		if known == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func HasScope(scopes []string, scope string) bool {
+	for _, granted := range scopes {
+		if granted == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,15 @@
+package apikey
+
+import "context"
+
+type Repository interface {
+	// Select returns the keys, revoked ones included, the latest issued first.
+	Select(ctx context.Context) (dest []Entity, err error)
+	// Create fails with store.ErrorAlreadyExists when a key with the hash exists.
+	Create(ctx context.Context, data Entity) (id string, err error)
+	Get(ctx context.Context, id string) (dest Entity, err error)
+	// GetByHash returns the key with the hash unless it is revoked.
+	GetByHash(ctx context.Context, hash string) (dest Entity, err error)
+	// Revoke fails with store.ErrorNotFound when the key is missing or revoked already.
+	Revoke(ctx context.Context, id string) (err error)
+}
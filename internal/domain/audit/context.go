@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"payment-service/pkg/auth"
+	"payment-service/pkg/store"
+)
+
+// Source returns who made the write of the context: the authenticated actor and the request id set by the router.
+// Requests without credentials are made by ActorAnonymous, the background work no request started by ActorSystem.
+func Source(ctx context.Context) (actor, requestID string) {
+	actor, requestID = auth.Actor(ctx), middleware.GetReqID(ctx)
+
+	switch {
+	case actor != "":
+	case requestID != "":
+		actor = ActorAnonymous
+	default:
+		actor = ActorSystem
+	}
+
+	return
+}
+
+// Detach returns a context of its own that keeps the caller, tenant and request id of ctx,
+// so the work that outlives a request is still recorded on behalf of its caller.
+func Detach(ctx context.Context) context.Context {
+	detached := context.Background()
+
+	if principal, ok := auth.FromContext(ctx); ok {
+		detached = auth.WithPrincipal(detached, principal)
+	}
+	if store.TenantScope(ctx) == store.OperatorTenant {
+		detached = store.WithOperator(detached)
+	}
+	detached = store.WithTenant(detached, store.TenantID(ctx))
+
+	if requestID := middleware.GetReqID(ctx); requestID != "" {
+		detached = context.WithValue(detached, middleware.RequestIDKey, requestID)
+	}
+
+	return detached
+}
@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+type Change struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Diff compares the fields of two entities of the same type by their db names, fields without one go by the lowercase
+// field name. A nil entity has no fields, so a create holds only the after values and a purge only the before ones.
+func Diff(before, after any) (diff json.RawMessage, err error) {
+	old, err := fields(before)
+	if err != nil {
+		return
+	}
+
+	next, err := fields(after)
+	if err != nil {
+		return
+	}
+
+	changes := make(map[string]Change)
+	for name, value := range old {
+		if !bytes.Equal(value, next[name]) {
+			changes[name] = Change{Before: value, After: next[name]}
+		}
+	}
+	for name, value := range next {
+		if _, ok := old[name]; !ok {
+			changes[name] = Change{After: value}
+		}
+	}
+
+	return json.Marshal(changes)
+}
+
+func fields(entity any) (dest map[string]json.RawMessage, err error) {
+	dest = make(map[string]json.RawMessage)
+	if entity == nil {
+		return
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(entity))
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Tag.Get("db")
+		if name == "" || name == "-" {
+			name = strings.ToLower(field.Name)
+		}
+
+		if dest[name], err = json.Marshal(value.Field(i).Interface()); err != nil {
+			return
+		}
+	}
+
+	return
+}
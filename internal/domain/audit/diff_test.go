@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type dimensions struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+type diffed struct {
+	ID         string            `db:"id"`
+	Name       *string           `db:"name"`
+	Size       dimensions        `db:"size"`
+	Labels     map[string]string `db:"labels"`
+	Attributes json.RawMessage   `db:"attributes"`
+	Brand      string
+	Skipped    string `db:"-"`
+	internal   string
+}
+
+func TestDiff(t *testing.T) {
+	name := "Apple juice"
+	original := diffed{
+		ID:         "42",
+		Size:       dimensions{Width: 10, Height: 20},
+		Labels:     map[string]string{"a": "1"},
+		Attributes: json.RawMessage(`{"volume":1}`),
+		Brand:      "Orchard",
+		internal:   "left out",
+	}
+
+	tests := []struct {
+		name          string
+		before, after any
+		want          string
+	}{
+		{
+			name:   "unchanged",
+			before: original,
+			after:  original,
+			want:   `{}`,
+		},
+		{
+			name:   "nil to value",
+			before: original,
+			after:  func() diffed { d := original; d.Name = &name; return d }(),
+			want:   `{"name":{"before":null,"after":"Apple juice"}}`,
+		},
+		{
+			name:   "value to nil",
+			before: func() diffed { d := original; d.Name = &name; return d }(),
+			after:  original,
+			want:   `{"name":{"before":"Apple juice","after":null}}`,
+		},
+		{
+			name:   "nested",
+			before: original,
+			after: func() diffed {
+				d := original
+				d.Size.Height = 30
+				d.Labels = map[string]string{"a": "1", "b": "2"}
+				d.Attributes = json.RawMessage(`{"volume":2}`)
+				return d
+			}(),
+			want: `{
+				"size":{"before":{"width":10,"height":20},"after":{"width":10,"height":30}},
+				"labels":{"before":{"a":"1"},"after":{"a":"1","b":"2"}},
+				"attributes":{"before":{"volume":1},"after":{"volume":2}}
+			}`,
+		},
+		{
+			name:   "names",
+			before: &original,
+			after: func() *diffed {
+				d := original
+				d.Brand, d.Skipped, d.internal = "Grove", "changed", "changed"
+				return &d
+			}(),
+			want: `{"brand":{"before":"Orchard","after":"Grove"},"skipped":{"before":"","after":"changed"}}`,
+		},
+		{
+			name:  "create",
+			after: diffed{ID: "42"},
+			want: `{
+				"id":{"after":"42"},"name":{"after":null},"size":{"after":{"width":0,"height":0}},
+				"labels":{"after":null},"attributes":{"after":null},"brand":{"after":""},"skipped":{"after":""}
+			}`,
+		},
+		{
+			name:   "purge",
+			before: diffed{ID: "42"},
+			want: `{
+				"id":{"before":"42"},"name":{"before":null},"size":{"before":{"width":0,"height":0}},
+				"labels":{"before":null},"attributes":{"before":null},"brand":{"before":""},"skipped":{"before":""}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := Diff(tt.before, tt.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got, want any
+			if err = json.Unmarshal(diff, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err = json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %s, want %s", diff, tt.want)
+			}
+		})
+	}
+}
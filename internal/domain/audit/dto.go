@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Response struct {
+	ID         string          `json:"id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Action     string          `json:"action"`
+	Actor      string          `json:"actor"`
+	RequestID  string          `json:"request_id,omitempty"`
+	Diff       json.RawMessage `json:"diff" swaggertype:"object"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		ID:         data.ID,
+		CreatedAt:  data.CreatedAt,
+		EntityType: data.EntityType,
+		EntityID:   data.EntityID,
+		Action:     data.Action,
+		Actor:      data.Actor,
+		RequestID:  data.RequestID,
+		Diff:       data.Diff,
+	}
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionMove    = "move"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+	// ActionExport and ActionErase are recorded for the data subject requests, see EntitySubject
+	ActionExport = "export"
+	ActionErase  = "erase"
+)
+
+// Entities whose writes are recorded. Prices, images and attributes are recorded under the id of their product
+// or category, so the trail of a product shows who changed its price.
+const (
+	EntityProduct   = "product"
+	EntityCategory  = "category"
+	EntityAttribute = "attribute"
+	EntityPrice     = "price"
+	EntityPriceList = "price_list"
+	EntityImage     = "image"
+	EntityBilling   = "billing"
+	EntityDispute   = "dispute"
+	EntityEvidence  = "evidence"
+	// EntitySubject is the payer whose data was exported or erased, recorded under a hash of the identifiers
+	EntitySubject = "subject"
+)
+
+const (
+	// ActorAnonymous made a request without credentials
+	ActorAnonymous = "anonymous"
+	// ActorSystem is background work that no request started, e.g. the trash purger
+	ActorSystem = "system"
+)
+
+// Redacted replaces the values of the fields redacted from the diffs, see Repository.Redact.
+const Redacted = "[redacted]"
+
+// Entity is a record of a single write, records are never deleted and only changed by a redaction.
+// Diff is a JSON object of the changed fields, each holding its before and after values, see Diff.
+type Entity struct {
+	CreatedAt  time.Time       `db:"created_at"`
+	ID         string          `db:"id"`
+	TenantID   string          `db:"tenant_id"`
+	EntityType string          `db:"entity_type"`
+	EntityID   string          `db:"entity_id"`
+	Action     string          `db:"action"`
+	Actor      string          `db:"actor"`
+	RequestID  string          `db:"request_id"`
+	Diff       json.RawMessage `db:"diff"`
+}
@@ -0,0 +1,13 @@
+package audit
+
+import "context"
+
+// Repository is append-only: there is no way to delete a record or to update one, but to redact it.
+type Repository interface {
+	Create(ctx context.Context, data Entity) (id string, err error)
+	// Select returns the records of the entity, the oldest first.
+	Select(ctx context.Context, entityID string) (dest []Entity, err error)
+	// Redact replaces the before and after values of the fields in the diffs of the records of the entities
+	// with Redacted, it is how the personal data of an erased data subject leaves the trail.
+	Redact(ctx context.Context, entityType string, entityIDs, fields []string) (err error)
+}
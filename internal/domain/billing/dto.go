@@ -3,6 +3,11 @@ package billing
 import (
 	"errors"
 	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"payment-service/pkg/pii"
 )
 
 type Request struct {
@@ -33,20 +38,183 @@ func (s *Request) Bind(r *http.Request) error {
 	return nil
 }
 
+// PatchRequest holds the billing fields a merge patch may change while the billing is not paid yet.
+type PatchRequest struct {
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	AccountID       string `json:"account_id"`
+	Email           string `json:"email"`
+	Phone           string `json:"phone"`
+	Backlink        string `json:"backlink"`
+	FailureBacklink string `json:"failure_backlink"`
+	PostLink        string `json:"post_link"`
+	FailurePostLink string `json:"failure_post_link"`
+	Language        string `json:"language"`
+}
+
+func NewPatchRequest(data Entity) PatchRequest {
+	return PatchRequest{
+		Name:            data.Name,
+		Description:     data.Description,
+		AccountID:       data.AccountID,
+		Email:           data.Email,
+		Phone:           data.Phone,
+		Backlink:        data.Backlink,
+		FailureBacklink: data.FailureBacklink,
+		PostLink:        data.PostLink,
+		FailurePostLink: data.FailurePostLink,
+		Language:        data.Language,
+	}
+}
+
+func (s *PatchRequest) Validate() error {
+	if s.Name == "" {
+		return errors.New("name: cannot be blank")
+	}
+
+	return nil
+}
+
+// Apply copies the fields onto the billing. A field can be changed but not removed, the store keeps
+// an empty value as unchanged.
+func (s *PatchRequest) Apply(data *Entity) error {
+	fields := []struct {
+		name  string
+		value string
+		dest  *string
+	}{
+		{"name", s.Name, &data.Name},
+		{"description", s.Description, &data.Description},
+		{"account_id", s.AccountID, &data.AccountID},
+		{"email", s.Email, &data.Email},
+		{"phone", s.Phone, &data.Phone},
+		{"backlink", s.Backlink, &data.Backlink},
+		{"failure_backlink", s.FailureBacklink, &data.FailureBacklink},
+		{"post_link", s.PostLink, &data.PostLink},
+		{"failure_post_link", s.FailurePostLink, &data.FailurePostLink},
+		{"language", s.Language, &data.Language},
+	}
+
+	for _, field := range fields {
+		if field.value == "" && *field.dest != "" {
+			return errors.New(field.name + ": cannot be removed")
+		}
+		*field.dest = field.value
+	}
+
+	return nil
+}
+
+// RefundRequest refunds what is left of the billing amount unless Amount is set.
+type RefundRequest struct {
+	Amount string `json:"amount,omitempty"`
+}
+
+func (s *RefundRequest) Bind(r *http.Request) error {
+	if s.Amount == "" {
+		return nil
+	}
+
+	amount, err := decimal.NewFromString(s.Amount)
+	if err != nil || !amount.IsPositive() {
+		return errors.New("amount: must be a positive number")
+	}
+
+	return nil
+}
+
+// TotalsRequest is read from the query string: from and to are YYYY-MM-DD and bound the creation date as [from, to],
+// status is repeated for several statuses and defaults to paid.
+type TotalsRequest struct {
+	Filter
+}
+
+func (s *TotalsRequest) Bind(r *http.Request) (err error) {
+	query := r.URL.Query()
+
+	s.Statuses = query["status"]
+	if len(s.Statuses) == 0 {
+		s.Statuses = []string{StatusPaid}
+	}
+
+	if value := query.Get("from"); value != "" {
+		if s.From, err = time.Parse("2006-01-02", value); err != nil {
+			return errors.New("from: must be YYYY-MM-DD")
+		}
+	}
+
+	if value := query.Get("to"); value != "" {
+		if s.To, err = time.Parse("2006-01-02", value); err != nil {
+			return errors.New("to: must be YYYY-MM-DD")
+		}
+		s.To = s.To.AddDate(0, 0, 1)
+	}
+
+	return nil
+}
+
+// CurrencyTotal sums the billings of one currency, BaseAmount covers only the billings a rate was found for.
+type CurrencyTotal struct {
+	Currency    string `json:"currency"`
+	Count       int    `json:"count"`
+	Amount      string `json:"amount"`
+	BaseAmount  string `json:"base_amount"`
+	Unconverted int    `json:"unconverted,omitempty"`
+}
+
+type TotalsResponse struct {
+	BaseCurrency string          `json:"base_currency"`
+	BaseAmount   string          `json:"base_amount"`
+	Currencies   []CurrencyTotal `json:"currencies"`
+}
+
+// Response holds the personal data of the payer in full only for the callers allowed to read it, see Mask.
 type Response struct {
-	ID   string `json:"id"`
-	Link string `json:"link"`
+	ID           string `json:"id"`
+	Version      int    `json:"version,omitempty"`
+	Link         string `json:"link"`
+	Status       string `json:"status,omitempty"`
+	Amount       string `json:"amount,omitempty"`
+	Refunded     string `json:"refunded,omitempty"`
+	Currency     string `json:"currency,omitempty"`
+	BaseAmount   string `json:"base_amount,omitempty"`
+	BaseCurrency string `json:"base_currency,omitempty"`
+	FXRate       string `json:"fx_rate,omitempty"`
+	Name         string `json:"name,omitempty"`
+	AccountID    string `json:"account_id,omitempty"`
+	Email        string `json:"email,omitempty"`
+	Phone        string `json:"phone,omitempty"`
 }
 
 func ParseFromEntity(data Entity) (res Response) {
 	res = Response{
-		ID:   data.ID,
-		Link: "https://freshgopher-account-service.onrender.com/api/v1/invoices/" + data.ID + "/pay",
+		ID:           data.ID,
+		Version:      data.Version,
+		Link:         "https://freshgopher-account-service.onrender.com/api/v1/invoices/" + data.ID + "/pay",
+		Status:       data.Status,
+		Amount:       data.Amount,
+		Refunded:     data.Refunded,
+		Currency:     data.Currency,
+		BaseAmount:   data.BaseAmount,
+		BaseCurrency: data.BaseCurrency,
+		FXRate:       data.FXRate,
+		Name:         data.Name,
+		AccountID:    data.AccountID,
+		Email:        data.Email,
+		Phone:        data.Phone,
 	}
 
 	return
 }
 
+// Mask leaves just enough of the personal data to tell the payer, e.g. j***@example.com.
+func (s *Response) Mask() {
+	s.Name = pii.MaskName(s.Name)
+	s.AccountID = pii.Mask(s.AccountID)
+	s.Email = pii.MaskEmail(s.Email)
+	s.Phone = pii.MaskPhone(s.Phone)
+}
+
 func ParseFromEntities(data []Entity) (res []Response) {
 	res = make([]Response, 0)
 	for _, object := range data {
@@ -1,15 +1,39 @@
 package billing
 
 import (
+	"errors"
 	"payment-service/pkg/store/postgres"
 	"time"
 )
 
+const (
+	StatusCreated  = "created"
+	StatusPaid     = "paid"
+	StatusFailed   = "failed"
+	StatusRefunded = "refunded"
+	// StatusPartiallyRefunded is a paid billing with a part of its amount refunded, it can be refunded further
+	StatusPartiallyRefunded = "partially_refunded"
+)
+
+var (
+	ErrInvalidStatus = errors.New("billing: operation is not allowed in the current status")
+	ErrInvalidAmount = errors.New("billing: amount exceeds the billing amount left to refund")
+	// ErrInvoicePending and ErrInvoiceMismatch reject a post link the gateway does not confirm
+	ErrInvoicePending  = errors.New("billing: invoice is not completed at the gateway")
+	ErrInvoiceMismatch = errors.New("billing: invoice amount or currency differs from the billing")
+)
+
+// Entity is a billing, Version counts its writes and guards its status changes, see Repository.Update.
+// Refunded is the total amount refunded so far, PaidAt is set once the payment is confirmed.
+// SubjectKeys are the blind indexes of the identifiers of the payer, see SubjectKeys, nil until they are computed.
 type Entity struct {
 	CreatedAt       time.Time      `db:"created_at"`
 	UpdatedAt       time.Time      `db:"updated_at"`
 	ID              string         `db:"id"`
+	TenantID        string         `db:"tenant_id"`
+	Version         int            `db:"version"`
 	Child           postgres.Array `db:"child"`
+	SubjectKeys     postgres.Array `db:"subject_keys"`
 	CorrelationID   string         `db:"correlation_id"`
 	Source          string         `db:"source"`
 	Amount          string         `db:"amount"`
@@ -27,4 +51,12 @@ type Entity struct {
 	FailurePostLink string         `db:"failure_post_link"`
 	Language        string         `db:"language"`
 	PaymentType     string         `db:"payment_type"`
+	Status          string         `db:"status"`
+	Refunded        string         `db:"refunded"`
+	PaidAt          *time.Time     `db:"paid_at"`
+	Reference       string         `db:"reference"`
+	IntReference    string         `db:"int_reference"`
+	FXRate          string         `db:"fx_rate"`
+	BaseAmount      string         `db:"base_amount"`
+	BaseCurrency    string         `db:"base_currency"`
 }
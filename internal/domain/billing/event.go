@@ -0,0 +1,38 @@
+package billing
+
+const (
+	Aggregate = "billing"
+
+	EventCreated  = "billing.created"
+	EventPaid     = "billing.paid"
+	EventFailed   = "billing.failed"
+	EventRefunded = "billing.refunded"
+)
+
+// Event is the payload published to the outbox on every billing state change.
+type Event struct {
+	ID            string `json:"id"`
+	TenantID      string `json:"tenant_id,omitempty"`
+	CorrelationID string `json:"correlation_id"`
+	Source        string `json:"source"`
+	InvoiceID     string `json:"invoice_id"`
+	Amount        string `json:"amount"`
+	Currency      string `json:"currency"`
+	Status        string `json:"status"`
+	Reference     string `json:"reference,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	Refunded      string `json:"refunded,omitempty"`
+}
+
+func NewEvent(data Entity) Event {
+	return Event{
+		ID:            data.ID,
+		TenantID:      data.TenantID,
+		CorrelationID: data.CorrelationID,
+		Source:        data.Source,
+		InvoiceID:     data.InvoiceID,
+		Amount:        data.Amount,
+		Currency:      data.Currency,
+		Status:        data.Status,
+	}
+}
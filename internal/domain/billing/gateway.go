@@ -0,0 +1,17 @@
+package billing
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+
+	"payment-service/pkg/epay"
+)
+
+// Gateway reads the state of an invoice from the payment gateway and refunds its payment. The post link is
+// not authenticated, so the result it carries is only applied as the gateway confirms it.
+type Gateway interface {
+	CheckInvoice(ctx context.Context, invoiceID string) (dest epay.Transaction, err error)
+	// RefundInvoice returns the amount of the invoice payment to the cardholder, a refund is recorded only once it succeeds.
+	RefundInvoice(ctx context.Context, invoiceID string, amount decimal.Decimal) (err error)
+}
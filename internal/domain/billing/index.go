@@ -0,0 +1,56 @@
+package billing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"payment-service/pkg/store/postgres"
+)
+
+// Index hashes an identifier of the payer into a blind index, the billings are looked up by it without the store
+// reading or keeping the identifier itself.
+type Index func(value string) string
+
+// Erased replaces the personal data of an erased billing, it is no identifier and has no key.
+const Erased = "[erased]"
+
+// PlainIndex is the SHA-256 of the value, the index of the billings whose personal data is not encrypted.
+func PlainIndex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// SubjectKeys returns the blind indexes of the email, phone and account id of a billing, normalized as the data
+// subject names them: emails in lowercase and phones by their digits. Empty and erased identifiers have no key.
+func SubjectKeys(index Index, email, phone, accountID string) postgres.Array {
+	identifiers := []struct {
+		name  string
+		value string
+	}{
+		{"email", strings.ToLower(strings.TrimSpace(email))},
+		{"phone", Digits(phone)},
+		{"account_id", strings.TrimSpace(accountID)},
+	}
+
+	keys := postgres.Array{}
+	for _, identifier := range identifiers {
+		if identifier.value != "" && identifier.value != Erased {
+			keys = append(keys, index(identifier.name+":"+identifier.value))
+		}
+	}
+
+	return keys
+}
+
+// Digits keeps the digits of the value, phones are compared by them.
+func Digits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,9 @@
+package billing
+
+import "context"
+
+// Observer is notified of every billing status change within the transaction that makes it,
+// an error rolls the change back.
+type Observer interface {
+	BillingChanged(ctx context.Context, data Entity) error
+}
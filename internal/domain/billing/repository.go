@@ -1,12 +1,42 @@
 package billing
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// Filter narrows SelectByFilter down, empty fields are not applied.
+type Filter struct {
+	Statuses []string
+	// From and To bound the creation time as [From, To)
+	From time.Time
+	To   time.Time
+	// PaidFrom and PaidTo bound the time the billing was paid as [PaidFrom, PaidTo)
+	PaidFrom time.Time
+	PaidTo   time.Time
+	// Keys matches billings whose invoice id, reference or internal reference is one of the keys
+	Keys []string
+	// SubjectKeys matches billings with any of the blind indexes, see SubjectKeys
+	SubjectKeys []string
+}
+
+// Rekeyer encrypts the personal data of the billings kept under a retired key, or not encrypted yet,
+// under the primary key, and computes the subject keys of the billings that have none.
+type Rekeyer interface {
+	Rekey(ctx context.Context) (count int, err error)
+}
 
 type Repository interface {
 	Select(ctx context.Context) (dest []Entity, err error)
+	SelectByFilter(ctx context.Context, filter Filter) (dest []Entity, err error)
 	Create(ctx context.Context, data Entity) (id string, err error)
 	SelectByParentID(ctx context.Context, parentID string) (dest []Entity, err error)
 	Get(ctx context.Context, id string) (dest Entity, err error)
+	GetByInvoiceID(ctx context.Context, invoiceID string) (dest Entity, err error)
+	// Update writes the fields that are set and increments the version. A non-zero data.Version is the version
+	// the billing is expected to have, the update fails with store.ErrorStaleVersion when it has another one,
+	// so a status change made from a billing read earlier never overwrites a concurrent one.
 	Update(ctx context.Context, id string, data Entity) (err error)
-	Delete(ctx context.Context, id string) (err error)
+	// Delete removes the billing, a non-zero version is checked like in Update.
+	Delete(ctx context.Context, id string, version int) (err error)
 }
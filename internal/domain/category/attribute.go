@@ -0,0 +1,220 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	AttributeString = "string"
+	AttributeNumber = "number"
+	AttributeEnum   = "enum"
+)
+
+// attributeCode keeps codes usable as attr.<code> search parameters
+var attributeCode = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Attribute is a typed property the products of the category and of its descendants have.
+// Options lists the allowed values of an enum attribute.
+type Attribute struct {
+	CreatedAt  time.Time `db:"created_at"`
+	ID         string    `db:"id"`
+	TenantID   string    `db:"tenant_id"`
+	CategoryID string    `db:"category_id"`
+	Code       string    `db:"code"`
+	Name       string    `db:"name"`
+	Type       string    `db:"type"`
+	Required   bool      `db:"required"`
+	Options    []string  `db:"-"`
+}
+
+type AttributeRepository interface {
+	// SelectAttributes returns the attributes defined on any of the categories ordered by code.
+	SelectAttributes(ctx context.Context, categoryIDs []string) (dest []Attribute, err error)
+	// CreateAttribute fails with store.ErrorAlreadyExists when the category has an attribute with the code.
+	CreateAttribute(ctx context.Context, data Attribute) (id string, err error)
+	DeleteAttribute(ctx context.Context, categoryID, code string) (err error)
+}
+
+// AttributeError tells which attribute value of a product is wrong.
+type AttributeError struct {
+	Code   string
+	Reason string
+}
+
+func (e *AttributeError) Error() string {
+	return "attributes." + e.Code + ": " + e.Reason
+}
+
+// Schema holds the attributes products of a category have by code: the attributes of the category and of its
+// ancestors, an attribute of a nearer category replaces the one with the same code further up.
+type Schema map[string]Attribute
+
+// NewSchema builds the schema from the ancestors of the category ordered from the root down and their attributes.
+func NewSchema(ancestors []Entity, attributes []Attribute) Schema {
+	depth := make(map[string]int, len(ancestors))
+	for i, data := range ancestors {
+		depth[data.ID] = i
+	}
+
+	s := make(Schema)
+	for _, data := range attributes {
+		if current, ok := s[data.Code]; !ok || depth[data.CategoryID] > depth[current.CategoryID] {
+			s[data.Code] = data
+		}
+	}
+
+	return s
+}
+
+// Attributes returns the attributes of the schema ordered by code.
+func (s Schema) Attributes() (dest []Attribute) {
+	dest = make([]Attribute, 0, len(s))
+	for _, data := range s {
+		dest = append(dest, data)
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].Code < dest[j].Code
+	})
+
+	return
+}
+
+// Validate checks the values against the schema and returns them with numbers as float64, the way JSON decodes them.
+// Unknown codes, values of the wrong type and missing required attributes fail with *AttributeError.
+func (s Schema) Validate(values map[string]any) (dest map[string]any, err error) {
+	dest = make(map[string]any, len(values))
+	for code, value := range values {
+		attribute, ok := s[code]
+		if !ok {
+			return nil, &AttributeError{Code: code, Reason: "is not defined for the category"}
+		}
+
+		if dest[code], err = attribute.check(value); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, attribute := range s.Attributes() {
+		if _, ok := dest[attribute.Code]; attribute.Required && !ok {
+			return nil, &AttributeError{Code: attribute.Code, Reason: "cannot be blank"}
+		}
+	}
+
+	return
+}
+
+func (a Attribute) check(value any) (any, error) {
+	switch a.Type {
+	case AttributeNumber:
+		switch number := value.(type) {
+		case float64:
+			return number, nil
+		case int:
+			return float64(number), nil
+		}
+		return nil, &AttributeError{Code: a.Code, Reason: "must be a number"}
+	case AttributeEnum:
+		text, ok := value.(string)
+		if !ok || !containsOption(a.Options, text) {
+			return nil, &AttributeError{Code: a.Code, Reason: "must be one of " + strings.Join(a.Options, ", ")}
+		}
+		return text, nil
+	default:
+		text, ok := value.(string)
+		if !ok || text == "" {
+			return nil, &AttributeError{Code: a.Code, Reason: "must be a non-empty string"}
+		}
+		return text, nil
+	}
+}
+
+func containsOption(options []string, value string) bool {
+	for _, option := range options {
+		if option == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+type AttributeRequest struct {
+	Code     string   `json:"code"`
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Options  []string `json:"options"`
+	Required bool     `json:"required"`
+}
+
+func (s *AttributeRequest) Bind(r *http.Request) error {
+	if !attributeCode.MatchString(s.Code) {
+		return errors.New("code: must start with a letter and hold lower-case letters, digits and underscores")
+	}
+
+	if s.Name == "" {
+		return errors.New("name: cannot be blank")
+	}
+
+	switch s.Type {
+	case AttributeString, AttributeNumber:
+		if len(s.Options) > 0 {
+			return errors.New("options: are allowed for enum attributes only")
+		}
+	case AttributeEnum:
+		if len(s.Options) == 0 {
+			return errors.New("options: cannot be blank")
+		}
+
+		seen := make(map[string]bool, len(s.Options))
+		for _, option := range s.Options {
+			if option == "" || seen[option] {
+				return errors.New("options: must be distinct non-empty values")
+			}
+			seen[option] = true
+		}
+	default:
+		return errors.New("type: must be string, number or enum")
+	}
+
+	return nil
+}
+
+type AttributeResponse struct {
+	ID         string   `json:"id"`
+	CategoryID string   `json:"category_id"`
+	Code       string   `json:"code"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Options    []string `json:"options,omitempty"`
+	Required   bool     `json:"required"`
+}
+
+func ParseFromAttribute(data Attribute) (res AttributeResponse) {
+	res = AttributeResponse{
+		ID:         data.ID,
+		CategoryID: data.CategoryID,
+		Code:       data.Code,
+		Name:       data.Name,
+		Type:       data.Type,
+		Options:    data.Options,
+		Required:   data.Required,
+	}
+
+	return
+}
+
+func ParseFromAttributes(data []Attribute) (res []AttributeResponse) {
+	res = make([]AttributeResponse, 0, len(data))
+	for _, object := range data {
+		res = append(res, ParseFromAttribute(object))
+	}
+
+	return
+}
@@ -3,6 +3,8 @@ package category
 import (
 	"errors"
 	"net/http"
+	"strings"
+	"time"
 )
 
 type Request struct {
@@ -11,6 +13,10 @@ type Request struct {
 }
 
 func (s *Request) Bind(r *http.Request) error {
+	return s.Validate()
+}
+
+func (s *Request) Validate() error {
 	if s.Name == "" {
 		return errors.New("name: cannot be blank")
 	}
@@ -18,17 +24,85 @@ func (s *Request) Bind(r *http.Request) error {
 	return nil
 }
 
+// NewRequest returns the category as a request, a merge patch is applied to it.
+func NewRequest(data Entity) Request {
+	return Request{
+		ParentID: data.ParentID,
+		Name:     *data.Name,
+	}
+}
+
+// MoveRequest names the new parent of a category, an empty parent makes the category a root.
+type MoveRequest struct {
+	ParentID string `json:"parentID"`
+}
+
+func (s *MoveRequest) Bind(r *http.Request) error {
+	return nil
+}
+
 type Response struct {
-	ID       string     `json:"id"`
-	Name     string     `json:"name"`
-	Children []Response `json:"children,omitempty"`
+	ID        string     `json:"id"`
+	Version   int        `json:"version,omitempty"`
+	ParentID  string     `json:"parentID,omitempty"`
+	Name      string     `json:"name"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+	Children  []Response `json:"children,omitempty"`
 }
 
 func ParseFromEntity(data Entity) (res Response) {
 	res = Response{
-		ID:   data.ID,
-		Name: *data.Name,
+		ID:        data.ID,
+		Version:   data.Version,
+		ParentID:  data.ParentID,
+		Name:      *data.Name,
+		DeletedAt: data.DeletedAt,
+	}
+
+	return
+}
+
+// ParseFromTree nests the categories under their parents, categories whose parent isn't among them are the roots.
+func ParseFromTree(data []Entity) (res []Response) {
+	ids := make(map[string]bool, len(data))
+	children := make(map[string][]Entity)
+	for _, object := range data {
+		ids[object.ID] = true
+		children[object.ParentID] = append(children[object.ParentID], object)
+	}
+
+	var nest func(parent Entity) Response
+	nest = func(parent Entity) Response {
+		node := ParseFromEntity(parent)
+		for _, child := range children[parent.ID] {
+			node.Children = append(node.Children, nest(child))
+		}
+		return node
+	}
+
+	res = make([]Response, 0)
+	for _, object := range data {
+		if !ids[object.ParentID] {
+			res = append(res, nest(object))
+		}
+	}
+	return
+}
+
+// BreadcrumbResponse lists the categories from the root down to the category, Path joins their names.
+type BreadcrumbResponse struct {
+	Items []Response `json:"items"`
+	Path  string     `json:"path"`
+}
+
+func ParseFromAncestors(data []Entity) (res BreadcrumbResponse) {
+	res.Items = ParseFromEntities(data)
+
+	names := make([]string, 0, len(data))
+	for _, object := range data {
+		names = append(names, *object.Name)
 	}
+	res.Path = strings.Join(names, " / ")
 
 	return
 }
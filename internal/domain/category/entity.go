@@ -1,15 +1,26 @@
 package category
 
 import (
-	"payment-service/pkg/store/postgres"
+	"errors"
 	"time"
 )
 
+var (
+	ErrCycle          = errors.New("category: cannot be moved under itself or one of its descendants")
+	ErrParentNotFound = errors.New("category: parent category not found")
+	ErrNotEmpty       = errors.New("category: has subcategories or products, delete them with it by cascade")
+)
+
+// Entity is a category, Version counts its writes, see Repository.Update. DeletedAt is set while it is in the trash,
+// TrashedWith names the category whose deletion took it along.
 type Entity struct {
-	CreatedAt time.Time      `db:"created_at"`
-	UpdatedAt time.Time      `db:"updated_at"`
-	ID        string         `db:"id"`
-	ParentID  string         `db:"parent_id"`
-	Name      *string        `db:"name"`
-	Child     postgres.Array `db:"child"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+	ID          string     `db:"id"`
+	TenantID    string     `db:"tenant_id"`
+	Version     int        `db:"version"`
+	ParentID    string     `db:"parent_id"`
+	Name        *string    `db:"name"`
+	DeletedAt   *time.Time `db:"deleted_at"`
+	TrashedWith string     `db:"trashed_with"`
 }
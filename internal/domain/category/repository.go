@@ -1,12 +1,39 @@
 package category
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// Repository leaves categories in the trash out of every read but GetDeleted and SelectDeleted.
 type Repository interface {
 	Select(ctx context.Context) (dest []Entity, err error)
+	// SelectDeleted returns the categories in the trash, the latest deleted first.
+	SelectDeleted(ctx context.Context) (dest []Entity, err error)
 	SelectByParentID(ctx context.Context, parentID string) (dest []Entity, err error)
+	// Tree returns the category with all its descendants, or every category when rootID is empty.
+	// Siblings are ordered by creation.
+	Tree(ctx context.Context, rootID string) (dest []Entity, err error)
+	// SelectAncestors returns the category and its ancestors from the root down.
+	SelectAncestors(ctx context.Context, id string) (dest []Entity, err error)
 	Create(ctx context.Context, data Entity) (id string, err error)
 	Get(ctx context.Context, id string) (dest Entity, err error)
+	// GetDeleted reads the category from the trash.
+	GetDeleted(ctx context.Context, id string) (dest Entity, err error)
+	// Update writes the fields that are set and increments the version. A non-zero data.Version is the version
+	// the category is expected to have, the update fails with store.ErrorStaleVersion when it has another one.
 	Update(ctx context.Context, id string, data Entity) (err error)
-	Delete(ctx context.Context, id string) (err error)
+	// Move changes the parent of the category, an empty parentID makes it a root, a non-zero version is checked
+	// like in Update. It fails with ErrCycle when the parent is the category or its descendant and has to run
+	// within store.Transactor.
+	Move(ctx context.Context, id, parentID string, version int) (err error)
+	// Delete moves the category to the trash, a non-zero version is checked like in Update.
+	// Its subcategories and products are left as they are. trashedWith is the category whose deletion took
+	// this one along, empty when it is deleted on its own.
+	Delete(ctx context.Context, id string, version int, trashedWith string) (err error)
+	// Restore takes the category out of the trash and clears its TrashedWith.
+	Restore(ctx context.Context, id string) (err error)
+	// Purge removes the categories deleted before the time for good, once they have no subcategories
+	// or products left, in the trash or not.
+	Purge(ctx context.Context, before time.Time) (purged int, err error)
 }
@@ -0,0 +1,141 @@
+package dispute
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Request struct {
+	BillingID  string    `json:"billing_id"`
+	ReasonCode string    `json:"reason_code"`
+	Amount     string    `json:"amount"`
+	DueAt      time.Time `json:"due_at"`
+}
+
+func (s *Request) Bind(r *http.Request) error {
+	if s.BillingID == "" {
+		return errors.New("billing_id: cannot be blank")
+	}
+
+	if s.ReasonCode == "" {
+		return errors.New("reason_code: cannot be blank")
+	}
+
+	if amount, err := decimal.NewFromString(s.Amount); err != nil || !amount.IsPositive() {
+		return errors.New("amount: must be a positive number")
+	}
+
+	if s.DueAt.IsZero() {
+		return errors.New("due_at: cannot be blank")
+	}
+
+	return nil
+}
+
+// defaultDueWithin is how far ahead DueRequest looks when within is not given.
+const defaultDueWithin = 72 * time.Hour
+
+// DueRequest is read from the query string: within is a duration such as 48h.
+type DueRequest struct {
+	Within time.Duration
+}
+
+func (s *DueRequest) Bind(r *http.Request) (err error) {
+	s.Within = defaultDueWithin
+
+	if value := r.URL.Query().Get("within"); value != "" {
+		if s.Within, err = time.ParseDuration(value); err != nil || s.Within <= 0 {
+			return errors.New("within: must be a positive duration")
+		}
+	}
+
+	return nil
+}
+
+type EvidenceRequest struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Note        string `json:"note"`
+}
+
+func (s *EvidenceRequest) Bind(r *http.Request) error {
+	if s.Name == "" {
+		return errors.New("name: cannot be blank")
+	}
+
+	if s.URL == "" {
+		return errors.New("url: cannot be blank")
+	}
+
+	return nil
+}
+
+type ResolveRequest struct {
+	Outcome string `json:"outcome"`
+}
+
+func (s *ResolveRequest) Bind(r *http.Request) error {
+	if s.Outcome != StatusWon && s.Outcome != StatusLost {
+		return errors.New("outcome: must be " + StatusWon + " or " + StatusLost)
+	}
+
+	return nil
+}
+
+type EvidenceResponse struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	ContentType string    `json:"content_type,omitempty"`
+	Note        string    `json:"note,omitempty"`
+}
+
+type Response struct {
+	ID         string             `json:"id"`
+	BillingID  string             `json:"billing_id"`
+	ReasonCode string             `json:"reason_code"`
+	Amount     string             `json:"amount"`
+	Currency   string             `json:"currency"`
+	Status     string             `json:"status"`
+	DueAt      time.Time          `json:"due_at"`
+	ResolvedAt *time.Time         `json:"resolved_at,omitempty"`
+	Evidence   []EvidenceResponse `json:"evidence,omitempty"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		ID:         data.ID,
+		BillingID:  data.BillingID,
+		ReasonCode: data.ReasonCode,
+		Amount:     data.Amount.String(),
+		Currency:   data.Currency,
+		Status:     data.Status,
+		DueAt:      data.DueAt,
+		ResolvedAt: data.ResolvedAt,
+	}
+
+	for _, evidence := range data.Evidence {
+		res.Evidence = append(res.Evidence, EvidenceResponse{
+			ID:          evidence.ID,
+			CreatedAt:   evidence.CreatedAt,
+			Name:        evidence.Name,
+			URL:         evidence.URL,
+			ContentType: evidence.ContentType,
+			Note:        evidence.Note,
+		})
+	}
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
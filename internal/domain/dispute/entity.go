@@ -0,0 +1,64 @@
+package dispute
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	StatusOpened            = "opened"
+	StatusEvidenceSubmitted = "evidence_submitted"
+	StatusWon               = "won"
+	StatusLost              = "lost"
+)
+
+var (
+	ErrInvalidStatus = errors.New("dispute: transition is not allowed in the current status")
+	ErrNoEvidence    = errors.New("dispute: evidence is required before submitting")
+	ErrInvalidAmount = errors.New("dispute: amount exceeds the billing amount")
+)
+
+// transitions lists the statuses a dispute can move to from each status.
+var transitions = map[string][]string{
+	StatusOpened:            {StatusEvidenceSubmitted},
+	StatusEvidenceSubmitted: {StatusWon, StatusLost},
+}
+
+type Entity struct {
+	CreatedAt  time.Time       `db:"created_at"`
+	UpdatedAt  time.Time       `db:"updated_at"`
+	ID         string          `db:"id"`
+	TenantID   string          `db:"tenant_id"`
+	BillingID  string          `db:"billing_id"`
+	ReasonCode string          `db:"reason_code"`
+	Amount     decimal.Decimal `db:"amount"`
+	Currency   string          `db:"currency"`
+	Status     string          `db:"status"`
+	DueAt      time.Time       `db:"due_at"`
+	ResolvedAt *time.Time      `db:"resolved_at"`
+	Evidence   []Evidence      `db:"-"`
+}
+
+// CanTransition reports whether the dispute can move to the status.
+func (e Entity) CanTransition(status string) bool {
+	for _, next := range transitions[e.Status] {
+		if next == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Evidence is an attachment sent to the bank to contest the dispute.
+type Evidence struct {
+	CreatedAt   time.Time `db:"created_at"`
+	ID          string    `db:"id"`
+	DisputeID   string    `db:"dispute_id"`
+	Name        string    `db:"name"`
+	URL         string    `db:"url"`
+	ContentType string    `db:"content_type"`
+	Note        string    `db:"note"`
+}
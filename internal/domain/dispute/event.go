@@ -0,0 +1,33 @@
+package dispute
+
+const (
+	Aggregate = "dispute"
+
+	EventOpened            = "dispute.opened"
+	EventEvidenceSubmitted = "dispute.evidence_submitted"
+	EventWon               = "dispute.won"
+	EventLost              = "dispute.lost"
+)
+
+// Event is the payload published to the outbox on every dispute state change.
+type Event struct {
+	ID         string `json:"id"`
+	TenantID   string `json:"tenant_id,omitempty"`
+	BillingID  string `json:"billing_id"`
+	ReasonCode string `json:"reason_code"`
+	Amount     string `json:"amount"`
+	Currency   string `json:"currency"`
+	Status     string `json:"status"`
+}
+
+func NewEvent(data Entity) Event {
+	return Event{
+		ID:         data.ID,
+		TenantID:   data.TenantID,
+		BillingID:  data.BillingID,
+		ReasonCode: data.ReasonCode,
+		Amount:     data.Amount.String(),
+		Currency:   data.Currency,
+		Status:     data.Status,
+	}
+}
@@ -0,0 +1,18 @@
+package dispute
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Select returns disputes in the status or all of them when status is empty.
+	Select(ctx context.Context, status string) (dest []Entity, err error)
+	// SelectDue returns opened disputes whose deadline is before the time, the nearest deadline first.
+	SelectDue(ctx context.Context, before time.Time) (dest []Entity, err error)
+	Create(ctx context.Context, data Entity) (id string, err error)
+	// Get returns the dispute with its evidence.
+	Get(ctx context.Context, id string) (dest Entity, err error)
+	Update(ctx context.Context, id string, data Entity) (err error)
+	AddEvidence(ctx context.Context, data Evidence) (id string, err error)
+}
@@ -0,0 +1,52 @@
+package fx
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Request is read from the query string: date is YYYY-MM-DD and defaults to today.
+type Request struct {
+	Date time.Time
+}
+
+func (s *Request) Bind(r *http.Request) (err error) {
+	s.Date = Day(time.Now())
+
+	if value := r.URL.Query().Get("date"); value != "" {
+		if s.Date, err = time.Parse("2006-01-02", value); err != nil {
+			return errors.New("date: must be YYYY-MM-DD")
+		}
+	}
+
+	return nil
+}
+
+type Response struct {
+	Currency string `json:"currency"`
+	Base     string `json:"base"`
+	Rate     string `json:"rate"`
+	Date     string `json:"date"`
+	Source   string `json:"source"`
+}
+
+func ParseFromEntity(data Rate) (res Response) {
+	res = Response{
+		Currency: data.Currency,
+		Base:     data.Base,
+		Rate:     data.Rate.String(),
+		Date:     data.Date.Format("2006-01-02"),
+		Source:   data.Source,
+	}
+
+	return
+}
+
+func ParseFromEntities(data []Rate) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
@@ -0,0 +1,60 @@
+package fx
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ratePrecision is the number of decimal places a rate is stored with.
+const ratePrecision = 8
+
+var ErrRateNotFound = errors.New("fx: no rate for the currency on the date")
+
+// Rate is the price of one unit of Currency in the Base currency, effective from Date until a later rate.
+type Rate struct {
+	CreatedAt time.Time       `db:"created_at"`
+	Currency  string          `db:"currency"`
+	Base      string          `db:"base"`
+	Rate      decimal.Decimal `db:"rate"`
+	Date      time.Time       `db:"date"`
+	Source    string          `db:"source"`
+}
+
+// Convert returns the amount in the base currency rounded to cents.
+func (r Rate) Convert(amount decimal.Decimal) decimal.Decimal {
+	return amount.Mul(r.Rate).Round(2)
+}
+
+// Identity is the rate of the base currency to itself.
+func Identity(base string, date time.Time) Rate {
+	return Rate{
+		Currency: base,
+		Base:     base,
+		Rate:     decimal.NewFromInt(1),
+		Date:     date,
+	}
+}
+
+// Quote builds the rate from the amount of the currency one unit of the base buys, as most rate APIs publish them.
+func Quote(currency, base string, perBase decimal.Decimal, date time.Time) Rate {
+	return Rate{
+		Currency: NormalizeCurrency(currency),
+		Base:     base,
+		Rate:     decimal.NewFromInt(1).DivRound(perBase, ratePrecision),
+		Date:     Day(date),
+	}
+}
+
+// NormalizeCurrency turns a currency code into the ISO 4217 form rates are stored with.
+func NormalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// Day truncates the time to the UTC date rates are effective on.
+func Day(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
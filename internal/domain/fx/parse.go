@@ -0,0 +1,110 @@
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrNoHeader = errors.New("fx: feed has no header with currency and rate columns")
+
+// columns lists the header names accepted for each feed field.
+var columns = map[string][]string{
+	"currency": {"currency", "code", "ccy"},
+	"rate":     {"rate", "value"},
+	"date":     {"date", "effective_date"},
+}
+
+// RowError describes a feed line that can't be imported.
+type RowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ParseError collects every invalid line of a feed.
+type ParseError struct {
+	Rows []RowError
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("fx: %d invalid rows, first at line %d: %s", len(e.Rows), e.Rows[0].Line, e.Rows[0].Message)
+}
+
+// ParseRates reads rates from CSV records with currency, rate and an optional date column. The rate is
+// the price of one unit of the currency in the base currency, rows without a date are effective on the date given.
+func ParseRates(records [][]string, base string, date time.Time) (rates []Rate, err error) {
+	if len(records) == 0 {
+		return nil, ErrNoHeader
+	}
+
+	index := make(map[string]int)
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for field, aliases := range columns {
+			for _, alias := range aliases {
+				if name == alias {
+					index[field] = i
+				}
+			}
+		}
+	}
+
+	_, hasCurrency := index["currency"]
+	_, hasRate := index["rate"]
+	if !hasCurrency || !hasRate {
+		return nil, ErrNoHeader
+	}
+
+	parseErr := &ParseError{}
+	for i, record := range records[1:] {
+		line := i + 2
+
+		value := func(field string) string {
+			column, ok := index[field]
+			if !ok || column >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[column])
+		}
+
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		rate := Rate{
+			Currency: NormalizeCurrency(value("currency")),
+			Base:     base,
+			Date:     Day(date),
+		}
+
+		if len(rate.Currency) != 3 {
+			parseErr.Rows = append(parseErr.Rows, RowError{Line: line, Message: "currency: must be a 3-letter code"})
+			continue
+		}
+
+		rate.Rate, err = decimal.NewFromString(strings.Replace(value("rate"), ",", ".", 1))
+		if err != nil || !rate.Rate.IsPositive() {
+			parseErr.Rows = append(parseErr.Rows, RowError{Line: line, Message: "rate: must be a positive number"})
+			continue
+		}
+
+		if effective := value("date"); effective != "" {
+			if rate.Date, err = time.Parse("2006-01-02", effective); err != nil {
+				parseErr.Rows = append(parseErr.Rows, RowError{Line: line, Message: "date: must be YYYY-MM-DD"})
+				continue
+			}
+		}
+
+		rates = append(rates, rate)
+	}
+	err = nil
+
+	if len(parseErr.Rows) > 0 {
+		return nil, parseErr
+	}
+
+	return
+}
@@ -0,0 +1,78 @@
+package fx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRates(t *testing.T) {
+	date := time.Date(2024, 3, 15, 18, 30, 0, 0, time.UTC)
+
+	t.Run("rates", func(t *testing.T) {
+		got, err := ParseRates([][]string{
+			{" Code ", "Value", "Effective_Date"},
+			{"usd", "449,5", ""},
+			{"EUR", "488.25", "2024-03-14"},
+			{"", "", ""},
+			{"RUB", "4.9"},
+		}, "KZT", date)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []struct {
+			currency, rate string
+			date           time.Time
+		}{
+			{"USD", "449.5", Day(date)},
+			{"EUR", "488.25", time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)},
+			{"RUB", "4.9", Day(date)},
+		}
+		if len(got) != len(want) {
+			t.Fatalf("got %+v, want %d rates", got, len(want))
+		}
+		for i, rate := range got {
+			if rate.Currency != want[i].currency || rate.Rate.String() != want[i].rate ||
+				!rate.Date.Equal(want[i].date) || rate.Base != "KZT" {
+				t.Errorf("got %s %s on %s in %s, want %s %s on %s", rate.Currency, rate.Rate, rate.Date, rate.Base,
+					want[i].currency, want[i].rate, want[i].date)
+			}
+		}
+	})
+
+	t.Run("invalid rows", func(t *testing.T) {
+		_, err := ParseRates([][]string{
+			{"currency", "rate", "date"},
+			{"USD", "449.5", ""},
+			{"DOLLAR", "449.5", ""},
+			{"EUR", "-1", ""},
+			{"RUB", "1.2.3", ""},
+			{"CNY", "62", "15.03.2024"},
+		}, "KZT", date)
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("err = %v, want a ParseError", err)
+		}
+
+		want := []RowError{
+			{Line: 3, Message: "currency: must be a 3-letter code"},
+			{Line: 4, Message: "rate: must be a positive number"},
+			{Line: 5, Message: "rate: must be a positive number"},
+			{Line: 6, Message: "date: must be YYYY-MM-DD"},
+		}
+		if !reflect.DeepEqual(parseErr.Rows, want) {
+			t.Errorf("got %+v, want %+v", parseErr.Rows, want)
+		}
+	})
+
+	t.Run("no header", func(t *testing.T) {
+		for _, records := range [][][]string{nil, {{"currency", "amount"}}, {{"USD", "449.5"}}} {
+			if _, err := ParseRates(records, "KZT", date); err != ErrNoHeader {
+				t.Errorf("got %v for %q, want %v", err, records, ErrNoHeader)
+			}
+		}
+	})
+}
@@ -0,0 +1,8 @@
+package fx
+
+import "context"
+
+// Provider fetches the current rates to the base currency from an external feed.
+type Provider interface {
+	Fetch(ctx context.Context, base string) (dest []Rate, err error)
+}
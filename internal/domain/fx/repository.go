@@ -0,0 +1,15 @@
+package fx
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Save stores the rates, a rate for the same currency, base and date replaces the previous one.
+	Save(ctx context.Context, data []Rate) (err error)
+	// Select returns the latest rate of every currency effective on the date.
+	Select(ctx context.Context, base string, date time.Time) (dest []Rate, err error)
+	// Get returns the latest rate of the currency effective on the date or ErrRateNotFound.
+	Get(ctx context.Context, currency, base string, date time.Time) (dest Rate, err error)
+}
@@ -0,0 +1,98 @@
+package inventory
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type WarehouseRequest struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+func (s *WarehouseRequest) Bind(r *http.Request) error {
+	if s.Code == "" {
+		return errors.New("code: cannot be blank")
+	}
+
+	if s.Name == "" {
+		return errors.New("name: cannot be blank")
+	}
+
+	return nil
+}
+
+type StockRequest struct {
+	ProductID   string `json:"product_id"`
+	WarehouseID string `json:"warehouse_id"`
+	OnHand      string `json:"on_hand"`
+}
+
+func (s *StockRequest) Bind(r *http.Request) error {
+	if s.ProductID == "" {
+		return errors.New("product_id: cannot be blank")
+	}
+
+	if s.WarehouseID == "" {
+		return errors.New("warehouse_id: cannot be blank")
+	}
+
+	if onHand, err := decimal.NewFromString(s.OnHand); err != nil || onHand.IsNegative() {
+		return errors.New("on_hand: must be a non-negative number")
+	}
+
+	return nil
+}
+
+type WarehouseResponse struct {
+	ID   string `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+func ParseFromWarehouse(data Warehouse) WarehouseResponse {
+	return WarehouseResponse{
+		ID:   data.ID,
+		Code: data.Code,
+		Name: data.Name,
+	}
+}
+
+func ParseFromWarehouses(data []Warehouse) (res []WarehouseResponse) {
+	res = make([]WarehouseResponse, 0)
+	for _, object := range data {
+		res = append(res, ParseFromWarehouse(object))
+	}
+	return
+}
+
+type StockResponse struct {
+	ProductID   string    `json:"product_id"`
+	WarehouseID string    `json:"warehouse_id"`
+	OnHand      string    `json:"on_hand"`
+	Reserved    string    `json:"reserved"`
+	Available   string    `json:"available"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func ParseFromStock(data Stock) StockResponse {
+	return StockResponse{
+		ProductID:   data.ProductID,
+		WarehouseID: data.WarehouseID,
+		OnHand:      data.OnHand.String(),
+		Reserved:    data.Reserved.String(),
+		Available:   data.Available().String(),
+		UpdatedAt:   data.UpdatedAt,
+	}
+}
+
+func ParseFromStocks(data []Stock) (res []StockResponse) {
+	res = make([]StockResponse, 0)
+	for _, object := range data {
+		res = append(res, ParseFromStock(object))
+	}
+	return
+}
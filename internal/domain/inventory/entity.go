@@ -0,0 +1,71 @@
+package inventory
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// StatusActive holds the stock until the billing is paid, fails or the reservation expires
+	StatusActive = "active"
+	// StatusCommitted took the stock off hand once the billing was paid
+	StatusCommitted = "committed"
+	// StatusReleased returned the stock after the billing failed or the reservation expired
+	StatusReleased = "released"
+	// StatusBackordered marks a billing paid after its reservation was released while the stock had run out
+	StatusBackordered = "backordered"
+)
+
+var ErrInsufficientStock = errors.New("inventory: not enough stock available")
+
+type Warehouse struct {
+	CreatedAt time.Time `db:"created_at"`
+	ID        string    `db:"id"`
+	TenantID  string    `db:"tenant_id"`
+	Code      string    `db:"code"`
+	Name      string    `db:"name"`
+}
+
+// Stock is the quantity of a product in a warehouse, Reserved is held for unpaid orders and is part of OnHand.
+// The product and the warehouse belong to the tenant of the stock.
+type Stock struct {
+	UpdatedAt   time.Time       `db:"updated_at"`
+	TenantID    string          `db:"tenant_id"`
+	ProductID   string          `db:"product_id"`
+	WarehouseID string          `db:"warehouse_id"`
+	OnHand      decimal.Decimal `db:"on_hand"`
+	Reserved    decimal.Decimal `db:"reserved"`
+}
+
+// Available is the quantity that can still be reserved.
+func (s Stock) Available() decimal.Decimal {
+	return s.OnHand.Sub(s.Reserved)
+}
+
+// Allocation is the quantity of a product reserved in one warehouse.
+type Allocation struct {
+	ProductID   string          `db:"product_id"`
+	WarehouseID string          `db:"warehouse_id"`
+	Quantity    decimal.Decimal `db:"quantity"`
+}
+
+// Reservation holds stock for the items of an order until its billing settles.
+type Reservation struct {
+	CreatedAt   time.Time    `db:"created_at"`
+	UpdatedAt   time.Time    `db:"updated_at"`
+	ID          string       `db:"id"`
+	TenantID    string       `db:"tenant_id"`
+	OrderID     string       `db:"order_id"`
+	BillingID   string       `db:"billing_id"`
+	Status      string       `db:"status"`
+	ExpiresAt   time.Time    `db:"expires_at"`
+	Allocations []Allocation `db:"-"`
+}
+
+// Item is a product quantity to reserve.
+type Item struct {
+	ProductID string
+	Quantity  decimal.Decimal
+}
@@ -0,0 +1,34 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Repository interface {
+	SelectWarehouses(ctx context.Context) (dest []Warehouse, err error)
+	CreateWarehouse(ctx context.Context, data Warehouse) (id string, err error)
+
+	// SelectStock returns the stock of the product in every warehouse, or of all products when productID is empty.
+	SelectStock(ctx context.Context, productID string) (dest []Stock, err error)
+	// SetStock sets the quantity on hand, it fails with ErrInsufficientStock below the reserved quantity.
+	SetStock(ctx context.Context, data Stock) (err error)
+
+	// Allocate reserves the quantity of the product across warehouses, the most available first.
+	// It reserves all of it or nothing and fails with ErrInsufficientStock.
+	Allocate(ctx context.Context, productID string, quantity decimal.Decimal) (dest []Allocation, err error)
+	// Deallocate returns the reserved quantities, commit takes them off hand as well.
+	Deallocate(ctx context.Context, data []Allocation, commit bool) (err error)
+
+	// CreateReservation stores the reservation with its allocations.
+	CreateReservation(ctx context.Context, data Reservation) (id string, err error)
+	// GetReservation and GetReservationByBillingID lock the reservation until the transaction ends.
+	GetReservation(ctx context.Context, id string) (dest Reservation, err error)
+	GetReservationByBillingID(ctx context.Context, billingID string) (dest Reservation, err error)
+	// UpdateReservation changes the status, the order and the billing of the reservation.
+	UpdateReservation(ctx context.Context, id string, data Reservation) (err error)
+	// SelectExpiredReservations returns active reservations that expired before the time.
+	SelectExpiredReservations(ctx context.Context, before time.Time) (dest []Reservation, err error)
+}
@@ -0,0 +1,130 @@
+package ledger
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// BalanceRequest is read from the query string: currency is required, from and to are RFC 3339 or YYYY-MM-DD.
+type BalanceRequest struct {
+	Currency string
+	From     time.Time
+	To       time.Time
+}
+
+func (s *BalanceRequest) Bind(r *http.Request) (err error) {
+	query := r.URL.Query()
+
+	s.Currency = query.Get("currency")
+	if s.Currency == "" {
+		return errors.New("currency: cannot be blank")
+	}
+
+	if s.From, err = parseTime(query.Get("from")); err != nil {
+		return errors.New("from: " + err.Error())
+	}
+
+	if s.To, err = parseTime(query.Get("to")); err != nil {
+		return errors.New("to: " + err.Error())
+	}
+
+	return
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+type AccountResponse struct {
+	ID   string `json:"id"`
+	Code string `json:"code"`
+	Type string `json:"type"`
+}
+
+func ParseFromAccount(data Account) (res AccountResponse) {
+	res = AccountResponse{
+		ID:   data.ID,
+		Code: data.Code,
+		Type: data.Type,
+	}
+	return
+}
+
+func ParseFromAccounts(data []Account) (res []AccountResponse) {
+	res = make([]AccountResponse, 0)
+	for _, object := range data {
+		res = append(res, ParseFromAccount(object))
+	}
+	return
+}
+
+type PostingResponse struct {
+	Account  string `json:"account"`
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type EntryResponse struct {
+	ID          string            `json:"id"`
+	CreatedAt   time.Time         `json:"created_at"`
+	Kind        string            `json:"kind"`
+	BillingID   string            `json:"billing_id"`
+	Description string            `json:"description"`
+	Postings    []PostingResponse `json:"postings"`
+}
+
+func ParseFromEntry(data Entry) (res EntryResponse) {
+	res = EntryResponse{
+		ID:          data.ID,
+		CreatedAt:   data.CreatedAt,
+		Kind:        data.Kind,
+		BillingID:   data.BillingID,
+		Description: data.Description,
+		Postings:    make([]PostingResponse, 0, len(data.Postings)),
+	}
+
+	for _, posting := range data.Postings {
+		res.Postings = append(res.Postings, PostingResponse{
+			Account:  posting.AccountCode,
+			Amount:   posting.Amount.String(),
+			Currency: posting.Currency,
+		})
+	}
+	return
+}
+
+func ParseFromEntries(data []Entry) (res []EntryResponse) {
+	res = make([]EntryResponse, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntry(object))
+	}
+	return
+}
+
+type BalanceResponse struct {
+	Account  string `json:"account"`
+	Currency string `json:"currency"`
+	Debit    string `json:"debit"`
+	Credit   string `json:"credit"`
+	Balance  string `json:"balance"`
+}
+
+func ParseFromBalance(account Account, currency string, data Balance) (res BalanceResponse) {
+	res = BalanceResponse{
+		Account:  account.Code,
+		Currency: currency,
+		Debit:    data.Debit.String(),
+		Credit:   data.Credit.String(),
+		Balance:  data.Debit.Sub(data.Credit).String(),
+	}
+	return
+}
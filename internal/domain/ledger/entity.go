@@ -0,0 +1,107 @@
+package ledger
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	TypeAsset   = "asset"
+	TypeRevenue = "revenue"
+	TypeExpense = "expense"
+)
+
+// Accounts are opened per terminal, the code is the account kind followed by the terminal id.
+const (
+	AccountGateway     = "gateway"     // funds the gateway collected and owes the terminal
+	AccountSales       = "sales"       // captured payments
+	AccountRefunds     = "refunds"     // money returned to cardholders
+	AccountChargebacks = "chargebacks" // money lost to disputes
+	AccountFees        = "fees"        // gateway fees
+)
+
+const (
+	KindCapture    = "capture"
+	KindRefund     = "refund"
+	KindChargeback = "chargeback"
+	KindFee        = "fee"
+)
+
+var (
+	ErrUnbalanced = errors.New("ledger: entry does not balance to zero")
+	ErrNoPostings = errors.New("ledger: entry needs at least two postings")
+)
+
+var accountTypes = map[string]string{
+	AccountGateway:     TypeAsset,
+	AccountSales:       TypeRevenue,
+	AccountRefunds:     TypeExpense,
+	AccountChargebacks: TypeExpense,
+	AccountFees:        TypeExpense,
+}
+
+type Account struct {
+	CreatedAt time.Time `db:"created_at"`
+	ID        string    `db:"id"`
+	Code      string    `db:"code"`
+	Type      string    `db:"type"`
+}
+
+// NewAccount returns the account of the given kind for the terminal.
+func NewAccount(kind, terminalID string) Account {
+	return Account{
+		Code: kind + ":" + terminalID,
+		Type: accountTypes[kind],
+	}
+}
+
+// Entry is an immutable journal entry, it is never updated or deleted once created.
+// Mistakes are corrected with a new entry that reverses the postings.
+type Entry struct {
+	CreatedAt   time.Time `db:"created_at"`
+	ID          string    `db:"id"`
+	TenantID    string    `db:"tenant_id"`
+	Kind        string    `db:"kind"`
+	BillingID   string    `db:"billing_id"`
+	Description string    `db:"description"`
+	DedupKey    string    `db:"dedup_key"`
+	Postings    []Posting `db:"-"`
+}
+
+// Posting moves Amount to the account: debits are positive and credits are negative.
+type Posting struct {
+	ID          string          `db:"id"`
+	EntryID     string          `db:"entry_id"`
+	AccountID   string          `db:"account_id"`
+	AccountCode string          `db:"account_code"`
+	Amount      decimal.Decimal `db:"amount"`
+	Currency    string          `db:"currency"`
+}
+
+// Validate checks that the postings of every currency in the entry sum up to zero.
+func (e Entry) Validate() error {
+	if len(e.Postings) < 2 {
+		return ErrNoPostings
+	}
+
+	sums := make(map[string]decimal.Decimal)
+	for _, posting := range e.Postings {
+		sums[posting.Currency] = sums[posting.Currency].Add(posting.Amount)
+	}
+
+	for _, sum := range sums {
+		if !sum.IsZero() {
+			return ErrUnbalanced
+		}
+	}
+
+	return nil
+}
+
+// Balance is the turnover of an account in a currency over a period.
+type Balance struct {
+	Debit  decimal.Decimal `db:"debit"`
+	Credit decimal.Decimal `db:"credit"`
+}
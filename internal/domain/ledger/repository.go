@@ -0,0 +1,19 @@
+package ledger
+
+import (
+	"context"
+	"time"
+)
+
+// Repository is append-only: there is no way to update or delete an entry.
+type Repository interface {
+	// EnsureAccount returns the id of the account with the code, creating it when it doesn't exist.
+	EnsureAccount(ctx context.Context, data Account) (id string, err error)
+	SelectAccounts(ctx context.Context) (dest []Account, err error)
+	GetAccountByCode(ctx context.Context, code string) (dest Account, err error)
+	// CreateEntry stores the entry with its postings unless an entry with the same dedup key exists.
+	CreateEntry(ctx context.Context, data Entry) (id string, err error)
+	SelectEntries(ctx context.Context, billingID string) (dest []Entry, err error)
+	// Balance sums the postings of the account in the currency made within [from, to), zero bounds are open.
+	Balance(ctx context.Context, accountID, currency string, from, to time.Time) (dest Balance, err error)
+}
@@ -0,0 +1,162 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type ItemRequest struct {
+	ProductID string `json:"product_id"`
+	Quantity  string `json:"quantity"`
+	Discount  string `json:"discount,omitempty"`
+}
+
+// Request places an order priced from the price list, the customer and link fields are passed on to its billing.
+type Request struct {
+	Currency  string        `json:"currency"`
+	PriceList string        `json:"price_list"`
+	Discount  string        `json:"discount,omitempty"`
+	Items     []ItemRequest `json:"items"`
+
+	Source          string `json:"source"`
+	TerminalID      string `json:"terminal_id"`
+	InvoiceID       string `json:"invoice_id"`
+	Name            string `json:"name"`
+	AccountID       string `json:"account_id"`
+	Email           string `json:"email"`
+	Phone           string `json:"phone"`
+	Backlink        string `json:"backlink"`
+	FailureBacklink string `json:"failure_backlink"`
+	PostLink        string `json:"post_link"`
+	FailurePostLink string `json:"failure_post_link"`
+	Language        string `json:"language"`
+	PaymentType     string `json:"payment_type"`
+}
+
+func (s *Request) Bind(r *http.Request) error {
+	s.Currency = strings.ToUpper(strings.TrimSpace(s.Currency))
+	if len(s.Currency) != 3 {
+		return errors.New("currency: must be a 3-letter code")
+	}
+
+	if s.PriceList == "" {
+		return errors.New("price_list: cannot be blank")
+	}
+
+	if s.InvoiceID == "" {
+		return errors.New("invoice_id: cannot be blank")
+	}
+
+	if s.Name == "" {
+		return errors.New("name: cannot be blank")
+	}
+
+	if err := nonNegative(s.Discount); err != nil {
+		return errors.New("discount: " + err.Error())
+	}
+
+	if len(s.Items) == 0 {
+		return ErrNoItems
+	}
+
+	for i, item := range s.Items {
+		if item.ProductID == "" {
+			return fmt.Errorf("items[%d].product_id: cannot be blank", i)
+		}
+
+		if quantity, err := decimal.NewFromString(item.Quantity); err != nil || !quantity.IsPositive() {
+			return fmt.Errorf("items[%d].quantity: must be a positive number", i)
+		}
+
+		if err := nonNegative(item.Discount); err != nil {
+			return fmt.Errorf("items[%d].discount: %s", i, err)
+		}
+	}
+
+	return nil
+}
+
+// nonNegative accepts an empty value as zero.
+func nonNegative(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if amount, err := decimal.NewFromString(value); err != nil || amount.IsNegative() {
+		return errors.New("must be a non-negative number")
+	}
+
+	return nil
+}
+
+type ItemResponse struct {
+	Line      int    `json:"line"`
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	Quantity  string `json:"quantity"`
+	UnitPrice string `json:"unit_price"`
+	Subtotal  string `json:"subtotal"`
+	Discount  string `json:"discount"`
+	TaxRate   string `json:"tax_rate"`
+	Tax       string `json:"tax"`
+	Total     string `json:"total"`
+}
+
+type Response struct {
+	ID          string         `json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Status      string         `json:"status"`
+	Currency    string         `json:"currency"`
+	PriceListID string         `json:"price_list_id"`
+	Subtotal    string         `json:"subtotal"`
+	Discount    string         `json:"discount"`
+	Tax         string         `json:"tax"`
+	Total       string         `json:"total"`
+	BillingID   string         `json:"billing_id,omitempty"`
+	PaymentLink string         `json:"payment_link,omitempty"`
+	Items       []ItemResponse `json:"items,omitempty"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		ID:          data.ID,
+		CreatedAt:   data.CreatedAt,
+		Status:      data.Status,
+		Currency:    data.Currency,
+		PriceListID: data.PriceListID,
+		Subtotal:    data.Subtotal.StringFixed(2),
+		Discount:    data.Discount.StringFixed(2),
+		Tax:         data.Tax.StringFixed(2),
+		Total:       data.Total.StringFixed(2),
+		BillingID:   data.BillingID,
+	}
+
+	for _, item := range data.Items {
+		res.Items = append(res.Items, ItemResponse{
+			Line:      item.Line,
+			ProductID: item.ProductID,
+			Name:      item.Name,
+			Quantity:  item.Quantity.String(),
+			UnitPrice: item.UnitPrice.StringFixed(2),
+			Subtotal:  item.Subtotal.StringFixed(2),
+			Discount:  item.Discount.StringFixed(2),
+			TaxRate:   item.TaxRate.String(),
+			Tax:       item.Tax.StringFixed(2),
+			Total:     item.Total.StringFixed(2),
+		})
+	}
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
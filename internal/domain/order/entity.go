@@ -0,0 +1,133 @@
+package order
+
+import (
+	"errors"
+	"payment-service/internal/domain/billing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// An order follows the status of its billing.
+const (
+	StatusCreated           = "created"
+	StatusPaid              = "paid"
+	StatusFailed            = "failed"
+	StatusRefunded          = "refunded"
+	StatusPartiallyRefunded = "partially_refunded"
+)
+
+var (
+	ErrNoItems           = errors.New("order: at least one item is required")
+	ErrInvalidDiscount   = errors.New("order: discount exceeds the amount it applies to")
+	ErrProductNotFound   = errors.New("order: product is not in the catalogue")
+	ErrPriceListNotFound = errors.New("order: price list does not exist")
+	ErrProductNotPriced  = errors.New("order: product has no price in the list and currency")
+)
+
+type Entity struct {
+	CreatedAt   time.Time       `db:"created_at"`
+	UpdatedAt   time.Time       `db:"updated_at"`
+	ID          string          `db:"id"`
+	TenantID    string          `db:"tenant_id"`
+	Status      string          `db:"status"`
+	Currency    string          `db:"currency"`
+	PriceListID string          `db:"price_list_id"`
+	Subtotal    decimal.Decimal `db:"subtotal"`
+	Discount    decimal.Decimal `db:"discount"`
+	Tax         decimal.Decimal `db:"tax"`
+	Total       decimal.Decimal `db:"total"`
+	BillingID   string          `db:"billing_id"`
+	Items       []Item          `db:"-"`
+}
+
+// Item is a line of the order, Subtotal is UnitPrice times Quantity and Total is what the line adds to the order.
+type Item struct {
+	OrderID   string          `db:"order_id"`
+	Line      int             `db:"line"`
+	ProductID string          `db:"product_id"`
+	Name      string          `db:"name"`
+	Quantity  decimal.Decimal `db:"quantity"`
+	UnitPrice decimal.Decimal `db:"unit_price"`
+	Subtotal  decimal.Decimal `db:"subtotal"`
+	Discount  decimal.Decimal `db:"discount"`
+	TaxRate   decimal.Decimal `db:"tax_rate"`
+	Tax       decimal.Decimal `db:"tax"`
+	Total     decimal.Decimal `db:"total"`
+}
+
+// Tax describes how tax is charged on item prices.
+type Tax struct {
+	// Rate is the share of the taxable amount, e.g. 0.12 for 12%
+	Rate decimal.Decimal
+	// Included tells prices already contain the tax, otherwise it is added on top
+	Included bool
+}
+
+// Calculate fills in the amounts of the items and the order. The order discount is spread across the items
+// in proportion to what is left of them after their own discounts, the last item takes the rounding remainder.
+func (e *Entity) Calculate(discount decimal.Decimal, tax Tax) (err error) {
+	if len(e.Items) == 0 {
+		return ErrNoItems
+	}
+
+	net := decimal.Zero
+	for i := range e.Items {
+		item := &e.Items[i]
+		item.Line = i + 1
+		item.Subtotal = item.UnitPrice.Mul(item.Quantity).Round(2)
+
+		if item.Discount.GreaterThan(item.Subtotal) {
+			return ErrInvalidDiscount
+		}
+		net = net.Add(item.Subtotal.Sub(item.Discount))
+	}
+
+	if discount.GreaterThan(net) {
+		return ErrInvalidDiscount
+	}
+
+	e.Subtotal, e.Discount, e.Tax, e.Total = decimal.Zero, decimal.Zero, decimal.Zero, decimal.Zero
+	spread := decimal.Zero
+	for i := range e.Items {
+		item := &e.Items[i]
+
+		share := discount.Sub(spread)
+		if i < len(e.Items)-1 && net.IsPositive() {
+			share = discount.Mul(item.Subtotal.Sub(item.Discount)).Div(net).Round(2)
+		}
+		spread = spread.Add(share)
+		item.Discount = item.Discount.Add(share)
+
+		taxable := item.Subtotal.Sub(item.Discount)
+		item.TaxRate = tax.Rate
+		if tax.Included {
+			item.Tax = taxable.Sub(taxable.Div(tax.Rate.Add(decimal.NewFromInt(1)))).Round(2)
+			item.Total = taxable
+		} else {
+			item.Tax = taxable.Mul(tax.Rate).Round(2)
+			item.Total = taxable.Add(item.Tax)
+		}
+
+		e.Subtotal = e.Subtotal.Add(item.Subtotal)
+		e.Discount = e.Discount.Add(item.Discount)
+		e.Tax = e.Tax.Add(item.Tax)
+		e.Total = e.Total.Add(item.Total)
+	}
+
+	return
+}
+
+// StatusOf maps the status of a billing onto the status of its order.
+func StatusOf(billingStatus string) (status string, ok bool) {
+	statuses := map[string]string{
+		billing.StatusCreated:           StatusCreated,
+		billing.StatusPaid:              StatusPaid,
+		billing.StatusFailed:            StatusFailed,
+		billing.StatusRefunded:          StatusRefunded,
+		billing.StatusPartiallyRefunded: StatusPartiallyRefunded,
+	}
+	status, ok = statuses[billingStatus]
+
+	return
+}
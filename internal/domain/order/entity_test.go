@@ -0,0 +1,120 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCalculate(t *testing.T) {
+	d := decimal.RequireFromString
+	item := func(quantity, unitPrice, discount string) Item {
+		return Item{Quantity: d(quantity), UnitPrice: d(unitPrice), Discount: d(discount)}
+	}
+	vat := Tax{Rate: d("0.12")}
+
+	tests := []struct {
+		name     string
+		items    []Item
+		discount string
+		tax      Tax
+		// discounts, taxes and totals of the items, then the subtotal, discount, tax and total of the order
+		discounts, taxes, totals []string
+		order                    [4]string
+		err                      error
+	}{
+		{
+			name:      "tax added",
+			items:     []Item{item("2", "10", "0"), item("1", "5.55", "0")},
+			discount:  "3",
+			tax:       vat,
+			discounts: []string{"2.35", "0.65"},
+			taxes:     []string{"2.12", "0.59"},
+			totals:    []string{"19.77", "5.49"},
+			order:     [4]string{"25.55", "3", "2.71", "25.26"},
+		},
+		{
+			name:      "tax included",
+			items:     []Item{item("2", "10", "0"), item("1", "5.55", "0")},
+			discount:  "3",
+			tax:       Tax{Rate: d("0.12"), Included: true},
+			discounts: []string{"2.35", "0.65"},
+			taxes:     []string{"1.89", "0.53"},
+			totals:    []string{"17.65", "4.9"},
+			order:     [4]string{"25.55", "3", "2.42", "22.55"},
+		},
+		{
+			name:      "rounding remainder",
+			items:     []Item{item("1", "1", "0"), item("1", "1", "0"), item("1", "1", "0")},
+			discount:  "1",
+			discounts: []string{"0.33", "0.33", "0.34"},
+			taxes:     []string{"0", "0", "0"},
+			totals:    []string{"0.67", "0.67", "0.66"},
+			order:     [4]string{"3", "1", "0", "2"},
+		},
+		{
+			name:      "item discounts",
+			items:     []Item{item("1", "10", "4"), item("1", "4", "0")},
+			discount:  "5",
+			discounts: []string{"7", "2"},
+			taxes:     []string{"0", "0"},
+			totals:    []string{"3", "2"},
+			order:     [4]string{"14", "9", "0", "5"},
+		},
+		{
+			name:      "fractional quantity",
+			items:     []Item{item("0.333", "3", "0")},
+			discount:  "0",
+			tax:       vat,
+			discounts: []string{"0"},
+			taxes:     []string{"0.12"},
+			totals:    []string{"1.12"},
+			order:     [4]string{"1", "0", "0.12", "1.12"},
+		},
+		{
+			name:     "no items",
+			discount: "0",
+			err:      ErrNoItems,
+		},
+		{
+			name:     "item discount over its subtotal",
+			items:    []Item{item("1", "10", "10.01")},
+			discount: "0",
+			err:      ErrInvalidDiscount,
+		},
+		{
+			name:     "order discount over the items",
+			items:    []Item{item("1", "10", "4"), item("1", "4", "0")},
+			discount: "10.01",
+			err:      ErrInvalidDiscount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := Entity{Items: tt.items}
+			if err := data.Calculate(d(tt.discount), tt.tax); err != tt.err {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+
+			for i, item := range data.Items {
+				if item.Line != i+1 || !item.Discount.Equal(d(tt.discounts[i])) || !item.Tax.Equal(d(tt.taxes[i])) ||
+					!item.Total.Equal(d(tt.totals[i])) {
+					t.Errorf("got line %d with %s off, %s tax, %s total, want %s off, %s tax, %s total", item.Line,
+						item.Discount, item.Tax, item.Total, tt.discounts[i], tt.taxes[i], tt.totals[i])
+				}
+			}
+
+			got := [4]decimal.Decimal{data.Subtotal, data.Discount, data.Tax, data.Total}
+			for i, amount := range got {
+				if !amount.Equal(d(tt.order[i])) {
+					t.Errorf("got %v, want %v", got, tt.order)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package order
+
+import "context"
+
+type Repository interface {
+	Select(ctx context.Context) (dest []Entity, err error)
+	// Create stores the order with its items.
+	Create(ctx context.Context, data Entity) (id string, err error)
+	// Get returns the order with its items.
+	Get(ctx context.Context, id string) (dest Entity, err error)
+	GetByBillingID(ctx context.Context, billingID string) (dest Entity, err error)
+	// Update changes the status and the billing of the order.
+	Update(ctx context.Context, id string, data Entity) (err error)
+}
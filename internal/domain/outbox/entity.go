@@ -0,0 +1,35 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Entity struct {
+	CreatedAt   time.Time       `db:"created_at"`
+	PublishedAt *time.Time      `db:"published_at"`
+	ID          string          `db:"id"`
+	TenantID    string          `db:"tenant_id"`
+	Aggregate   string          `db:"aggregate"`
+	AggregateID string          `db:"aggregate_id"`
+	EventType   string          `db:"event_type"`
+	DedupKey    string          `db:"dedup_key"`
+	Payload     json.RawMessage `db:"payload"`
+	Attempts    int             `db:"attempts"`
+	LastError   *string         `db:"last_error"`
+}
+
+// New builds an event for the aggregate. The dedup key is derived from the event type and
+// the aggregate id, so the same transition never produces two events.
+func New(aggregate, aggregateID, eventType string, payload any) (data Entity, err error) {
+	data = Entity{
+		CreatedAt:   time.Now(),
+		Aggregate:   aggregate,
+		AggregateID: aggregateID,
+		EventType:   eventType,
+		DedupKey:    eventType + ":" + aggregateID,
+	}
+	data.Payload, err = json.Marshal(payload)
+
+	return
+}
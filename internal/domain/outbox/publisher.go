@@ -0,0 +1,9 @@
+package outbox
+
+import "context"
+
+// Publisher delivers an event to an external consumer. Delivery is at-least-once,
+// consumers are expected to deduplicate by Entity.DedupKey.
+type Publisher interface {
+	Publish(ctx context.Context, event Entity) (err error)
+}
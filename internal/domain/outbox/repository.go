@@ -0,0 +1,16 @@
+package outbox
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Create stores the event unless an event with the same dedup key already exists,
+	// in which case it stores nothing and returns the ID of the existing event.
+	Create(ctx context.Context, data Entity) (id string, err error)
+	// Claim leases a batch of unpublished events, so other relays skip them until the lease expires.
+	Claim(ctx context.Context, limit int, lease time.Duration) (dest []Entity, err error)
+	MarkPublished(ctx context.Context, id string) (err error)
+	MarkFailed(ctx context.Context, id string, reason string) (err error)
+}
@@ -0,0 +1,178 @@
+package price
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type ListRequest struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	MerchantID string `json:"merchant_id"`
+}
+
+func (s *ListRequest) Bind(r *http.Request) error {
+	if s.Code == "" {
+		return errors.New("code: cannot be blank")
+	}
+
+	if s.Name == "" {
+		return errors.New("name: cannot be blank")
+	}
+
+	switch s.Kind {
+	case KindRetail, KindWholesale:
+		if s.MerchantID != "" {
+			return errors.New("merchant_id: is allowed for merchant lists only")
+		}
+	case KindMerchant:
+		if s.MerchantID == "" {
+			return errors.New("merchant_id: cannot be blank for a merchant list")
+		}
+	default:
+		return errors.New("kind: must be " + KindRetail + ", " + KindWholesale + " or " + KindMerchant)
+	}
+
+	return nil
+}
+
+// Request sets the price of a product in a list from ValidFrom, now when it is not given.
+type Request struct {
+	List      string     `json:"list"`
+	Currency  string     `json:"currency"`
+	Amount    string     `json:"amount"`
+	ValidFrom *time.Time `json:"valid_from"`
+	ValidTo   *time.Time `json:"valid_to"`
+}
+
+func (s *Request) Bind(r *http.Request) error {
+	if s.List == "" {
+		return errors.New("list: cannot be blank")
+	}
+
+	s.Currency = strings.ToUpper(strings.TrimSpace(s.Currency))
+	if len(s.Currency) != 3 {
+		return errors.New("currency: must be a 3-letter code")
+	}
+
+	if amount, err := decimal.NewFromString(s.Amount); err != nil || amount.IsNegative() {
+		return errors.New("amount: must be a non-negative number")
+	}
+
+	if s.ValidFrom != nil && s.ValidTo != nil && !s.ValidTo.After(*s.ValidFrom) {
+		return errors.New("valid_to: must be after valid_from")
+	}
+
+	return nil
+}
+
+// EffectiveRequest is read from the query string: list is an id or code, date is YYYY-MM-DD or RFC 3339 and defaults to now.
+type EffectiveRequest struct {
+	List     string
+	Currency string
+	At       time.Time
+}
+
+func (s *EffectiveRequest) Bind(r *http.Request) (err error) {
+	query := r.URL.Query()
+
+	if s.List = query.Get("list"); s.List == "" {
+		return errors.New("list: cannot be blank")
+	}
+
+	if s.Currency = strings.ToUpper(query.Get("currency")); s.Currency == "" {
+		return errors.New("currency: cannot be blank")
+	}
+
+	s.At = time.Now()
+	if value := query.Get("date"); value != "" {
+		if s.At, err = time.Parse("2006-01-02", value); err == nil {
+			// a date asks for the price at the end of that day
+			s.At = s.At.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		} else if s.At, err = time.Parse(time.RFC3339, value); err != nil {
+			return errors.New("date: must be YYYY-MM-DD or RFC 3339")
+		}
+	}
+
+	return nil
+}
+
+// HistoryRequest is read from the query string, list and currency narrow the history down.
+type HistoryRequest struct {
+	List     string
+	Currency string
+}
+
+func (s *HistoryRequest) Bind(r *http.Request) error {
+	query := r.URL.Query()
+
+	s.List = query.Get("list")
+	s.Currency = strings.ToUpper(query.Get("currency"))
+
+	return nil
+}
+
+type ListResponse struct {
+	ID         string `json:"id"`
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	MerchantID string `json:"merchant_id,omitempty"`
+}
+
+func ParseFromList(data List) ListResponse {
+	return ListResponse{
+		ID:         data.ID,
+		Code:       data.Code,
+		Name:       data.Name,
+		Kind:       data.Kind,
+		MerchantID: data.MerchantID,
+	}
+}
+
+func ParseFromLists(data []List) (res []ListResponse) {
+	res = make([]ListResponse, 0)
+	for _, object := range data {
+		res = append(res, ParseFromList(object))
+	}
+	return
+}
+
+type Response struct {
+	ID        string     `json:"id"`
+	ProductID string     `json:"product_id"`
+	ListID    string     `json:"list_id"`
+	Currency  string     `json:"currency"`
+	Amount    string     `json:"amount"`
+	ValidFrom time.Time  `json:"valid_from"`
+	ValidTo   *time.Time `json:"valid_to,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		ID:        data.ID,
+		ProductID: data.ProductID,
+		ListID:    data.ListID,
+		Currency:  data.Currency,
+		Amount:    data.Amount.String(),
+		ValidFrom: data.ValidFrom,
+		ValidTo:   data.ValidTo,
+		CreatedAt: data.CreatedAt,
+	}
+
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
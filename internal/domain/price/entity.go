@@ -0,0 +1,56 @@
+package price
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	KindRetail    = "retail"
+	KindWholesale = "wholesale"
+	KindMerchant  = "merchant"
+)
+
+var ErrPriceNotFound = errors.New("price: no price of the product is effective on the date")
+
+// List groups the prices a kind of customer pays, a merchant list belongs to one merchant.
+type List struct {
+	CreatedAt  time.Time `db:"created_at"`
+	ID         string    `db:"id"`
+	TenantID   string    `db:"tenant_id"`
+	Code       string    `db:"code"`
+	Name       string    `db:"name"`
+	Kind       string    `db:"kind"`
+	MerchantID string    `db:"merchant_id"`
+}
+
+// Entity is the price of a product in a list and currency from ValidFrom until ValidTo, an open-ended one
+// when ValidTo is nil. Prices are never changed, a new one supersedes the previous from its ValidFrom,
+// so the rows of a product make up its price history.
+type Entity struct {
+	CreatedAt time.Time       `db:"created_at"`
+	ID        string          `db:"id"`
+	ProductID string          `db:"product_id"`
+	ListID    string          `db:"list_id"`
+	Currency  string          `db:"currency"`
+	Amount    decimal.Decimal `db:"amount"`
+	ValidFrom time.Time       `db:"valid_from"`
+	ValidTo   *time.Time      `db:"valid_to"`
+}
+
+// EffectiveAt reports whether the price window covers the time.
+func (e Entity) EffectiveAt(at time.Time) bool {
+	return !e.ValidFrom.After(at) && (e.ValidTo == nil || at.Before(*e.ValidTo))
+}
+
+// Supersedes reports whether the price wins over the other one where both are effective:
+// the one that started later, or of two that started together the one recorded later.
+func (e Entity) Supersedes(other Entity) bool {
+	if !e.ValidFrom.Equal(other.ValidFrom) {
+		return e.ValidFrom.After(other.ValidFrom)
+	}
+
+	return e.CreatedAt.After(other.CreatedAt)
+}
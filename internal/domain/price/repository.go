@@ -0,0 +1,26 @@
+package price
+
+import (
+	"context"
+	"time"
+)
+
+// Filter narrows the price history down, empty fields are not applied.
+type Filter struct {
+	ProductID string
+	ListID    string
+	Currency  string
+}
+
+type Repository interface {
+	SelectLists(ctx context.Context) (dest []List, err error)
+	CreateList(ctx context.Context, data List) (id string, err error)
+	// GetList finds the list by its id or code.
+	GetList(ctx context.Context, idOrCode string) (dest List, err error)
+
+	// Select returns the price history of the filter, the latest ValidFrom first.
+	Select(ctx context.Context, filter Filter) (dest []Entity, err error)
+	Create(ctx context.Context, data Entity) (id string, err error)
+	// GetEffective returns the price of the product in the list and currency effective at the time or ErrPriceNotFound.
+	GetEffective(ctx context.Context, productID, listID, currency string, at time.Time) (dest Entity, err error)
+}
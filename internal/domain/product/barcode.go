@@ -0,0 +1,45 @@
+package product
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidBarcode = errors.New("barcode: must be an EAN-8, EAN-13 or UPC-A code with a valid check digit")
+
+// NormalizeBarcode validates the check digit of an EAN-8, EAN-13 or UPC-A code. A UPC-A code is returned
+// as the EAN-13 code with a leading zero, the same item scanned either way then has one barcode.
+func NormalizeBarcode(code string) (string, error) {
+	code = strings.TrimSpace(code)
+
+	switch len(code) {
+	case 8, 13:
+	case 12:
+		code = "0" + code
+	default:
+		return "", ErrInvalidBarcode
+	}
+
+	// weights alternate 3 and 1 from the digit next to the check digit, for both lengths
+	sum := 0
+	for i, r := range code {
+		if r < '0' || r > '9' {
+			return "", ErrInvalidBarcode
+		}
+
+		digit := int(r - '0')
+		if i == len(code)-1 {
+			if (10-sum%10)%10 != digit {
+				return "", ErrInvalidBarcode
+			}
+			break
+		}
+
+		if (len(code)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	return code, nil
+}
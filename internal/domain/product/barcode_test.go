@@ -0,0 +1,37 @@
+package product
+
+import "testing"
+
+func TestNormalizeBarcode(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+		err  error
+	}{
+		{name: "ean-13", code: "4006381333931", want: "4006381333931"},
+		{name: "ean-13 check digit zero", code: "4870001234560", want: "4870001234560"},
+		{name: "ean-8", code: "96385074", want: "96385074"},
+		{name: "upc-a", code: "036000291452", want: "0036000291452"},
+		{name: "surrounding spaces", code: " 96385074\n", want: "96385074"},
+		{name: "ean-13 wrong check digit", code: "4006381333932", err: ErrInvalidBarcode},
+		{name: "ean-8 wrong check digit", code: "96385075", err: ErrInvalidBarcode},
+		{name: "upc-a wrong check digit", code: "036000291453", err: ErrInvalidBarcode},
+		{name: "letters", code: "40063813339a1", err: ErrInvalidBarcode},
+		{name: "inner space", code: "9638 5074", err: ErrInvalidBarcode},
+		{name: "sign", code: "-6385074", err: ErrInvalidBarcode},
+		{name: "empty", code: "", err: ErrInvalidBarcode},
+		{name: "too short", code: "9638507", err: ErrInvalidBarcode},
+		{name: "between lengths", code: "40063813339", err: ErrInvalidBarcode},
+		{name: "too long", code: "40063813339310", err: ErrInvalidBarcode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeBarcode(tt.code)
+			if got != tt.want || err != tt.err {
+				t.Errorf("got %q, err = %v, want %q, %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
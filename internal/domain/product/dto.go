@@ -1,12 +1,28 @@
 package product
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	// attributeParam prefixes the codes of attribute filters in the query string, e.g. attr.size=42
+	attributeParam = "attr."
+)
+
+var ErrInvalidCursor = errors.New("cursor: is malformed or belongs to another sort")
+
 type Request struct {
 	ID          string `json:"id"`
+	ParentID    string `json:"parent_id"`
 	CategoryID  string `json:"category_id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
@@ -15,10 +31,18 @@ type Request struct {
 	Country     string `json:"country"`
 	Barcode     string `json:"barcode"`
 	Brand       string `json:"brand"`
+	// Attributes holds the attribute values by code, a variant takes the values of its parent it doesn't set
+	Attributes map[string]any `json:"attributes"`
 }
 
 func (s *Request) Bind(r *http.Request) error {
-	if s.CategoryID == "" {
+	return s.Validate()
+}
+
+// Validate checks the product and normalizes its barcode, imported rows are checked with it too.
+func (s *Request) Validate() error {
+	// a variant is put into the category of its parent
+	if s.CategoryID == "" && s.ParentID == "" {
 		return errors.New("CategoryID: cannot be blank")
 	}
 
@@ -26,6 +50,12 @@ func (s *Request) Bind(r *http.Request) error {
 		return errors.New("Barcode: cannot be blank")
 	}
 
+	barcode, err := NormalizeBarcode(s.Barcode)
+	if err != nil {
+		return err
+	}
+	s.Barcode = barcode
+
 	if s.Name == "" {
 		return errors.New("Name: cannot be blank")
 	}
@@ -37,8 +67,174 @@ func (s *Request) Bind(r *http.Request) error {
 	return nil
 }
 
+// NewRequest returns the product as a request, a merge patch is applied to it.
+func NewRequest(data Entity) (req Request) {
+	req = Request{
+		ID:          data.ID,
+		ParentID:    data.ParentID,
+		CategoryID:  data.CategoryID,
+		Name:        data.Name,
+		Description: *data.Description,
+		Measure:     *data.Measure,
+		ImageURL:    *data.ImageURL,
+		Country:     *data.Country,
+		Barcode:     *data.Barcode,
+		Brand:       *data.Brand,
+	}
+
+	if len(data.Attributes) > 0 {
+		json.Unmarshal(data.Attributes, &req.Attributes)
+	}
+	return
+}
+
+// SearchRequest is read from the query string, see Filter for the meaning of the fields.
+type SearchRequest struct {
+	Filter
+}
+
+func (s *SearchRequest) Bind(r *http.Request) (err error) {
+	query := r.URL.Query()
+
+	s.Query = query.Get("q")
+	s.CategoryID = query.Get("category_id")
+	s.Country = query.Get("country")
+	s.Brand = query.Get("brand")
+	s.ParentID = query.Get("parent_id")
+
+	if value := query.Get("deleted"); value != "" {
+		if s.Deleted, err = strconv.ParseBool(value); err != nil {
+			return errors.New("deleted: must be true or false")
+		}
+	}
+
+	for key, values := range query {
+		code := strings.TrimPrefix(key, attributeParam)
+		if code == key || code == "" {
+			continue
+		}
+
+		if s.Attributes == nil {
+			s.Attributes = make(map[string][]any)
+		}
+		// a value that reads as a number matches number attributes too
+		for _, value := range strings.Split(strings.Join(values, ","), ",") {
+			s.Attributes[code] = append(s.Attributes[code], value)
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				s.Attributes[code] = append(s.Attributes[code], number)
+			}
+		}
+	}
+
+	s.Sort = query.Get("sort")
+	switch s.Sort {
+	case "":
+		s.Sort = SortCreatedAt
+		if s.Query != "" {
+			s.Sort = SortRelevance
+		}
+	case SortRelevance, SortName, SortNameDesc, SortCreatedAt, SortCreatedAtDesc:
+	default:
+		return errors.New("sort: must be one of relevance, name, -name, created_at, -created_at")
+	}
+
+	s.Limit = defaultSearchLimit
+	if value := query.Get("limit"); value != "" {
+		if s.Limit, err = strconv.Atoi(value); err != nil || s.Limit < 1 || s.Limit > maxSearchLimit {
+			return errors.New("limit: must be a number from 1 to 100")
+		}
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		if s.After, err = DecodeCursor(value); err != nil || s.After.Sort != s.Sort {
+			return ErrInvalidCursor
+		}
+	}
+
+	return nil
+}
+
+// EncodeCursor makes the cursor opaque to clients.
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(value string) (cursor *Cursor, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return
+	}
+
+	cursor = &Cursor{}
+	if err = json.Unmarshal(data, cursor); err != nil {
+		return
+	}
+
+	if cursor.ID == "" {
+		err = ErrInvalidCursor
+	}
+
+	return
+}
+
+type SearchResponse struct {
+	Items      []Response `json:"items"`
+	Total      int        `json:"total"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+func ParseFromPage(data Page) (res SearchResponse) {
+	res = SearchResponse{
+		Items: ParseFromEntities(data.Items),
+		Total: data.Total,
+	}
+
+	if data.Next != nil {
+		res.NextCursor = EncodeCursor(*data.Next)
+	}
+	return
+}
+
+type JobResponse struct {
+	ID        string     `json:"id"`
+	Format    string     `json:"format"`
+	Status    string     `json:"status"`
+	Total     int        `json:"total"`
+	Created   int        `json:"created"`
+	Updated   int        `json:"updated"`
+	Failed    int        `json:"failed"`
+	Message   string     `json:"message,omitempty"`
+	Errors    []RowError `json:"errors"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func ParseFromJob(data Job) JobResponse {
+	res := JobResponse{
+		ID:        data.ID,
+		Format:    data.Format,
+		Status:    data.Status,
+		Total:     data.Total,
+		Created:   data.Created,
+		Updated:   data.Updated,
+		Failed:    data.Failed,
+		Message:   data.Message,
+		Errors:    data.Errors,
+		CreatedAt: data.CreatedAt,
+		UpdatedAt: data.UpdatedAt,
+	}
+
+	if res.Errors == nil {
+		res.Errors = make([]RowError, 0)
+	}
+	return res
+}
+
 type Response struct {
 	ID          string `json:"id"`
+	Version     int    `json:"version,omitempty"`
+	ParentID    string `json:"parent_id,omitempty"`
 	CategoryID  string `json:"category_id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
@@ -47,11 +243,16 @@ type Response struct {
 	Country     string `json:"country"`
 	Barcode     string `json:"barcode"`
 	Brand       string `json:"brand"`
+
+	Attributes json.RawMessage `json:"attributes" swaggertype:"object"`
+	DeletedAt  *time.Time      `json:"deleted_at,omitempty"`
 }
 
 func ParseFromEntity(data Entity) (res Response) {
 	res = Response{
 		ID:          data.ID,
+		Version:     data.Version,
+		ParentID:    data.ParentID,
 		CategoryID:  data.CategoryID,
 		Name:        data.Name,
 		Description: *data.Description,
@@ -60,6 +261,12 @@ func ParseFromEntity(data Entity) (res Response) {
 		Country:     *data.Country,
 		Barcode:     *data.Barcode,
 		Brand:       *data.Brand,
+		Attributes:  data.Attributes,
+		DeletedAt:   data.DeletedAt,
+	}
+
+	if len(res.Attributes) == 0 {
+		res.Attributes = json.RawMessage("{}")
 	}
 	return
 }
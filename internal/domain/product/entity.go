@@ -1,17 +1,41 @@
 package product
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
 
+var (
+	ErrCategoryNotFound = errors.New("category_id: category not found")
+	ErrParentNotFound   = errors.New("parent_id: product not found")
+	ErrNestedVariant    = errors.New("parent_id: a variant cannot have variants")
+	ErrVariantCategory  = errors.New("category_id: must be the category of the parent product")
+	ErrReadOnly         = errors.New("id, parent_id: cannot be changed")
+	ErrHasVariants      = errors.New("category_id: cannot be changed while the product has variants")
+	ErrReferenced       = errors.New("product: is referred to by orders and cannot be purged")
+)
+
+// Entity is a product or, when ParentID is set, a variant of one, e.g. a size or colour with its own barcode and prices.
+// Attributes is a JSON object of the attribute values checked against the schema of the category.
+// Version counts the writes of the product, see Repository.Update. DeletedAt is set while the product is in the trash,
+// TrashedWith names the category or product whose deletion took it along.
 type Entity struct {
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
-	ID          string    `db:"id"`
-	CategoryID  string    `db:"category_id"`
-	Name        string    `db:"name"`
-	Description *string   `db:"description"`
-	Measure     *string   `db:"measure"`
-	ImageURL    *string   `db:"image_url"`
-	Country     *string   `db:"country"`
-	Barcode     *string   `db:"barcode"`
-	Brand       *string   `db:"brand"`
+	CreatedAt   time.Time       `db:"created_at"`
+	UpdatedAt   time.Time       `db:"updated_at"`
+	ID          string          `db:"id"`
+	TenantID    string          `db:"tenant_id"`
+	Version     int             `db:"version"`
+	ParentID    string          `db:"parent_id"`
+	CategoryID  string          `db:"category_id"`
+	Name        string          `db:"name"`
+	Description *string         `db:"description"`
+	Measure     *string         `db:"measure"`
+	ImageURL    *string         `db:"image_url"`
+	Country     *string         `db:"country"`
+	Barcode     *string         `db:"barcode"`
+	Brand       *string         `db:"brand"`
+	Attributes  json.RawMessage `db:"attributes"`
+	DeletedAt   *time.Time      `db:"deleted_at"`
+	TrashedWith string          `db:"trashed_with"`
 }
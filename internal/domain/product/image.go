@@ -0,0 +1,106 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("image: must be a JPEG, PNG or GIF picture")
+	ErrImageTooLarge    = errors.New("image: file or picture dimensions are too large")
+	ErrImageOrder       = errors.New("ids: must list every image of the product once")
+)
+
+// Image is a picture of the product kept in the blob storage, images are shown in the order of their positions.
+type Image struct {
+	CreatedAt    time.Time `db:"created_at"`
+	ID           string    `db:"id"`
+	TenantID     string    `db:"tenant_id"`
+	ProductID    string    `db:"product_id"`
+	Position     int       `db:"position"`
+	ContentType  string    `db:"content_type"`
+	Size         int64     `db:"size"`
+	Width        int       `db:"width"`
+	Height       int       `db:"height"`
+	Key          string    `db:"key"`
+	URL          string    `db:"url"`
+	ThumbnailKey string    `db:"thumbnail_key"`
+	ThumbnailURL string    `db:"thumbnail_url"`
+}
+
+type ImageRepository interface {
+	// SelectImages returns the images of the product ordered by position.
+	SelectImages(ctx context.Context, productID string) (dest []Image, err error)
+	// CreateImage puts the image after the last image of the product.
+	CreateImage(ctx context.Context, data Image) (id string, err error)
+	GetImage(ctx context.Context, id string) (dest Image, err error)
+	// ReorderImages sets the position of every image to its index in ids.
+	ReorderImages(ctx context.Context, productID string, ids []string) (err error)
+	DeleteImage(ctx context.Context, id string) (err error)
+}
+
+// Storage keeps the image files, Put returns the URL the file is served from.
+type Storage interface {
+	Put(ctx context.Context, key, contentType string, data []byte) (url string, err error)
+	Delete(ctx context.Context, key string) (err error)
+}
+
+// Upload is an image file as it was received.
+type Upload struct {
+	Name string
+	Data []byte
+}
+
+// OrderRequest lists the ids of all images of the product in the new order.
+type OrderRequest struct {
+	IDs []string `json:"ids"`
+}
+
+func (s *OrderRequest) Bind(r *http.Request) error {
+	if len(s.IDs) == 0 {
+		return errors.New("ids: cannot be blank")
+	}
+
+	return nil
+}
+
+type ImageResponse struct {
+	ID           string    `json:"id"`
+	ProductID    string    `json:"product_id"`
+	Position     int       `json:"position"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func ParseFromImage(data Image) (res ImageResponse) {
+	res = ImageResponse{
+		ID:           data.ID,
+		ProductID:    data.ProductID,
+		Position:     data.Position,
+		ContentType:  data.ContentType,
+		Size:         data.Size,
+		Width:        data.Width,
+		Height:       data.Height,
+		URL:          data.URL,
+		ThumbnailURL: data.ThumbnailURL,
+		CreatedAt:    data.CreatedAt,
+	}
+
+	return
+}
+
+func ParseFromImages(data []Image) (res []ImageResponse) {
+	res = make([]ImageResponse, 0, len(data))
+	for _, object := range data {
+		res = append(res, ParseFromImage(object))
+	}
+
+	return
+}
@@ -0,0 +1,40 @@
+package product
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+)
+
+// Job is a bulk import of products, rows are upserted by barcode in batches after the file was validated.
+type Job struct {
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	ID        string    `db:"id"`
+	TenantID  string    `db:"tenant_id"`
+	Format    string    `db:"format"`
+	Status    string    `db:"status"`
+	// Total counts the rows of the file, Failed the rows that were rejected
+	Total   int `db:"total"`
+	Created int `db:"created"`
+	Updated int `db:"updated"`
+	Failed  int `db:"failed"`
+	// Message tells why a failed job stopped
+	Message string     `db:"message"`
+	Errors  []RowError `db:"-"`
+}
+
+type JobRepository interface {
+	CreateJob(ctx context.Context, data Job) (id string, err error)
+	// GetJob returns the job with its row errors ordered by line.
+	GetJob(ctx context.Context, id string) (dest Job, err error)
+	// UpdateJob saves the status, counters and message of the job.
+	UpdateJob(ctx context.Context, id string, data Job) (err error)
+	AddJobErrors(ctx context.Context, id string, data []RowError) (err error)
+}
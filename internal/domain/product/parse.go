@@ -0,0 +1,133 @@
+package product
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+var ErrUnknownFormat = errors.New("format: must be csv or ndjson")
+
+var ErrNoHeader = errors.New("product: file has no header with category_id, name, measure and barcode columns")
+
+// columns lists the import columns in the order they are exported.
+var columns = []string{"category_id", "name", "description", "measure", "image_url", "country", "barcode", "brand"}
+
+// RowError describes a line of an import that can't be saved.
+type RowError struct {
+	JobID   string `json:"-" db:"job_id"`
+	Line    int    `json:"line" db:"line"`
+	Message string `json:"message" db:"message"`
+}
+
+// Row is a valid product of an import file, lines are counted from one as they appear in the file.
+type Row struct {
+	Line int
+	Request
+}
+
+// ParseCSV reads products from spreadsheet records, the first record has to be the header.
+// Invalid rows and rows repeating the barcode of an earlier row are returned as row errors.
+func ParseCSV(records [][]string) (rows []Row, errs []RowError, err error) {
+	if len(records) == 0 {
+		return nil, nil, ErrNoHeader
+	}
+
+	index := make(map[string]int)
+	for i, name := range records[0] {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range []string{"category_id", "name", "measure", "barcode"} {
+		if _, ok := index[name]; !ok {
+			return nil, nil, ErrNoHeader
+		}
+	}
+
+	barcodes := make(map[string]int)
+	for i, record := range records[1:] {
+		line := i + 2
+
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		value := func(field string) string {
+			column, ok := index[field]
+			if !ok || column >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[column])
+		}
+
+		row := Row{Line: line, Request: Request{
+			CategoryID:  value("category_id"),
+			Name:        value("name"),
+			Description: value("description"),
+			Measure:     value("measure"),
+			ImageURL:    value("image_url"),
+			Country:     value("country"),
+			Barcode:     value("barcode"),
+			Brand:       value("brand"),
+		}}
+		rows, errs = appendRow(rows, errs, row, barcodes)
+	}
+
+	return
+}
+
+// ParseNDJSON reads products from JSON lines with the fields of Request, blank lines are skipped.
+func ParseNDJSON(r io.Reader) (rows []Row, errs []RowError, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	barcodes := make(map[string]int)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		row := Row{Line: line}
+		if err := json.Unmarshal([]byte(text), &row.Request); err != nil {
+			errs = append(errs, RowError{Line: line, Message: "json: " + err.Error()})
+			continue
+		}
+		rows, errs = appendRow(rows, errs, row, barcodes)
+	}
+	err = scanner.Err()
+
+	return
+}
+
+// appendRow validates the row and keeps the first row of every barcode.
+func appendRow(rows []Row, errs []RowError, row Row, barcodes map[string]int) ([]Row, []RowError) {
+	if err := row.Validate(); err != nil {
+		return rows, append(errs, RowError{Line: row.Line, Message: err.Error()})
+	}
+
+	if line, ok := barcodes[row.Barcode]; ok {
+		return rows, append(errs, RowError{Line: row.Line, Message: fmt.Sprintf("barcode: repeats line %d", line)})
+	}
+	barcodes[row.Barcode] = row.Line
+
+	return append(rows, row), errs
+}
+
+// Record returns the product as a CSV record in the order of the export header.
+func (s Response) Record() []string {
+	return []string{s.ID, s.CategoryID, s.Name, s.Description, s.Measure, s.ImageURL, s.Country, s.Barcode, s.Brand}
+}
+
+// Header returns the CSV header of an export, the id column is ignored when the file is imported back.
+func Header() []string {
+	return append([]string{"id"}, columns...)
+}
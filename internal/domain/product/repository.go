@@ -1,11 +1,99 @@
 package product
 
-import "context"
+import (
+	"context"
+	"strconv"
+	"time"
+)
 
+const (
+	// SortRelevance orders by how well the query matches, the best match first. The relevance is scored for every
+	// page, a product edited between pages may be skipped or read twice.
+	SortRelevance     = "relevance"
+	SortName          = "name"
+	SortNameDesc      = "-name"
+	SortCreatedAt     = "created_at"
+	SortCreatedAtDesc = "-created_at"
+)
+
+// Filter narrows Search down, empty fields are not applied.
+type Filter struct {
+	// Query is free text matched against the name, brand and description
+	Query      string
+	CategoryID string
+	// CategoryIDs matches products in any of the categories, e.g. of a category subtree
+	CategoryIDs []string
+	// Country and Brand are matched case-insensitively
+	Country string
+	Brand   string
+	// ParentID matches the variants of the product
+	ParentID string
+	// Attributes matches products having any of the values of every attribute, numbers are float64
+	Attributes map[string][]any
+	// Deleted searches the trash instead of the catalogue, DeletedBefore narrows it to products deleted before the time
+	Deleted       bool
+	DeletedBefore time.Time
+	Sort          string
+	// After continues the search past the last product of the previous page
+	After *Cursor
+	Limit int
+}
+
+// Cursor is the sort key and id of the last product of a page, ties on the key are broken by the id.
+type Cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"id"`
+}
+
+// Page is a page of products with the number of products matching the filter.
+type Page struct {
+	Items []Entity
+	Total int
+	// Next is nil on the last page
+	Next *Cursor
+}
+
+// NewCursor points past the product in the sort, rank is the relevance the repository scored it with.
+func NewCursor(sort string, data Entity, rank float64) *Cursor {
+	cursor := &Cursor{Sort: sort, ID: data.ID}
+
+	switch sort {
+	case SortName, SortNameDesc:
+		cursor.Key = data.Name
+	case SortCreatedAt, SortCreatedAtDesc:
+		cursor.Key = data.CreatedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Key = strconv.FormatFloat(rank, 'g', -1, 64)
+	}
+
+	return cursor
+}
+
+// Repository keeps barcodes unique, Create and Update fail with store.ErrorAlreadyExists on a taken barcode.
+// Products in the trash are left out of every read but GetDeleted and a search of the trash, their barcodes are free.
 type Repository interface {
 	Select(ctx context.Context) (dest []Entity, err error)
+	Search(ctx context.Context, filter Filter) (dest Page, err error)
 	Create(ctx context.Context, data Entity) (id string, err error)
 	Get(ctx context.Context, id string) (dest Entity, err error)
+	// GetDeleted reads the product from the trash.
+	GetDeleted(ctx context.Context, id string) (dest Entity, err error)
+	GetByBarcode(ctx context.Context, barcode string) (dest Entity, err error)
+	// SelectByBarcodes returns the products out of the trash with one of the barcodes.
+	SelectByBarcodes(ctx context.Context, barcodes []string) (dest []Entity, err error)
+	// Upsert creates the products and updates the imported fields of those whose barcode is taken.
+	// dest holds the products as they were stored, in no particular order.
+	Upsert(ctx context.Context, data []Entity) (dest []Entity, created, updated int, err error)
+	// Update writes the fields that are set and increments the version. A non-zero data.Version is the version
+	// the product is expected to have, the update fails with store.ErrorStaleVersion when it has another one.
 	Update(ctx context.Context, id string, data Entity) (err error)
-	Delete(ctx context.Context, id string) (err error)
+	// Delete moves the product to the trash, a non-zero version is checked like in Update. trashedWith is
+	// the category or product whose deletion took this one along, empty when it is deleted on its own.
+	Delete(ctx context.Context, id string, version int, trashedWith string) (err error)
+	// Restore takes the product out of the trash and clears its TrashedWith, it fails with store.ErrorAlreadyExists when its barcode was taken since.
+	Restore(ctx context.Context, id string) (err error)
+	// Purge removes the product in the trash for good together with its images, prices and stock.
+	// It fails with ErrReferenced while orders refer to the product.
+	Purge(ctx context.Context, id string) (err error)
 }
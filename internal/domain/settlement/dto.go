@@ -0,0 +1,85 @@
+package settlement
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Request describes the uploaded statement, it is read from the query string.
+type Request struct {
+	Date     time.Time
+	Filename string
+}
+
+func (s *Request) Bind(r *http.Request) (err error) {
+	query := r.URL.Query()
+
+	if s.Date, err = time.Parse("2006-01-02", query.Get("date")); err != nil {
+		return errors.New("date: must be YYYY-MM-DD")
+	}
+	s.Filename = query.Get("filename")
+
+	return
+}
+
+type DiscrepancyResponse struct {
+	Kind      string `json:"kind"`
+	Line      int    `json:"line,omitempty"`
+	BillingID string `json:"billing_id,omitempty"`
+	InvoiceID string `json:"invoice_id,omitempty"`
+	Reference string `json:"reference,omitempty"`
+	Expected  string `json:"expected,omitempty"`
+	Actual    string `json:"actual,omitempty"`
+}
+
+type Response struct {
+	ID            string                `json:"id"`
+	CreatedAt     time.Time             `json:"created_at"`
+	Date          string                `json:"date"`
+	Filename      string                `json:"filename"`
+	RowsTotal     int                   `json:"rows_total"`
+	Matched       int                   `json:"matched"`
+	TotalAmount   string                `json:"total_amount"`
+	TotalFee      string                `json:"total_fee"`
+	Discrepancies []DiscrepancyResponse `json:"discrepancies,omitempty"`
+}
+
+func ParseFromEntity(data Statement) (res Response) {
+	res = Response{
+		ID:          data.ID,
+		CreatedAt:   data.CreatedAt,
+		Date:        data.Date.Format("2006-01-02"),
+		Filename:    data.Filename,
+		RowsTotal:   data.RowsTotal,
+		Matched:     data.Matched,
+		TotalAmount: data.TotalAmount.String(),
+		TotalFee:    data.TotalFee.String(),
+	}
+
+	for _, discrepancy := range data.Discrepancies {
+		item := DiscrepancyResponse{
+			Kind:      discrepancy.Kind,
+			Line:      discrepancy.Line,
+			BillingID: discrepancy.BillingID,
+			InvoiceID: discrepancy.InvoiceID,
+			Reference: discrepancy.Reference,
+		}
+		if discrepancy.Kind != KindExtra {
+			item.Expected = discrepancy.Expected.String()
+		}
+		if discrepancy.Kind != KindMissing {
+			item.Actual = discrepancy.Actual.String()
+		}
+		res.Discrepancies = append(res.Discrepancies, item)
+	}
+	return
+}
+
+func ParseFromEntities(data []Statement) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
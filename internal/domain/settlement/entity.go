@@ -0,0 +1,55 @@
+package settlement
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// KindMissing is a paid billing of the statement day the bank didn't settle
+	KindMissing = "missing"
+	// KindExtra is a statement row that doesn't match any billing
+	KindExtra = "extra"
+	// KindAmountMismatch is a statement row whose amount differs from the matched billing
+	KindAmountMismatch = "amount_mismatch"
+	// KindDuplicate is a statement row matching a billing an earlier row of the statement matched already
+	KindDuplicate = "duplicate"
+)
+
+// Statement is a daily settlement statement received from the bank.
+type Statement struct {
+	CreatedAt     time.Time       `db:"created_at"`
+	ID            string          `db:"id"`
+	Date          time.Time       `db:"date"`
+	Filename      string          `db:"filename"`
+	RowsTotal     int             `db:"rows_total"`
+	Matched       int             `db:"matched"`
+	TotalAmount   decimal.Decimal `db:"total_amount"`
+	TotalFee      decimal.Decimal `db:"total_fee"`
+	Rows          []Row           `db:"-"`
+	Discrepancies []Discrepancy   `db:"-"`
+}
+
+type Row struct {
+	StatementID  string          `db:"statement_id"`
+	Line         int             `db:"line"`
+	Reference    string          `db:"reference"`
+	IntReference string          `db:"int_reference"`
+	InvoiceID    string          `db:"invoice_id"`
+	Amount       decimal.Decimal `db:"amount"`
+	Fee          decimal.Decimal `db:"fee"`
+	BillingID    string          `db:"billing_id"`
+}
+
+// Discrepancy is a finding of the reconciliation, Line is zero for billings missing from the statement.
+type Discrepancy struct {
+	StatementID string          `db:"statement_id"`
+	Kind        string          `db:"kind"`
+	Line        int             `db:"line"`
+	BillingID   string          `db:"billing_id"`
+	InvoiceID   string          `db:"invoice_id"`
+	Reference   string          `db:"reference"`
+	Expected    decimal.Decimal `db:"expected"`
+	Actual      decimal.Decimal `db:"actual"`
+}
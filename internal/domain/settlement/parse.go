@@ -0,0 +1,126 @@
+package settlement
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrNoHeader = errors.New("settlement: statement has no header with reference, invoice_id and amount columns")
+
+// columns lists the header names accepted for each statement field.
+var columns = map[string][]string{
+	"reference":     {"reference", "ref", "rrn"},
+	"int_reference": {"int_reference", "intreference", "int_ref"},
+	"invoice_id":    {"invoice_id", "invoiceid", "invoice"},
+	"amount":        {"amount", "sum"},
+	"fee":           {"fee", "commission"},
+}
+
+// RowError describes a statement line that can't be imported.
+type RowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ParseError collects every invalid line of a statement.
+type ParseError struct {
+	Rows []RowError
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("settlement: %d invalid rows, first at line %d: %s", len(e.Rows), e.Rows[0].Line, e.Rows[0].Message)
+}
+
+// ParseRows reads statement rows from spreadsheet records. The first record has to be the header,
+// lines are counted from one as they appear in the file.
+func ParseRows(records [][]string) (rows []Row, err error) {
+	if len(records) == 0 {
+		return nil, ErrNoHeader
+	}
+
+	index := make(map[string]int)
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for field, aliases := range columns {
+			for _, alias := range aliases {
+				if name == alias {
+					index[field] = i
+				}
+			}
+		}
+	}
+
+	if _, ok := index["amount"]; !ok {
+		return nil, ErrNoHeader
+	}
+
+	_, hasReference := index["reference"]
+	_, hasIntReference := index["int_reference"]
+	_, hasInvoiceID := index["invoice_id"]
+	if !hasReference && !hasIntReference && !hasInvoiceID {
+		return nil, ErrNoHeader
+	}
+
+	parseErr := &ParseError{}
+	for i, record := range records[1:] {
+		line := i + 2
+
+		value := func(field string) string {
+			column, ok := index[field]
+			if !ok || column >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[column])
+		}
+
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		row := Row{
+			Line:         line,
+			Reference:    value("reference"),
+			IntReference: value("int_reference"),
+			InvoiceID:    value("invoice_id"),
+		}
+
+		if row.Reference == "" && row.IntReference == "" && row.InvoiceID == "" {
+			parseErr.Rows = append(parseErr.Rows, RowError{Line: line, Message: "reference, int_reference or invoice_id is required"})
+			continue
+		}
+
+		if row.Amount, err = parseAmount(value("amount")); err != nil {
+			parseErr.Rows = append(parseErr.Rows, RowError{Line: line, Message: "amount: " + err.Error()})
+			continue
+		}
+
+		if fee := value("fee"); fee != "" {
+			if row.Fee, err = parseAmount(fee); err != nil {
+				parseErr.Rows = append(parseErr.Rows, RowError{Line: line, Message: "fee: " + err.Error()})
+				continue
+			}
+		}
+
+		rows = append(rows, row)
+	}
+	err = nil
+
+	if len(parseErr.Rows) > 0 {
+		return nil, parseErr
+	}
+
+	return
+}
+
+// parseAmount accepts both decimal separators and spaces between thousands as banks export them.
+func parseAmount(value string) (decimal.Decimal, error) {
+	value = strings.NewReplacer(" ", "", "\u00a0", "", ",", ".").Replace(value)
+	if value == "" {
+		return decimal.Decimal{}, errors.New("cannot be blank")
+	}
+
+	return decimal.NewFromString(value)
+}
@@ -0,0 +1,11 @@
+package settlement
+
+import "context"
+
+type Repository interface {
+	// Create stores the statement with its rows and discrepancies.
+	Create(ctx context.Context, data Statement) (id string, err error)
+	Select(ctx context.Context) (dest []Statement, err error)
+	// Get returns the statement with its discrepancies, rows are not loaded.
+	Get(ctx context.Context, id string) (dest Statement, err error)
+}
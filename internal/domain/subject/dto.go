@@ -0,0 +1,112 @@
+package subject
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/order"
+)
+
+// Request names the data subject by any of the email, phone or account id the payer gave, a billing belongs to
+// the subject when one of them matches. The identifiers are sent in the body so they stay out of the request logs.
+type Request struct {
+	Email     string `json:"email"`
+	Phone     string `json:"phone"`
+	AccountID string `json:"account_id"`
+}
+
+func (s *Request) Bind(r *http.Request) error {
+	s.Email = strings.ToLower(strings.TrimSpace(s.Email))
+	s.Phone = billing.Digits(s.Phone)
+	s.AccountID = strings.TrimSpace(s.AccountID)
+
+	if s.Email == "" && s.Phone == "" && s.AccountID == "" {
+		return errors.New("email, phone or account_id: cannot be blank")
+	}
+
+	return nil
+}
+
+// Matches tells whether the billing belongs to the subject, emails are matched case-insensitively and phones
+// by their digits.
+func (s Request) Matches(data billing.Entity) bool {
+	switch {
+	case s.Email != "" && strings.EqualFold(strings.TrimSpace(data.Email), s.Email):
+		return true
+	case s.Phone != "" && billing.Digits(data.Phone) == s.Phone:
+		return true
+	case s.AccountID != "" && data.AccountID == s.AccountID:
+		return true
+	}
+
+	return false
+}
+
+// ID identifies the subject in the audit trail without keeping its identifiers there, it is the SHA-256
+// of the identifiers as they were bound.
+func (s Request) ID() string {
+	sum := sha256.Sum256([]byte("email:" + s.Email + "\nphone:" + s.Phone + "\naccount_id:" + s.AccountID))
+	return hex.EncodeToString(sum[:])
+}
+
+// Keys returns the blind indexes the billings of the subject are looked up by, see billing.SubjectKeys.
+func (s Request) Keys(index billing.Index) []string {
+	return billing.SubjectKeys(index, s.Email, s.Phone, s.AccountID)
+}
+
+// Billing is a billing of the subject as it is exported, with every detail the payer gave.
+type Billing struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	InvoiceID   string    `json:"invoice_id"`
+	Status      string    `json:"status"`
+	Amount      string    `json:"amount"`
+	Currency    string    `json:"currency"`
+	Description string    `json:"description,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	AccountID   string    `json:"account_id,omitempty"`
+	Email       string    `json:"email,omitempty"`
+	Phone       string    `json:"phone,omitempty"`
+	Language    string    `json:"language,omitempty"`
+	Reference   string    `json:"reference,omitempty"`
+}
+
+func ParseBilling(data billing.Entity) Billing {
+	return Billing{
+		ID:          data.ID,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+		InvoiceID:   data.InvoiceID,
+		Status:      data.Status,
+		Amount:      data.Amount,
+		Currency:    data.Currency,
+		Description: data.Description,
+		Name:        data.Name,
+		AccountID:   data.AccountID,
+		Email:       data.Email,
+		Phone:       data.Phone,
+		Language:    data.Language,
+		Reference:   data.Reference,
+	}
+}
+
+// Bundle is the export of the data kept about the subject: the billings and the orders paid with them.
+// Saved cards are kept by ePay, not by the service, the export has none of them.
+type Bundle struct {
+	SubjectID  string           `json:"subject_id"`
+	ExportedAt time.Time        `json:"exported_at"`
+	Billings   []Billing        `json:"billings"`
+	Orders     []order.Response `json:"orders"`
+}
+
+// ErasureResponse lists the billings whose personal data was erased, their amounts and statuses are kept.
+type ErasureResponse struct {
+	SubjectID string   `json:"subject_id"`
+	Billings  []string `json:"billings"`
+}
@@ -0,0 +1,39 @@
+package subject
+
+import (
+	"payment-service/internal/domain/billing"
+	"payment-service/pkg/store/postgres"
+)
+
+// Erased replaces the personal data of an erased billing, the store keeps an empty value as unchanged.
+const Erased = billing.Erased
+
+// PersonalFields are the db names of the fields of a billing holding personal data, they are redacted from
+// the audit trail of an erased billing.
+var PersonalFields = []string{"name", "account_id", "email", "phone", "subject_keys"}
+
+// Erasure returns the write that anonymizes the personal data of the billing and drops its subject keys, so it is
+// no longer found by them. Only the personal fields that are not empty are replaced with Erased, a blank field can't
+// be cleared by an update and has nothing to erase. The other fields are left empty, so the store keeps the amounts,
+// statuses and references the records of the payment require.
+func Erasure(data billing.Entity) (update billing.Entity) {
+	update.SubjectKeys = postgres.Array{}
+
+	fields := []struct {
+		value string
+		dest  *string
+	}{
+		{data.Name, &update.Name},
+		{data.AccountID, &update.AccountID},
+		{data.Email, &update.Email},
+		{data.Phone, &update.Phone},
+	}
+
+	for _, field := range fields {
+		if field.value != "" {
+			*field.dest = Erased
+		}
+	}
+
+	return
+}
@@ -0,0 +1,93 @@
+package tenant
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"payment-service/internal/domain/fx"
+)
+
+// idPattern keeps tenant ids short and printable, they are sent in headers and token claims.
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Request replaces the settings of the tenant of the path.
+type Request struct {
+	Name          string   `json:"name"`
+	TerminalID    string   `json:"terminal_id"`
+	Currency      string   `json:"currency"`
+	WebhookURLs   []string `json:"webhook_urls"`
+	WebhookSecret string   `json:"webhook_secret"`
+}
+
+func (s *Request) Bind(r *http.Request) error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" {
+		return errors.New("name: cannot be blank")
+	}
+
+	if s.Currency != "" {
+		s.Currency = fx.NormalizeCurrency(s.Currency)
+		if len(s.Currency) != 3 {
+			return errors.New("currency: must be an ISO 4217 code")
+		}
+	}
+
+	for _, value := range s.WebhookURLs {
+		link, err := url.Parse(value)
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+			return errors.New("webhook_urls: must be http or https URLs")
+		}
+	}
+
+	return nil
+}
+
+// ValidateID tells whether the id can name a tenant.
+func ValidateID(id string) error {
+	if !idPattern.MatchString(id) {
+		return errors.New("id: must be lower-case letters, digits, - or _")
+	}
+
+	return nil
+}
+
+// Response leaves the webhook secret out, it only tells whether one is set.
+type Response struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Name        string    `json:"name"`
+	TerminalID  string    `json:"terminal_id"`
+	Currency    string    `json:"currency"`
+	WebhookURLs []string  `json:"webhook_urls"`
+	Signed      bool      `json:"signed"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		ID:          data.ID,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+		Name:        data.Name,
+		TerminalID:  data.TerminalID,
+		Currency:    data.Currency,
+		WebhookURLs: data.WebhookURLs,
+		Signed:      data.WebhookSecret != "",
+	}
+	if res.WebhookURLs == nil {
+		res.WebhookURLs = []string{}
+	}
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
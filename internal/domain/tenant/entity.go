@@ -0,0 +1,22 @@
+package tenant
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrNotFound = errors.New("tenant: tenant does not exist")
+
+// Entity holds the settings of a tenant sharing the deployment, its ID is the tenant id API keys and tokens carry.
+// TerminalID and Currency are taken by the billings that don't set their own, billing events are also posted
+// to the WebhookURLs, signed with WebhookSecret when it is set.
+type Entity struct {
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+	ID            string    `db:"id"`
+	Name          string    `db:"name"`
+	TerminalID    string    `db:"terminal_id"`
+	Currency      string    `db:"currency"`
+	WebhookURLs   []string  `db:"-"`
+	WebhookSecret string    `db:"webhook_secret"`
+}
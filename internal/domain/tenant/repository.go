@@ -0,0 +1,10 @@
+package tenant
+
+import "context"
+
+type Repository interface {
+	Select(ctx context.Context) (dest []Entity, err error)
+	Get(ctx context.Context, id string) (dest Entity, err error)
+	// Save creates the tenant settings or replaces the stored ones.
+	Save(ctx context.Context, data Entity) (err error)
+}
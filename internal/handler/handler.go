@@ -3,22 +3,46 @@ package handler
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/swaggo/http-swagger/v2"
+	nethttp "net/http"
 	"net/url"
 	"payment-service/docs"
 	_ "payment-service/docs"
 	"payment-service/internal/config"
+	"payment-service/internal/domain/apikey"
 	"payment-service/internal/handler/http"
+	"payment-service/internal/service/access"
+	"payment-service/internal/service/accounting"
 	"payment-service/internal/service/catalogue"
+	"payment-service/internal/service/checkout"
+	"payment-service/internal/service/compliance"
+	"payment-service/internal/service/exchange"
 	"payment-service/internal/service/payment"
+	"payment-service/internal/service/reconciliation"
+	"payment-service/internal/service/stock"
+	"payment-service/pkg/auth"
 	"payment-service/pkg/epay"
+	"payment-service/pkg/ratelimit"
 	"payment-service/pkg/server/router"
 )
 
 type Dependencies struct {
-	Configs          config.Configs
-	CatalogueService *catalogue.Service
-	PaymentService   *payment.Service
-	EPayClient       *epay.Client
+	Configs               config.Configs
+	CatalogueService      *catalogue.Service
+	PaymentService        *payment.Service
+	AccountingService     *accounting.Service
+	ExchangeService       *exchange.Service
+	CheckoutService       *checkout.Service
+	ReconciliationService *reconciliation.Service
+	StockService          *stock.Service
+	ComplianceService     *compliance.Service
+	AccessService         *access.Service
+	EPayClient            *epay.Client
+
+	// Authenticators find the caller of an API request, the first one that recognizes the credentials wins
+	Authenticators []auth.Authenticator
+
+	// RateLimitStore keeps the buckets the API requests take tokens from
+	RateLimitStore ratelimit.Store
 }
 
 // Configuration is an alias for a function that will take in a pointer to a Handler and modify it
@@ -53,8 +77,32 @@ func New(d Dependencies, configs ...Configuration) (h *Handler, err error) {
 // WithHTTPHandler applies a http handler to the Handler
 func WithHTTPHandler() Configuration {
 	return func(h *Handler) (err error) {
+		trustedProxies, err := router.ParseProxies(h.dependencies.Configs.HTTP.TrustedProxies)
+		if err != nil {
+			return
+		}
+
 		// Create the http handler, if we needed parameters, such as connection strings they could be inputted here
-		h.HTTP = router.New()
+		h.HTTP = router.New(trustedProxies, h.dependencies.Configs.HTTP.AllowedOrigins...)
+
+		var rateLimit ratelimit.Limit
+		if value := h.dependencies.Configs.RateLimit.Default; value != "" {
+			if rateLimit, err = ratelimit.ParseLimit(value); err != nil {
+				return
+			}
+		}
+
+		var ipRateLimit ratelimit.Limit
+		if value := h.dependencies.Configs.RateLimit.IP; value != "" {
+			if ipRateLimit, err = ratelimit.ParseLimit(value); err != nil {
+				return
+			}
+		}
+
+		rateLimitRules, err := ratelimit.ParseRules(h.dependencies.Configs.RateLimit.Routes)
+		if err != nil {
+			return
+		}
 
 		docs.SwaggerInfo.BasePath = "/api/v1"
 		docs.SwaggerInfo.Host = h.dependencies.Configs.HTTP.Host
@@ -70,13 +118,49 @@ func WithHTTPHandler() Configuration {
 			httpSwagger.URL(swaggerURL.String()),
 		))
 
+		// images kept on the local disk are served by the service itself
+		if storage := h.dependencies.Configs.Storage; storage.Driver == "local" {
+			h.HTTP.Handle("/media/*", nethttp.StripPrefix("/media/", nethttp.FileServer(nethttp.Dir(storage.Path))))
+		}
+
 		productHandler := http.NewProductHandler(h.dependencies.CatalogueService)
 		categoryHandler := http.NewCategory(h.dependencies.CatalogueService)
+		priceListHandler := http.NewPriceList(h.dependencies.CatalogueService)
 		billingHandler := http.NewBilling(h.dependencies.PaymentService)
+		orderHandler := http.NewOrder(h.dependencies.CheckoutService)
+		inventoryHandler := http.NewInventory(h.dependencies.StockService)
+		disputeHandler := http.NewDispute(h.dependencies.PaymentService)
+		ledgerHandler := http.NewLedger(h.dependencies.AccountingService)
+		settlementHandler := http.NewSettlement(h.dependencies.ReconciliationService)
+		fxHandler := http.NewFX(h.dependencies.ExchangeService)
+		auditHandler := http.NewAudit(h.dependencies.ComplianceService)
+		subjectHandler := http.NewSubject(h.dependencies.ComplianceService)
+		apiKeyHandler := http.NewAPIKey(h.dependencies.AccessService)
+		tenantHandler := http.NewTenant(h.dependencies.AccessService)
 		h.HTTP.Route("/api/v1", func(r chi.Router) {
+			r.Use(router.RateLimitIP(h.dependencies.RateLimitStore, ipRateLimit))
+			r.Use(router.Authenticate(h.dependencies.Authenticators...))
+			r.Use(router.RateLimit(h.dependencies.RateLimitStore, rateLimit, rateLimitRules...))
+
 			r.Mount("/products", productHandler.Routes())
 			r.Mount("/categories", categoryHandler.Routes())
+			r.Mount("/price-lists", priceListHandler.Routes())
 			r.Mount("/billings", billingHandler.Routes())
+			r.Mount("/orders", orderHandler.Routes())
+			r.Mount("/inventory", inventoryHandler.Routes())
+			r.Mount("/disputes", disputeHandler.Routes())
+			r.With(router.RequireScope(apikey.ScopeAdmin), router.RequireOperator()).Mount("/ledger", ledgerHandler.Routes())
+
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(router.RequireScope(apikey.ScopeAdmin), router.RequireOperator())
+
+				r.Mount("/settlements", settlementHandler.Routes())
+				r.Mount("/fx", fxHandler.Routes())
+				r.Mount("/audit", auditHandler.Routes())
+				r.Mount("/subjects", subjectHandler.Routes())
+				r.Mount("/api-keys", apiKeyHandler.Routes())
+				r.Mount("/tenants", tenantHandler.Routes())
+			})
 		})
 
 		return
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"payment-service/internal/domain/apikey"
+	"payment-service/internal/domain/tenant"
+	"payment-service/internal/service/access"
+	"payment-service/pkg/server/response"
+	"payment-service/pkg/store"
+)
+
+type APIKeyHandler struct {
+	Access *access.Service
+}
+
+func NewAPIKey(s *access.Service) *APIKeyHandler {
+	return &APIKeyHandler{Access: s}
+}
+
+func (h *APIKeyHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Post("/", h.add)
+	r.Delete("/{id}", h.revoke)
+
+	return r
+}
+
+// List of the API keys issued to source services
+//
+//	@Summary	List of the API keys issued to source services
+//	@Tags		api-keys
+//	@Accept		json
+//	@Produce	json
+//	@Success	200	{array}		response.Object
+//	@Failure	401	{object}	response.Object
+//	@Failure	403	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/admin/api-keys [get]
+func (h *APIKeyHandler) list(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Access.ListKeys(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Issue an API key to a source service, the key is only shown in this response
+//
+//	@Summary	Issue an API key to a source service, the key is only shown in this response
+//	@Tags		api-keys
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		apikey.Request	true	"body param"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	401		{object}	response.Object
+//	@Failure	403		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/admin/api-keys [post]
+func (h *APIKeyHandler) add(w http.ResponseWriter, r *http.Request) {
+	req := apikey.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	res, err := h.Access.IssueKey(r.Context(), req)
+	switch {
+	case err == tenant.ErrNotFound, err == apikey.ErrTenantAdmin:
+		response.BadRequest(w, r, err, req)
+		return
+	case err != nil:
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Revoke the API key
+//
+//	@Summary	Revoke the API key
+//	@Tags		api-keys
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path	string	true	"path param"
+//	@Success	200
+//	@Failure	401	{object}	response.Object
+//	@Failure	403	{object}	response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) revoke(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.Access.RevokeKey(r.Context(), id)
+	switch err {
+	case nil:
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"payment-service/internal/service/compliance"
+	"payment-service/pkg/server/response"
+)
+
+type AuditHandler struct {
+	Compliance *compliance.Service
+}
+
+func NewAudit(s *compliance.Service) *AuditHandler {
+	return &AuditHandler{Compliance: s}
+}
+
+func (h *AuditHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+
+	return r
+}
+
+// List of the audit records of an entity
+//
+//	@Summary	List of the audit records of an entity
+//	@Tags		audit
+//	@Accept		json
+//	@Produce	json
+//	@Param		entity_id	query		string	true	"id of the product, category, billing or dispute"
+//	@Success	200			{array}		response.Object
+//	@Failure	400			{object}	response.Object
+//	@Failure	500			{object}	response.Object
+//	@Router		/admin/audit [get]
+func (h *AuditHandler) list(w http.ResponseWriter, r *http.Request) {
+	entityID := r.URL.Query().Get("entity_id")
+	if entityID == "" {
+		response.BadRequest(w, r, errors.New("entity_id: cannot be blank"), nil)
+		return
+	}
+
+	res, err := h.Compliance.ListAuditRecords(r.Context(), entityID)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
@@ -1,14 +1,20 @@
 package http
 
 import (
+	"io"
 	"net/http"
 	"payment-service/internal/domain/billing"
 	"payment-service/internal/service/payment"
+	"payment-service/pkg/epay"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 
+	"payment-service/internal/domain/apikey"
+	"payment-service/pkg/auth"
 	"payment-service/pkg/server/response"
+	"payment-service/pkg/server/router"
+	"payment-service/pkg/store"
 )
 
 type BillingHandler struct {
@@ -22,7 +28,18 @@ func NewBilling(s *payment.Service) *BillingHandler {
 func (h *BillingHandler) Routes() chi.Router {
 	r := chi.NewRouter()
 
-	r.Post("/", h.add)
+	// ePay posts the payment results without credentials
+	r.Post("/postlink", h.postLink)
+
+	r.Group(func(r chi.Router) {
+		r.Use(router.RequireScope())
+
+		r.With(router.RequireScope(apikey.ScopeBillingCreate)).Post("/", h.add)
+		r.Get("/totals", h.totals)
+		r.Get("/{id}", h.get)
+		r.With(router.RequireScope(apikey.ScopeBillingUpdate)).Patch("/{id}", h.patch)
+		r.With(router.RequireScope(apikey.ScopeBillingRefund)).Post("/{id}/refund", h.refund)
+	})
 
 	return r
 }
@@ -36,6 +53,7 @@ func (h *BillingHandler) Routes() chi.Router {
 //	@Param		request	body		billing.Request	true	"body param"
 //	@Success	200		{object}	response.Object
 //	@Failure	400		{object}	response.Object
+//	@Failure	429		{object}	response.Object
 //	@Failure	500		{object}	response.Object
 //	@Router		/billings [post]
 func (h *BillingHandler) add(w http.ResponseWriter, r *http.Request) {
@@ -51,5 +69,200 @@ func (h *BillingHandler) add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	maskPII(r, &res)
 	response.OK(w, r, res)
 }
+
+// Totals of billings per currency converted to the base currency
+//
+//	@Summary	Totals of billings per currency converted to the base currency
+//	@Tags		billings
+//	@Accept		json
+//	@Produce	json
+//	@Param		from	query		string		false	"first creation date, YYYY-MM-DD"
+//	@Param		to		query		string		false	"last creation date, YYYY-MM-DD"
+//	@Param		status	query		[]string	false	"billing statuses, defaults to paid"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/billings/totals [get]
+func (h *BillingHandler) totals(w http.ResponseWriter, r *http.Request) {
+	req := billing.TotalsRequest{}
+	if err := req.Bind(r); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.Billing.BillingTotals(r.Context(), req)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Read the billing with its amount in the base currency
+//
+//	@Summary	Read the billing with its amount in the base currency
+//	@Tags		billings
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	response.Object
+//	@Header		200	{string}	ETag	"quoted version of the billing"
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/billings/{id} [get]
+func (h *BillingHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Billing.GetBilling(r.Context(), id)
+	if err != nil && err != store.ErrorNotFound {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if err == store.ErrorNotFound {
+		response.NotFound(w, r, err)
+		return
+	}
+
+	maskPII(r, &res)
+	setETag(w, res.Version)
+	response.OK(w, r, res)
+}
+
+// Change the contact and redirect details of an unpaid billing named in a JSON merge patch
+//
+//	@Summary	Change the contact and redirect details of an unpaid billing named in a JSON merge patch
+//	@Tags		billings
+//	@Accept		json
+//	@Produce	json
+//	@Param		id			path		string					true	"path param"
+//	@Param		If-Match	header		string					true	"ETag of the billing, * skips the check"
+//	@Param		request		body		billing.PatchRequest	true	"body param, fields can be changed but not removed"
+//	@Success	200			{object}	billing.Response
+//	@Failure	400			{object}	response.Object
+//	@Failure	404			{object}	response.Object
+//	@Failure	409			{object}	response.Object
+//	@Failure	412			{object}	response.Object
+//	@Failure	428			{object}	response.Object
+//	@Failure	500			{object}	response.Object
+//	@Router		/billings/{id} [patch]
+func (h *BillingHandler) patch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	version, err := ifMatch(r)
+	if err != nil {
+		writePrecondition(w, r, err)
+		return
+	}
+
+	patch, err := readPatch(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.Billing.PatchBilling(r.Context(), id, version, patch)
+	switch {
+	case err == nil:
+		maskPII(r, &res)
+		setETag(w, res.Version)
+		response.OK(w, r, res)
+	case err == store.ErrorStaleVersion:
+		response.PreconditionFailed(w, r, err)
+	case isPatchError(err):
+		response.BadRequest(w, r, err, nil)
+	case err == store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case err == billing.ErrInvalidStatus:
+		response.Conflict(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Accept the payment result ePay posts for an invoice
+//
+//	@Summary	Accept the payment result ePay posts for an invoice
+//	@Tags		billings
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body	epay.Invoice	true	"body param"
+//	@Success	200
+//	@Failure	400	{object}	response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	409	{object}	response.Object
+//	@Failure	429	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/billings/postlink [post]
+func (h *BillingHandler) postLink(w http.ResponseWriter, r *http.Request) {
+	req := epay.Invoice{}
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	// a postlink that raced another one, or came before the payment completed, gets 409, ePay posts it again
+	// and it finds the billing processed
+	err := h.Billing.ProcessInvoice(r.Context(), req)
+	switch err {
+	case nil:
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case store.ErrorStaleVersion, billing.ErrInvoicePending:
+		response.Conflict(w, r, err)
+	case billing.ErrInvoiceMismatch:
+		response.BadRequest(w, r, err, nil)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Refund the paid billing through ePay, in full or in part
+//
+//	@Summary	Refund the paid billing through ePay, in full or in part
+//	@Tags		billings
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path	string					true	"path param"
+//	@Param		request	body	billing.RefundRequest	false	"body param"
+//	@Success	200
+//	@Failure	400	{object}	response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	409	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/billings/{id}/refund [post]
+func (h *BillingHandler) refund(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	// the body is optional, an empty one refunds what is left of the amount
+	req := billing.RefundRequest{}
+	if err := render.Bind(r, &req); err != nil && err != io.EOF {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	err := h.Billing.RefundBilling(r.Context(), id, req)
+	switch err {
+	case nil:
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case billing.ErrInvalidStatus, store.ErrorStaleVersion:
+		response.Conflict(w, r, err)
+	case billing.ErrInvalidAmount:
+		response.BadRequest(w, r, err, req)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// maskPII masks the personal data of the payer unless the caller was granted apikey.ScopePIIRead.
+func maskPII(r *http.Request, res *billing.Response) {
+	if principal, ok := auth.FromContext(r.Context()); ok && principal.HasScope(apikey.ScopePIIRead) {
+		return
+	}
+	res.Mask()
+}
@@ -1,14 +1,19 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"payment-service/internal/domain/category"
+	"payment-service/internal/domain/product"
 	"payment-service/internal/service/catalogue"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 
+	"payment-service/internal/domain/apikey"
 	"payment-service/pkg/server/response"
+	"payment-service/pkg/server/router"
 	"payment-service/pkg/store"
 )
 
@@ -22,14 +27,31 @@ func NewCategory(s *catalogue.Service) *CategoryHandler {
 
 func (h *CategoryHandler) Routes() chi.Router {
 	r := chi.NewRouter()
+	r.Use(router.RequireScope())
+
+	write := r.With(router.RequireScope(apikey.ScopeCatalogueWrite))
 
 	r.Get("/", h.list)
-	r.Post("/", h.add)
+	write.Post("/", h.add)
+	r.Get("/tree", h.tree)
 
 	r.Route("/{id}", func(r chi.Router) {
+		write := r.With(router.RequireScope(apikey.ScopeCatalogueWrite))
+
 		r.Get("/", h.get)
-		r.Put("/", h.update)
-		r.Delete("/", h.delete)
+		write.Put("/", h.update)
+		write.Patch("/", h.patch)
+		write.Delete("/", h.delete)
+		write.Post("/restore", h.restore)
+
+		r.Get("/ancestors", h.ancestors)
+		r.Get("/breadcrumb", h.breadcrumb)
+		r.Get("/products", h.products)
+		write.Post("/move", h.move)
+
+		r.Get("/attributes", h.listAttributes)
+		write.Post("/attributes", h.addAttribute)
+		write.Delete("/attributes/{code}", h.deleteAttribute)
 	})
 
 	return r
@@ -41,11 +63,24 @@ func (h *CategoryHandler) Routes() chi.Router {
 //	@Tags		categories
 //	@Accept		json
 //	@Produce	json
+//	@Param		deleted			query		bool	false	"lists the trash instead, the latest deleted first"
 //	@Success	200				{array}		response.Object
+//	@Failure	400				{object}	response.Object
 //	@Failure	500				{object}	response.Object
 //	@Router		/categories 	[get]
 func (h *CategoryHandler) list(w http.ResponseWriter, r *http.Request) {
-	res, err := h.Category.ListCategories(r.Context())
+	deleted, err := queryBool(r, "deleted")
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var res []category.Response
+	if deleted {
+		res, err = h.Category.ListDeletedCategories(r.Context())
+	} else {
+		res, err = h.Category.ListCategories(r.Context())
+	}
 	if err != nil {
 		response.InternalServerError(w, r, err)
 		return
@@ -89,6 +124,7 @@ func (h *CategoryHandler) add(w http.ResponseWriter, r *http.Request) {
 //	@Produce	json
 //	@Param		id	path		string	true	"path param"
 //	@Success	200	{object}	response.Object
+//	@Header		200	{string}	ETag	"quoted version of the category"
 //	@Failure	404	{object}	response.Object
 //	@Failure	500	{object}	response.Object
 //	@Router		/categories/{id} [get]
@@ -106,6 +142,7 @@ func (h *CategoryHandler) get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, res.Version)
 	response.OK(w, r, res)
 }
 
@@ -115,49 +152,262 @@ func (h *CategoryHandler) get(w http.ResponseWriter, r *http.Request) {
 //	@Tags		categories
 //	@Accept		json
 //	@Produce	json
-//	@Param		id		path	string				true	"path param"
-//	@Param		request	body	category.Request	true	"body param"
+//	@Param		id			path	string				true	"path param"
+//	@Param		If-Match	header	string				true	"ETag of the category, * skips the check"
+//	@Param		request		body	category.Request	true	"body param"
 //	@Success	200
 //	@Failure	400	{object}	response.Object
 //	@Failure	404	{object}	response.Object
+//	@Failure	412	{object}	response.Object
+//	@Failure	428	{object}	response.Object
 //	@Failure	500	{object}	response.Object
 //	@Router		/categories/{id} [put]
 func (h *CategoryHandler) update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	version, err := ifMatch(r)
+	if err != nil {
+		writePrecondition(w, r, err)
+		return
+	}
+
 	req := category.Request{}
 	if err := render.Bind(r, &req); err != nil {
 		response.BadRequest(w, r, err, req)
 		return
 	}
 
-	err := h.Category.UpdateCategory(r.Context(), id, req)
-	if err != nil && err != store.ErrorNotFound {
+	err = h.Category.UpdateCategory(r.Context(), id, version, req)
+	switch {
+	case err == nil:
+		// with * the new version is not known
+		if version != 0 {
+			setETag(w, version+1)
+		}
+	case err == store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case err == store.ErrorStaleVersion:
+		response.PreconditionFailed(w, r, err)
+	default:
 		response.InternalServerError(w, r, err)
+	}
+}
+
+// Change the fields of the category named in a JSON merge patch
+//
+//	@Summary	Change the fields of the category named in a JSON merge patch
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		id			path		string				true	"path param"
+//	@Param		If-Match	header		string				true	"ETag of the category, * skips the check"
+//	@Param		request		body		category.Request	true	"body param, a changed parentID moves the category"
+//	@Success	200			{object}	category.Response
+//	@Failure	400			{object}	response.Object
+//	@Failure	404			{object}	response.Object
+//	@Failure	409			{object}	response.Object
+//	@Failure	412			{object}	response.Object
+//	@Failure	428			{object}	response.Object
+//	@Failure	500			{object}	response.Object
+//	@Router		/categories/{id} [patch]
+func (h *CategoryHandler) patch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	version, err := ifMatch(r)
+	if err != nil {
+		writePrecondition(w, r, err)
 		return
 	}
 
-	if err == store.ErrorNotFound {
-		response.NotFound(w, r, err)
+	patch, err := readPatch(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
 		return
 	}
+
+	res, err := h.Category.PatchCategory(r.Context(), id, version, patch)
+	switch {
+	case err == nil:
+		setETag(w, res.Version)
+		response.OK(w, r, res)
+	case err == store.ErrorStaleVersion:
+		response.PreconditionFailed(w, r, err)
+	case isPatchError(err), err == category.ErrParentNotFound:
+		response.BadRequest(w, r, err, nil)
+	case err == store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case err == category.ErrCycle:
+		response.Conflict(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
 }
 
-// Delete the category from the database
+// Move the category to the trash
 //
-//	@Summary	Delete the category from the database
+//	@Summary	Move the category to the trash
 //	@Tags		categories
 //	@Accept		json
 //	@Produce	json
-//	@Param		id	path	string	true	"path param"
+//	@Param		id			path	string	true	"path param"
+//	@Param		If-Match	header	string	true	"ETag of the category, * skips the check"
+//	@Param		cascade		query	bool	false	"moves the subcategories and products to the trash too, a category with any is refused otherwise"
 //	@Success	200
+//	@Failure	400	{object}	response.Object
 //	@Failure	404	{object}	response.Object
+//	@Failure	409	{object}	response.Object
+//	@Failure	412	{object}	response.Object
+//	@Failure	428	{object}	response.Object
 //	@Failure	500	{object}	response.Object
 //	@Router		/categories/{id} [delete]
 func (h *CategoryHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	err := h.Category.DeleteCategory(r.Context(), id)
+	version, err := ifMatch(r)
+	if err != nil {
+		writePrecondition(w, r, err)
+		return
+	}
+
+	cascade, err := queryBool(r, "cascade")
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	err = h.Category.DeleteCategory(r.Context(), id, version, cascade)
+	switch {
+	case err == nil:
+	case err == store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case err == category.ErrNotEmpty:
+		response.Conflict(w, r, err)
+	case err == store.ErrorStaleVersion:
+		response.PreconditionFailed(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Take the category out of the trash
+//
+//	@Summary	Take the category out of the trash
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string	true	"path param"
+//	@Param		cascade	query		bool	false	"restores the subcategories and products its deletion took along too"
+//	@Success	200		{object}	category.Response
+//	@Failure	400		{object}	response.Object
+//	@Failure	404		{object}	response.Object
+//	@Failure	409		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/categories/{id}/restore [post]
+func (h *CategoryHandler) restore(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	cascade, err := queryBool(r, "cascade")
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.Category.RestoreCategory(r.Context(), id, cascade)
+	switch {
+	case err == nil:
+		setETag(w, res.Version)
+		response.OK(w, r, res)
+	case err == store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case err == category.ErrParentNotFound, err == store.ErrorAlreadyExists:
+		response.Conflict(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// queryBool reads a true or false query parameter, a missing one is false.
+func queryBool(r *http.Request, name string) (value bool, err error) {
+	param := r.URL.Query().Get(name)
+	if param == "" {
+		return
+	}
+
+	if value, err = strconv.ParseBool(param); err != nil {
+		err = errors.New(name + ": must be true or false")
+	}
+	return
+}
+
+// The category hierarchy as a nested tree
+//
+//	@Summary	The category hierarchy as a nested tree
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		root	query		string	false	"id of the category to start from, every root when empty"
+//	@Success	200		{array}		response.Object
+//	@Failure	404		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/categories/tree [get]
+func (h *CategoryHandler) tree(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Category.CategoryTree(r.Context(), r.URL.Query().Get("root"))
+	if err != nil && err != store.ErrorNotFound {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if err == store.ErrorNotFound {
+		response.NotFound(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Ancestors of the category from the root down
+//
+//	@Summary	Ancestors of the category from the root down
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{array}		response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/categories/{id}/ancestors [get]
+func (h *CategoryHandler) ancestors(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Category.ListAncestors(r.Context(), id)
+	if err != nil && err != store.ErrorNotFound {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if err == store.ErrorNotFound {
+		response.NotFound(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Breadcrumb from the root down to the category
+//
+//	@Summary	Breadcrumb from the root down to the category
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/categories/{id}/breadcrumb [get]
+func (h *CategoryHandler) breadcrumb(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Category.GetBreadcrumb(r.Context(), id)
 	if err != nil && err != store.ErrorNotFound {
 		response.InternalServerError(w, r, err)
 		return
@@ -167,4 +417,185 @@ func (h *CategoryHandler) delete(w http.ResponseWriter, r *http.Request) {
 		response.NotFound(w, r, err)
 		return
 	}
+
+	response.OK(w, r, res)
+}
+
+// Search products of the category and its descendants
+//
+//	@Summary	Search products of the category and its descendants
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string	true	"path param"
+//	@Param		q		query		string	false	"free text matched against the name, brand and description"
+//	@Param		country	query		string	false	"country of origin"
+//	@Param		brand	query		string	false	"brand"
+//	@Param		sort	query		string	false	"relevance, name, -name, created_at or -created_at"
+//	@Param		cursor	query		string	false	"next_cursor of the previous page"
+//	@Param		limit	query		int		false	"page size from 1 to 100, defaults to 20"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	404		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/categories/{id}/products [get]
+func (h *CategoryHandler) products(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := product.SearchRequest{}
+	if err := req.Bind(r); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.Category.ListSubtreeProducts(r.Context(), id, req)
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case product.ErrInvalidCursor:
+		response.BadRequest(w, r, err, nil)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Move the category with its subtree under another parent
+//
+//	@Summary	Move the category with its subtree under another parent
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		id			path	string					true	"path param"
+//	@Param		If-Match	header	string					true	"ETag of the category, * skips the check"
+//	@Param		request		body	category.MoveRequest	true	"body param, an empty parentID makes the category a root"
+//	@Success	200
+//	@Failure	400	{object}	response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	409	{object}	response.Object
+//	@Failure	412	{object}	response.Object
+//	@Failure	428	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/categories/{id}/move [post]
+func (h *CategoryHandler) move(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	version, err := ifMatch(r)
+	if err != nil {
+		writePrecondition(w, r, err)
+		return
+	}
+
+	req := category.MoveRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	err = h.Category.MoveCategory(r.Context(), id, version, req)
+	switch err {
+	case nil:
+		// with * the new version is not known
+		if version != 0 {
+			setETag(w, version+1)
+		}
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case store.ErrorStaleVersion:
+		response.PreconditionFailed(w, r, err)
+	case category.ErrParentNotFound:
+		response.BadRequest(w, r, err, req)
+	case category.ErrCycle:
+		response.Conflict(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Attributes products of the category have, including those defined on its ancestors
+//
+//	@Summary	Attributes products of the category have, including those defined on its ancestors
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{array}		response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/categories/{id}/attributes [get]
+func (h *CategoryHandler) listAttributes(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Category.ListCategoryAttributes(r.Context(), id)
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Define an attribute on the category, it applies to the products of its subtree
+//
+//	@Summary	Define an attribute on the category, it applies to the products of its subtree
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string						true	"path param"
+//	@Param		request	body		category.AttributeRequest	true	"body param, type is string, number or enum"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	404		{object}	response.Object
+//	@Failure	409		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/categories/{id}/attributes [post]
+func (h *CategoryHandler) addAttribute(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := category.AttributeRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	res, err := h.Category.AddCategoryAttribute(r.Context(), id, req)
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case store.ErrorAlreadyExists:
+		response.Conflict(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Delete the attribute defined on the category
+//
+//	@Summary	Delete the attribute defined on the category
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path	string	true	"path param"
+//	@Param		code	path	string	true	"attribute code"
+//	@Success	200
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/categories/{id}/attributes/{code} [delete]
+func (h *CategoryHandler) deleteAttribute(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	code := chi.URLParam(r, "code")
+
+	err := h.Category.DeleteCategoryAttribute(r.Context(), id, code)
+	switch err {
+	case nil:
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
 }
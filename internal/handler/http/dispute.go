@@ -0,0 +1,243 @@
+package http
+
+import (
+	"net/http"
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/dispute"
+	"payment-service/internal/service/payment"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"payment-service/internal/domain/apikey"
+	"payment-service/pkg/server/response"
+	"payment-service/pkg/server/router"
+	"payment-service/pkg/store"
+)
+
+type DisputeHandler struct {
+	Payment *payment.Service
+}
+
+func NewDispute(s *payment.Service) *DisputeHandler {
+	return &DisputeHandler{Payment: s}
+}
+
+func (h *DisputeHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(router.RequireScope())
+
+	write := r.With(router.RequireScope(apikey.ScopeDisputeWrite))
+
+	r.Get("/", h.list)
+	write.Post("/", h.add)
+	r.Get("/due", h.due)
+
+	r.Route("/{id}", func(r chi.Router) {
+		write := r.With(router.RequireScope(apikey.ScopeDisputeWrite))
+
+		r.Get("/", h.get)
+		write.Post("/evidence", h.addEvidence)
+		write.Post("/submit", h.submit)
+		write.Post("/resolve", h.resolve)
+	})
+
+	return r
+}
+
+// List of disputes
+//
+//	@Summary	List of disputes
+//	@Tags		disputes
+//	@Accept		json
+//	@Produce	json
+//	@Param		status	query		string	false	"opened, evidence_submitted, won or lost"
+//	@Success	200		{array}		response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/disputes [get]
+func (h *DisputeHandler) list(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Payment.ListDisputes(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// List of opened disputes close to their deadline
+//
+//	@Summary	List of opened disputes close to their deadline
+//	@Tags		disputes
+//	@Accept		json
+//	@Produce	json
+//	@Param		within	query		string	false	"window ahead of now, e.g. 48h, defaults to 72h"
+//	@Success	200		{array}		response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/disputes/due [get]
+func (h *DisputeHandler) due(w http.ResponseWriter, r *http.Request) {
+	req := dispute.DueRequest{}
+	if err := req.Bind(r); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.Payment.ListDueDisputes(r.Context(), req)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Open a dispute against the billing
+//
+//	@Summary	Open a dispute against the billing
+//	@Tags		disputes
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		dispute.Request	true	"body param"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	404		{object}	response.Object
+//	@Failure	409		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/disputes [post]
+func (h *DisputeHandler) add(w http.ResponseWriter, r *http.Request) {
+	req := dispute.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	res, err := h.Payment.OpenDispute(r.Context(), req)
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case billing.ErrInvalidStatus:
+		response.Conflict(w, r, err)
+	case dispute.ErrInvalidAmount:
+		response.BadRequest(w, r, err, req)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Read the dispute with its evidence
+//
+//	@Summary	Read the dispute with its evidence
+//	@Tags		disputes
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/disputes/{id} [get]
+func (h *DisputeHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Payment.GetDispute(r.Context(), id)
+	if err != nil && err != store.ErrorNotFound {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if err == store.ErrorNotFound {
+		response.NotFound(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Attach evidence to the opened dispute
+//
+//	@Summary	Attach evidence to the opened dispute
+//	@Tags		disputes
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string					true	"path param"
+//	@Param		request	body		dispute.EvidenceRequest	true	"body param"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	404		{object}	response.Object
+//	@Failure	409		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/disputes/{id}/evidence [post]
+func (h *DisputeHandler) addEvidence(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := dispute.EvidenceRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	res, err := h.Payment.AddDisputeEvidence(r.Context(), id, req)
+	h.respond(w, r, res, err)
+}
+
+// Submit the evidence of the dispute to the bank
+//
+//	@Summary	Submit the evidence of the dispute to the bank
+//	@Tags		disputes
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	409	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/disputes/{id}/submit [post]
+func (h *DisputeHandler) submit(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Payment.SubmitDispute(r.Context(), id)
+	h.respond(w, r, res, err)
+}
+
+// Record the outcome of the submitted dispute
+//
+//	@Summary	Record the outcome of the submitted dispute
+//	@Tags		disputes
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string					true	"path param"
+//	@Param		request	body		dispute.ResolveRequest	true	"body param"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	404		{object}	response.Object
+//	@Failure	409		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/disputes/{id}/resolve [post]
+func (h *DisputeHandler) resolve(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := dispute.ResolveRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	res, err := h.Payment.ResolveDispute(r.Context(), id, req)
+	h.respond(w, r, res, err)
+}
+
+// respond writes the dispute or maps the error of a dispute transition onto a status code.
+func (h *DisputeHandler) respond(w http.ResponseWriter, r *http.Request, res dispute.Response, err error) {
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case dispute.ErrInvalidStatus, dispute.ErrNoEvidence:
+		response.Conflict(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
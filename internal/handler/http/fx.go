@@ -0,0 +1,130 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"payment-service/internal/domain/fx"
+	"payment-service/internal/service/exchange"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"payment-service/pkg/server/response"
+	"payment-service/pkg/spreadsheet"
+)
+
+// maxRateFeedSize caps the size of an uploaded rate feed.
+const maxRateFeedSize = 4 << 20
+
+type FXHandler struct {
+	Exchange *exchange.Service
+}
+
+func NewFX(s *exchange.Service) *FXHandler {
+	return &FXHandler{Exchange: s}
+}
+
+func (h *FXHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/rates", h.list)
+	r.Post("/rates", h.add)
+	r.Post("/rates/refresh", h.refresh)
+
+	return r
+}
+
+// List of exchange rates to the base currency effective on the date
+//
+//	@Summary	List of exchange rates to the base currency effective on the date
+//	@Tags		fx
+//	@Accept		json
+//	@Produce	json
+//	@Param		date	query		string	false	"YYYY-MM-DD, defaults to today"
+//	@Success	200		{array}		response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/admin/fx/rates [get]
+func (h *FXHandler) list(w http.ResponseWriter, r *http.Request) {
+	req := fx.Request{}
+	if err := req.Bind(r); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.Exchange.ListRates(r.Context(), req)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Import exchange rates from a CSV feed
+//
+//	@Summary	Import exchange rates from a CSV feed
+//	@Tags		fx
+//	@Accept		text/csv
+//	@Produce	json
+//	@Param		request	body		string	true	"feed with currency, rate and optional date columns, rate is the price of one unit in the base currency"
+//	@Success	200		{array}		response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/admin/fx/rates [post]
+func (h *FXHandler) add(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), contentTypeCSV) {
+		response.BadRequest(w, r, errors.New("content type: must be "+contentTypeCSV), nil)
+		return
+	}
+
+	records, err := spreadsheet.ReadCSV(io.LimitReader(r.Body, maxRateFeedSize))
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.Exchange.ImportRates(r.Context(), records, "upload")
+	if parseErr := (*fx.ParseError)(nil); errors.As(err, &parseErr) {
+		response.BadRequest(w, r, err, parseErr.Rows)
+		return
+	}
+
+	if err == fx.ErrNoHeader {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Load the current exchange rates from the configured provider
+//
+//	@Summary	Load the current exchange rates from the configured provider
+//	@Tags		fx
+//	@Accept		json
+//	@Produce	json
+//	@Success	200	{array}		response.Object
+//	@Failure	409	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/admin/fx/rates/refresh [post]
+func (h *FXHandler) refresh(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Exchange.RefreshRates(r.Context())
+	if err == exchange.ErrNoProvider {
+		response.Conflict(w, r, err)
+		return
+	}
+
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
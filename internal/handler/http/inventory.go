@@ -0,0 +1,142 @@
+package http
+
+import (
+	"net/http"
+	"payment-service/internal/domain/inventory"
+	"payment-service/internal/service/stock"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"payment-service/internal/domain/apikey"
+	"payment-service/pkg/server/response"
+	"payment-service/pkg/server/router"
+	"payment-service/pkg/store"
+)
+
+type InventoryHandler struct {
+	stockService *stock.Service
+}
+
+func NewInventory(s *stock.Service) *InventoryHandler {
+	return &InventoryHandler{stockService: s}
+}
+
+func (h *InventoryHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(router.RequireScope())
+
+	write := r.With(router.RequireScope(apikey.ScopeInventoryWrite))
+
+	r.Get("/warehouses", h.listWarehouses)
+	write.Post("/warehouses", h.addWarehouse)
+
+	r.Get("/stock", h.listStock)
+	write.Put("/stock", h.setStock)
+
+	return r
+}
+
+// List of warehouses
+//
+//	@Summary	List of warehouses
+//	@Tags		inventory
+//	@Accept		json
+//	@Produce	json
+//	@Success	200						{array}		response.Object
+//	@Failure	500						{object}	response.Object
+//	@Router		/inventory/warehouses	[get]
+func (h *InventoryHandler) listWarehouses(w http.ResponseWriter, r *http.Request) {
+	res, err := h.stockService.ListWarehouses(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Add a new warehouse
+//
+//	@Summary	Add a new warehouse
+//	@Tags		inventory
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		inventory.WarehouseRequest	true	"body param"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	409		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/inventory/warehouses [post]
+func (h *InventoryHandler) addWarehouse(w http.ResponseWriter, r *http.Request) {
+	req := inventory.WarehouseRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	res, err := h.stockService.AddWarehouse(r.Context(), req)
+	if err != nil && err != store.ErrorAlreadyExists {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if err == store.ErrorAlreadyExists {
+		response.Conflict(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Stock levels per product and warehouse
+//
+//	@Summary	Stock levels per product and warehouse
+//	@Tags		inventory
+//	@Accept		json
+//	@Produce	json
+//	@Param		product_id	query		string	false	"product id, all products when empty"
+//	@Success	200			{array}		response.Object
+//	@Failure	500			{object}	response.Object
+//	@Router		/inventory/stock [get]
+func (h *InventoryHandler) listStock(w http.ResponseWriter, r *http.Request) {
+	res, err := h.stockService.ListStock(r.Context(), r.URL.Query().Get("product_id"))
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Set the quantity of a product on hand in a warehouse
+//
+//	@Summary	Set the quantity of a product on hand in a warehouse
+//	@Tags		inventory
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body	inventory.StockRequest	true	"body param"
+//	@Success	200
+//	@Failure	400	{object}	response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	409	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/inventory/stock [put]
+func (h *InventoryHandler) setStock(w http.ResponseWriter, r *http.Request) {
+	req := inventory.StockRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	err := h.stockService.SetStock(r.Context(), req)
+	switch err {
+	case nil:
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case inventory.ErrInsufficientStock:
+		response.Conflict(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"net/http"
+	"payment-service/internal/domain/ledger"
+	"payment-service/internal/service/accounting"
+
+	"github.com/go-chi/chi/v5"
+
+	"payment-service/pkg/server/response"
+	"payment-service/pkg/store"
+)
+
+type LedgerHandler struct {
+	Accounting *accounting.Service
+}
+
+func NewLedger(s *accounting.Service) *LedgerHandler {
+	return &LedgerHandler{Accounting: s}
+}
+
+func (h *LedgerHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/accounts", h.listAccounts)
+	r.Get("/accounts/{code}/balance", h.balance)
+	r.Get("/entries", h.listEntries)
+
+	return r
+}
+
+// List of ledger accounts
+//
+//	@Summary	List of ledger accounts
+//	@Tags		ledger
+//	@Accept		json
+//	@Produce	json
+//	@Success	200					{array}		response.Object
+//	@Failure	500					{object}	response.Object
+//	@Router		/ledger/accounts	[get]
+func (h *LedgerHandler) listAccounts(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Accounting.ListAccounts(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Balance of the ledger account in a currency over a period
+//
+//	@Summary	Balance of the ledger account in a currency over a period
+//	@Tags		ledger
+//	@Accept		json
+//	@Produce	json
+//	@Param		code		path		string	true	"account code, e.g. sales:{terminal_id}"
+//	@Param		currency	query		string	true	"currency"
+//	@Param		from		query		string	false	"period start, YYYY-MM-DD or RFC 3339"
+//	@Param		to			query		string	false	"period end (exclusive), YYYY-MM-DD or RFC 3339"
+//	@Success	200			{object}	response.Object
+//	@Failure	400			{object}	response.Object
+//	@Failure	404			{object}	response.Object
+//	@Failure	500			{object}	response.Object
+//	@Router		/ledger/accounts/{code}/balance [get]
+func (h *LedgerHandler) balance(w http.ResponseWriter, r *http.Request) {
+	code := chi.URLParam(r, "code")
+
+	req := ledger.BalanceRequest{}
+	if err := req.Bind(r); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.Accounting.GetBalance(r.Context(), code, req)
+	if err != nil && err != store.ErrorNotFound {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if err == store.ErrorNotFound {
+		response.NotFound(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// List of journal entries, optionally of one billing
+//
+//	@Summary	List of journal entries, optionally of one billing
+//	@Tags		ledger
+//	@Accept		json
+//	@Produce	json
+//	@Param		billing_id	query		string	false	"billing id"
+//	@Success	200			{array}		response.Object
+//	@Failure	500			{object}	response.Object
+//	@Router		/ledger/entries [get]
+func (h *LedgerHandler) listEntries(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Accounting.ListEntries(r.Context(), r.URL.Query().Get("billing_id"))
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"net/http"
+	"payment-service/internal/domain/inventory"
+	"payment-service/internal/domain/order"
+	"payment-service/internal/service/checkout"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"payment-service/internal/domain/apikey"
+	"payment-service/pkg/server/response"
+	"payment-service/pkg/server/router"
+	"payment-service/pkg/store"
+)
+
+type OrderHandler struct {
+	Checkout *checkout.Service
+}
+
+func NewOrder(s *checkout.Service) *OrderHandler {
+	return &OrderHandler{Checkout: s}
+}
+
+func (h *OrderHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(router.RequireScope())
+
+	create := r.With(router.RequireScope(apikey.ScopeOrderCreate))
+
+	r.Get("/", h.list)
+	create.Post("/", h.add)
+	r.Get("/{id}", h.get)
+
+	return r
+}
+
+// List of orders
+//
+//	@Summary	List of orders
+//	@Tags		orders
+//	@Accept		json
+//	@Produce	json
+//	@Success	200			{array}		response.Object
+//	@Failure	500			{object}	response.Object
+//	@Router		/orders		[get]
+func (h *OrderHandler) list(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Checkout.ListOrders(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Place an order of catalogue products and create its billing
+//
+//	@Summary	Place an order of catalogue products and create its billing
+//	@Tags		orders
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		order.Request	true	"body param"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	409		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/orders [post]
+func (h *OrderHandler) add(w http.ResponseWriter, r *http.Request) {
+	req := order.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	res, err := h.Checkout.PlaceOrder(r.Context(), req)
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case order.ErrProductNotFound, order.ErrPriceListNotFound, order.ErrProductNotPriced, order.ErrInvalidDiscount:
+		response.BadRequest(w, r, err, req)
+	case inventory.ErrInsufficientStock:
+		response.Conflict(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Read the order with its items
+//
+//	@Summary	Read the order with its items
+//	@Tags		orders
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/orders/{id} [get]
+func (h *OrderHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Checkout.GetOrder(r.Context(), id)
+	if err != nil && err != store.ErrorNotFound {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if err == store.ErrorNotFound {
+		response.NotFound(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
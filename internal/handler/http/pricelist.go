@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+	"payment-service/internal/domain/price"
+	"payment-service/internal/service/catalogue"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"payment-service/internal/domain/apikey"
+	"payment-service/pkg/server/response"
+	"payment-service/pkg/server/router"
+	"payment-service/pkg/store"
+)
+
+type PriceListHandler struct {
+	catalogueService *catalogue.Service
+}
+
+func NewPriceList(s *catalogue.Service) *PriceListHandler {
+	return &PriceListHandler{catalogueService: s}
+}
+
+func (h *PriceListHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(router.RequireScope())
+
+	write := r.With(router.RequireScope(apikey.ScopeCatalogueWrite))
+
+	r.Get("/", h.list)
+	write.Post("/", h.add)
+
+	return r
+}
+
+// List of price lists
+//
+//	@Summary	List of price lists
+//	@Tags		price-lists
+//	@Accept		json
+//	@Produce	json
+//	@Success	200				{array}		response.Object
+//	@Failure	500				{object}	response.Object
+//	@Router		/price-lists	[get]
+func (h *PriceListHandler) list(w http.ResponseWriter, r *http.Request) {
+	res, err := h.catalogueService.ListPriceLists(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Add a new price list
+//
+//	@Summary	Add a new price list
+//	@Tags		price-lists
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		price.ListRequest	true	"body param"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	409		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/price-lists [post]
+func (h *PriceListHandler) add(w http.ResponseWriter, r *http.Request) {
+	req := price.ListRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	res, err := h.catalogueService.AddPriceList(r.Context(), req)
+	if err != nil && err != store.ErrorAlreadyExists {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if err == store.ErrorAlreadyExists {
+		response.Conflict(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
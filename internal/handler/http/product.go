@@ -1,17 +1,45 @@
 package http
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"payment-service/internal/service/catalogue"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 
+	"payment-service/internal/domain/apikey"
+	"payment-service/internal/domain/category"
+	"payment-service/internal/domain/price"
 	"payment-service/internal/domain/product"
+	"payment-service/pkg/mergepatch"
 	"payment-service/pkg/server/response"
+	"payment-service/pkg/server/router"
+	"payment-service/pkg/spreadsheet"
 	"payment-service/pkg/store"
 )
 
+var (
+	errIfMatchRequired = errors.New("if-match: header is required")
+	errIfMatchInvalid  = errors.New("if-match: must be * or the quoted version of the ETag")
+)
+
+const (
+	contentTypeNDJSON = "application/x-ndjson"
+
+	maxImportSize = 64 << 20
+	// maxPatchSize caps a merge patch document
+	maxPatchSize = 1 << 20
+
+	// maxUploadSize caps a whole multipart upload, every file is checked against the image size limit too
+	maxUploadSize = 64 << 20
+	// imagesField is the multipart field the files are sent in, it may repeat
+	imagesField = "images"
+)
+
 type ProductHandler struct {
 	productService *catalogue.Service
 }
@@ -22,30 +50,75 @@ func NewProductHandler(s *catalogue.Service) *ProductHandler {
 
 func (h *ProductHandler) Routes() chi.Router {
 	r := chi.NewRouter()
+	r.Use(router.RequireScope())
+
+	write := r.With(router.RequireScope(apikey.ScopeCatalogueWrite))
 
 	r.Get("/", h.list)
-	r.Post("/", h.add)
+	write.Post("/", h.add)
+	r.Get("/barcode/{code}", h.getByBarcode)
+	write.Post("/import", h.importProducts)
+	r.Get("/imports/{id}", h.getImport)
+	r.Get("/export", h.exportProducts)
 
 	r.Route("/{id}", func(r chi.Router) {
+		write := r.With(router.RequireScope(apikey.ScopeCatalogueWrite))
+
 		r.Get("/", h.get)
-		r.Put("/", h.update)
-		r.Delete("/", h.delete)
+		write.Put("/", h.update)
+		write.Patch("/", h.patch)
+		write.Delete("/", h.delete)
+		write.Post("/restore", h.restore)
+
+		r.Get("/prices", h.listPrices)
+		write.Post("/prices", h.addPrice)
+		r.Get("/price", h.getPrice)
+
+		r.Get("/variants", h.listVariants)
+		write.Post("/variants", h.addVariant)
+
+		r.Get("/images", h.listImages)
+		write.Post("/images", h.addImages)
+		write.Put("/images/order", h.reorderImages)
+		write.Delete("/images/{imageID}", h.deleteImage)
 	})
 
 	return r
 }
 
-// List of products from the database
+// Search products from the database
 //
-//	@Summary	List of products from the database
+//	@Summary	Search products from the database
 //	@Tags		products
 //	@Accept		json
 //	@Produce	json
-//	@Success	200			{array}		response.Object
+//	@Param		q			query		string	false	"free text matched against the name, brand and description"
+//	@Param		category_id	query		string	false	"category id"
+//	@Param		country		query		string	false	"country of origin"
+//	@Param		brand		query		string	false	"brand"
+//	@Param		parent_id	query		string	false	"product id, matches its variants"
+//	@Param		attr.code	query		string	false	"attribute value, any attr.<code> parameter filters by that attribute, commas separate alternatives"
+//	@Param		deleted		query		bool	false	"searches the trash instead"
+//	@Param		sort		query		string	false	"relevance, name, -name, created_at or -created_at"
+//	@Param		cursor		query		string	false	"next_cursor of the previous page"
+//	@Param		limit		query		int		false	"page size from 1 to 100, defaults to 20"
+//	@Success	200			{object}	response.Object
+//	@Failure	400			{object}	response.Object
 //	@Failure	500			{object}	response.Object
 //	@Router		/products 	[get]
 func (h *ProductHandler) list(w http.ResponseWriter, r *http.Request) {
-	res, err := h.productService.ListProducts(r.Context())
+	req := product.SearchRequest{}
+	if err := req.Bind(r); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.productService.ListProducts(r.Context(), req)
+	if err == product.ErrInvalidCursor {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
 	if err != nil {
 		response.InternalServerError(w, r, err)
 		return
@@ -63,6 +136,7 @@ func (h *ProductHandler) list(w http.ResponseWriter, r *http.Request) {
 //	@Param		request	body		product.Request	true	"body param"
 //	@Success	200		{object}	response.Object
 //	@Failure	400		{object}	response.Object
+//	@Failure	409		{object}	response.Object
 //	@Failure	500		{object}	response.Object
 //	@Router		/products [post]
 func (h *ProductHandler) add(w http.ResponseWriter, r *http.Request) {
@@ -73,12 +147,126 @@ func (h *ProductHandler) add(w http.ResponseWriter, r *http.Request) {
 	}
 
 	res, err := h.productService.AddProduct(r.Context(), req)
+	switch {
+	case err == nil:
+		response.OK(w, r, res)
+	case isProductError(err):
+		response.BadRequest(w, r, err, req)
+	case err == store.ErrorAlreadyExists:
+		response.Conflict(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Read the product by its barcode
+//
+//	@Summary	Read the product by its barcode
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		code	path		string	true	"EAN-8, EAN-13 or UPC-A code"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	404		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/products/barcode/{code} [get]
+func (h *ProductHandler) getByBarcode(w http.ResponseWriter, r *http.Request) {
+	code := chi.URLParam(r, "code")
+
+	res, err := h.productService.GetProductByBarcode(r.Context(), code)
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case product.ErrInvalidBarcode:
+		response.BadRequest(w, r, err, nil)
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Import products from a CSV or NDJSON file, rows are upserted by barcode in the background
+//
+//	@Summary	Import products from a CSV or NDJSON file, rows are upserted by barcode in the background
+//	@Tags		products
+//	@Accept		text/csv
+//	@Accept		application/x-ndjson
+//	@Produce	json
+//	@Param		request	body		string	true	"file with category_id, name, description, measure, image_url, country, barcode and brand columns or fields"
+//	@Success	202		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/products/import [post]
+func (h *ProductHandler) importProducts(w http.ResponseWriter, r *http.Request) {
+	format, rows, rowErrs, err := readProducts(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.productService.ImportProducts(r.Context(), format, rows, rowErrs)
 	if err != nil {
 		response.InternalServerError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, res)
+	response.Accepted(w, r, res)
+}
+
+// Read the product import job with its row errors
+//
+//	@Summary	Read the product import job with its row errors
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/products/imports/{id} [get]
+func (h *ProductHandler) getImport(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.productService.GetImport(r.Context(), id)
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Export the catalogue as a CSV or NDJSON file
+//
+//	@Summary	Export the catalogue as a CSV or NDJSON file
+//	@Tags		products
+//	@Produce	text/csv
+//	@Produce	application/x-ndjson
+//	@Param		format	query		string	false	"csv or ndjson, defaults to csv"
+//	@Success	200		{string}	string
+//	@Failure	400		{object}	response.Object
+//	@Router		/products/export [get]
+func (h *ProductHandler) exportProducts(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+
+	switch format {
+	case "", product.FormatCSV:
+		format = product.FormatCSV
+		w.Header().Set("Content-Type", contentTypeCSV)
+	case product.FormatNDJSON:
+		w.Header().Set("Content-Type", contentTypeNDJSON)
+	default:
+		response.BadRequest(w, r, product.ErrUnknownFormat, nil)
+		return
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="products.`+format+`"`)
+
+	// the status is sent with the first page, a later failure can only cut the file short
+	h.productService.ExportProducts(r.Context(), format, w)
 }
 
 // Read the product from the database
@@ -89,6 +277,7 @@ func (h *ProductHandler) add(w http.ResponseWriter, r *http.Request) {
 //	@Produce	json
 //	@Param		id	path		string	true	"path param"
 //	@Success	200	{object}	response.Object
+//	@Header		200	{string}	ETag	"quoted version of the product"
 //	@Failure	404	{object}	response.Object
 //	@Failure	500	{object}	response.Object
 //	@Router		/products/{id} [get]
@@ -106,6 +295,7 @@ func (h *ProductHandler) get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, res.Version)
 	response.OK(w, r, res)
 }
 
@@ -115,49 +305,190 @@ func (h *ProductHandler) get(w http.ResponseWriter, r *http.Request) {
 //	@Tags		products
 //	@Accept		json
 //	@Produce	json
-//	@Param		id		path	string			true	"path param"
-//	@Param		request	body	product.Request	true	"body param"
+//	@Param		id			path	string			true	"path param"
+//	@Param		If-Match	header	string			true	"ETag of the product, * skips the check"
+//	@Param		request		body	product.Request	true	"body param"
 //	@Success	200
 //	@Failure	400	{object}	response.Object
 //	@Failure	404	{object}	response.Object
+//	@Failure	409	{object}	response.Object
+//	@Failure	412	{object}	response.Object
+//	@Failure	428	{object}	response.Object
 //	@Failure	500	{object}	response.Object
 //	@Router		/products/{id} [put]
 func (h *ProductHandler) update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	version, err := ifMatch(r)
+	if err != nil {
+		writePrecondition(w, r, err)
+		return
+	}
+
 	req := product.Request{}
 	if err := render.Bind(r, &req); err != nil {
 		response.BadRequest(w, r, err, req)
 		return
 	}
 
-	err := h.productService.UpdateProduct(r.Context(), id, req)
-	if err != nil && err != store.ErrorNotFound {
+	err = h.productService.UpdateProduct(r.Context(), id, version, req)
+	switch {
+	case err == nil:
+		// with * the new version is not known
+		if version != 0 {
+			setETag(w, version+1)
+		}
+	case err == store.ErrorStaleVersion:
+		response.PreconditionFailed(w, r, err)
+	case isProductError(err):
+		response.BadRequest(w, r, err, req)
+	case err == store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case err == store.ErrorAlreadyExists:
+		response.Conflict(w, r, err)
+	default:
 		response.InternalServerError(w, r, err)
+	}
+}
+
+// Change the fields of the product named in a JSON merge patch
+//
+//	@Summary	Change the fields of the product named in a JSON merge patch
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id			path		string			true	"path param"
+//	@Param		If-Match	header		string			true	"ETag of the product, * skips the check"
+//	@Param		request		body		product.Request	true	"body param, a field set to null is removed"
+//	@Success	200			{object}	product.Response
+//	@Failure	400			{object}	response.Object
+//	@Failure	404			{object}	response.Object
+//	@Failure	409			{object}	response.Object
+//	@Failure	412			{object}	response.Object
+//	@Failure	428			{object}	response.Object
+//	@Failure	500			{object}	response.Object
+//	@Router		/products/{id} [patch]
+func (h *ProductHandler) patch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	version, err := ifMatch(r)
+	if err != nil {
+		writePrecondition(w, r, err)
 		return
 	}
 
-	if err == store.ErrorNotFound {
-		response.NotFound(w, r, err)
+	patch, err := readPatch(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
 		return
 	}
+
+	res, err := h.productService.PatchProduct(r.Context(), id, version, patch)
+	switch {
+	case err == nil:
+		setETag(w, res.Version)
+		response.OK(w, r, res)
+	case err == store.ErrorStaleVersion:
+		response.PreconditionFailed(w, r, err)
+	case isPatchError(err), isProductError(err):
+		response.BadRequest(w, r, err, nil)
+	case err == store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case err == store.ErrorAlreadyExists:
+		response.Conflict(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
 }
 
-// Delete the product from the database
+// Move the product with its variants to the trash
 //
-//	@Summary	Delete the product from the database
+//	@Summary	Move the product with its variants to the trash
 //	@Tags		products
 //	@Accept		json
 //	@Produce	json
-//	@Param		id	path	string	true	"path param"
+//	@Param		id			path	string	true	"path param"
+//	@Param		If-Match	header	string	true	"ETag of the product, * skips the check"
 //	@Success	200
+//	@Failure	400	{object}	response.Object
 //	@Failure	404	{object}	response.Object
+//	@Failure	412	{object}	response.Object
+//	@Failure	428	{object}	response.Object
 //	@Failure	500	{object}	response.Object
 //	@Router		/products/{id} [delete]
 func (h *ProductHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	err := h.productService.DeleteProduct(r.Context(), id)
+	version, err := ifMatch(r)
+	if err != nil {
+		writePrecondition(w, r, err)
+		return
+	}
+
+	err = h.productService.DeleteProduct(r.Context(), id, version)
+	switch {
+	case err == nil:
+	case err == store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case err == store.ErrorStaleVersion:
+		response.PreconditionFailed(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Take the product with its variants out of the trash
+//
+//	@Summary	Take the product with its variants out of the trash
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	product.Response
+//	@Failure	404	{object}	response.Object
+//	@Failure	409	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/products/{id}/restore [post]
+func (h *ProductHandler) restore(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.productService.RestoreProduct(r.Context(), id)
+	switch err {
+	case nil:
+		setETag(w, res.Version)
+		response.OK(w, r, res)
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	case product.ErrCategoryNotFound, product.ErrParentNotFound, store.ErrorAlreadyExists:
+		response.Conflict(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Price history of the product
+//
+//	@Summary	Price history of the product
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id			path		string	true	"path param"
+//	@Param		list		query		string	false	"price list id or code"
+//	@Param		currency	query		string	false	"currency code"
+//	@Success	200			{array}		response.Object
+//	@Failure	404			{object}	response.Object
+//	@Failure	500			{object}	response.Object
+//	@Router		/products/{id}/prices [get]
+func (h *ProductHandler) listPrices(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := price.HistoryRequest{}
+	if err := req.Bind(r); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.productService.ListProductPrices(r.Context(), id, req)
 	if err != nil && err != store.ErrorNotFound {
 		response.InternalServerError(w, r, err)
 		return
@@ -167,4 +498,387 @@ func (h *ProductHandler) delete(w http.ResponseWriter, r *http.Request) {
 		response.NotFound(w, r, err)
 		return
 	}
+
+	response.OK(w, r, res)
+}
+
+// Set a new price of the product in a price list
+//
+//	@Summary	Set a new price of the product in a price list
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string			true	"path param"
+//	@Param		request	body		price.Request	true	"body param"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	404		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/products/{id}/prices [post]
+func (h *ProductHandler) addPrice(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := price.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	res, err := h.productService.SetProductPrice(r.Context(), id, req)
+	if err != nil && err != store.ErrorNotFound {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if err == store.ErrorNotFound {
+		response.NotFound(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Effective price of the product in a price list on a date
+//
+//	@Summary	Effective price of the product in a price list on a date
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id			path		string	true	"path param"
+//	@Param		list		query		string	true	"price list id or code"
+//	@Param		currency	query		string	true	"currency code"
+//	@Param		date		query		string	false	"YYYY-MM-DD or RFC 3339, defaults to now"
+//	@Success	200			{object}	response.Object
+//	@Failure	400			{object}	response.Object
+//	@Failure	404			{object}	response.Object
+//	@Failure	500			{object}	response.Object
+//	@Router		/products/{id}/price [get]
+func (h *ProductHandler) getPrice(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := price.EffectiveRequest{}
+	if err := req.Bind(r); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.productService.GetEffectivePrice(r.Context(), id, req)
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case store.ErrorNotFound, price.ErrPriceNotFound:
+		response.NotFound(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// readProducts parses the request body as CSV or NDJSON depending on its content type.
+func readProducts(r *http.Request) (format string, rows []product.Row, rowErrs []product.RowError, err error) {
+	body := io.LimitReader(r.Body, maxImportSize)
+
+	switch contentType := r.Header.Get("Content-Type"); {
+	case strings.HasPrefix(contentType, contentTypeCSV):
+		var records [][]string
+		if records, err = spreadsheet.ReadCSV(body); err != nil {
+			return
+		}
+
+		rows, rowErrs, err = product.ParseCSV(records)
+		return product.FormatCSV, rows, rowErrs, err
+	case strings.HasPrefix(contentType, contentTypeNDJSON):
+		rows, rowErrs, err = product.ParseNDJSON(body)
+		return product.FormatNDJSON, rows, rowErrs, err
+	default:
+		err = errors.New("content type: must be " + contentTypeCSV + " or " + contentTypeNDJSON)
+		return
+	}
+}
+
+// readPatch reads a JSON merge patch document from the request body.
+func readPatch(r *http.Request) (patch []byte, err error) {
+	if patch, err = io.ReadAll(io.LimitReader(r.Body, maxPatchSize+1)); err != nil {
+		return
+	}
+
+	if len(patch) > maxPatchSize {
+		err = errors.New("patch: must not exceed 1 MB")
+	}
+	return
+}
+
+func isPatchError(err error) bool {
+	patchErr := (*mergepatch.Error)(nil)
+	return errors.As(err, &patchErr)
+}
+
+// ifMatch reads the version a write expects from the If-Match header, "*" matches any version and reads as 0.
+func ifMatch(r *http.Request) (version int, err error) {
+	value := r.Header.Get("If-Match")
+	switch value {
+	case "":
+		return 0, errIfMatchRequired
+	case "*":
+		return 0, nil
+	}
+
+	value = strings.TrimPrefix(value, "W/")
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, errIfMatchInvalid
+	}
+
+	if version, err = strconv.Atoi(value[1 : len(value)-1]); err != nil || version < 1 {
+		return 0, errIfMatchInvalid
+	}
+	return
+}
+
+// writePrecondition answers a failed ifMatch: a missing header is 428, a malformed one is 400.
+func writePrecondition(w http.ResponseWriter, r *http.Request, err error) {
+	if err == errIfMatchRequired {
+		response.PreconditionRequired(w, r, err)
+		return
+	}
+	response.BadRequest(w, r, err, nil)
+}
+
+// setETag tags the response with the version of the entity, a write sends it back in If-Match.
+func setETag(w http.ResponseWriter, version int) {
+	if version > 0 {
+		w.Header().Set("ETag", `"`+strconv.Itoa(version)+`"`)
+	}
+}
+
+// Variants of the product in the order they were added
+//
+//	@Summary	Variants of the product in the order they were added
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{array}		response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/products/{id}/variants [get]
+func (h *ProductHandler) listVariants(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.productService.ListVariants(r.Context(), id)
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Add a variant of the product with a barcode of its own, it takes the category and attribute values of the product
+//
+//	@Summary	Add a variant of the product with a barcode of its own, it takes the category and attribute values of the product
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string			true	"path param"
+//	@Param		request	body		product.Request	true	"body param, category_id may be left out"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	404		{object}	response.Object
+//	@Failure	409		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/products/{id}/variants [post]
+func (h *ProductHandler) addVariant(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := product.Request{ParentID: id}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	res, err := h.productService.AddVariant(r.Context(), id, req)
+	switch {
+	case err == nil:
+		response.OK(w, r, res)
+	case err == product.ErrParentNotFound:
+		response.NotFound(w, r, err)
+	case isProductError(err):
+		response.BadRequest(w, r, err, req)
+	case err == store.ErrorAlreadyExists:
+		response.Conflict(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Images of the product in their order
+//
+//	@Summary	Images of the product in their order
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{array}		response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/products/{id}/images [get]
+func (h *ProductHandler) listImages(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.productService.ListProductImages(r.Context(), id)
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Upload images of the product, they are put after the images it already has
+//
+//	@Summary	Upload images of the product, they are put after the images it already has
+//	@Tags		products
+//	@Accept		multipart/form-data
+//	@Produce	json
+//	@Param		id		path		string	true	"path param"
+//	@Param		images	formData	file	true	"JPEG, PNG or GIF picture, the field may repeat"
+//	@Success	200		{array}		response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	404		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/products/{id}/images [post]
+func (h *ProductHandler) addImages(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	uploads, err := readImages(w, r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.productService.AddProductImages(r.Context(), id, uploads)
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case product.ErrUnsupportedImage, product.ErrImageTooLarge:
+		response.BadRequest(w, r, err, nil)
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Put the images of the product in a new order
+//
+//	@Summary	Put the images of the product in a new order
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string					true	"path param"
+//	@Param		request	body		product.OrderRequest	true	"body param"
+//	@Success	200		{array}		response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	404		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/products/{id}/images/order [put]
+func (h *ProductHandler) reorderImages(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := product.OrderRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	res, err := h.productService.ReorderProductImages(r.Context(), id, req)
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case product.ErrImageOrder:
+		response.BadRequest(w, r, err, req)
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Delete the image of the product with its files
+//
+//	@Summary	Delete the image of the product with its files
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path	string	true	"path param"
+//	@Param		imageID	path	string	true	"path param"
+//	@Success	200
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/products/{id}/images/{imageID} [delete]
+func (h *ProductHandler) deleteImage(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	imageID := chi.URLParam(r, "imageID")
+
+	err := h.productService.DeleteProductImage(r.Context(), id, imageID)
+	switch err {
+	case nil:
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// readImages reads the files of the images field of a multipart request.
+func readImages(w http.ResponseWriter, r *http.Request) (uploads []product.Upload, err error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if part.FormName() != imagesField || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		data, err := io.ReadAll(part)
+		part.Close()
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, product.Upload{Name: part.FileName(), Data: data})
+	}
+
+	if len(uploads) == 0 {
+		return nil, errors.New(imagesField + ": cannot be blank")
+	}
+
+	return
+}
+
+// isProductError tells whether the product was refused for its category, parent or attribute values.
+func isProductError(err error) bool {
+	switch err {
+	case product.ErrCategoryNotFound, product.ErrParentNotFound, product.ErrNestedVariant, product.ErrVariantCategory,
+		product.ErrReadOnly, product.ErrHasVariants:
+		return true
+	}
+
+	attributeErr := (*category.AttributeError)(nil)
+	return errors.As(err, &attributeErr)
 }
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"payment-service/internal/domain/settlement"
+	"payment-service/internal/service/reconciliation"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"payment-service/pkg/server/response"
+	"payment-service/pkg/spreadsheet"
+	"payment-service/pkg/store"
+)
+
+const (
+	contentTypeCSV  = "text/csv"
+	contentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	maxStatementSize = 32 << 20
+)
+
+type SettlementHandler struct {
+	Reconciliation *reconciliation.Service
+}
+
+func NewSettlement(s *reconciliation.Service) *SettlementHandler {
+	return &SettlementHandler{Reconciliation: s}
+}
+
+func (h *SettlementHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Post("/", h.add)
+	r.Get("/{id}", h.get)
+
+	return r
+}
+
+// List of imported settlement statements
+//
+//	@Summary	List of imported settlement statements
+//	@Tags		settlements
+//	@Accept		json
+//	@Produce	json
+//	@Success	200						{array}		response.Object
+//	@Failure	500						{object}	response.Object
+//	@Router		/admin/settlements		[get]
+func (h *SettlementHandler) list(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Reconciliation.ListStatements(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Import a daily settlement statement and reconcile it with billings
+//
+//	@Summary	Import a daily settlement statement and reconcile it with billings
+//	@Tags		settlements
+//	@Accept		text/csv
+//	@Accept		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Produce	json
+//	@Param		date		query		string	true	"statement date, YYYY-MM-DD"
+//	@Param		filename	query		string	false	"original file name"
+//	@Param		request		body		string	true	"statement with reference, int_reference, invoice_id, amount and fee columns"
+//	@Success	200			{object}	response.Object
+//	@Failure	400			{object}	response.Object
+//	@Failure	500			{object}	response.Object
+//	@Router		/admin/settlements [post]
+func (h *SettlementHandler) add(w http.ResponseWriter, r *http.Request) {
+	req := settlement.Request{}
+	if err := req.Bind(r); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	records, err := readStatement(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.Reconciliation.ImportStatement(r.Context(), req, records)
+	if parseErr := (*settlement.ParseError)(nil); errors.As(err, &parseErr) {
+		response.BadRequest(w, r, err, parseErr.Rows)
+		return
+	}
+
+	if err == settlement.ErrNoHeader {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Read the settlement statement with its discrepancy report
+//
+//	@Summary	Read the settlement statement with its discrepancy report
+//	@Tags		settlements
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/admin/settlements/{id} [get]
+func (h *SettlementHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Reconciliation.GetStatement(r.Context(), id)
+	if err != nil && err != store.ErrorNotFound {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if err == store.ErrorNotFound {
+		response.NotFound(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// readStatement reads the request body as CSV or XLSX depending on its content type.
+func readStatement(r *http.Request) (records [][]string, err error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxStatementSize))
+	if err != nil {
+		return
+	}
+
+	switch contentType := r.Header.Get("Content-Type"); {
+	case strings.HasPrefix(contentType, contentTypeXLSX):
+		return spreadsheet.ReadXLSX(data)
+	case strings.HasPrefix(contentType, contentTypeCSV):
+		return spreadsheet.ReadCSV(bytes.NewReader(data))
+	default:
+		return nil, errors.New("content type: must be " + contentTypeCSV + " or " + contentTypeXLSX)
+	}
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"payment-service/internal/domain/subject"
+	"payment-service/internal/service/compliance"
+	"payment-service/pkg/server/response"
+)
+
+type SubjectHandler struct {
+	Compliance *compliance.Service
+}
+
+func NewSubject(s *compliance.Service) *SubjectHandler {
+	return &SubjectHandler{Compliance: s}
+}
+
+func (h *SubjectHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/export", h.export)
+	r.Post("/erase", h.erase)
+
+	return r
+}
+
+// Export the personal data of the data subject
+//
+//	@Summary	Export the personal data of the data subject
+//	@Tags		subjects
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		subject.Request	true	"body param"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	401		{object}	response.Object
+//	@Failure	403		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/admin/subjects/export [post]
+func (h *SubjectHandler) export(w http.ResponseWriter, r *http.Request) {
+	req := subject.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.Compliance.ExportSubject(r.Context(), req)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Erase the personal data of the data subject
+//
+//	@Summary	Erase the personal data of the data subject
+//	@Tags		subjects
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		subject.Request	true	"body param"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	401		{object}	response.Object
+//	@Failure	403		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/admin/subjects/erase [post]
+func (h *SubjectHandler) erase(w http.ResponseWriter, r *http.Request) {
+	req := subject.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	res, err := h.Compliance.EraseSubject(r.Context(), req)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"payment-service/internal/domain/tenant"
+	"payment-service/internal/service/access"
+	"payment-service/pkg/server/response"
+	"payment-service/pkg/store"
+)
+
+type TenantHandler struct {
+	Access *access.Service
+}
+
+func NewTenant(s *access.Service) *TenantHandler {
+	return &TenantHandler{Access: s}
+}
+
+func (h *TenantHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Put("/", h.save)
+	})
+
+	return r
+}
+
+// List of the tenants
+//
+//	@Summary	List of the tenants
+//	@Tags		tenants
+//	@Accept		json
+//	@Produce	json
+//	@Success	200	{array}		response.Object
+//	@Failure	401	{object}	response.Object
+//	@Failure	403	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/admin/tenants [get]
+func (h *TenantHandler) list(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Access.ListTenants(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
+
+// Read the settings of the tenant
+//
+//	@Summary	Read the settings of the tenant
+//	@Tags		tenants
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	response.Object
+//	@Failure	401	{object}	response.Object
+//	@Failure	403	{object}	response.Object
+//	@Failure	404	{object}	response.Object
+//	@Failure	500	{object}	response.Object
+//	@Router		/admin/tenants/{id} [get]
+func (h *TenantHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Access.GetTenant(r.Context(), id)
+	switch err {
+	case nil:
+		response.OK(w, r, res)
+	case store.ErrorNotFound:
+		response.NotFound(w, r, err)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// Create the tenant or replace its settings
+//
+//	@Summary	Create the tenant or replace its settings
+//	@Tags		tenants
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string			true	"path param"
+//	@Param		request	body		tenant.Request	true	"body param"
+//	@Success	200		{object}	response.Object
+//	@Failure	400		{object}	response.Object
+//	@Failure	401		{object}	response.Object
+//	@Failure	403		{object}	response.Object
+//	@Failure	500		{object}	response.Object
+//	@Router		/admin/tenants/{id} [put]
+func (h *TenantHandler) save(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := tenant.ValidateID(id); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	req := tenant.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	res, err := h.Access.SaveTenant(r.Context(), id, req)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, res)
+}
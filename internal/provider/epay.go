@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+
+	"payment-service/pkg/epay"
+)
+
+// EPay confirms the post links of ePay by reading the status of their invoice back from it, and refunds the invoices.
+type EPay struct {
+	client *epay.Client
+}
+
+func NewEPay(client *epay.Client) *EPay {
+	return &EPay{client: client}
+}
+
+// CheckInvoice reads the invoice through the client, which bounds the request with its own timeout.
+func (p *EPay) CheckInvoice(_ context.Context, invoiceID string) (dest epay.Transaction, err error) {
+	data, err := p.client.CheckStatus(invoiceID)
+	if err != nil {
+		return
+	}
+
+	return *data, nil
+}
+
+// RefundInvoice refunds the transaction the invoice was paid with, ePay refunds transactions rather than invoices.
+func (p *EPay) RefundInvoice(_ context.Context, invoiceID string, amount decimal.Decimal) (err error) {
+	data, err := p.client.CheckStatus(invoiceID)
+	if err != nil {
+		return
+	}
+
+	return p.client.Refund(data.ID, amount)
+}
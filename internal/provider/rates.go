@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"payment-service/internal/domain/fx"
+	"payment-service/pkg/spreadsheet"
+)
+
+// maxFeedSize caps the size of a rate feed response.
+const maxFeedSize = 4 << 20
+
+// RatesAPI fetches rates from a JSON API answering with {"base": "KZT", "date": "2006-01-02", "rates": {"USD": 0.0021}},
+// where every rate is the amount of the currency one unit of the base buys. The base is passed in the base query parameter.
+type RatesAPI struct {
+	client *http.Client
+	url    string
+}
+
+func NewRatesAPI(url string) *RatesAPI {
+	return &RatesAPI{
+		client: &http.Client{Timeout: 10 * time.Second},
+		url:    url,
+	}
+}
+
+func (p *RatesAPI) Fetch(ctx context.Context, base string) (dest []fx.Rate, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return
+	}
+
+	query := req.URL.Query()
+	query.Set("base", base)
+	req.URL.RawQuery = query.Encode()
+
+	body, err := get(p.client, req)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	var res struct {
+		Base  string                     `json:"base"`
+		Date  string                     `json:"date"`
+		Rates map[string]decimal.Decimal `json:"rates"`
+	}
+	if err = json.NewDecoder(io.LimitReader(body, maxFeedSize)).Decode(&res); err != nil {
+		return
+	}
+
+	if fx.NormalizeCurrency(res.Base) != base {
+		return nil, fmt.Errorf("fx: provider answered with base %q instead of %q", res.Base, base)
+	}
+
+	date := time.Now()
+	if res.Date != "" {
+		if date, err = time.Parse("2006-01-02", res.Date); err != nil {
+			return
+		}
+	}
+
+	for currency, perBase := range res.Rates {
+		if !perBase.IsPositive() || fx.NormalizeCurrency(currency) == base {
+			continue
+		}
+
+		rate := fx.Quote(currency, base, perBase, date)
+		rate.Source = p.url
+		dest = append(dest, rate)
+	}
+
+	return
+}
+
+// RatesFeed reads a CSV feed with currency, rate and optional date columns from a URL or a local file,
+// where every rate is the price of one unit of the currency in the base currency.
+type RatesFeed struct {
+	client   *http.Client
+	location string
+}
+
+func NewRatesFeed(location string) *RatesFeed {
+	return &RatesFeed{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		location: location,
+	}
+}
+
+func (p *RatesFeed) Fetch(ctx context.Context, base string) (dest []fx.Rate, err error) {
+	var body io.ReadCloser
+	if strings.HasPrefix(p.location, "http://") || strings.HasPrefix(p.location, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.location, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if body, err = get(p.client, req); err != nil {
+			return nil, err
+		}
+	} else {
+		if body, err = os.Open(p.location); err != nil {
+			return
+		}
+	}
+	defer body.Close()
+
+	records, err := spreadsheet.ReadCSV(io.LimitReader(body, maxFeedSize))
+	if err != nil {
+		return
+	}
+
+	if dest, err = fx.ParseRates(records, base, time.Now()); err != nil {
+		return
+	}
+
+	for i := range dest {
+		dest[i].Source = p.location
+	}
+
+	return
+}
+
+// get sends the request and returns the body of a successful response.
+func get(client *http.Client, req *http.Request) (body io.ReadCloser, err error) {
+	res, err := client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("fx: provider responded with status %d", res.StatusCode)
+	}
+
+	return res.Body, nil
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRatesAPI(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("base") {
+		case "KZT":
+			w.Write([]byte(`{"base": "kzt", "date": "2024-03-15", "rates": {"USD": 0.002, "EUR": 0.0025, "KZT": 1, "XXX": 0}}`))
+		case "USD":
+			w.Write([]byte(`{"base": "EUR", "rates": {"KZT": 500}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("rates", func(t *testing.T) {
+		got, err := NewRatesAPI(server.URL).Fetch(ctx, "KZT")
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Slice(got, func(i, j int) bool { return got[i].Currency < got[j].Currency })
+
+		// every rate is turned into the price of the currency in the base, the base itself and zeros are left out
+		want := map[string]string{"EUR": "400", "USD": "500"}
+		if len(got) != len(want) {
+			t.Fatalf("got %+v, want %v", got, want)
+		}
+		for _, rate := range got {
+			if !rate.Rate.Equal(decimal.RequireFromString(want[rate.Currency])) || rate.Base != "KZT" ||
+				!rate.Date.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) || rate.Source != server.URL {
+				t.Errorf("got %+v, want %s of %s on 2024-03-15", rate, rate.Currency, want[rate.Currency])
+			}
+		}
+	})
+
+	t.Run("other base", func(t *testing.T) {
+		if _, err := NewRatesAPI(server.URL).Fetch(ctx, "USD"); err == nil {
+			t.Error("err = nil, want the base the provider answered with rejected")
+		}
+	})
+
+	t.Run("status", func(t *testing.T) {
+		if _, err := NewRatesAPI(server.URL).Fetch(ctx, "RUB"); err == nil {
+			t.Error("err = nil, want the status rejected")
+		}
+	})
+}
+
+func TestRatesFeed(t *testing.T) {
+	ctx := context.Background()
+
+	location := filepath.Join(t.TempDir(), "rates.csv")
+	if err := os.WriteFile(location, []byte("currency;rate\nUSD;449,5\neur;488.25\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewRatesFeed(location).Fetch(ctx, "KZT")
+	if err != nil || len(got) != 2 {
+		t.Fatalf("got %+v, err = %v, want two rates", got, err)
+	}
+
+	if got[0].Currency != "USD" || got[0].Rate.String() != "449.5" || got[1].Currency != "EUR" || got[1].Source != location {
+		t.Errorf("got %+v, want USD of 449.5 and EUR read from %s", got, location)
+	}
+
+	if _, err = NewRatesFeed(filepath.Join(t.TempDir(), "missing.csv")).Fetch(ctx, "KZT"); err == nil {
+		t.Error("err = nil, want the missing feed reported")
+	}
+}
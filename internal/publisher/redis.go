@@ -0,0 +1,40 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/redis/go-redis/v9"
+
+	"payment-service/internal/domain/outbox"
+)
+
+// RedisStream appends events to a Redis stream, the message is stored in the "message" field
+// and the dedup key is duplicated in its own field, so consumers can check it without decoding.
+type RedisStream struct {
+	client *redis.Client
+	stream string
+}
+
+func NewRedisStream(client *redis.Client, stream string) *RedisStream {
+	return &RedisStream{
+		client: client,
+		stream: stream,
+	}
+}
+
+func (p *RedisStream) Publish(ctx context.Context, event outbox.Entity) (err error) {
+	message, err := json.Marshal(NewMessage(event))
+	if err != nil {
+		return
+	}
+
+	return p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		Values: map[string]any{
+			"type":      event.EventType,
+			"dedup_key": event.DedupKey,
+			"message":   string(message),
+		},
+	}).Err()
+}
@@ -0,0 +1,48 @@
+package publisher
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"payment-service/internal/domain/outbox"
+	"payment-service/internal/domain/tenant"
+	"payment-service/pkg/store"
+)
+
+// TenantWebhook posts events to the webhook URLs in the settings of the tenant of the event, signed with
+// the secret of the tenant. Events of no tenant, or of a tenant without URLs, are skipped.
+type TenantWebhook struct {
+	client           *http.Client
+	tenantRepository tenant.Repository
+}
+
+func NewTenantWebhook(tenantRepository tenant.Repository) *TenantWebhook {
+	return &TenantWebhook{
+		client:           &http.Client{Timeout: 10 * time.Second},
+		tenantRepository: tenantRepository,
+	}
+}
+
+func (p *TenantWebhook) Publish(ctx context.Context, event outbox.Entity) (err error) {
+	if event.TenantID == "" {
+		return
+	}
+
+	settings, err := p.tenantRepository.Get(ctx, event.TenantID)
+	if err == store.ErrorNotFound {
+		return nil
+	}
+	if err != nil {
+		return
+	}
+
+	// every URL is posted again on a retry, consumers deduplicate by the Idempotency-Key
+	for _, url := range settings.WebhookURLs {
+		if err = post(ctx, p.client, url, settings.WebhookSecret, event); err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,92 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"payment-service/internal/domain/outbox"
+)
+
+// Message is the envelope every publisher delivers.
+type Message struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Aggregate   string          `json:"aggregate"`
+	AggregateID string          `json:"aggregate_id"`
+	DedupKey    string          `json:"dedup_key"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+func NewMessage(event outbox.Entity) Message {
+	return Message{
+		ID:          event.ID,
+		Type:        event.EventType,
+		Aggregate:   event.Aggregate,
+		AggregateID: event.AggregateID,
+		DedupKey:    event.DedupKey,
+		CreatedAt:   event.CreatedAt,
+		Payload:     event.Payload,
+	}
+}
+
+// Webhook posts events to an HTTP endpoint. When a secret is set, the body is signed
+// with HMAC-SHA256 and the signature is sent in the X-Signature header.
+type Webhook struct {
+	client *http.Client
+	url    string
+	secret string
+}
+
+func NewWebhook(url, secret string) *Webhook {
+	return &Webhook{
+		client: &http.Client{Timeout: 10 * time.Second},
+		url:    url,
+		secret: secret,
+	}
+}
+
+func (p *Webhook) Publish(ctx context.Context, event outbox.Entity) (err error) {
+	return post(ctx, p.client, p.url, p.secret, event)
+}
+
+// post delivers the event to the url, signing the body when the secret is set.
+func post(ctx context.Context, client *http.Client, url, secret string, event outbox.Entity) (err error) {
+	body, err := json.Marshal(NewMessage(event))
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", event.DedupKey)
+	req.Header.Set("X-Event-Type", event.EventType)
+
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		err = fmt.Errorf("webhook: unexpected status %d", res.StatusCode)
+	}
+
+	return
+}
@@ -0,0 +1,86 @@
+package audited
+
+import (
+	"context"
+
+	"payment-service/internal/domain/audit"
+	"payment-service/internal/domain/billing"
+	"payment-service/pkg/pii"
+)
+
+type billingRepository struct {
+	billing.Repository
+	recorder *Recorder
+}
+
+// NewBillingRepository records the writes of the billings made through the repository, status changes included.
+func NewBillingRepository(repository billing.Repository, recorder *Recorder) billing.Repository {
+	return &billingRepository{
+		Repository: repository,
+		recorder:   recorder,
+	}
+}
+
+func (r *billingRepository) Create(ctx context.Context, data billing.Entity) (id string, err error) {
+	err = r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		if id, err = r.Repository.Create(ctx, data); err != nil {
+			return
+		}
+
+		after, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityBilling, id, audit.ActionCreate, nil, masked(after))
+	})
+
+	return
+}
+
+func (r *billingRepository) Update(ctx context.Context, id string, data billing.Entity) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		before, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if err = r.Repository.Update(ctx, id, data); err != nil {
+			return
+		}
+
+		after, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityBilling, id, audit.ActionUpdate, masked(before), masked(after))
+	})
+}
+
+func (r *billingRepository) Delete(ctx context.Context, id string, version int) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		before, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if err = r.Repository.Delete(ctx, id, version); err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityBilling, id, audit.ActionDelete, masked(before), nil)
+	})
+}
+
+// masked keeps the personal data of the payer out of the audit trail, a change of it still shows as a change
+// of the masked value. The subject keys are left out, they would tell the payer of the billing apart.
+func masked(data billing.Entity) billing.Entity {
+	data.SubjectKeys = nil
+	data.Name = pii.MaskName(data.Name)
+	data.AccountID = pii.Mask(data.AccountID)
+	data.Email = pii.MaskEmail(data.Email)
+	data.Phone = pii.MaskPhone(data.Phone)
+
+	return data
+}
@@ -0,0 +1,199 @@
+package audited
+
+import (
+	"context"
+	"time"
+
+	"payment-service/internal/domain/audit"
+	"payment-service/internal/domain/category"
+	"payment-service/pkg/store"
+)
+
+type categoryRepository struct {
+	category.Repository
+	recorder *Recorder
+}
+
+// NewCategoryRepository records the writes of the categories made through the repository.
+func NewCategoryRepository(repository category.Repository, recorder *Recorder) category.Repository {
+	return &categoryRepository{
+		Repository: repository,
+		recorder:   recorder,
+	}
+}
+
+func (r *categoryRepository) Create(ctx context.Context, data category.Entity) (id string, err error) {
+	err = r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		if id, err = r.Repository.Create(ctx, data); err != nil {
+			return
+		}
+
+		after, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityCategory, id, audit.ActionCreate, nil, after)
+	})
+
+	return
+}
+
+func (r *categoryRepository) Update(ctx context.Context, id string, data category.Entity) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		before, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if err = r.Repository.Update(ctx, id, data); err != nil {
+			return
+		}
+
+		after, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityCategory, id, audit.ActionUpdate, before, after)
+	})
+}
+
+func (r *categoryRepository) Move(ctx context.Context, id, parentID string, version int) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		before, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if err = r.Repository.Move(ctx, id, parentID, version); err != nil {
+			return
+		}
+
+		after, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityCategory, id, audit.ActionMove, before, after)
+	})
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, id string, version int, trashedWith string) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		before, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if err = r.Repository.Delete(ctx, id, version, trashedWith); err != nil {
+			return
+		}
+
+		after, err := r.Repository.GetDeleted(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityCategory, id, audit.ActionDelete, before, after)
+	})
+}
+
+func (r *categoryRepository) Restore(ctx context.Context, id string) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		before, err := r.Repository.GetDeleted(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if err = r.Repository.Restore(ctx, id); err != nil {
+			return
+		}
+
+		after, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityCategory, id, audit.ActionRestore, before, after)
+	})
+}
+
+// Purge records the categories of the trash that are gone after the purge, since the ids are not known.
+func (r *categoryRepository) Purge(ctx context.Context, before time.Time) (purged int, err error) {
+	err = r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		trash, err := r.Repository.SelectDeleted(ctx)
+		if err != nil {
+			return
+		}
+
+		if purged, err = r.Repository.Purge(ctx, before); err != nil || purged == 0 {
+			return
+		}
+
+		for _, data := range trash {
+			switch _, err = r.Repository.GetDeleted(ctx, data.ID); err {
+			case nil:
+				continue
+			case store.ErrorNotFound:
+			default:
+				return
+			}
+
+			if err = r.recorder.record(ctx, audit.EntityCategory, data.ID, audit.ActionPurge, data, nil); err != nil {
+				return
+			}
+		}
+
+		return nil
+	})
+
+	return
+}
+
+type attributeRepository struct {
+	category.AttributeRepository
+	recorder *Recorder
+}
+
+// NewAttributeRepository records the writes of the category attributes under the id of their category.
+func NewAttributeRepository(repository category.AttributeRepository, recorder *Recorder) category.AttributeRepository {
+	return &attributeRepository{
+		AttributeRepository: repository,
+		recorder:            recorder,
+	}
+}
+
+func (r *attributeRepository) CreateAttribute(ctx context.Context, data category.Attribute) (id string, err error) {
+	err = r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		if id, err = r.AttributeRepository.CreateAttribute(ctx, data); err != nil {
+			return
+		}
+		data.ID = id
+
+		return r.recorder.record(ctx, audit.EntityAttribute, data.CategoryID, audit.ActionCreate, nil, data)
+	})
+
+	return
+}
+
+func (r *attributeRepository) DeleteAttribute(ctx context.Context, categoryID, code string) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		attributes, err := r.AttributeRepository.SelectAttributes(ctx, []string{categoryID})
+		if err != nil {
+			return
+		}
+
+		if err = r.AttributeRepository.DeleteAttribute(ctx, categoryID, code); err != nil {
+			return
+		}
+
+		for _, data := range attributes {
+			if data.CategoryID == categoryID && data.Code == code {
+				return r.recorder.record(ctx, audit.EntityAttribute, categoryID, audit.ActionDelete, data, nil)
+			}
+		}
+
+		return
+	})
+}
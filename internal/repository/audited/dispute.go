@@ -0,0 +1,72 @@
+package audited
+
+import (
+	"context"
+
+	"payment-service/internal/domain/audit"
+	"payment-service/internal/domain/dispute"
+)
+
+type disputeRepository struct {
+	dispute.Repository
+	recorder *Recorder
+}
+
+// NewDisputeRepository records the writes of the disputes made through the repository,
+// evidence is recorded under the id of its dispute.
+func NewDisputeRepository(repository dispute.Repository, recorder *Recorder) dispute.Repository {
+	return &disputeRepository{
+		Repository: repository,
+		recorder:   recorder,
+	}
+}
+
+func (r *disputeRepository) Create(ctx context.Context, data dispute.Entity) (id string, err error) {
+	err = r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		if id, err = r.Repository.Create(ctx, data); err != nil {
+			return
+		}
+
+		after, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityDispute, id, audit.ActionCreate, nil, after)
+	})
+
+	return
+}
+
+func (r *disputeRepository) Update(ctx context.Context, id string, data dispute.Entity) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		before, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if err = r.Repository.Update(ctx, id, data); err != nil {
+			return
+		}
+
+		after, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityDispute, id, audit.ActionUpdate, before, after)
+	})
+}
+
+func (r *disputeRepository) AddEvidence(ctx context.Context, data dispute.Evidence) (id string, err error) {
+	err = r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		if id, err = r.Repository.AddEvidence(ctx, data); err != nil {
+			return
+		}
+		data.ID = id
+
+		return r.recorder.record(ctx, audit.EntityEvidence, data.DisputeID, audit.ActionCreate, nil, data)
+	})
+
+	return
+}
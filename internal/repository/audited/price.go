@@ -0,0 +1,64 @@
+package audited
+
+import (
+	"context"
+
+	"payment-service/internal/domain/audit"
+	"payment-service/internal/domain/price"
+	"payment-service/pkg/store"
+)
+
+type priceRepository struct {
+	price.Repository
+	recorder *Recorder
+}
+
+// NewPriceRepository records the price lists and prices made through the repository,
+// a price is recorded under the id of its product.
+func NewPriceRepository(repository price.Repository, recorder *Recorder) price.Repository {
+	return &priceRepository{
+		Repository: repository,
+		recorder:   recorder,
+	}
+}
+
+func (r *priceRepository) CreateList(ctx context.Context, data price.List) (id string, err error) {
+	err = r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		if id, err = r.Repository.CreateList(ctx, data); err != nil {
+			return
+		}
+
+		after, err := r.Repository.GetList(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityPriceList, id, audit.ActionCreate, nil, after)
+	})
+
+	return
+}
+
+func (r *priceRepository) Create(ctx context.Context, data price.Entity) (id string, err error) {
+	err = r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		if id, err = r.Repository.Create(ctx, data); err != nil {
+			return
+		}
+
+		// the history of the product is read back since there is no way to get a single price
+		history, err := r.Repository.Select(ctx, price.Filter{ProductID: data.ProductID, ListID: data.ListID, Currency: data.Currency})
+		if err != nil {
+			return
+		}
+
+		for _, after := range history {
+			if after.ID == id {
+				return r.recorder.record(ctx, audit.EntityPrice, data.ProductID, audit.ActionCreate, nil, after)
+			}
+		}
+
+		return store.ErrorNotFound
+	})
+
+	return
+}
@@ -0,0 +1,247 @@
+package audited
+
+import (
+	"context"
+
+	"payment-service/internal/domain/audit"
+	"payment-service/internal/domain/product"
+)
+
+type productRepository struct {
+	product.Repository
+	recorder *Recorder
+}
+
+// NewProductRepository records the writes of the products and their variants made through the repository.
+func NewProductRepository(repository product.Repository, recorder *Recorder) product.Repository {
+	return &productRepository{
+		Repository: repository,
+		recorder:   recorder,
+	}
+}
+
+func (r *productRepository) Create(ctx context.Context, data product.Entity) (id string, err error) {
+	err = r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		if id, err = r.Repository.Create(ctx, data); err != nil {
+			return
+		}
+
+		after, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityProduct, id, audit.ActionCreate, nil, after)
+	})
+
+	return
+}
+
+// Upsert records a create or an update per stored product, the products the barcodes took before the upsert
+// are read in one go and tell the updates apart.
+func (r *productRepository) Upsert(ctx context.Context, data []product.Entity) (dest []product.Entity, created, updated int, err error) {
+	err = r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		var barcodes []string
+		for _, object := range data {
+			if barcode := stringValue(object.Barcode); barcode != "" {
+				barcodes = append(barcodes, barcode)
+			}
+		}
+
+		stored, err := r.Repository.SelectByBarcodes(ctx, barcodes)
+		if err != nil {
+			return
+		}
+
+		before := make(map[string]product.Entity, len(stored))
+		for _, data := range stored {
+			before[stringValue(data.Barcode)] = data
+		}
+
+		if dest, created, updated, err = r.Repository.Upsert(ctx, data); err != nil {
+			return
+		}
+
+		for _, after := range dest {
+			if stored, ok := before[stringValue(after.Barcode)]; ok && stored.ID == after.ID {
+				err = r.recorder.record(ctx, audit.EntityProduct, after.ID, audit.ActionUpdate, stored, after)
+			} else {
+				err = r.recorder.record(ctx, audit.EntityProduct, after.ID, audit.ActionCreate, nil, after)
+			}
+			if err != nil {
+				return
+			}
+		}
+
+		return
+	})
+
+	return
+}
+
+func (r *productRepository) Update(ctx context.Context, id string, data product.Entity) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		before, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if err = r.Repository.Update(ctx, id, data); err != nil {
+			return
+		}
+
+		after, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityProduct, id, audit.ActionUpdate, before, after)
+	})
+}
+
+func (r *productRepository) Delete(ctx context.Context, id string, version int, trashedWith string) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		before, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if err = r.Repository.Delete(ctx, id, version, trashedWith); err != nil {
+			return
+		}
+
+		after, err := r.Repository.GetDeleted(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityProduct, id, audit.ActionDelete, before, after)
+	})
+}
+
+func (r *productRepository) Restore(ctx context.Context, id string) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		before, err := r.Repository.GetDeleted(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if err = r.Repository.Restore(ctx, id); err != nil {
+			return
+		}
+
+		after, err := r.Repository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityProduct, id, audit.ActionRestore, before, after)
+	})
+}
+
+func (r *productRepository) Purge(ctx context.Context, id string) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		before, err := r.Repository.GetDeleted(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if err = r.Repository.Purge(ctx, id); err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityProduct, id, audit.ActionPurge, before, nil)
+	})
+}
+
+type imageRepository struct {
+	product.ImageRepository
+	recorder *Recorder
+}
+
+// imageOrder is recorded when the images of a product are reordered.
+type imageOrder struct {
+	Images []string `db:"images"`
+}
+
+// NewImageRepository records the writes of the product images under the id of their product.
+func NewImageRepository(repository product.ImageRepository, recorder *Recorder) product.ImageRepository {
+	return &imageRepository{
+		ImageRepository: repository,
+		recorder:        recorder,
+	}
+}
+
+func (r *imageRepository) CreateImage(ctx context.Context, data product.Image) (id string, err error) {
+	err = r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		if id, err = r.ImageRepository.CreateImage(ctx, data); err != nil {
+			return
+		}
+
+		after, err := r.ImageRepository.GetImage(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityImage, after.ProductID, audit.ActionCreate, nil, after)
+	})
+
+	return
+}
+
+func (r *imageRepository) ReorderImages(ctx context.Context, productID string, ids []string) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		before, err := r.order(ctx, productID)
+		if err != nil {
+			return
+		}
+
+		if err = r.ImageRepository.ReorderImages(ctx, productID, ids); err != nil {
+			return
+		}
+
+		after, err := r.order(ctx, productID)
+		if err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityImage, productID, audit.ActionUpdate, before, after)
+	})
+}
+
+func (r *imageRepository) DeleteImage(ctx context.Context, id string) (err error) {
+	return r.recorder.transact(ctx, func(ctx context.Context) (err error) {
+		before, err := r.ImageRepository.GetImage(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if err = r.ImageRepository.DeleteImage(ctx, id); err != nil {
+			return
+		}
+
+		return r.recorder.record(ctx, audit.EntityImage, before.ProductID, audit.ActionDelete, before, nil)
+	})
+}
+
+func (r *imageRepository) order(ctx context.Context, productID string) (dest imageOrder, err error) {
+	images, err := r.ImageRepository.SelectImages(ctx, productID)
+	if err != nil {
+		return
+	}
+
+	dest.Images = make([]string, 0, len(images))
+	for _, image := range images {
+		dest.Images = append(dest.Images, image.ID)
+	}
+
+	return
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
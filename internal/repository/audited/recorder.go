@@ -0,0 +1,46 @@
+package audited
+
+import (
+	"context"
+
+	"payment-service/internal/domain/audit"
+	"payment-service/pkg/store"
+)
+
+// Recorder keeps the audit trail of the decorated repositories. Each write is read before and after it is made
+// and its diff is recorded within the same transaction, so a write is never left without its record.
+type Recorder struct {
+	repository audit.Repository
+	transactor store.Transactor
+}
+
+func NewRecorder(repository audit.Repository, transactor store.Transactor) *Recorder {
+	return &Recorder{
+		repository: repository,
+		transactor: transactor,
+	}
+}
+
+func (r *Recorder) transact(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.transactor.Transact(ctx, fn)
+}
+
+// record stores the diff of the entity made by the action, before is nil for a create and after for a purge.
+func (r *Recorder) record(ctx context.Context, entityType, entityID, action string, before, after any) (err error) {
+	diff, err := audit.Diff(before, after)
+	if err != nil {
+		return
+	}
+
+	data := audit.Entity{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Diff:       diff,
+	}
+	data.Actor, data.RequestID = audit.Source(ctx)
+
+	_, err = r.repository.Create(ctx, data)
+
+	return
+}
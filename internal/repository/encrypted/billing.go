@@ -0,0 +1,219 @@
+package encrypted
+
+import (
+	"context"
+
+	"payment-service/internal/domain/billing"
+	"payment-service/pkg/envelope"
+	"payment-service/pkg/store"
+)
+
+// personalData returns the fields of the billing holding personal data, they are kept encrypted.
+func personalData(data *billing.Entity) []*string {
+	return []*string{&data.Name, &data.AccountID, &data.Email, &data.Phone}
+}
+
+// BillingRepository encrypts the personal data of the billings written through it and decrypts it on the way
+// back, so the store and the caches in front of it only see the encrypted values.
+type BillingRepository struct {
+	billing.Repository
+	keyring *envelope.Keyring
+}
+
+func NewBillingRepository(repository billing.Repository, keyring *envelope.Keyring) *BillingRepository {
+	return &BillingRepository{
+		Repository: repository,
+		keyring:    keyring,
+	}
+}
+
+func (r *BillingRepository) Select(ctx context.Context) (dest []billing.Entity, err error) {
+	if dest, err = r.Repository.Select(ctx); err != nil {
+		return
+	}
+	err = r.decryptAll(dest)
+
+	return
+}
+
+func (r *BillingRepository) SelectByFilter(ctx context.Context, filter billing.Filter) (dest []billing.Entity, err error) {
+	if dest, err = r.Repository.SelectByFilter(ctx, filter); err != nil {
+		return
+	}
+	err = r.decryptAll(dest)
+
+	return
+}
+
+func (r *BillingRepository) SelectByParentID(ctx context.Context, parentID string) (dest []billing.Entity, err error) {
+	if dest, err = r.Repository.SelectByParentID(ctx, parentID); err != nil {
+		return
+	}
+	err = r.decryptAll(dest)
+
+	return
+}
+
+func (r *BillingRepository) Create(ctx context.Context, data billing.Entity) (id string, err error) {
+	for _, field := range personalData(&data) {
+		if *field, err = r.keyring.Encrypt(*field); err != nil {
+			return
+		}
+	}
+
+	return r.Repository.Create(ctx, data)
+}
+
+func (r *BillingRepository) Get(ctx context.Context, id string) (dest billing.Entity, err error) {
+	if dest, err = r.Repository.Get(ctx, id); err != nil {
+		return
+	}
+	err = r.decrypt(&dest)
+
+	return
+}
+
+func (r *BillingRepository) GetByInvoiceID(ctx context.Context, invoiceID string) (dest billing.Entity, err error) {
+	if dest, err = r.Repository.GetByInvoiceID(ctx, invoiceID); err != nil {
+		return
+	}
+	err = r.decrypt(&dest)
+
+	return
+}
+
+// Update keeps the stored value of a field that is written unchanged, every encryption gives another value
+// and the audit trail would take it for a change.
+func (r *BillingRepository) Update(ctx context.Context, id string, data billing.Entity) (err error) {
+	fields := personalData(&data)
+
+	set := false
+	for _, field := range fields {
+		set = set || *field != ""
+	}
+	if !set {
+		return r.Repository.Update(ctx, id, data)
+	}
+
+	current, err := r.Repository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	for i, stored := range personalData(&current) {
+		if *fields[i] == "" {
+			continue
+		}
+
+		plain, err := r.keyring.Decrypt(*stored)
+		if err != nil {
+			return err
+		}
+
+		if plain == *fields[i] && !r.keyring.Stale(*stored) {
+			*fields[i] = *stored
+			continue
+		}
+
+		if *fields[i], err = r.keyring.Encrypt(*fields[i]); err != nil {
+			return err
+		}
+	}
+
+	return r.Repository.Update(ctx, id, data)
+}
+
+// Rekey implements billing.Rekeyer, the data keys of the values encrypted under a retired key are encrypted under
+// the primary one and the values stored before the encryption are encrypted. The subject keys of a billing are
+// computed with the index key along, they are missing or were computed without the key. A billing written since
+// it was read is skipped, the write may have changed the values and the next run picks whatever is still stale up.
+func (r *BillingRepository) Rekey(ctx context.Context) (count int, err error) {
+	data, err := r.Repository.Select(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, object := range data {
+		update := billing.Entity{Version: object.Version}
+		fields := personalData(&update)
+
+		stale := false
+		for i, stored := range personalData(&object) {
+			if !r.keyring.Stale(*stored) {
+				continue
+			}
+
+			if *fields[i], err = r.keyring.Rewrap(*stored); err != nil {
+				return
+			}
+			stale = true
+		}
+
+		if !stale && object.SubjectKeys != nil {
+			continue
+		}
+
+		if err = r.decrypt(&object); err != nil {
+			return
+		}
+		update.SubjectKeys = billing.SubjectKeys(r.keyring.Index, object.Email, object.Phone, object.AccountID)
+
+		switch err = r.Repository.Update(ctx, object.ID, update); err {
+		case nil:
+			count++
+		case store.ErrorStaleVersion:
+			err = nil
+		default:
+			return
+		}
+	}
+
+	return
+}
+
+func (r *BillingRepository) decrypt(data *billing.Entity) (err error) {
+	for _, field := range personalData(data) {
+		if *field, err = r.keyring.Decrypt(*field); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (r *BillingRepository) decryptAll(data []billing.Entity) (err error) {
+	for i := range data {
+		if err = r.decrypt(&data[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+type billingCache struct {
+	cache   billing.Cache
+	keyring *envelope.Keyring
+}
+
+// NewBillingCache decrypts the personal data of the billings read through the cache, the cache keeps them encrypted.
+func NewBillingCache(cache billing.Cache, keyring *envelope.Keyring) billing.Cache {
+	return &billingCache{
+		cache:   cache,
+		keyring: keyring,
+	}
+}
+
+func (c *billingCache) Get(ctx context.Context, id string) (dest billing.Entity, err error) {
+	if dest, err = c.cache.Get(ctx, id); err != nil {
+		return
+	}
+
+	for _, field := range personalData(&dest) {
+		if *field, err = c.keyring.Decrypt(*field); err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/domain/apikey"
+	"payment-service/pkg/store"
+)
+
+type APIKeyRepository struct {
+	db map[string]apikey.Entity
+	sync.RWMutex
+}
+
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{
+		db: make(map[string]apikey.Entity),
+	}
+}
+
+func (r *APIKeyRepository) Select(ctx context.Context) (dest []apikey.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]apikey.Entity, 0, len(r.db))
+	for _, data := range r.db {
+		if store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].CreatedAt.After(dest[j].CreatedAt)
+	})
+
+	return
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, data apikey.Entity) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, key := range r.db {
+		if key.Hash == data.Hash {
+			err = store.ErrorAlreadyExists
+			return
+		}
+	}
+
+	id = r.generateID()
+	data.ID = id
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	data.CreatedAt = time.Now()
+	data.Scopes = append([]string(nil), data.Scopes...)
+	r.db[id] = data
+
+	return
+}
+
+func (r *APIKeyRepository) Get(ctx context.Context, id string) (dest apikey.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest, ok := r.db[id]
+	if !ok || !store.TenantAllows(ctx, dest.TenantID) {
+		return apikey.Entity{}, store.ErrorNotFound
+	}
+
+	return
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (dest apikey.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, data := range r.db {
+		if data.Hash == hash && data.RevokedAt == nil && store.TenantAllows(ctx, data.TenantID) {
+			return data, nil
+		}
+	}
+	err = store.ErrorNotFound
+
+	return
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, id string) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	data, ok := r.db[id]
+	if !ok || data.RevokedAt != nil || !store.TenantAllows(ctx, data.TenantID) {
+		return store.ErrorNotFound
+	}
+
+	now := time.Now()
+	data.RevokedAt = &now
+	r.db[id] = data
+
+	return
+}
+
+func (r *APIKeyRepository) generateID() string {
+	return uuid.New().String()
+}
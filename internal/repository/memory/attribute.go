@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/domain/category"
+	"payment-service/pkg/store"
+)
+
+type AttributeRepository struct {
+	db map[string]category.Attribute
+	sync.RWMutex
+}
+
+func NewAttributeRepository() *AttributeRepository {
+	return &AttributeRepository{
+		db: make(map[string]category.Attribute),
+	}
+}
+
+func (r *AttributeRepository) SelectAttributes(ctx context.Context, categoryIDs []string) (dest []category.Attribute, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]category.Attribute, 0)
+	for _, data := range r.db {
+		if containsString(categoryIDs, data.CategoryID) && store.TenantAllows(ctx, data.TenantID) {
+			data.Options = append([]string(nil), data.Options...)
+			dest = append(dest, data)
+		}
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		if dest[i].Code != dest[j].Code {
+			return dest[i].Code < dest[j].Code
+		}
+		return dest[i].CategoryID < dest[j].CategoryID
+	})
+
+	return
+}
+
+func (r *AttributeRepository) CreateAttribute(ctx context.Context, data category.Attribute) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, current := range r.db {
+		if current.CategoryID == data.CategoryID && current.Code == data.Code {
+			return "", store.ErrorAlreadyExists
+		}
+	}
+
+	id = r.generateID()
+	data.ID = id
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	data.CreatedAt = time.Now()
+	data.Options = append([]string(nil), data.Options...)
+	r.db[id] = data
+
+	return
+}
+
+func (r *AttributeRepository) DeleteAttribute(ctx context.Context, categoryID, code string) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	for id, data := range r.db {
+		if data.CategoryID == categoryID && data.Code == code && store.TenantAllows(ctx, data.TenantID) {
+			delete(r.db, id)
+			return
+		}
+	}
+
+	return store.ErrorNotFound
+}
+
+func (r *AttributeRepository) generateID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/domain/audit"
+	"payment-service/pkg/store"
+)
+
+type AuditRepository struct {
+	db []audit.Entity
+	sync.RWMutex
+}
+
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, data audit.Entity) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	data.ID = r.generateID()
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	data.CreatedAt = time.Now()
+	r.db = append(r.db, data)
+
+	return data.ID, nil
+}
+
+func (r *AuditRepository) Select(ctx context.Context, entityID string) (dest []audit.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]audit.Entity, 0)
+	for _, data := range r.db {
+		if data.EntityID == entityID && store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
+	}
+
+	return
+}
+
+func (r *AuditRepository) Redact(ctx context.Context, entityType string, entityIDs, fields []string) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	redacted, err := json.Marshal(audit.Redacted)
+	if err != nil {
+		return
+	}
+
+	for i, data := range r.db {
+		if data.EntityType != entityType || !containsString(entityIDs, data.EntityID) ||
+			!store.TenantAllows(ctx, data.TenantID) {
+			continue
+		}
+
+		var changes map[string]audit.Change
+		if err = json.Unmarshal(data.Diff, &changes); err != nil {
+			return
+		}
+
+		for _, field := range fields {
+			change, ok := changes[field]
+			if !ok {
+				continue
+			}
+
+			if len(change.Before) > 0 {
+				change.Before = redacted
+			}
+			if len(change.After) > 0 {
+				change.After = redacted
+			}
+			changes[field] = change
+		}
+
+		if r.db[i].Diff, err = json.Marshal(changes); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (r *AuditRepository) generateID() string {
+	return uuid.New().String()
+}
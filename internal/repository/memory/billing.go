@@ -3,11 +3,13 @@ package memory
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 
 	"payment-service/internal/domain/billing"
 	"payment-service/pkg/store"
+	"payment-service/pkg/store/postgres"
 )
 
 type BillingRepository struct {
@@ -27,19 +29,90 @@ func (r *BillingRepository) Select(ctx context.Context) (dest []billing.Entity,
 
 	dest = make([]billing.Entity, 0, len(r.db))
 	for _, data := range r.db {
-		dest = append(dest, data)
+		if store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
 	}
 
 	return
 }
 
+func (r *BillingRepository) SelectByFilter(ctx context.Context, filter billing.Filter) (dest []billing.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]billing.Entity, 0)
+	for _, data := range r.db {
+		if matchBilling(filter, data) && store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
+	}
+
+	return
+}
+
+func matchBilling(filter billing.Filter, data billing.Entity) bool {
+	if len(filter.Statuses) > 0 && !containsString(filter.Statuses, data.Status) {
+		return false
+	}
+
+	if !filter.From.IsZero() && data.CreatedAt.Before(filter.From) {
+		return false
+	}
+
+	if !filter.To.IsZero() && !data.CreatedAt.Before(filter.To) {
+		return false
+	}
+
+	if !filter.PaidFrom.IsZero() && (data.PaidAt == nil || data.PaidAt.Before(filter.PaidFrom)) {
+		return false
+	}
+
+	if !filter.PaidTo.IsZero() && (data.PaidAt == nil || !data.PaidAt.Before(filter.PaidTo)) {
+		return false
+	}
+
+	if len(filter.Keys) > 0 && !containsString(filter.Keys, data.InvoiceID) &&
+		!containsString(filter.Keys, data.Reference) && !containsString(filter.Keys, data.IntReference) {
+		return false
+	}
+
+	if len(filter.SubjectKeys) > 0 && !containsAny(filter.SubjectKeys, data.SubjectKeys) {
+		return false
+	}
+
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if value != "" && v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsAny(values, keys []string) bool {
+	for _, key := range keys {
+		if containsString(values, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (r *BillingRepository) SelectByParentID(ctx context.Context, parentID string) (dest []billing.Entity, err error) {
 	r.RLock()
 	defer r.RUnlock()
 
 	dest = make([]billing.Entity, 0, len(r.db))
 	for _, data := range r.db {
-		dest = append(dest, data)
+		if data.CorrelationID == parentID && store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
 	}
 
 	return
@@ -51,6 +124,13 @@ func (r *BillingRepository) Create(ctx context.Context, data billing.Entity) (de
 
 	id := r.generateID()
 	data.ID = id
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	data.Version = 1
+	if data.SubjectKeys != nil {
+		data.SubjectKeys = append(postgres.Array{}, data.SubjectKeys...)
+	}
+	data.CreatedAt = time.Now()
+	data.UpdatedAt = data.CreatedAt
 	r.db[id] = data
 
 	return id, nil
@@ -61,10 +141,23 @@ func (r *BillingRepository) Get(ctx context.Context, id string) (dest billing.En
 	defer r.RUnlock()
 
 	dest, ok := r.db[id]
-	if !ok {
-		err = store.ErrorNotFound
-		return
+	if !ok || !store.TenantAllows(ctx, dest.TenantID) {
+		return billing.Entity{}, store.ErrorNotFound
+	}
+
+	return
+}
+
+func (r *BillingRepository) GetByInvoiceID(ctx context.Context, invoiceID string) (dest billing.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, data := range r.db {
+		if data.InvoiceID == invoiceID && store.TenantAllows(ctx, data.TenantID) {
+			return data, nil
+		}
 	}
+	err = store.ErrorNotFound
 
 	return
 }
@@ -73,21 +166,71 @@ func (r *BillingRepository) Update(ctx context.Context, id string, data billing.
 	r.Lock()
 	defer r.Unlock()
 
-	if _, ok := r.db[id]; !ok {
+	current, ok := r.db[id]
+	if !ok || !store.TenantAllows(ctx, current.TenantID) {
 		return store.ErrorNotFound
 	}
-	r.db[id] = data
+
+	if data.Version != 0 && data.Version != current.Version {
+		return store.ErrorStaleVersion
+	}
+
+	// the same fields as the postgres store updates, empty values are left as they are
+	for _, field := range []struct {
+		dest  *string
+		value string
+	}{
+		{&current.Amount, data.Amount},
+		{&current.Currency, data.Currency},
+		{&current.Description, data.Description},
+		{&current.AccountID, data.AccountID},
+		{&current.Name, data.Name},
+		{&current.Email, data.Email},
+		{&current.Phone, data.Phone},
+		{&current.Language, data.Language},
+		{&current.Backlink, data.Backlink},
+		{&current.FailureBacklink, data.FailureBacklink},
+		{&current.PostLink, data.PostLink},
+		{&current.FailurePostLink, data.FailurePostLink},
+		{&current.Status, data.Status},
+		{&current.Refunded, data.Refunded},
+		{&current.Reference, data.Reference},
+		{&current.IntReference, data.IntReference},
+		{&current.FXRate, data.FXRate},
+		{&current.BaseAmount, data.BaseAmount},
+		{&current.BaseCurrency, data.BaseCurrency},
+	} {
+		if field.value != "" {
+			*field.dest = field.value
+		}
+	}
+
+	if data.PaidAt != nil {
+		paidAt := *data.PaidAt
+		current.PaidAt = &paidAt
+	}
+	if data.SubjectKeys != nil {
+		current.SubjectKeys = append(postgres.Array{}, data.SubjectKeys...)
+	}
+	current.Version++
+	current.UpdatedAt = time.Now()
+	r.db[id] = current
 
 	return
 }
 
-func (r *BillingRepository) Delete(ctx context.Context, id string) (err error) {
+func (r *BillingRepository) Delete(ctx context.Context, id string, version int) (err error) {
 	r.Lock()
 	defer r.Unlock()
 
-	if _, ok := r.db[id]; !ok {
+	current, ok := r.db[id]
+	if !ok || !store.TenantAllows(ctx, current.TenantID) {
 		return store.ErrorNotFound
 	}
+
+	if version != 0 && version != current.Version {
+		return store.ErrorStaleVersion
+	}
 	delete(r.db, id)
 
 	return
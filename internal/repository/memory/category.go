@@ -2,7 +2,9 @@ package memory
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -27,9 +29,36 @@ func (r *CategoryRepository) Select(ctx context.Context) (dest []category.Entity
 
 	dest = make([]category.Entity, 0, len(r.db))
 	for _, data := range r.db {
-		dest = append(dest, data)
+		if data.DeletedAt == nil && store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
 	}
 
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].CreatedAt.Before(dest[j].CreatedAt)
+	})
+
+	return
+}
+
+func (r *CategoryRepository) SelectDeleted(ctx context.Context) (dest []category.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]category.Entity, 0)
+	for _, data := range r.db {
+		if data.DeletedAt != nil && store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		if !dest[i].DeletedAt.Equal(*dest[j].DeletedAt) {
+			return dest[i].DeletedAt.After(*dest[j].DeletedAt)
+		}
+		return dest[i].CreatedAt.Before(dest[j].CreatedAt)
+	})
+
 	return
 }
 
@@ -37,9 +66,61 @@ func (r *CategoryRepository) SelectByParentID(ctx context.Context, parentID stri
 	r.RLock()
 	defer r.RUnlock()
 
-	dest = make([]category.Entity, 0, len(r.db))
+	dest = make([]category.Entity, 0)
 	for _, data := range r.db {
-		dest = append(dest, data)
+		if data.ParentID == parentID && data.DeletedAt == nil && store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].CreatedAt.Before(dest[j].CreatedAt)
+	})
+
+	return
+}
+
+func (r *CategoryRepository) Tree(ctx context.Context, rootID string) (dest []category.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	if rootID != "" {
+		if _, ok := r.live(ctx, rootID); !ok {
+			err = store.ErrorNotFound
+			return
+		}
+	}
+
+	// the trash is left out with the subtrees under it
+	dest = make([]category.Entity, 0)
+	for _, data := range r.db {
+		if r.reachable(ctx, data.ID) && (rootID == "" || r.descends(data.ID, rootID)) {
+			dest = append(dest, data)
+		}
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].CreatedAt.Before(dest[j].CreatedAt)
+	})
+
+	return
+}
+
+func (r *CategoryRepository) SelectAncestors(ctx context.Context, id string) (dest []category.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	for current := id; current != "" && len(dest) <= len(r.db); {
+		data, ok := r.live(ctx, current)
+		if !ok {
+			break
+		}
+		dest = append([]category.Entity{data}, dest...)
+		current = data.ParentID
+	}
+
+	if len(dest) == 0 {
+		err = store.ErrorNotFound
 	}
 
 	return
@@ -51,6 +132,10 @@ func (r *CategoryRepository) Create(ctx context.Context, data category.Entity) (
 
 	id := r.generateID()
 	data.ID = id
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	data.Version = 1
+	data.CreatedAt = time.Now()
+	data.UpdatedAt = data.CreatedAt
 	r.db[id] = data
 
 	return id, nil
@@ -60,7 +145,7 @@ func (r *CategoryRepository) Get(ctx context.Context, id string) (dest category.
 	r.RLock()
 	defer r.RUnlock()
 
-	dest, ok := r.db[id]
+	dest, ok := r.live(ctx, id)
 	if !ok {
 		err = store.ErrorNotFound
 		return
@@ -69,30 +154,178 @@ func (r *CategoryRepository) Get(ctx context.Context, id string) (dest category.
 	return
 }
 
+func (r *CategoryRepository) GetDeleted(ctx context.Context, id string) (dest category.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest, ok := r.db[id]
+	if !ok || dest.DeletedAt == nil || !store.TenantAllows(ctx, dest.TenantID) {
+		return category.Entity{}, store.ErrorNotFound
+	}
+
+	return
+}
+
 func (r *CategoryRepository) Update(ctx context.Context, id string, data category.Entity) (err error) {
 	r.Lock()
 	defer r.Unlock()
 
-	if _, ok := r.db[id]; !ok {
+	current, ok := r.live(ctx, id)
+	if !ok {
 		return store.ErrorNotFound
 	}
+
+	if data.Version != 0 && data.Version != current.Version {
+		return store.ErrorStaleVersion
+	}
+
+	if data.Name != nil {
+		name := *data.Name
+		current.Name = &name
+	}
+	current.Version++
+	current.UpdatedAt = time.Now()
+	r.db[id] = current
+
+	return
+}
+
+func (r *CategoryRepository) Move(ctx context.Context, id, parentID string, version int) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	data, ok := r.live(ctx, id)
+	if !ok {
+		return store.ErrorNotFound
+	}
+
+	if version != 0 && version != data.Version {
+		return store.ErrorStaleVersion
+	}
+
+	if parentID != "" {
+		if _, ok = r.live(ctx, parentID); !ok {
+			return category.ErrParentNotFound
+		}
+
+		if r.descends(parentID, id) {
+			return category.ErrCycle
+		}
+	}
+
+	data.ParentID = parentID
+	data.Version++
+	data.UpdatedAt = time.Now()
 	r.db[id] = data
 
 	return
 }
 
-func (r *CategoryRepository) Delete(ctx context.Context, id string) (err error) {
+func (r *CategoryRepository) Delete(ctx context.Context, id string, version int, trashedWith string) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	current, ok := r.live(ctx, id)
+	if !ok {
+		return store.ErrorNotFound
+	}
+
+	if version != 0 && version != current.Version {
+		return store.ErrorStaleVersion
+	}
+
+	now := time.Now()
+	current.DeletedAt = &now
+	current.TrashedWith = trashedWith
+	current.Version++
+	current.UpdatedAt = now
+	r.db[id] = current
+
+	return
+}
+
+func (r *CategoryRepository) Restore(ctx context.Context, id string) (err error) {
 	r.Lock()
 	defer r.Unlock()
 
-	if _, ok := r.db[id]; !ok {
+	current, ok := r.db[id]
+	if !ok || current.DeletedAt == nil || !store.TenantAllows(ctx, current.TenantID) {
 		return store.ErrorNotFound
 	}
-	delete(r.db, id)
+
+	current.DeletedAt = nil
+	current.TrashedWith = ""
+	current.Version++
+	current.UpdatedAt = time.Now()
+	r.db[id] = current
 
 	return
 }
 
+// Purge removes the leaves of the trash, the memory store doesn't look for products left in them:
+// the catalogue purges the products first.
+func (r *CategoryRepository) Purge(ctx context.Context, before time.Time) (purged int, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	for removed := true; removed; {
+		removed = false
+		for id, data := range r.db {
+			if data.DeletedAt == nil || !data.DeletedAt.Before(before) || !store.TenantAllows(ctx, data.TenantID) ||
+				r.hasChildren(id) {
+				continue
+			}
+
+			delete(r.db, id)
+			removed = true
+			purged++
+		}
+	}
+
+	return
+}
+
+// live returns the category unless it is missing, in the trash or of another tenant than the one of the context.
+func (r *CategoryRepository) live(ctx context.Context, id string) (data category.Entity, ok bool) {
+	data, ok = r.db[id]
+	return data, ok && data.DeletedAt == nil && store.TenantAllows(ctx, data.TenantID)
+}
+
+// reachable tells whether the category and all its ancestors are out of the trash.
+func (r *CategoryRepository) reachable(ctx context.Context, id string) bool {
+	for steps := 0; id != "" && steps <= len(r.db); steps++ {
+		data, ok := r.live(ctx, id)
+		if !ok {
+			return false
+		}
+		id = data.ParentID
+	}
+
+	return true
+}
+
+func (r *CategoryRepository) hasChildren(id string) bool {
+	for _, data := range r.db {
+		if data.ParentID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// descends tells whether the category is the ancestor or one of its descendants.
+func (r *CategoryRepository) descends(id, ancestorID string) bool {
+	for steps := 0; id != "" && steps <= len(r.db); steps++ {
+		if id == ancestorID {
+			return true
+		}
+		id = r.db[id].ParentID
+	}
+
+	return false
+}
+
 func (r *CategoryRepository) generateID() string {
 	return uuid.New().String()
 }
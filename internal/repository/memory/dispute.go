@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/domain/dispute"
+	"payment-service/pkg/store"
+)
+
+type DisputeRepository struct {
+	db map[string]dispute.Entity
+	sync.RWMutex
+}
+
+func NewDisputeRepository() *DisputeRepository {
+	return &DisputeRepository{
+		db: make(map[string]dispute.Entity),
+	}
+}
+
+func (r *DisputeRepository) Select(ctx context.Context, status string) (dest []dispute.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]dispute.Entity, 0, len(r.db))
+	for _, data := range r.db {
+		if status != "" && data.Status != status || !store.TenantAllows(ctx, data.TenantID) {
+			continue
+		}
+		data.Evidence = nil
+		dest = append(dest, data)
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].CreatedAt.After(dest[j].CreatedAt)
+	})
+
+	return
+}
+
+func (r *DisputeRepository) SelectDue(ctx context.Context, before time.Time) (dest []dispute.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]dispute.Entity, 0)
+	for _, data := range r.db {
+		if data.Status != dispute.StatusOpened || !data.DueAt.Before(before) || !store.TenantAllows(ctx, data.TenantID) {
+			continue
+		}
+		data.Evidence = nil
+		dest = append(dest, data)
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].DueAt.Before(dest[j].DueAt)
+	})
+
+	return
+}
+
+func (r *DisputeRepository) Create(ctx context.Context, data dispute.Entity) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	id = r.generateID()
+	data.ID = id
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	data.CreatedAt = time.Now()
+	data.UpdatedAt = data.CreatedAt
+	data.Evidence = nil
+	r.db[id] = data
+
+	return
+}
+
+func (r *DisputeRepository) Get(ctx context.Context, id string) (dest dispute.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest, ok := r.db[id]
+	if !ok || !store.TenantAllows(ctx, dest.TenantID) {
+		return dispute.Entity{}, store.ErrorNotFound
+	}
+	dest.Evidence = append([]dispute.Evidence(nil), dest.Evidence...)
+
+	return
+}
+
+func (r *DisputeRepository) Update(ctx context.Context, id string, data dispute.Entity) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	current, ok := r.db[id]
+	if !ok || !store.TenantAllows(ctx, current.TenantID) {
+		return store.ErrorNotFound
+	}
+
+	current.Status = data.Status
+	current.ResolvedAt = data.ResolvedAt
+	current.UpdatedAt = time.Now()
+	r.db[id] = current
+
+	return
+}
+
+func (r *DisputeRepository) AddEvidence(ctx context.Context, data dispute.Evidence) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	current, ok := r.db[data.DisputeID]
+	if !ok || !store.TenantAllows(ctx, current.TenantID) {
+		err = store.ErrorNotFound
+		return
+	}
+
+	id = r.generateID()
+	data.ID = id
+	data.CreatedAt = time.Now()
+	current.Evidence = append(current.Evidence, data)
+	r.db[data.DisputeID] = current
+
+	return
+}
+
+func (r *DisputeRepository) generateID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"payment-service/internal/domain/fx"
+)
+
+type FXRepository struct {
+	// db keeps the rates of every currency and base ordered by date
+	db map[string][]fx.Rate
+	sync.RWMutex
+}
+
+func NewFXRepository() *FXRepository {
+	return &FXRepository{
+		db: make(map[string][]fx.Rate),
+	}
+}
+
+func (r *FXRepository) Save(ctx context.Context, data []fx.Rate) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, rate := range data {
+		rate.CreatedAt = time.Now()
+		key := rate.Currency + "/" + rate.Base
+
+		rates, replaced := r.db[key], false
+		for i := range rates {
+			if rates[i].Date.Equal(rate.Date) {
+				rates[i], replaced = rate, true
+			}
+		}
+
+		if !replaced {
+			rates = append(rates, rate)
+			sort.Slice(rates, func(i, j int) bool {
+				return rates[i].Date.Before(rates[j].Date)
+			})
+		}
+		r.db[key] = rates
+	}
+
+	return
+}
+
+func (r *FXRepository) Select(ctx context.Context, base string, date time.Time) (dest []fx.Rate, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]fx.Rate, 0)
+	for _, rates := range r.db {
+		if rate, ok := effective(rates, date); ok && rate.Base == base {
+			dest = append(dest, rate)
+		}
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].Currency < dest[j].Currency
+	})
+
+	return
+}
+
+func (r *FXRepository) Get(ctx context.Context, currency, base string, date time.Time) (dest fx.Rate, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest, ok := effective(r.db[currency+"/"+base], date)
+	if !ok {
+		err = fx.ErrRateNotFound
+	}
+
+	return
+}
+
+// effective returns the latest of the rates ordered by date that is in effect on the date.
+func effective(rates []fx.Rate, date time.Time) (rate fx.Rate, ok bool) {
+	for _, candidate := range rates {
+		if candidate.Date.After(date) {
+			break
+		}
+		rate, ok = candidate, true
+	}
+
+	return
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/domain/product"
+	"payment-service/pkg/store"
+)
+
+type ImageRepository struct {
+	db map[string]product.Image
+	sync.RWMutex
+}
+
+func NewImageRepository() *ImageRepository {
+	return &ImageRepository{
+		db: make(map[string]product.Image),
+	}
+}
+
+func (r *ImageRepository) SelectImages(ctx context.Context, productID string) (dest []product.Image, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = r.selectImages(ctx, productID)
+
+	return
+}
+
+func (r *ImageRepository) CreateImage(ctx context.Context, data product.Image) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	data.Position = 0
+	if images := r.selectImages(ctx, data.ProductID); len(images) > 0 {
+		data.Position = images[len(images)-1].Position + 1
+	}
+
+	id = r.generateID()
+	data.ID = id
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	data.CreatedAt = time.Now()
+	r.db[id] = data
+
+	return
+}
+
+func (r *ImageRepository) GetImage(ctx context.Context, id string) (dest product.Image, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest, ok := r.db[id]
+	if !ok || !store.TenantAllows(ctx, dest.TenantID) {
+		err = store.ErrorNotFound
+		return
+	}
+
+	return
+}
+
+func (r *ImageRepository) ReorderImages(ctx context.Context, productID string, ids []string) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	for position, id := range ids {
+		data, ok := r.db[id]
+		if !ok || data.ProductID != productID || !store.TenantAllows(ctx, data.TenantID) {
+			continue
+		}
+		data.Position = position
+		r.db[id] = data
+	}
+
+	return
+}
+
+func (r *ImageRepository) DeleteImage(ctx context.Context, id string) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if data, ok := r.db[id]; !ok || !store.TenantAllows(ctx, data.TenantID) {
+		return store.ErrorNotFound
+	}
+	delete(r.db, id)
+
+	return
+}
+
+func (r *ImageRepository) selectImages(ctx context.Context, productID string) (dest []product.Image) {
+	dest = make([]product.Image, 0)
+	for _, data := range r.db {
+		if data.ProductID == productID && store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		if dest[i].Position != dest[j].Position {
+			return dest[i].Position < dest[j].Position
+		}
+		return dest[i].CreatedAt.Before(dest[j].CreatedAt)
+	})
+
+	return
+}
+
+func (r *ImageRepository) generateID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/domain/product"
+	"payment-service/pkg/store"
+)
+
+type ImportRepository struct {
+	db map[string]product.Job
+	sync.RWMutex
+}
+
+func NewImportRepository() *ImportRepository {
+	return &ImportRepository{
+		db: make(map[string]product.Job),
+	}
+}
+
+func (r *ImportRepository) CreateJob(ctx context.Context, data product.Job) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	id = r.generateID()
+	data.ID = id
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	data.CreatedAt = time.Now()
+	data.UpdatedAt = data.CreatedAt
+	data.Errors = nil
+	r.db[id] = data
+
+	return
+}
+
+func (r *ImportRepository) GetJob(ctx context.Context, id string) (dest product.Job, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest, ok := r.db[id]
+	if !ok || !store.TenantAllows(ctx, dest.TenantID) {
+		err = store.ErrorNotFound
+		return
+	}
+	dest.Errors = append([]product.RowError(nil), dest.Errors...)
+
+	sort.Slice(dest.Errors, func(i, j int) bool {
+		return dest.Errors[i].Line < dest.Errors[j].Line
+	})
+
+	return
+}
+
+func (r *ImportRepository) UpdateJob(ctx context.Context, id string, data product.Job) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	current, ok := r.db[id]
+	if !ok || !store.TenantAllows(ctx, current.TenantID) {
+		return store.ErrorNotFound
+	}
+
+	current.Status = data.Status
+	current.Total = data.Total
+	current.Created = data.Created
+	current.Updated = data.Updated
+	current.Failed = data.Failed
+	current.Message = data.Message
+	current.UpdatedAt = time.Now()
+	r.db[id] = current
+
+	return
+}
+
+func (r *ImportRepository) AddJobErrors(ctx context.Context, id string, data []product.RowError) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	current, ok := r.db[id]
+	if !ok || !store.TenantAllows(ctx, current.TenantID) {
+		return store.ErrorNotFound
+	}
+
+	for _, rowErr := range data {
+		rowErr.JobID = id
+		current.Errors = append(current.Errors, rowErr)
+	}
+	r.db[id] = current
+
+	return
+}
+
+func (r *ImportRepository) generateID() string {
+	return uuid.New().String()
+}
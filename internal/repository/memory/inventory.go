@@ -0,0 +1,284 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"payment-service/internal/domain/inventory"
+	"payment-service/pkg/store"
+)
+
+type InventoryRepository struct {
+	warehouses   map[string]inventory.Warehouse
+	stock        map[stockKey]inventory.Stock
+	reservations map[string]inventory.Reservation
+	sync.RWMutex
+}
+
+type stockKey struct {
+	productID   string
+	warehouseID string
+}
+
+func NewInventoryRepository() *InventoryRepository {
+	return &InventoryRepository{
+		warehouses:   make(map[string]inventory.Warehouse),
+		stock:        make(map[stockKey]inventory.Stock),
+		reservations: make(map[string]inventory.Reservation),
+	}
+}
+
+func (r *InventoryRepository) SelectWarehouses(ctx context.Context) (dest []inventory.Warehouse, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]inventory.Warehouse, 0, len(r.warehouses))
+	for _, data := range r.warehouses {
+		if store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].Code < dest[j].Code
+	})
+
+	return
+}
+
+func (r *InventoryRepository) CreateWarehouse(ctx context.Context, data inventory.Warehouse) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	for _, warehouse := range r.warehouses {
+		if warehouse.TenantID == data.TenantID && warehouse.Code == data.Code {
+			err = store.ErrorAlreadyExists
+			return
+		}
+	}
+
+	id = r.generateID()
+	data.ID = id
+	data.CreatedAt = time.Now()
+	r.warehouses[id] = data
+
+	return
+}
+
+func (r *InventoryRepository) SelectStock(ctx context.Context, productID string) (dest []inventory.Stock, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]inventory.Stock, 0)
+	for key, data := range r.stock {
+		if (productID == "" || key.productID == productID) && store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		if dest[i].ProductID != dest[j].ProductID {
+			return dest[i].ProductID < dest[j].ProductID
+		}
+		return dest[i].WarehouseID < dest[j].WarehouseID
+	})
+
+	return
+}
+
+func (r *InventoryRepository) SetStock(ctx context.Context, data inventory.Stock) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	// the stock belongs to the tenant of the product, the warehouse has to be one of its own
+	warehouse, ok := r.warehouses[data.WarehouseID]
+	if !ok || !store.TenantAllows(ctx, warehouse.TenantID) || warehouse.TenantID != data.TenantID {
+		return store.ErrorNotFound
+	}
+
+	key := stockKey{productID: data.ProductID, warehouseID: data.WarehouseID}
+	current := r.stock[key]
+	if data.OnHand.LessThan(current.Reserved) {
+		return inventory.ErrInsufficientStock
+	}
+
+	current.TenantID = warehouse.TenantID
+	current.ProductID = data.ProductID
+	current.WarehouseID = data.WarehouseID
+	current.OnHand = data.OnHand
+	current.UpdatedAt = time.Now()
+	r.stock[key] = current
+
+	return
+}
+
+func (r *InventoryRepository) Allocate(ctx context.Context, productID string, quantity decimal.Decimal) (dest []inventory.Allocation, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	var stocks []inventory.Stock
+	available := decimal.Zero
+	for key, data := range r.stock {
+		if key.productID == productID && data.Available().IsPositive() && store.TenantAllows(ctx, data.TenantID) {
+			stocks = append(stocks, data)
+			available = available.Add(data.Available())
+		}
+	}
+
+	if available.LessThan(quantity) {
+		err = inventory.ErrInsufficientStock
+		return
+	}
+
+	sort.Slice(stocks, func(i, j int) bool {
+		return stocks[i].Available().GreaterThan(stocks[j].Available())
+	})
+
+	rest := quantity
+	for _, data := range stocks {
+		if !rest.IsPositive() {
+			break
+		}
+
+		taken := decimal.Min(rest, data.Available())
+		rest = rest.Sub(taken)
+
+		data.Reserved = data.Reserved.Add(taken)
+		data.UpdatedAt = time.Now()
+		r.stock[stockKey{productID: data.ProductID, warehouseID: data.WarehouseID}] = data
+
+		dest = append(dest, inventory.Allocation{
+			ProductID:   data.ProductID,
+			WarehouseID: data.WarehouseID,
+			Quantity:    taken,
+		})
+	}
+
+	return
+}
+
+func (r *InventoryRepository) Deallocate(ctx context.Context, data []inventory.Allocation, commit bool) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, allocation := range data {
+		key := stockKey{productID: allocation.ProductID, warehouseID: allocation.WarehouseID}
+		current, ok := r.stock[key]
+		if !ok || !store.TenantAllows(ctx, current.TenantID) || current.Reserved.LessThan(allocation.Quantity) {
+			return inventory.ErrInsufficientStock
+		}
+	}
+
+	for _, allocation := range data {
+		key := stockKey{productID: allocation.ProductID, warehouseID: allocation.WarehouseID}
+
+		current := r.stock[key]
+		current.Reserved = current.Reserved.Sub(allocation.Quantity)
+		if commit {
+			current.OnHand = current.OnHand.Sub(allocation.Quantity)
+		}
+		current.UpdatedAt = time.Now()
+		r.stock[key] = current
+	}
+
+	return
+}
+
+func (r *InventoryRepository) CreateReservation(ctx context.Context, data inventory.Reservation) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	id = r.generateID()
+	data.ID = id
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	data.CreatedAt = time.Now()
+	data.UpdatedAt = data.CreatedAt
+	data.Allocations = append([]inventory.Allocation(nil), data.Allocations...)
+	r.reservations[id] = data
+
+	return
+}
+
+func (r *InventoryRepository) GetReservation(ctx context.Context, id string) (dest inventory.Reservation, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest, ok := r.reservations[id]
+	if !ok || !store.TenantAllows(ctx, dest.TenantID) {
+		err = store.ErrorNotFound
+		return
+	}
+	dest.Allocations = append([]inventory.Allocation(nil), dest.Allocations...)
+
+	return
+}
+
+func (r *InventoryRepository) GetReservationByBillingID(ctx context.Context, billingID string) (dest inventory.Reservation, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, data := range r.reservations {
+		if data.BillingID == billingID && store.TenantAllows(ctx, data.TenantID) {
+			dest = data
+			dest.Allocations = append([]inventory.Allocation(nil), data.Allocations...)
+			return
+		}
+	}
+	err = store.ErrorNotFound
+
+	return
+}
+
+func (r *InventoryRepository) UpdateReservation(ctx context.Context, id string, data inventory.Reservation) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	current, ok := r.reservations[id]
+	if !ok || !store.TenantAllows(ctx, current.TenantID) {
+		return store.ErrorNotFound
+	}
+
+	if data.Status != "" {
+		current.Status = data.Status
+	}
+
+	if data.OrderID != "" {
+		current.OrderID = data.OrderID
+	}
+
+	if data.BillingID != "" {
+		current.BillingID = data.BillingID
+	}
+	current.UpdatedAt = time.Now()
+	r.reservations[id] = current
+
+	return
+}
+
+func (r *InventoryRepository) SelectExpiredReservations(ctx context.Context, before time.Time) (dest []inventory.Reservation, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, data := range r.reservations {
+		if data.Status == inventory.StatusActive && data.ExpiresAt.Before(before) && store.TenantAllows(ctx, data.TenantID) {
+			data.Allocations = append([]inventory.Allocation(nil), data.Allocations...)
+			dest = append(dest, data)
+		}
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].ExpiresAt.Before(dest[j].ExpiresAt)
+	})
+
+	return
+}
+
+func (r *InventoryRepository) generateID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/domain/ledger"
+	"payment-service/pkg/store"
+)
+
+type LedgerRepository struct {
+	accounts map[string]ledger.Account
+	entries  []ledger.Entry
+	sync.RWMutex
+}
+
+func NewLedgerRepository() *LedgerRepository {
+	return &LedgerRepository{
+		accounts: make(map[string]ledger.Account),
+	}
+}
+
+func (r *LedgerRepository) EnsureAccount(ctx context.Context, data ledger.Account) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if account, ok := r.accounts[data.Code]; ok {
+		return account.ID, nil
+	}
+
+	data.ID = r.generateID()
+	data.CreatedAt = time.Now()
+	r.accounts[data.Code] = data
+
+	return data.ID, nil
+}
+
+func (r *LedgerRepository) SelectAccounts(ctx context.Context) (dest []ledger.Account, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]ledger.Account, 0, len(r.accounts))
+	for _, data := range r.accounts {
+		dest = append(dest, data)
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].Code < dest[j].Code
+	})
+
+	return
+}
+
+func (r *LedgerRepository) GetAccountByCode(ctx context.Context, code string) (dest ledger.Account, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest, ok := r.accounts[code]
+	if !ok {
+		err = store.ErrorNotFound
+		return
+	}
+
+	return
+}
+
+func (r *LedgerRepository) CreateEntry(ctx context.Context, data ledger.Entry) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, entry := range r.entries {
+		if entry.DedupKey == data.DedupKey {
+			return entry.ID, nil
+		}
+	}
+
+	data.ID = r.generateID()
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	data.CreatedAt = time.Now()
+
+	// postings are copied, so the caller can't change an entry after it has been stored
+	postings := make([]ledger.Posting, len(data.Postings))
+	for i, posting := range data.Postings {
+		posting.ID = r.generateID()
+		posting.EntryID = data.ID
+		postings[i] = posting
+	}
+	data.Postings = postings
+	r.entries = append(r.entries, data)
+
+	return data.ID, nil
+}
+
+func (r *LedgerRepository) SelectEntries(ctx context.Context, billingID string) (dest []ledger.Entry, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]ledger.Entry, 0)
+	for _, data := range r.entries {
+		if (billingID == "" || data.BillingID == billingID) && store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
+	}
+
+	return
+}
+
+func (r *LedgerRepository) Balance(ctx context.Context, accountID, currency string, from, to time.Time) (dest ledger.Balance, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, data := range r.entries {
+		if !store.TenantAllows(ctx, data.TenantID) {
+			continue
+		}
+
+		if !from.IsZero() && data.CreatedAt.Before(from) || !to.IsZero() && !data.CreatedAt.Before(to) {
+			continue
+		}
+
+		for _, posting := range data.Postings {
+			if posting.AccountID != accountID || posting.Currency != currency {
+				continue
+			}
+
+			if posting.Amount.IsPositive() {
+				dest.Debit = dest.Debit.Add(posting.Amount)
+			} else {
+				dest.Credit = dest.Credit.Sub(posting.Amount)
+			}
+		}
+	}
+
+	return
+}
+
+func (r *LedgerRepository) generateID() string {
+	return uuid.New().String()
+}
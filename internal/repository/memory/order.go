@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/domain/order"
+	"payment-service/pkg/store"
+)
+
+type OrderRepository struct {
+	db map[string]order.Entity
+	sync.RWMutex
+}
+
+func NewOrderRepository() *OrderRepository {
+	return &OrderRepository{
+		db: make(map[string]order.Entity),
+	}
+}
+
+func (r *OrderRepository) Select(ctx context.Context) (dest []order.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]order.Entity, 0, len(r.db))
+	for _, data := range r.db {
+		if !store.TenantAllows(ctx, data.TenantID) {
+			continue
+		}
+		data.Items = nil
+		dest = append(dest, data)
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].CreatedAt.After(dest[j].CreatedAt)
+	})
+
+	return
+}
+
+func (r *OrderRepository) Create(ctx context.Context, data order.Entity) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	id = r.generateID()
+	data.ID = id
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	data.CreatedAt = time.Now()
+	data.UpdatedAt = data.CreatedAt
+
+	items := make([]order.Item, len(data.Items))
+	for i, item := range data.Items {
+		item.OrderID = id
+		items[i] = item
+	}
+	data.Items = items
+	r.db[id] = data
+
+	return
+}
+
+func (r *OrderRepository) Get(ctx context.Context, id string) (dest order.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest, ok := r.db[id]
+	if !ok || !store.TenantAllows(ctx, dest.TenantID) {
+		return order.Entity{}, store.ErrorNotFound
+	}
+	dest.Items = append([]order.Item(nil), dest.Items...)
+
+	return
+}
+
+func (r *OrderRepository) GetByBillingID(ctx context.Context, billingID string) (dest order.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, data := range r.db {
+		if data.BillingID != "" && data.BillingID == billingID && store.TenantAllows(ctx, data.TenantID) {
+			dest = data
+			dest.Items = append([]order.Item(nil), data.Items...)
+			return
+		}
+	}
+	err = store.ErrorNotFound
+
+	return
+}
+
+func (r *OrderRepository) Update(ctx context.Context, id string, data order.Entity) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	current, ok := r.db[id]
+	if !ok || !store.TenantAllows(ctx, current.TenantID) {
+		return store.ErrorNotFound
+	}
+
+	if data.Status != "" {
+		current.Status = data.Status
+	}
+	if data.BillingID != "" {
+		current.BillingID = data.BillingID
+	}
+	current.UpdatedAt = time.Now()
+	r.db[id] = current
+
+	return
+}
+
+func (r *OrderRepository) generateID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/domain/outbox"
+	"payment-service/pkg/store"
+)
+
+type OutboxRepository struct {
+	db     map[string]outbox.Entity
+	leases map[string]time.Time
+	sync.Mutex
+}
+
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{
+		db:     make(map[string]outbox.Entity),
+		leases: make(map[string]time.Time),
+	}
+}
+
+func (r *OutboxRepository) Create(ctx context.Context, data outbox.Entity) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, event := range r.db {
+		if event.DedupKey == data.DedupKey {
+			return event.ID, nil
+		}
+	}
+
+	id = r.generateID()
+	data.ID = id
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	r.db[id] = data
+
+	return
+}
+
+func (r *OutboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) (dest []outbox.Entity, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	now := time.Now()
+	for id, data := range r.db {
+		if data.PublishedAt != nil || r.leases[id].After(now) {
+			continue
+		}
+		dest = append(dest, data)
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].CreatedAt.Before(dest[j].CreatedAt)
+	})
+	if len(dest) > limit {
+		dest = dest[:limit]
+	}
+
+	for _, data := range dest {
+		r.leases[data.ID] = now.Add(lease)
+	}
+
+	return
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id string) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	data, ok := r.db[id]
+	if !ok {
+		return store.ErrorNotFound
+	}
+
+	now := time.Now()
+	data.PublishedAt = &now
+	r.db[id] = data
+	delete(r.leases, id)
+
+	return
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id string, reason string) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	data, ok := r.db[id]
+	if !ok {
+		return store.ErrorNotFound
+	}
+
+	data.Attempts++
+	data.LastError = &reason
+	r.db[id] = data
+
+	return
+}
+
+func (r *OutboxRepository) generateID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/domain/price"
+	"payment-service/pkg/store"
+)
+
+type PriceRepository struct {
+	lists  map[string]price.List
+	prices []price.Entity
+	sync.RWMutex
+}
+
+func NewPriceRepository() *PriceRepository {
+	return &PriceRepository{
+		lists: make(map[string]price.List),
+	}
+}
+
+func (r *PriceRepository) SelectLists(ctx context.Context) (dest []price.List, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]price.List, 0, len(r.lists))
+	for _, data := range r.lists {
+		if store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].Code < dest[j].Code
+	})
+
+	return
+}
+
+func (r *PriceRepository) CreateList(ctx context.Context, data price.List) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	// codes only have to differ within a tenant
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	for _, list := range r.lists {
+		if list.Code == data.Code && list.TenantID == data.TenantID {
+			err = store.ErrorAlreadyExists
+			return
+		}
+	}
+
+	id = r.generateID()
+	data.ID = id
+	data.CreatedAt = time.Now()
+	r.lists[id] = data
+
+	return
+}
+
+func (r *PriceRepository) GetList(ctx context.Context, idOrCode string) (dest price.List, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	if dest, ok := r.lists[idOrCode]; ok && store.TenantAllows(ctx, dest.TenantID) {
+		return dest, nil
+	}
+
+	for _, list := range r.lists {
+		if list.Code == idOrCode && store.TenantAllows(ctx, list.TenantID) {
+			return list, nil
+		}
+	}
+	err = store.ErrorNotFound
+
+	return
+}
+
+func (r *PriceRepository) Select(ctx context.Context, filter price.Filter) (dest []price.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]price.Entity, 0)
+	for _, data := range r.prices {
+		if (filter.ProductID == "" || data.ProductID == filter.ProductID) &&
+			(filter.ListID == "" || data.ListID == filter.ListID) &&
+			(filter.Currency == "" || data.Currency == filter.Currency) && r.visible(ctx, data) {
+			dest = append(dest, data)
+		}
+	}
+
+	sort.SliceStable(dest, func(i, j int) bool {
+		return dest[i].Supersedes(dest[j])
+	})
+
+	return
+}
+
+func (r *PriceRepository) Create(ctx context.Context, data price.Entity) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	id = r.generateID()
+	data.ID = id
+	data.CreatedAt = time.Now()
+	r.prices = append(r.prices, data)
+
+	return
+}
+
+func (r *PriceRepository) GetEffective(ctx context.Context, productID, listID, currency string, at time.Time) (dest price.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	found := false
+	for _, data := range r.prices {
+		if data.ProductID != productID || data.ListID != listID || data.Currency != currency || !data.EffectiveAt(at) ||
+			!r.visible(ctx, data) {
+			continue
+		}
+
+		if !found || data.Supersedes(dest) {
+			dest, found = data, true
+		}
+	}
+
+	if !found {
+		err = price.ErrPriceNotFound
+	}
+
+	return
+}
+
+// visible tells whether the list of the price belongs to the tenant of the context, prices have no tenant of their own.
+func (r *PriceRepository) visible(ctx context.Context, data price.Entity) bool {
+	return store.TenantAllows(ctx, r.lists[data.ListID].TenantID)
+}
+
+func (r *PriceRepository) generateID() string {
+	return uuid.New().String()
+}
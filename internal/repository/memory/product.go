@@ -2,7 +2,13 @@ package memory
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 
@@ -27,7 +33,99 @@ func (r *ProductRepository) Select(ctx context.Context) (dest []product.Entity,
 
 	dest = make([]product.Entity, 0, len(r.db))
 	for _, data := range r.db {
-		dest = append(dest, data)
+		if data.DeletedAt == nil && store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].CreatedAt.Before(dest[j].CreatedAt)
+	})
+
+	return
+}
+
+// productHit is a product matching the search with its relevance.
+type productHit struct {
+	data product.Entity
+	rank float64
+}
+
+// Search matches products whose name, brand or description contain every word of the query,
+// words in the name weigh the most and in the description the least, as the postgres ranking does.
+func (r *ProductRepository) Search(ctx context.Context, filter product.Filter) (dest product.Page, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	terms := searchTerms(filter.Query)
+
+	var hits []productHit
+	for _, data := range r.db {
+		if filter.Deleted != (data.DeletedAt != nil) || !store.TenantAllows(ctx, data.TenantID) {
+			continue
+		}
+
+		if filter.Deleted && !filter.DeletedBefore.IsZero() && !data.DeletedAt.Before(filter.DeletedBefore) {
+			continue
+		}
+
+		if filter.CategoryID != "" && data.CategoryID != filter.CategoryID {
+			continue
+		}
+
+		if len(filter.CategoryIDs) > 0 && !containsString(filter.CategoryIDs, data.CategoryID) {
+			continue
+		}
+
+		if filter.Country != "" && !strings.EqualFold(stringValue(data.Country), filter.Country) {
+			continue
+		}
+
+		if filter.Brand != "" && !strings.EqualFold(stringValue(data.Brand), filter.Brand) {
+			continue
+		}
+
+		if filter.ParentID != "" && data.ParentID != filter.ParentID {
+			continue
+		}
+
+		if len(filter.Attributes) > 0 && !matchAttributes(data.Attributes, filter.Attributes) {
+			continue
+		}
+
+		rank, ok := rankProduct(data, terms)
+		if !ok {
+			continue
+		}
+		hits = append(hits, productHit{data: data, rank: rank})
+	}
+	dest.Total = len(hits)
+
+	sort.Slice(hits, func(i, j int) bool {
+		return compareHits(filter.Sort, hits[i], hits[j]) < 0
+	})
+
+	if filter.After != nil {
+		after, err := hitFromCursor(*filter.After)
+		if err != nil {
+			return dest, err
+		}
+
+		start := sort.Search(len(hits), func(i int) bool {
+			return compareHits(filter.Sort, hits[i], after) > 0
+		})
+		hits = hits[start:]
+	}
+
+	if filter.Limit > 0 && len(hits) > filter.Limit {
+		hits = hits[:filter.Limit]
+		last := hits[len(hits)-1]
+		dest.Next = product.NewCursor(filter.Sort, last.data, last.rank)
+	}
+
+	dest.Items = make([]product.Entity, 0, len(hits))
+	for _, hit := range hits {
+		dest.Items = append(dest.Items, hit.data)
 	}
 
 	return
@@ -37,8 +135,16 @@ func (r *ProductRepository) Create(ctx context.Context, data product.Entity) (de
 	r.Lock()
 	defer r.Unlock()
 
+	data.TenantID = store.ResolveTenant(ctx, data.TenantID)
+	if r.barcodeTaken(data.TenantID, data.Barcode, "") {
+		return "", store.ErrorAlreadyExists
+	}
+
 	id := r.generateID()
 	data.ID = id
+	data.Version = 1
+	data.CreatedAt = time.Now()
+	data.UpdatedAt = data.CreatedAt
 	r.db[id] = data
 
 	return id, nil
@@ -48,7 +154,7 @@ func (r *ProductRepository) Get(ctx context.Context, id string) (dest product.En
 	r.RLock()
 	defer r.RUnlock()
 
-	dest, ok := r.db[id]
+	dest, ok := r.live(ctx, id)
 	if !ok {
 		err = store.ErrorNotFound
 		return
@@ -57,23 +163,200 @@ func (r *ProductRepository) Get(ctx context.Context, id string) (dest product.En
 	return
 }
 
+func (r *ProductRepository) GetDeleted(ctx context.Context, id string) (dest product.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest, ok := r.db[id]
+	if !ok || dest.DeletedAt == nil || !store.TenantAllows(ctx, dest.TenantID) {
+		return product.Entity{}, store.ErrorNotFound
+	}
+
+	return
+}
+
+func (r *ProductRepository) GetByBarcode(ctx context.Context, barcode string) (dest product.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, data := range r.db {
+		if data.DeletedAt == nil && stringValue(data.Barcode) == barcode && store.TenantAllows(ctx, data.TenantID) {
+			return data, nil
+		}
+	}
+	err = store.ErrorNotFound
+
+	return
+}
+
+func (r *ProductRepository) SelectByBarcodes(ctx context.Context, barcodes []string) (dest []product.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]product.Entity, 0)
+	for _, data := range r.db {
+		if data.DeletedAt == nil && containsString(barcodes, stringValue(data.Barcode)) && store.TenantAllows(ctx, data.TenantID) {
+			dest = append(dest, data)
+		}
+	}
+
+	return
+}
+
+func (r *ProductRepository) Upsert(ctx context.Context, data []product.Entity) (dest []product.Entity, created, updated int, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	// barcodes only have to differ within the tenant the products are imported into
+	tenantID := store.TenantID(ctx)
+	ids := make(map[string]string, len(r.db))
+	for id, current := range r.db {
+		if barcode := stringValue(current.Barcode); barcode != "" && current.DeletedAt == nil && current.TenantID == tenantID {
+			ids[barcode] = id
+		}
+	}
+
+	now := time.Now()
+	dest = make([]product.Entity, 0, len(data))
+	for _, object := range data {
+		barcode := stringValue(object.Barcode)
+
+		// only the imported fields change, like in the postgres store
+		if id, ok := ids[barcode]; ok && barcode != "" {
+			current := r.db[id]
+			object.ID = id
+			object.TenantID = current.TenantID
+			object.ParentID = current.ParentID
+			object.Attributes = current.Attributes
+			object.Version = current.Version + 1
+			object.CreatedAt = current.CreatedAt
+			object.UpdatedAt = now
+			r.db[id] = object
+			dest = append(dest, object)
+			updated++
+			continue
+		}
+
+		object.ID = r.generateID()
+		object.TenantID = tenantID
+		object.Version = 1
+		object.CreatedAt = now
+		object.UpdatedAt = now
+		r.db[object.ID] = object
+		if barcode != "" {
+			ids[barcode] = object.ID
+		}
+		dest = append(dest, object)
+		created++
+	}
+
+	return
+}
+
 func (r *ProductRepository) Update(ctx context.Context, id string, data product.Entity) (err error) {
 	r.Lock()
 	defer r.Unlock()
 
-	if _, ok := r.db[id]; !ok {
+	current, ok := r.live(ctx, id)
+	if !ok {
 		return store.ErrorNotFound
 	}
-	r.db[id] = data
+
+	if data.Version != 0 && data.Version != current.Version {
+		return store.ErrorStaleVersion
+	}
+
+	if r.barcodeTaken(current.TenantID, data.Barcode, id) {
+		return store.ErrorAlreadyExists
+	}
+
+	if data.CategoryID != "" {
+		current.CategoryID = data.CategoryID
+	}
+
+	if data.Name != "" {
+		current.Name = data.Name
+	}
+
+	for _, field := range []struct {
+		dest  **string
+		value *string
+	}{
+		{&current.Description, data.Description},
+		{&current.Measure, data.Measure},
+		{&current.ImageURL, data.ImageURL},
+		{&current.Country, data.Country},
+		{&current.Barcode, data.Barcode},
+		{&current.Brand, data.Brand},
+	} {
+		if field.value != nil {
+			value := *field.value
+			*field.dest = &value
+		}
+	}
+
+	if data.Attributes != nil {
+		current.Attributes = data.Attributes
+	}
+	current.Version++
+	current.UpdatedAt = time.Now()
+	r.db[id] = current
+
+	return
+}
+
+func (r *ProductRepository) Delete(ctx context.Context, id string, version int, trashedWith string) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	current, ok := r.live(ctx, id)
+	if !ok {
+		return store.ErrorNotFound
+	}
+
+	if version != 0 && version != current.Version {
+		return store.ErrorStaleVersion
+	}
+
+	now := time.Now()
+	current.DeletedAt = &now
+	current.TrashedWith = trashedWith
+	current.Version++
+	current.UpdatedAt = now
+	r.db[id] = current
 
 	return
 }
 
-func (r *ProductRepository) Delete(ctx context.Context, id string) (err error) {
+func (r *ProductRepository) Restore(ctx context.Context, id string) (err error) {
 	r.Lock()
 	defer r.Unlock()
 
-	if _, ok := r.db[id]; !ok {
+	current, ok := r.db[id]
+	if !ok || current.DeletedAt == nil || !store.TenantAllows(ctx, current.TenantID) {
+		return store.ErrorNotFound
+	}
+
+	if r.barcodeTaken(current.TenantID, current.Barcode, id) {
+		return store.ErrorAlreadyExists
+	}
+
+	current.DeletedAt = nil
+	current.TrashedWith = ""
+	current.Version++
+	current.UpdatedAt = time.Now()
+	r.db[id] = current
+
+	return
+}
+
+// Purge removes the product, the memory store doesn't look for orders referring to it.
+func (r *ProductRepository) Purge(ctx context.Context, id string) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	current, ok := r.db[id]
+	if !ok || current.DeletedAt == nil || !store.TenantAllows(ctx, current.TenantID) {
 		return store.ErrorNotFound
 	}
 	delete(r.db, id)
@@ -81,6 +364,157 @@ func (r *ProductRepository) Delete(ctx context.Context, id string) (err error) {
 	return
 }
 
+// live returns the product unless it is missing, in the trash or of another tenant than the one of the context.
+func (r *ProductRepository) live(ctx context.Context, id string) (data product.Entity, ok bool) {
+	data, ok = r.db[id]
+	return data, ok && data.DeletedAt == nil && store.TenantAllows(ctx, data.TenantID)
+}
+
+// barcodeTaken tells whether another product of the tenant than the one with the id has the barcode, empty barcodes
+// are never taken and products in the trash don't hold theirs.
+func (r *ProductRepository) barcodeTaken(tenantID string, barcode *string, id string) bool {
+	if stringValue(barcode) == "" {
+		return false
+	}
+
+	for key, data := range r.db {
+		if key != id && data.TenantID == tenantID && data.DeletedAt == nil && stringValue(data.Barcode) == *barcode {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (r *ProductRepository) generateID() string {
 	return uuid.New().String()
 }
+
+// searchTerms splits text into lower-case words the way the simple text search configuration does.
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// rankProduct sums the weight of the best field every term is found in, ok is false when a term is missing.
+func rankProduct(data product.Entity, terms []string) (rank float64, ok bool) {
+	fields := []struct {
+		words  []string
+		weight float64
+	}{
+		{searchTerms(data.Name), 1.0},
+		{searchTerms(stringValue(data.Brand)), 0.4},
+		{searchTerms(stringValue(data.Description)), 0.2},
+	}
+
+	for _, term := range terms {
+		weight := 0.0
+		for _, field := range fields {
+			for _, word := range field.words {
+				if word == term && field.weight > weight {
+					weight = field.weight
+				}
+			}
+		}
+
+		if weight == 0 {
+			return 0, false
+		}
+		rank += weight
+	}
+
+	return rank, true
+}
+
+// matchAttributes tells whether the product has one of the values of every attribute, values match when their JSON
+// types agree as the postgres containment does.
+func matchAttributes(data json.RawMessage, filter map[string][]any) bool {
+	var attributes map[string]any
+	if len(data) > 0 && json.Unmarshal(data, &attributes) != nil {
+		return false
+	}
+
+	for code, values := range filter {
+		value, ok := attributes[code]
+		if !ok {
+			return false
+		}
+
+		found := false
+		for _, candidate := range values {
+			if candidate == value {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareHits orders products by the sort key, ties and products without a key are ordered by id.
+func compareHits(sort string, a, b productHit) (c int) {
+	switch sort {
+	case product.SortName:
+		c = strings.Compare(a.data.Name, b.data.Name)
+	case product.SortNameDesc:
+		c = -strings.Compare(a.data.Name, b.data.Name)
+	case product.SortCreatedAt, product.SortCreatedAtDesc:
+		switch {
+		case a.data.CreatedAt.Before(b.data.CreatedAt):
+			c = -1
+		case a.data.CreatedAt.After(b.data.CreatedAt):
+			c = 1
+		}
+
+		if sort == product.SortCreatedAtDesc {
+			c = -c
+		}
+	default:
+		switch {
+		case a.rank > b.rank:
+			c = -1
+		case a.rank < b.rank:
+			c = 1
+		}
+	}
+
+	if c == 0 {
+		c = strings.Compare(a.data.ID, b.data.ID)
+	}
+
+	return
+}
+
+// hitFromCursor restores the sort key of the last product of the previous page.
+func hitFromCursor(cursor product.Cursor) (hit productHit, err error) {
+	hit.data.ID = cursor.ID
+
+	switch cursor.Sort {
+	case product.SortName, product.SortNameDesc:
+		hit.data.Name = cursor.Key
+	case product.SortCreatedAt, product.SortCreatedAtDesc:
+		hit.data.CreatedAt, err = time.Parse(time.RFC3339Nano, cursor.Key)
+	default:
+		hit.rank, err = strconv.ParseFloat(cursor.Key, 64)
+	}
+
+	if err != nil {
+		err = product.ErrInvalidCursor
+	}
+
+	return
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/domain/settlement"
+	"payment-service/pkg/store"
+)
+
+type SettlementRepository struct {
+	db map[string]settlement.Statement
+	sync.RWMutex
+}
+
+func NewSettlementRepository() *SettlementRepository {
+	return &SettlementRepository{
+		db: make(map[string]settlement.Statement),
+	}
+}
+
+func (r *SettlementRepository) Create(ctx context.Context, data settlement.Statement) (id string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	id = r.generateID()
+	data.ID = id
+	data.CreatedAt = time.Now()
+	data.Rows = nil
+	r.db[id] = data
+
+	return
+}
+
+func (r *SettlementRepository) Select(ctx context.Context) (dest []settlement.Statement, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]settlement.Statement, 0, len(r.db))
+	for _, data := range r.db {
+		data.Discrepancies = nil
+		dest = append(dest, data)
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].CreatedAt.After(dest[j].CreatedAt)
+	})
+
+	return
+}
+
+func (r *SettlementRepository) Get(ctx context.Context, id string) (dest settlement.Statement, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest, ok := r.db[id]
+	if !ok {
+		err = store.ErrorNotFound
+		return
+	}
+
+	return
+}
+
+func (r *SettlementRepository) generateID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"payment-service/internal/domain/tenant"
+	"payment-service/pkg/store"
+)
+
+type TenantRepository struct {
+	db map[string]tenant.Entity
+	sync.RWMutex
+}
+
+func NewTenantRepository() *TenantRepository {
+	return &TenantRepository{
+		db: make(map[string]tenant.Entity),
+	}
+}
+
+func (r *TenantRepository) Select(ctx context.Context) (dest []tenant.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest = make([]tenant.Entity, 0, len(r.db))
+	for _, data := range r.db {
+		dest = append(dest, data)
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].ID < dest[j].ID
+	})
+
+	return
+}
+
+func (r *TenantRepository) Get(ctx context.Context, id string) (dest tenant.Entity, err error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	dest, ok := r.db[id]
+	if !ok {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (r *TenantRepository) Save(ctx context.Context, data tenant.Entity) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	data.UpdatedAt = time.Now()
+	data.CreatedAt = data.UpdatedAt
+	if current, ok := r.db[data.ID]; ok {
+		data.CreatedAt = current.CreatedAt
+	}
+	data.WebhookURLs = append([]string(nil), data.WebhookURLs...)
+	r.db[data.ID] = data
+
+	return
+}
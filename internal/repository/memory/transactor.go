@@ -0,0 +1,25 @@
+package memory
+
+import (
+	"context"
+
+	"payment-service/pkg/store"
+)
+
+// Transactor runs the unit of work as is: the memory store has nothing to roll back. The functions given to
+// store.AfterCommit run once the unit of work succeeds, as they do after a database commit.
+type Transactor struct{}
+
+func NewTransactor() *Transactor {
+	return &Transactor{}
+}
+
+func (t *Transactor) Transact(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	ctx, committed := store.DeferAfterCommit(ctx)
+	if err = fn(ctx); err != nil {
+		return
+	}
+
+	committed()
+	return
+}
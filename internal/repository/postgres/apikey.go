@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"payment-service/internal/domain/apikey"
+	"payment-service/pkg/store"
+)
+
+type APIKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+const apiKeyColumns = `
+	created_at, id, tenant_id, name, hash, scopes, revoked_at`
+
+// apiKeyRow scans the scopes array into the entity.
+type apiKeyRow struct {
+	apikey.Entity
+	Scopes pq.StringArray `db:"scopes"`
+}
+
+func (s *APIKeyRepository) Select(ctx context.Context) (dest []apikey.Entity, err error) {
+	var args []any
+	query := `
+		SELECT` + apiKeyColumns + `
+		FROM api_keys
+		WHERE ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY created_at DESC`
+
+	var rows []apiKeyRow
+	if err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &rows, query, args...); err != nil {
+		return
+	}
+
+	dest = make([]apikey.Entity, 0, len(rows))
+	for _, row := range rows {
+		row.Entity.Scopes = row.Scopes
+		dest = append(dest, row.Entity)
+	}
+
+	return
+}
+
+func (s *APIKeyRepository) Create(ctx context.Context, data apikey.Entity) (id string, err error) {
+	query := `
+		INSERT INTO api_keys (tenant_id, name, hash, scopes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+	args := []any{store.ResolveTenant(ctx, data.TenantID), data.Name, data.Hash, pq.Array(data.Scopes)}
+
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+	err = checkUniqueViolation(err)
+
+	return
+}
+
+func (s *APIKeyRepository) Get(ctx context.Context, id string) (dest apikey.Entity, err error) {
+	return s.get(ctx, "id=$1", id)
+}
+
+func (s *APIKeyRepository) GetByHash(ctx context.Context, hash string) (dest apikey.Entity, err error) {
+	return s.get(ctx, "hash=$1 AND revoked_at IS NULL", hash)
+}
+
+func (s *APIKeyRepository) get(ctx context.Context, condition, value string) (dest apikey.Entity, err error) {
+	args := []any{value}
+	query := `
+		SELECT` + apiKeyColumns + `
+		FROM api_keys
+		WHERE ` + condition + ` AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	var row apiKeyRow
+	if err = sqlx.GetContext(ctx, store.Executor(ctx, s.db), &row, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+		return
+	}
+
+	dest = row.Entity
+	dest.Scopes = row.Scopes
+
+	return
+}
+
+func (s *APIKeyRepository) Revoke(ctx context.Context, id string) (err error) {
+	args := []any{id}
+	query := `
+		UPDATE api_keys
+		SET revoked_at=CURRENT_TIMESTAMP
+		WHERE id=$1 AND revoked_at IS NULL AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	res, err := store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	return checkRowsAffected(res)
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"payment-service/internal/domain/category"
+	"payment-service/pkg/store"
+)
+
+const attributeColumns = `
+	created_at, id, tenant_id, category_id, code, name, type, required, options`
+
+type AttributeRepository struct {
+	db *sqlx.DB
+}
+
+func NewAttributeRepository(db *sqlx.DB) *AttributeRepository {
+	return &AttributeRepository{
+		db: db,
+	}
+}
+
+func (s *AttributeRepository) SelectAttributes(ctx context.Context, categoryIDs []string) (dest []category.Attribute, err error) {
+	args := []any{pq.Array(categoryIDs)}
+	query := `
+		SELECT` + attributeColumns + `
+		FROM category_attributes
+		WHERE category_id::TEXT=ANY($1) AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY code, category_id`
+
+	var rows []struct {
+		category.Attribute
+		Options pq.StringArray `db:"options"`
+	}
+	if err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &rows, query, args...); err != nil {
+		return
+	}
+
+	dest = make([]category.Attribute, 0, len(rows))
+	for _, row := range rows {
+		row.Attribute.Options = row.Options
+		dest = append(dest, row.Attribute)
+	}
+
+	return
+}
+
+func (s *AttributeRepository) CreateAttribute(ctx context.Context, data category.Attribute) (id string, err error) {
+	query := `
+		INSERT INTO category_attributes (tenant_id, category_id, code, name, type, required, options)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	args := []any{store.ResolveTenant(ctx, data.TenantID), data.CategoryID, data.Code, data.Name, data.Type,
+		data.Required, pq.Array(data.Options)}
+
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return "", store.ErrorNotFound
+	}
+	err = checkUniqueViolation(err)
+
+	return
+}
+
+func (s *AttributeRepository) DeleteAttribute(ctx context.Context, categoryID, code string) (err error) {
+	args := []any{categoryID, code}
+	query := `
+		DELETE
+		FROM category_attributes
+		WHERE category_id=$1 AND code=$2 AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	res, err := store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	return checkRowsAffected(res)
+}
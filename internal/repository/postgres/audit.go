@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"payment-service/internal/domain/audit"
+	"payment-service/pkg/store"
+)
+
+// AuditRepository appends records within the transaction of the context, so a record is kept only with its write.
+type AuditRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (s *AuditRepository) Create(ctx context.Context, data audit.Entity) (id string, err error) {
+	query := `
+		INSERT INTO audit_records (tenant_id, entity_type, entity_id, action, actor, request_id, diff)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::JSONB)
+		RETURNING id`
+
+	args := []any{store.ResolveTenant(ctx, data.TenantID), data.EntityType, data.EntityID, data.Action, data.Actor,
+		data.RequestID, string(data.Diff)}
+
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+func (s *AuditRepository) Select(ctx context.Context, entityID string) (dest []audit.Entity, err error) {
+	args := []any{entityID}
+	query := `
+		SELECT created_at, id, tenant_id, entity_type, entity_id, action, actor, request_id, diff
+		FROM audit_records
+		WHERE entity_id=$1 AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY created_at`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+// Redact goes through audit_redact, the records refuse any other change.
+func (s *AuditRepository) Redact(ctx context.Context, entityType string, entityIDs, fields []string) (err error) {
+	query := `SELECT audit_redact($1, $2, $3, $4)`
+
+	args := []any{entityType, pq.Array(entityIDs), pq.Array(fields), audit.Redacted}
+
+	_, err = store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+
+	return
+}
@@ -0,0 +1,304 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"payment-service/internal/domain/billing"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"payment-service/pkg/store"
+)
+
+// billingColumns maps the billings table onto billing.Entity.
+const billingColumns = `
+	created_at, updated_at, id, tenant_id, version, correlation_id, source, invoice_id, amount, currency, description, terminal_id,
+	COALESCE(account_id, '') AS account_id, COALESCE(name, '') AS name, COALESCE(phone, '') AS phone,
+	COALESCE(email, '') AS email, COALESCE(language, '') AS language, back_link AS backlink,
+	COALESCE(failure_back_link, '') AS failure_backlink, post_link, COALESCE(failure_post_link, '') AS failure_post_link,
+	COALESCE(payment_type, '') AS payment_type, status, COALESCE(refunded::TEXT, '') AS refunded, COALESCE(reference, '') AS reference,
+	COALESCE(int_reference, '') AS int_reference, COALESCE(fx_rate::TEXT, '') AS fx_rate,
+	COALESCE(base_amount::TEXT, '') AS base_amount, COALESCE(base_currency, '') AS base_currency, paid_at, subject_keys`
+
+type BillingRepository struct {
+	db *sqlx.DB
+}
+
+func NewBillingRepository(db *sqlx.DB) *BillingRepository {
+	return &BillingRepository{
+		db: db,
+	}
+}
+
+func (s *BillingRepository) Select(ctx context.Context) (dest []billing.Entity, err error) {
+	var args []any
+	query := `
+		SELECT` + billingColumns + `
+		FROM billings
+		WHERE ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY created_at`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+func (s *BillingRepository) SelectByFilter(ctx context.Context, filter billing.Filter) (dest []billing.Entity, err error) {
+	wheres, args := s.prepareFilter(filter)
+	wheres = append(wheres, tenantCondition(ctx, "tenant_id", &args))
+
+	query := `
+		SELECT` + billingColumns + `
+		FROM billings
+		WHERE ` + strings.Join(wheres, " AND ") + `
+		ORDER BY created_at`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+func (s *BillingRepository) prepareFilter(filter billing.Filter) (wheres []string, args []any) {
+	if len(filter.Statuses) > 0 {
+		args = append(args, pq.Array(filter.Statuses))
+		wheres = append(wheres, fmt.Sprintf("status=ANY($%d)", len(args)))
+	}
+
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		wheres = append(wheres, fmt.Sprintf("created_at>=$%d", len(args)))
+	}
+
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		wheres = append(wheres, fmt.Sprintf("created_at<$%d", len(args)))
+	}
+
+	if !filter.PaidFrom.IsZero() {
+		args = append(args, filter.PaidFrom)
+		wheres = append(wheres, fmt.Sprintf("paid_at>=$%d", len(args)))
+	}
+
+	if !filter.PaidTo.IsZero() {
+		args = append(args, filter.PaidTo)
+		wheres = append(wheres, fmt.Sprintf("paid_at<$%d", len(args)))
+	}
+
+	if len(filter.Keys) > 0 {
+		args = append(args, pq.Array(filter.Keys))
+		wheres = append(wheres, fmt.Sprintf("(invoice_id=ANY($%[1]d) OR reference=ANY($%[1]d) OR int_reference=ANY($%[1]d))", len(args)))
+	}
+
+	if len(filter.SubjectKeys) > 0 {
+		args = append(args, pq.Array(filter.SubjectKeys))
+		wheres = append(wheres, fmt.Sprintf("subject_keys && $%d::VARCHAR[]", len(args)))
+	}
+
+	return
+}
+
+func (s *BillingRepository) SelectByParentID(ctx context.Context, parentID string) (dest []billing.Entity, err error) {
+	args := []any{parentID}
+	query := `
+		SELECT` + billingColumns + `
+		FROM billings
+		WHERE correlation_id=$1 AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY created_at`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+func (s *BillingRepository) Create(ctx context.Context, data billing.Entity) (id string, err error) {
+	query := `
+		INSERT INTO billings (correlation_id, source, invoice_id, amount, currency, description, terminal_id,
+			account_id, name, phone, email, language, back_link, failure_back_link, post_link, failure_post_link,
+			payment_type, status, fx_rate, base_amount, base_currency, tenant_id, subject_keys)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18,
+			NULLIF($19, '')::NUMERIC, NULLIF($20, '')::NUMERIC, NULLIF($21, ''), $22, $23)
+		RETURNING id`
+
+	args := []any{data.CorrelationID, data.Source, data.InvoiceID, data.Amount, data.Currency, data.Description,
+		data.TerminalID, data.AccountID, data.Name, data.Phone, data.Email, data.Language, data.Backlink,
+		data.FailureBacklink, data.PostLink, data.FailurePostLink, data.PaymentType, data.Status,
+		data.FXRate, data.BaseAmount, data.BaseCurrency, store.ResolveTenant(ctx, data.TenantID), data.SubjectKeys}
+
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+func (s *BillingRepository) Get(ctx context.Context, id string) (dest billing.Entity, err error) {
+	args := []any{id}
+	query := `
+		SELECT` + billingColumns + `
+		FROM billings
+		WHERE id=$1 AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	if err = sqlx.GetContext(ctx, store.Executor(ctx, s.db), &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *BillingRepository) GetByInvoiceID(ctx context.Context, invoiceID string) (dest billing.Entity, err error) {
+	args := []any{invoiceID}
+	query := `
+		SELECT` + billingColumns + `
+		FROM billings
+		WHERE invoice_id=$1 AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	if err = sqlx.GetContext(ctx, store.Executor(ctx, s.db), &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+// Update writes the fields that are set, empty values are left as they are.
+func (s *BillingRepository) Update(ctx context.Context, id string, data billing.Entity) (err error) {
+	sets, args := s.prepareArgs(data)
+
+	args = append(args, id, data.Version)
+	sets = append(sets, "updated_at=CURRENT_TIMESTAMP", "version=version+1")
+
+	db := store.Executor(ctx, s.db)
+	query := fmt.Sprintf("UPDATE billings SET %s WHERE id=$%d AND ($%d=0 OR version=$%d)",
+		strings.Join(sets, ", "), len(args)-1, len(args), len(args))
+	query += " AND " + tenantCondition(ctx, "tenant_id", &args)
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	return checkVersion(ctx, db, "billings", id, res)
+}
+
+func (s *BillingRepository) prepareArgs(data billing.Entity) (sets []string, args []any) {
+	columns := []struct {
+		name  string
+		value string
+	}{
+		{"amount", data.Amount},
+		{"currency", data.Currency},
+		{"description", data.Description},
+		{"account_id", data.AccountID},
+		{"name", data.Name},
+		{"email", data.Email},
+		{"phone", data.Phone},
+		{"language", data.Language},
+		{"back_link", data.Backlink},
+		{"failure_back_link", data.FailureBacklink},
+		{"post_link", data.PostLink},
+		{"failure_post_link", data.FailurePostLink},
+		{"status", data.Status},
+		{"refunded", data.Refunded},
+		{"reference", data.Reference},
+		{"int_reference", data.IntReference},
+		{"fx_rate", data.FXRate},
+		{"base_amount", data.BaseAmount},
+		{"base_currency", data.BaseCurrency},
+	}
+
+	for _, column := range columns {
+		if column.value != "" {
+			args = append(args, column.value)
+			sets = append(sets, fmt.Sprintf("%s=$%d", column.name, len(args)))
+		}
+	}
+
+	if data.PaidAt != nil {
+		args = append(args, *data.PaidAt)
+		sets = append(sets, fmt.Sprintf("paid_at=$%d", len(args)))
+	}
+
+	if data.SubjectKeys != nil {
+		args = append(args, data.SubjectKeys)
+		sets = append(sets, fmt.Sprintf("subject_keys=$%d", len(args)))
+	}
+
+	return
+}
+
+func (s *BillingRepository) Delete(ctx context.Context, id string, version int) (err error) {
+	args := []any{id, version}
+	query := `
+		DELETE
+		FROM billings
+		WHERE id=$1 AND ($2=0 OR version=$2) AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	db := store.Executor(ctx, s.db)
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	return checkVersion(ctx, db, "billings", id, res)
+}
+
+// checkRowsAffected reports store.ErrorNotFound when a statement didn't touch any row.
+func checkRowsAffected(res sql.Result) (err error) {
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rows == 0 {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+// checkVersion tells a missing row, store.ErrorNotFound, from a row whose version has moved on, store.ErrorStaleVersion,
+// when a statement guarded by the version didn't touch any row. The conditions narrow down the rows that count,
+// e.g. to those out of the trash, the table has to be scoped to tenants.
+func checkVersion(ctx context.Context, db sqlx.QueryerContext, table, id string, res sql.Result, conditions ...string) (err error) {
+	if err = checkRowsAffected(res); err != store.ErrorNotFound {
+		return
+	}
+
+	// a row of another tenant is as missing as one that doesn't exist
+	args := []any{id}
+	conditions = append([]string{"id=$1", tenantCondition(ctx, "tenant_id", &args)}, conditions...)
+
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM ` + table + ` WHERE ` + strings.Join(conditions, " AND ") + `)`
+	if err = sqlx.GetContext(ctx, db, &exists, query, args...); err != nil {
+		return
+	}
+
+	if exists {
+		return store.ErrorStaleVersion
+	}
+	return store.ErrorNotFound
+}
+
+// tenantCondition appends the tenant of the context to args and returns the condition limiting the rows to it,
+// an operator sees every row and a context scoped to none no row. Row-level security backs the condition up.
+func tenantCondition(ctx context.Context, column string, args *[]any) string {
+	*args = append(*args, store.TenantScope(ctx))
+	return fmt.Sprintf("($%[1]d='%[3]s' OR %[2]s=NULLIF($%[1]d, ''))", len(*args), column, store.OperatorTenant)
+}
+
+// checkUniqueViolation reports store.ErrorAlreadyExists when a statement broke a unique constraint.
+func checkUniqueViolation(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return store.ErrorAlreadyExists
+	}
+
+	return err
+}
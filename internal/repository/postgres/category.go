@@ -6,12 +6,22 @@ import (
 	"fmt"
 	"payment-service/internal/domain/category"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"payment-service/pkg/store"
 )
 
+// categoryColumns maps the categories table onto category.Entity, a root category has an empty parent id.
+const categoryColumns = `
+	created_at, updated_at, id, tenant_id, version, COALESCE(parent_id::TEXT, '') AS parent_id, name, deleted_at,
+	COALESCE(trashed_with::TEXT, '') AS trashed_with`
+
+// liveCategory leaves the categories in the trash out
+const liveCategory = "deleted_at IS NULL"
+
 type CategoryRepository struct {
 	db *sqlx.DB
 }
@@ -23,50 +33,134 @@ func NewCategoryRepository(db *sqlx.DB) *CategoryRepository {
 }
 
 func (s *CategoryRepository) Select(ctx context.Context) (dest []category.Entity, err error) {
+	var args []any
 	query := `
-		SELECT id, name
-		FROM categories`
+		SELECT` + categoryColumns + `
+		FROM categories
+		WHERE ` + liveCategory + ` AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY created_at`
 
-	err = s.db.SelectContext(ctx, &dest, query)
+	err = s.db.SelectContext(ctx, &dest, query, args...)
 
 	return
 }
 
-func (s *CategoryRepository) SelectByParentID(ctx context.Context, parentID string) (dest []category.Entity, err error) {
+func (s *CategoryRepository) SelectDeleted(ctx context.Context) (dest []category.Entity, err error) {
+	var args []any
 	query := `
-		SELECT id, name
+		SELECT` + categoryColumns + `
 		FROM categories
-		WHERE parent_id=$1`
+		WHERE deleted_at IS NOT NULL AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY deleted_at DESC, created_at`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
 
+	return
+}
+
+func (s *CategoryRepository) SelectByParentID(ctx context.Context, parentID string) (dest []category.Entity, err error) {
 	args := []any{parentID}
+	query := `
+		SELECT` + categoryColumns + `
+		FROM categories
+		WHERE parent_id=$1 AND ` + liveCategory + ` AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY created_at`
 
 	err = s.db.SelectContext(ctx, &dest, query, args...)
 
 	return
 }
 
+func (s *CategoryRepository) Tree(ctx context.Context, rootID string) (dest []category.Entity, err error) {
+	// UNION rather than UNION ALL stops the recursion even if the rows ever formed a cycle,
+	// subcategories belong to the tenant of their root
+	args := []any{rootID}
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id
+			FROM categories
+			WHERE (($1='' AND parent_id IS NULL) OR id::TEXT=$1) AND ` + liveCategory + `
+				AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+			UNION
+			SELECT c.id
+			FROM categories c
+			JOIN tree t ON c.parent_id=t.id
+			WHERE c.` + liveCategory + `
+		)
+		SELECT` + categoryColumns + `
+		FROM categories
+		WHERE id IN (SELECT id FROM tree)
+		ORDER BY created_at`
+
+	if err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...); err != nil {
+		return
+	}
+
+	if rootID != "" && len(dest) == 0 {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *CategoryRepository) SelectAncestors(ctx context.Context, id string) (dest []category.Entity, err error) {
+	args := []any{id}
+	query := `
+		WITH RECURSIVE ancestors (id, next_id, depth) AS (
+			SELECT id, parent_id, 0
+			FROM categories
+			WHERE id=$1 AND ` + liveCategory + ` AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+			UNION ALL
+			SELECT c.id, c.parent_id, a.depth+1
+			FROM categories c
+			JOIN ancestors a ON c.id=a.next_id
+		)
+		SELECT` + categoryColumns + `
+		FROM categories
+		JOIN ancestors USING (id)
+		ORDER BY depth DESC`
+
+	if err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...); err != nil {
+		return
+	}
+
+	if len(dest) == 0 {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
 func (s *CategoryRepository) Create(ctx context.Context, data category.Entity) (id string, err error) {
 	query := `
-		INSERT INTO categories (parent_id, name)
-		VALUES ($1, $2)
+		INSERT INTO categories (parent_id, name, tenant_id)
+		VALUES (NULLIF($1, '')::UUID, $2, $3)
 		RETURNING id`
 
-	args := []any{data.ParentID, data.Name}
+	args := []any{data.ParentID, data.Name, store.ResolveTenant(ctx, data.TenantID)}
 
-	err = s.db.QueryRowContext(ctx, query, args...).Scan(&id)
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
 
 	return
 }
 
 func (s *CategoryRepository) Get(ctx context.Context, id string) (dest category.Entity, err error) {
+	return s.get(ctx, "id=$1 AND "+liveCategory, id)
+}
+
+func (s *CategoryRepository) GetDeleted(ctx context.Context, id string) (dest category.Entity, err error) {
+	return s.get(ctx, "id=$1 AND deleted_at IS NOT NULL", id)
+}
+
+// get reads within the transaction of the context, so a unit of work sees the categories it has restored.
+func (s *CategoryRepository) get(ctx context.Context, condition, value string) (dest category.Entity, err error) {
+	args := []any{value}
 	query := `
-		SELECT id, name
+		SELECT` + categoryColumns + `
 		FROM categories
-		WHERE id=$1`
+		WHERE ` + condition + ` AND ` + tenantCondition(ctx, "tenant_id", &args)
 
-	args := []any{id}
-
-	if err = s.db.GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+	if err = sqlx.GetContext(ctx, store.Executor(ctx, s.db), &dest, query, args...); err != nil && err != sql.ErrNoRows {
 		return
 	}
 
@@ -77,25 +171,85 @@ func (s *CategoryRepository) Get(ctx context.Context, id string) (dest category.
 	return
 }
 
+// Update writes the fields that are set, nil values are left as they are.
 func (s *CategoryRepository) Update(ctx context.Context, id string, data category.Entity) (err error) {
 	sets, args := s.prepareArgs(data)
-	if len(args) > 0 {
 
-		args = append(args, id)
-		sets = append(sets, "updated_at=CURRENT_TIMESTAMP")
+	args = append(args, id, data.Version)
+	sets = append(sets, "updated_at=CURRENT_TIMESTAMP", "version=version+1")
+
+	db := store.Executor(ctx, s.db)
+	query := fmt.Sprintf("UPDATE categories SET %s WHERE id=$%d AND %s AND ($%d=0 OR version=$%d)",
+		strings.Join(sets, ", "), len(args)-1, liveCategory, len(args), len(args))
+	query += " AND " + tenantCondition(ctx, "tenant_id", &args)
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	return checkVersion(ctx, db, "categories", id, res, liveCategory)
+}
+
+func (s *CategoryRepository) Move(ctx context.Context, id, parentID string, version int) (err error) {
+	db := store.Executor(ctx, s.db)
+
+	// moves are serialized, two of them checked at the same time could close a cycle together
+	if _, err = db.ExecContext(ctx, `SELECT PG_ADVISORY_XACT_LOCK(HASHTEXT('categories_move'))`); err != nil {
+		return
+	}
+
+	if parentID != "" {
+		// the foreign key lets a parent in the trash or of another tenant through
+		args := []any{parentID}
+		query := `SELECT EXISTS (SELECT 1 FROM categories WHERE id::TEXT=$1 AND ` + liveCategory + ` AND ` +
+			tenantCondition(ctx, "tenant_id", &args) + `)`
+
+		var found bool
+		if err = sqlx.GetContext(ctx, db, &found, query, args...); err != nil {
+			return
+		}
+
+		if !found {
+			return category.ErrParentNotFound
+		}
 
-		query := fmt.Sprintf("UPDATE categories SET %s WHERE id=$%d", strings.Join(sets, ", "), len(args))
-		_, err = s.db.ExecContext(ctx, query, args...)
-		if err != nil && err != sql.ErrNoRows {
+		query = `
+			WITH RECURSIVE subtree AS (
+				SELECT id
+				FROM categories
+				WHERE id=$1
+				UNION
+				SELECT c.id
+				FROM categories c
+				JOIN subtree t ON c.parent_id=t.id
+			)
+			SELECT EXISTS (SELECT 1 FROM subtree WHERE id=$2)`
+
+		var cycle bool
+		if err = sqlx.GetContext(ctx, db, &cycle, query, id, parentID); err != nil {
 			return
 		}
 
-		if err == sql.ErrNoRows {
-			err = store.ErrorNotFound
+		if cycle {
+			return category.ErrCycle
 		}
 	}
 
-	return
+	args := []any{id, parentID, version}
+	query := `
+		UPDATE categories
+		SET parent_id=NULLIF($2, '')::UUID, updated_at=CURRENT_TIMESTAMP, version=version+1
+		WHERE id=$1 AND ` + liveCategory + ` AND ($3=0 OR version=$3) AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	res, err := db.ExecContext(ctx, query, args...)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return category.ErrParentNotFound
+	}
+	if err != nil {
+		return
+	}
+
+	return checkVersion(ctx, db, "categories", id, res, liveCategory)
 }
 
 func (s *CategoryRepository) prepareArgs(data category.Entity) (sets []string, args []any) {
@@ -107,22 +261,59 @@ func (s *CategoryRepository) prepareArgs(data category.Entity) (sets []string, a
 	return
 }
 
-func (s *CategoryRepository) Delete(ctx context.Context, id string) (err error) {
+func (s *CategoryRepository) Delete(ctx context.Context, id string, version int, trashedWith string) (err error) {
+	args := []any{id, version, trashedWith}
 	query := `
-		DELETE 
-		FROM categories
-		WHERE id=$1`
+		UPDATE categories
+		SET deleted_at=CURRENT_TIMESTAMP, trashed_with=NULLIF($3, '')::UUID, updated_at=CURRENT_TIMESTAMP,
+			version=version+1
+		WHERE id=$1 AND ` + liveCategory + ` AND ($2=0 OR version=$2) AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	db := store.Executor(ctx, s.db)
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
 
+	return checkVersion(ctx, db, "categories", id, res, liveCategory)
+}
+
+func (s *CategoryRepository) Restore(ctx context.Context, id string) (err error) {
 	args := []any{id}
+	query := `
+		UPDATE categories
+		SET deleted_at=NULL, trashed_with=NULL, updated_at=CURRENT_TIMESTAMP, version=version+1
+		WHERE id=$1 AND deleted_at IS NOT NULL AND ` + tenantCondition(ctx, "tenant_id", &args)
 
-	_, err = s.db.ExecContext(ctx, query, args...)
-	if err != nil && err != sql.ErrNoRows {
+	res, err := store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
 		return
 	}
 
-	if err == sql.ErrNoRows {
-		err = store.ErrorNotFound
-	}
+	return checkRowsAffected(res)
+}
 
-	return
+// Purge removes the leaves of the trash until none is left, a parent becomes a leaf once its subcategories are gone.
+func (s *CategoryRepository) Purge(ctx context.Context, before time.Time) (purged int, err error) {
+	args := []any{before}
+	query := `
+		DELETE
+		FROM categories c
+		WHERE deleted_at<$1 AND ` + tenantCondition(ctx, "c.tenant_id", &args) + `
+			AND NOT EXISTS (SELECT 1 FROM categories s WHERE s.parent_id=c.id)
+			AND NOT EXISTS (SELECT 1 FROM products p WHERE p.category_id=c.id)`
+
+	db := store.Executor(ctx, s.db)
+	for {
+		res, err := db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return purged, err
+		}
+
+		rows, err := res.RowsAffected()
+		if err != nil || rows == 0 {
+			return purged, err
+		}
+		purged += int(rows)
+	}
 }
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"payment-service/internal/domain/dispute"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"payment-service/pkg/store"
+)
+
+const disputeColumns = `
+	created_at, updated_at, id, tenant_id, billing_id, reason_code, amount, currency, status, due_at, resolved_at`
+
+type DisputeRepository struct {
+	db *sqlx.DB
+}
+
+func NewDisputeRepository(db *sqlx.DB) *DisputeRepository {
+	return &DisputeRepository{
+		db: db,
+	}
+}
+
+func (s *DisputeRepository) Select(ctx context.Context, status string) (dest []dispute.Entity, err error) {
+	args := []any{status}
+	query := `
+		SELECT` + disputeColumns + `
+		FROM disputes
+		WHERE ($1='' OR status=$1) AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY created_at DESC`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+func (s *DisputeRepository) SelectDue(ctx context.Context, before time.Time) (dest []dispute.Entity, err error) {
+	args := []any{dispute.StatusOpened, before}
+	query := `
+		SELECT` + disputeColumns + `
+		FROM disputes
+		WHERE status=$1 AND due_at<$2 AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY due_at`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+func (s *DisputeRepository) Create(ctx context.Context, data dispute.Entity) (id string, err error) {
+	query := `
+		INSERT INTO disputes (billing_id, reason_code, amount, currency, status, due_at, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	args := []any{data.BillingID, data.ReasonCode, data.Amount, data.Currency, data.Status, data.DueAt,
+		store.ResolveTenant(ctx, data.TenantID)}
+
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+func (s *DisputeRepository) Get(ctx context.Context, id string) (dest dispute.Entity, err error) {
+	args := []any{id}
+	query := `
+		SELECT` + disputeColumns + `
+		FROM disputes
+		WHERE id=$1 AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	db := store.Executor(ctx, s.db)
+	if err = sqlx.GetContext(ctx, db, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+		return
+	}
+
+	query = `
+		SELECT created_at, id, dispute_id, name, url, content_type, note
+		FROM dispute_evidence
+		WHERE dispute_id=$1
+		ORDER BY created_at`
+
+	err = sqlx.SelectContext(ctx, db, &dest.Evidence, query, dest.ID)
+
+	return
+}
+
+func (s *DisputeRepository) Update(ctx context.Context, id string, data dispute.Entity) (err error) {
+	args := []any{data.Status, data.ResolvedAt, id}
+	query := `
+		UPDATE disputes
+		SET status=$1, resolved_at=$2, updated_at=CURRENT_TIMESTAMP
+		WHERE id=$3 AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	res, err := store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	return checkRowsAffected(res)
+}
+
+func (s *DisputeRepository) AddEvidence(ctx context.Context, data dispute.Evidence) (id string, err error) {
+	query := `
+		INSERT INTO dispute_evidence (dispute_id, name, url, content_type, note)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	args := []any{data.DisputeID, data.Name, data.URL, data.ContentType, data.Note}
+
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+
+	return
+}
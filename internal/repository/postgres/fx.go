@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"payment-service/internal/domain/fx"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"payment-service/pkg/store"
+)
+
+type FXRepository struct {
+	db *sqlx.DB
+}
+
+func NewFXRepository(db *sqlx.DB) *FXRepository {
+	return &FXRepository{
+		db: db,
+	}
+}
+
+func (s *FXRepository) Save(ctx context.Context, data []fx.Rate) (err error) {
+	query := `
+		INSERT INTO fx_rates (currency, base, rate, date, source)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (currency, base, date) DO UPDATE
+		SET rate=EXCLUDED.rate, source=EXCLUDED.source, created_at=CURRENT_TIMESTAMP`
+
+	db := store.Executor(ctx, s.db)
+	for _, rate := range data {
+		args := []any{rate.Currency, rate.Base, rate.Rate, rate.Date, rate.Source}
+
+		if _, err = db.ExecContext(ctx, query, args...); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s *FXRepository) Select(ctx context.Context, base string, date time.Time) (dest []fx.Rate, err error) {
+	query := `
+		SELECT DISTINCT ON (currency) created_at, currency, base, rate, date, source
+		FROM fx_rates
+		WHERE base=$1 AND date<=$2
+		ORDER BY currency, date DESC`
+
+	args := []any{base, date}
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+func (s *FXRepository) Get(ctx context.Context, currency, base string, date time.Time) (dest fx.Rate, err error) {
+	query := `
+		SELECT created_at, currency, base, rate, date, source
+		FROM fx_rates
+		WHERE currency=$1 AND base=$2 AND date<=$3
+		ORDER BY date DESC
+		LIMIT 1`
+
+	args := []any{currency, base, date}
+
+	if err = sqlx.GetContext(ctx, store.Executor(ctx, s.db), &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = fx.ErrRateNotFound
+	}
+
+	return
+}
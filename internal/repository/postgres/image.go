@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"payment-service/internal/domain/product"
+	"payment-service/pkg/store"
+)
+
+const imageColumns = `
+	created_at, id, tenant_id, product_id, position, content_type, size, width, height, key, url, thumbnail_key, thumbnail_url`
+
+type ImageRepository struct {
+	db *sqlx.DB
+}
+
+func NewImageRepository(db *sqlx.DB) *ImageRepository {
+	return &ImageRepository{
+		db: db,
+	}
+}
+
+func (s *ImageRepository) SelectImages(ctx context.Context, productID string) (dest []product.Image, err error) {
+	args := []any{productID}
+	query := `
+		SELECT` + imageColumns + `
+		FROM product_images
+		WHERE product_id=$1 AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY position, created_at`
+
+	dest = make([]product.Image, 0)
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+func (s *ImageRepository) CreateImage(ctx context.Context, data product.Image) (id string, err error) {
+	query := `
+		INSERT INTO product_images (product_id, position, content_type, size, width, height, key, url, thumbnail_key, thumbnail_url, tenant_id)
+		VALUES ($1, COALESCE((SELECT MAX(position)+1 FROM product_images WHERE product_id=$1), 0), $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id`
+
+	args := []any{data.ProductID, data.ContentType, data.Size, data.Width, data.Height, data.Key, data.URL,
+		data.ThumbnailKey, data.ThumbnailURL, store.ResolveTenant(ctx, data.TenantID)}
+
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *ImageRepository) GetImage(ctx context.Context, id string) (dest product.Image, err error) {
+	args := []any{id}
+	query := `
+		SELECT` + imageColumns + `
+		FROM product_images
+		WHERE id=$1 AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	if err = sqlx.GetContext(ctx, store.Executor(ctx, s.db), &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *ImageRepository) ReorderImages(ctx context.Context, productID string, ids []string) (err error) {
+	args := []any{productID, pq.Array(ids)}
+	query := `
+		UPDATE product_images
+		SET position=ARRAY_POSITION($2::TEXT[], id::TEXT)-1
+		WHERE product_id=$1 AND id::TEXT=ANY($2) AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	_, err = store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+
+	return
+}
+
+func (s *ImageRepository) DeleteImage(ctx context.Context, id string) (err error) {
+	args := []any{id}
+	query := `
+		DELETE
+		FROM product_images
+		WHERE id=$1 AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	res, err := store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	return checkRowsAffected(res)
+}
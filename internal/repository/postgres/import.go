@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"payment-service/internal/domain/product"
+	"payment-service/pkg/store"
+)
+
+const importColumns = `
+	created_at, updated_at, id, tenant_id, format, status, total, created, updated, failed, message`
+
+type ImportRepository struct {
+	db *sqlx.DB
+}
+
+func NewImportRepository(db *sqlx.DB) *ImportRepository {
+	return &ImportRepository{
+		db: db,
+	}
+}
+
+func (s *ImportRepository) CreateJob(ctx context.Context, data product.Job) (id string, err error) {
+	query := `
+		INSERT INTO product_imports (tenant_id, format, status, total, created, updated, failed, message)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`
+
+	args := []any{store.ResolveTenant(ctx, data.TenantID), data.Format, data.Status, data.Total, data.Created, data.Updated, data.Failed, data.Message}
+
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+func (s *ImportRepository) GetJob(ctx context.Context, id string) (dest product.Job, err error) {
+	args := []any{id}
+	query := `
+		SELECT` + importColumns + `
+		FROM product_imports
+		WHERE id=$1 AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	db := store.Executor(ctx, s.db)
+	if err = sqlx.GetContext(ctx, db, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+		return
+	}
+
+	query = `
+		SELECT job_id, line, message
+		FROM product_import_errors
+		WHERE job_id=$1
+		ORDER BY line`
+
+	err = sqlx.SelectContext(ctx, db, &dest.Errors, query, id)
+
+	return
+}
+
+func (s *ImportRepository) UpdateJob(ctx context.Context, id string, data product.Job) (err error) {
+	args := []any{data.Status, data.Total, data.Created, data.Updated, data.Failed, data.Message, id}
+	query := `
+		UPDATE product_imports
+		SET status=$1, total=$2, created=$3, updated=$4, failed=$5, message=$6, updated_at=CURRENT_TIMESTAMP
+		WHERE id=$7 AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	res, err := store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	return checkRowsAffected(res)
+}
+
+func (s *ImportRepository) AddJobErrors(ctx context.Context, id string, data []product.RowError) (err error) {
+	// the errors are only added to a job of the tenant
+	args := []any{nil, nil, id}
+	query := `
+		INSERT INTO product_import_errors (job_id, line, message)
+		SELECT id, $1, $2
+		FROM product_imports
+		WHERE id=$3 AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	db := store.Executor(ctx, s.db)
+	for _, rowErr := range data {
+		args[0], args[1] = rowErr.Line, rowErr.Message
+
+		res, err := db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if err = checkRowsAffected(res); err != nil {
+			return err
+		}
+	}
+
+	return
+}
@@ -0,0 +1,353 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+
+	"payment-service/internal/domain/inventory"
+	"payment-service/pkg/store"
+)
+
+const (
+	warehouseColumns = `
+	created_at, id, tenant_id, code, name`
+
+	stockColumns = `
+	updated_at, tenant_id, product_id, warehouse_id, on_hand, reserved`
+
+	reservationColumns = `
+	created_at, updated_at, id, tenant_id, COALESCE(order_id::TEXT, '') AS order_id,
+	COALESCE(billing_id::TEXT, '') AS billing_id, status, expires_at`
+)
+
+// InventoryRepository locks the stock rows it allocates from, Allocate and CreateReservation have to run within store.Transactor.
+type InventoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewInventoryRepository(db *sqlx.DB) *InventoryRepository {
+	return &InventoryRepository{
+		db: db,
+	}
+}
+
+func (s *InventoryRepository) SelectWarehouses(ctx context.Context) (dest []inventory.Warehouse, err error) {
+	var args []any
+	query := `
+		SELECT` + warehouseColumns + `
+		FROM warehouses
+		WHERE ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY code`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+func (s *InventoryRepository) CreateWarehouse(ctx context.Context, data inventory.Warehouse) (id string, err error) {
+	query := `
+		INSERT INTO warehouses (tenant_id, code, name)
+		VALUES ($1, $2, $3)
+		RETURNING id`
+
+	args := []any{store.ResolveTenant(ctx, data.TenantID), data.Code, data.Name}
+
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+	err = checkUniqueViolation(err)
+
+	return
+}
+
+func (s *InventoryRepository) SelectStock(ctx context.Context, productID string) (dest []inventory.Stock, err error) {
+	args := []any{productID}
+	query := `
+		SELECT` + stockColumns + `
+		FROM stock
+		WHERE ($1='' OR product_id::TEXT=$1) AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY product_id, warehouse_id`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+// SetStock stores the stock under the tenant of its product, the warehouse has to belong to the same tenant.
+func (s *InventoryRepository) SetStock(ctx context.Context, data inventory.Stock) (err error) {
+	args := []any{data.ProductID, data.WarehouseID}
+	query := `
+		SELECT p.tenant_id
+		FROM products p
+		JOIN warehouses w ON w.tenant_id=p.tenant_id
+		WHERE p.id::TEXT=$1 AND w.id::TEXT=$2 AND ` + tenantCondition(ctx, "p.tenant_id", &args)
+
+	db := store.Executor(ctx, s.db)
+
+	var tenantID string
+	if err = db.QueryRowxContext(ctx, query, args...).Scan(&tenantID); err == sql.ErrNoRows {
+		return store.ErrorNotFound
+	}
+	if err != nil {
+		return
+	}
+
+	query = `
+		INSERT INTO stock (tenant_id, product_id, warehouse_id, on_hand)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (product_id, warehouse_id) DO UPDATE
+		SET on_hand=EXCLUDED.on_hand, updated_at=CURRENT_TIMESTAMP
+		WHERE stock.reserved <= EXCLUDED.on_hand`
+
+	args = []any{tenantID, data.ProductID, data.WarehouseID, data.OnHand}
+
+	res, err := db.ExecContext(ctx, query, args...)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return store.ErrorNotFound
+	}
+	if err != nil {
+		return
+	}
+
+	if err = checkRowsAffected(res); err == store.ErrorNotFound {
+		err = inventory.ErrInsufficientStock
+	}
+
+	return
+}
+
+func (s *InventoryRepository) Allocate(ctx context.Context, productID string, quantity decimal.Decimal) (dest []inventory.Allocation, err error) {
+	// rows are locked in key order so that concurrent orders can't deadlock on them
+	args := []any{productID}
+	query := `
+		SELECT` + stockColumns + `
+		FROM stock
+		WHERE product_id=$1 AND on_hand > reserved AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY warehouse_id
+		FOR UPDATE`
+
+	db := store.Executor(ctx, s.db)
+
+	var stocks []inventory.Stock
+	if err = sqlx.SelectContext(ctx, db, &stocks, query, args...); err != nil {
+		return
+	}
+
+	available := decimal.Zero
+	for _, data := range stocks {
+		available = available.Add(data.Available())
+	}
+
+	if available.LessThan(quantity) {
+		err = inventory.ErrInsufficientStock
+		return
+	}
+
+	sort.SliceStable(stocks, func(i, j int) bool {
+		return stocks[i].Available().GreaterThan(stocks[j].Available())
+	})
+
+	// the condition still guards the stock when the repository is called outside a transaction
+	query = `
+		UPDATE stock
+		SET reserved=reserved+$3, updated_at=CURRENT_TIMESTAMP
+		WHERE product_id=$1 AND warehouse_id=$2 AND on_hand-reserved >= $3`
+
+	rest := quantity
+	for _, data := range stocks {
+		if !rest.IsPositive() {
+			break
+		}
+
+		taken := decimal.Min(rest, data.Available())
+		rest = rest.Sub(taken)
+
+		res, err := db.ExecContext(ctx, query, productID, data.WarehouseID, taken)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = checkRowsAffected(res); err == store.ErrorNotFound {
+			return nil, inventory.ErrInsufficientStock
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		dest = append(dest, inventory.Allocation{
+			ProductID:   productID,
+			WarehouseID: data.WarehouseID,
+			Quantity:    taken,
+		})
+	}
+
+	return
+}
+
+func (s *InventoryRepository) Deallocate(ctx context.Context, data []inventory.Allocation, commit bool) (err error) {
+	set := "reserved=reserved-$3"
+	if commit {
+		set = "reserved=reserved-$3, on_hand=on_hand-$3"
+	}
+
+	tenantArgs := []any{nil, nil, nil}
+	query := `
+		UPDATE stock
+		SET ` + set + `, updated_at=CURRENT_TIMESTAMP
+		WHERE product_id=$1 AND warehouse_id=$2 AND reserved >= $3 AND ` + tenantCondition(ctx, "tenant_id", &tenantArgs)
+
+	db := store.Executor(ctx, s.db)
+	for _, allocation := range data {
+		args := []any{allocation.ProductID, allocation.WarehouseID, allocation.Quantity, tenantArgs[3]}
+
+		res, err := db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if err = checkRowsAffected(res); err == store.ErrorNotFound {
+			return inventory.ErrInsufficientStock
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+func (s *InventoryRepository) CreateReservation(ctx context.Context, data inventory.Reservation) (id string, err error) {
+	query := `
+		INSERT INTO reservations (tenant_id, order_id, billing_id, status, expires_at)
+		VALUES ($1, NULLIF($2, '')::UUID, NULLIF($3, '')::UUID, $4, $5)
+		RETURNING id`
+
+	args := []any{store.ResolveTenant(ctx, data.TenantID), data.OrderID, data.BillingID, data.Status, data.ExpiresAt}
+
+	db := store.Executor(ctx, s.db)
+	if err = db.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		return
+	}
+
+	query = `
+		INSERT INTO reservation_items (reservation_id, product_id, warehouse_id, quantity)
+		VALUES ($1, $2, $3, $4)`
+
+	for _, allocation := range data.Allocations {
+		args = []any{id, allocation.ProductID, allocation.WarehouseID, allocation.Quantity}
+
+		if _, err = db.ExecContext(ctx, query, args...); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s *InventoryRepository) GetReservation(ctx context.Context, id string) (dest inventory.Reservation, err error) {
+	return s.getReservation(ctx, "id", id)
+}
+
+func (s *InventoryRepository) GetReservationByBillingID(ctx context.Context, billingID string) (dest inventory.Reservation, err error) {
+	return s.getReservation(ctx, "billing_id", billingID)
+}
+
+func (s *InventoryRepository) getReservation(ctx context.Context, column, value string) (dest inventory.Reservation, err error) {
+	args := []any{value}
+	query := `
+		SELECT` + reservationColumns + `
+		FROM reservations
+		WHERE ` + column + `=$1 AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		FOR UPDATE`
+
+	db := store.Executor(ctx, s.db)
+	if err = sqlx.GetContext(ctx, db, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+		return
+	}
+
+	dest.Allocations, err = s.selectAllocations(ctx, dest.ID)
+
+	return
+}
+
+func (s *InventoryRepository) UpdateReservation(ctx context.Context, id string, data inventory.Reservation) (err error) {
+	var sets []string
+	var args []any
+
+	if data.Status != "" {
+		args = append(args, data.Status)
+		sets = append(sets, fmt.Sprintf("status=$%d", len(args)))
+	}
+
+	if data.OrderID != "" {
+		args = append(args, data.OrderID)
+		sets = append(sets, fmt.Sprintf("order_id=$%d", len(args)))
+	}
+
+	if data.BillingID != "" {
+		args = append(args, data.BillingID)
+		sets = append(sets, fmt.Sprintf("billing_id=$%d", len(args)))
+	}
+
+	if len(args) == 0 {
+		return
+	}
+
+	args = append(args, id)
+	sets = append(sets, "updated_at=CURRENT_TIMESTAMP")
+	where := fmt.Sprintf("id=$%d AND %s", len(args), tenantCondition(ctx, "tenant_id", &args))
+
+	query := fmt.Sprintf("UPDATE reservations SET %s WHERE %s", strings.Join(sets, ", "), where)
+	res, err := store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	return checkRowsAffected(res)
+}
+
+func (s *InventoryRepository) SelectExpiredReservations(ctx context.Context, before time.Time) (dest []inventory.Reservation, err error) {
+	args := []any{inventory.StatusActive, before}
+	query := `
+		SELECT` + reservationColumns + `
+		FROM reservations
+		WHERE status=$1 AND expires_at < $2 AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY expires_at`
+
+	if err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...); err != nil {
+		return
+	}
+
+	for i := range dest {
+		if dest[i].Allocations, err = s.selectAllocations(ctx, dest[i].ID); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s *InventoryRepository) selectAllocations(ctx context.Context, reservationID string) (dest []inventory.Allocation, err error) {
+	query := `
+		SELECT product_id, warehouse_id, quantity
+		FROM reservation_items
+		WHERE reservation_id=$1
+		ORDER BY product_id, warehouse_id`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, reservationID)
+
+	return
+}
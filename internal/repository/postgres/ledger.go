@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"payment-service/internal/domain/ledger"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"payment-service/pkg/store"
+)
+
+// LedgerRepository writes entries with their postings, CreateEntry has to run within store.Transactor,
+// otherwise a failure between the statements leaves an entry without postings.
+type LedgerRepository struct {
+	db *sqlx.DB
+}
+
+func NewLedgerRepository(db *sqlx.DB) *LedgerRepository {
+	return &LedgerRepository{
+		db: db,
+	}
+}
+
+func (s *LedgerRepository) EnsureAccount(ctx context.Context, data ledger.Account) (id string, err error) {
+	query := `
+		INSERT INTO ledger_accounts (code, type)
+		VALUES ($1, $2)
+		ON CONFLICT (code) DO UPDATE SET code=EXCLUDED.code
+		RETURNING id`
+
+	args := []any{data.Code, data.Type}
+
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+func (s *LedgerRepository) SelectAccounts(ctx context.Context) (dest []ledger.Account, err error) {
+	query := `
+		SELECT created_at, id, code, type
+		FROM ledger_accounts
+		ORDER BY code`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query)
+
+	return
+}
+
+func (s *LedgerRepository) GetAccountByCode(ctx context.Context, code string) (dest ledger.Account, err error) {
+	query := `
+		SELECT created_at, id, code, type
+		FROM ledger_accounts
+		WHERE code=$1`
+
+	args := []any{code}
+
+	if err = sqlx.GetContext(ctx, store.Executor(ctx, s.db), &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *LedgerRepository) CreateEntry(ctx context.Context, data ledger.Entry) (id string, err error) {
+	query := `
+		INSERT INTO ledger_entries (tenant_id, kind, billing_id, description, dedup_key)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (dedup_key) DO NOTHING
+		RETURNING id`
+
+	args := []any{store.ResolveTenant(ctx, data.TenantID), data.Kind, data.BillingID, data.Description, data.DedupKey}
+
+	db := store.Executor(ctx, s.db)
+	if err = db.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			// the entry has already been posted
+			err = db.QueryRowxContext(ctx, "SELECT id FROM ledger_entries WHERE dedup_key=$1", data.DedupKey).Scan(&id)
+		}
+		return
+	}
+
+	query = `
+		INSERT INTO ledger_postings (entry_id, account_id, amount, currency)
+		VALUES ($1, $2, $3, $4)`
+
+	for _, posting := range data.Postings {
+		args = []any{id, posting.AccountID, posting.Amount, posting.Currency}
+
+		if _, err = db.ExecContext(ctx, query, args...); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s *LedgerRepository) SelectEntries(ctx context.Context, billingID string) (dest []ledger.Entry, err error) {
+	args := []any{billingID}
+	query := `
+		SELECT created_at, id, tenant_id, kind, billing_id, description, dedup_key
+		FROM ledger_entries
+		WHERE ($1='' OR billing_id=$1) AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY created_at`
+
+	db := store.Executor(ctx, s.db)
+	if err = sqlx.SelectContext(ctx, db, &dest, query, args...); err != nil {
+		return
+	}
+
+	query = `
+		SELECT p.id, p.entry_id, p.account_id, a.code AS account_code, p.amount, p.currency
+		FROM ledger_postings p
+		JOIN ledger_entries e ON e.id=p.entry_id
+		JOIN ledger_accounts a ON a.id=p.account_id
+		WHERE ($1='' OR e.billing_id=$1) AND ($2='' OR e.tenant_id=$2)
+		ORDER BY p.id`
+
+	var postings []ledger.Posting
+	if err = sqlx.SelectContext(ctx, db, &postings, query, args...); err != nil {
+		return
+	}
+
+	index := make(map[string]int, len(dest))
+	for i, entry := range dest {
+		index[entry.ID] = i
+	}
+
+	for _, posting := range postings {
+		i := index[posting.EntryID]
+		dest[i].Postings = append(dest[i].Postings, posting)
+	}
+
+	return
+}
+
+func (s *LedgerRepository) Balance(ctx context.Context, accountID, currency string, from, to time.Time) (dest ledger.Balance, err error) {
+	query := `
+		SELECT
+			COALESCE(SUM(CASE WHEN p.amount > 0 THEN p.amount ELSE 0 END), 0) AS debit,
+			COALESCE(SUM(CASE WHEN p.amount < 0 THEN -p.amount ELSE 0 END), 0) AS credit
+		FROM ledger_postings p
+		JOIN ledger_entries e ON e.id=p.entry_id
+		WHERE p.account_id=$1 AND p.currency=$2
+			AND ($3::TIMESTAMP IS NULL OR e.created_at >= $3)
+			AND ($4::TIMESTAMP IS NULL OR e.created_at < $4)`
+
+	args := []any{accountID, currency, nullTime(from), nullTime(to)}
+	query += " AND " + tenantCondition(ctx, "e.tenant_id", &args)
+
+	err = sqlx.GetContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+// nullTime sends a zero time as NULL, so the query treats the bound as open.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
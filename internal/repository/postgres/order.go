@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"payment-service/internal/domain/order"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"payment-service/pkg/store"
+)
+
+const (
+	orderColumns = `
+	created_at, updated_at, id, tenant_id, status, currency, price_list_id, subtotal, discount, tax, total,
+	COALESCE(billing_id::TEXT, '') AS billing_id`
+
+	orderItemColumns = `
+	order_id, line, product_id, name, quantity, unit_price, subtotal, discount, tax_rate, tax, total`
+)
+
+// OrderRepository writes an order with its items, Create has to run within store.Transactor.
+type OrderRepository struct {
+	db *sqlx.DB
+}
+
+func NewOrderRepository(db *sqlx.DB) *OrderRepository {
+	return &OrderRepository{
+		db: db,
+	}
+}
+
+func (s *OrderRepository) Select(ctx context.Context) (dest []order.Entity, err error) {
+	var args []any
+	query := `
+		SELECT` + orderColumns + `
+		FROM orders
+		WHERE ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY created_at DESC`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+func (s *OrderRepository) Create(ctx context.Context, data order.Entity) (id string, err error) {
+	query := `
+		INSERT INTO orders (status, currency, price_list_id, subtotal, discount, tax, total, billing_id, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, '')::UUID, $9)
+		RETURNING id`
+
+	args := []any{data.Status, data.Currency, data.PriceListID, data.Subtotal, data.Discount, data.Tax, data.Total,
+		data.BillingID, store.ResolveTenant(ctx, data.TenantID)}
+
+	db := store.Executor(ctx, s.db)
+	if err = db.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		return
+	}
+
+	query = `
+		INSERT INTO order_items (order_id, line, product_id, name, quantity, unit_price, subtotal, discount, tax_rate, tax, total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	for _, item := range data.Items {
+		args = []any{id, item.Line, item.ProductID, item.Name, item.Quantity, item.UnitPrice, item.Subtotal,
+			item.Discount, item.TaxRate, item.Tax, item.Total}
+
+		if _, err = db.ExecContext(ctx, query, args...); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s *OrderRepository) Get(ctx context.Context, id string) (dest order.Entity, err error) {
+	return s.get(ctx, "id", id)
+}
+
+func (s *OrderRepository) GetByBillingID(ctx context.Context, billingID string) (dest order.Entity, err error) {
+	return s.get(ctx, "billing_id", billingID)
+}
+
+func (s *OrderRepository) get(ctx context.Context, column, value string) (dest order.Entity, err error) {
+	args := []any{value}
+	query := `
+		SELECT` + orderColumns + `
+		FROM orders
+		WHERE ` + column + `=$1 AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	db := store.Executor(ctx, s.db)
+	if err = sqlx.GetContext(ctx, db, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+		return
+	}
+
+	query = `
+		SELECT` + orderItemColumns + `
+		FROM order_items
+		WHERE order_id=$1
+		ORDER BY line`
+
+	err = sqlx.SelectContext(ctx, db, &dest.Items, query, dest.ID)
+
+	return
+}
+
+func (s *OrderRepository) Update(ctx context.Context, id string, data order.Entity) (err error) {
+	var sets []string
+	var args []any
+
+	if data.Status != "" {
+		args = append(args, data.Status)
+		sets = append(sets, fmt.Sprintf("status=$%d", len(args)))
+	}
+
+	if data.BillingID != "" {
+		args = append(args, data.BillingID)
+		sets = append(sets, fmt.Sprintf("billing_id=$%d", len(args)))
+	}
+
+	if len(args) == 0 {
+		return
+	}
+
+	args = append(args, id)
+	sets = append(sets, "updated_at=CURRENT_TIMESTAMP")
+
+	query := fmt.Sprintf("UPDATE orders SET %s WHERE id=$%d", strings.Join(sets, ", "), len(args))
+	query += " AND " + tenantCondition(ctx, "tenant_id", &args)
+	res, err := store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	return checkRowsAffected(res)
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"payment-service/internal/domain/outbox"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"payment-service/pkg/store"
+)
+
+type OutboxRepository struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+func (s *OutboxRepository) Create(ctx context.Context, data outbox.Entity) (id string, err error) {
+	query := `
+		INSERT INTO outbox (aggregate, aggregate_id, event_type, dedup_key, payload, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (dedup_key) DO NOTHING
+		RETURNING id`
+
+	args := []any{data.Aggregate, data.AggregateID, data.EventType, data.DedupKey, string(data.Payload),
+		store.ResolveTenant(ctx, data.TenantID)}
+
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+	if err == sql.ErrNoRows {
+		// the event has already been recorded by an earlier attempt
+		query = `SELECT id FROM outbox WHERE dedup_key=$1`
+		err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, data.DedupKey).Scan(&id)
+	}
+
+	return
+}
+
+func (s *OutboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) (dest []outbox.Entity, err error) {
+	query := `
+		UPDATE outbox
+		SET locked_until = CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id
+			FROM outbox
+			WHERE published_at IS NULL AND (locked_until IS NULL OR locked_until < CURRENT_TIMESTAMP)
+			ORDER BY created_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING created_at, published_at, id, tenant_id, aggregate, aggregate_id, event_type, dedup_key, payload, attempts, last_error`
+
+	args := []any{limit, lease.Milliseconds()}
+
+	if err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...); err != nil {
+		return
+	}
+
+	sort.Slice(dest, func(i, j int) bool {
+		return dest[i].CreatedAt.Before(dest[j].CreatedAt)
+	})
+
+	return
+}
+
+func (s *OutboxRepository) MarkPublished(ctx context.Context, id string) (err error) {
+	query := `
+		UPDATE outbox
+		SET published_at=CURRENT_TIMESTAMP, locked_until=NULL
+		WHERE id=$1`
+
+	args := []any{id}
+
+	res, err := store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	return checkRowsAffected(res)
+}
+
+func (s *OutboxRepository) MarkFailed(ctx context.Context, id string, reason string) (err error) {
+	query := `
+		UPDATE outbox
+		SET attempts=attempts+1, last_error=$1
+		WHERE id=$2`
+
+	args := []any{reason, id}
+
+	res, err := store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	return checkRowsAffected(res)
+}
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"payment-service/internal/domain/price"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"payment-service/pkg/store"
+)
+
+const (
+	priceListColumns = `
+	created_at, id, tenant_id, code, name, kind, COALESCE(merchant_id, '') AS merchant_id`
+
+	priceColumns = `
+	created_at, id, product_id, list_id, currency, amount, valid_from, valid_to`
+)
+
+// PriceRepository keeps prices append-only, the table rejects updates and deletes.
+type PriceRepository struct {
+	db *sqlx.DB
+}
+
+func NewPriceRepository(db *sqlx.DB) *PriceRepository {
+	return &PriceRepository{
+		db: db,
+	}
+}
+
+func (s *PriceRepository) SelectLists(ctx context.Context) (dest []price.List, err error) {
+	var args []any
+	query := `
+		SELECT` + priceListColumns + `
+		FROM price_lists
+		WHERE ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY code`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+func (s *PriceRepository) CreateList(ctx context.Context, data price.List) (id string, err error) {
+	query := `
+		INSERT INTO price_lists (code, name, kind, merchant_id, tenant_id)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		RETURNING id`
+
+	args := []any{data.Code, data.Name, data.Kind, data.MerchantID, store.ResolveTenant(ctx, data.TenantID)}
+
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+	err = checkUniqueViolation(err)
+
+	return
+}
+
+func (s *PriceRepository) GetList(ctx context.Context, idOrCode string) (dest price.List, err error) {
+	args := []any{idOrCode}
+	query := `
+		SELECT` + priceListColumns + `
+		FROM price_lists
+		WHERE (id::TEXT=$1 OR code=$1) AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	if err = sqlx.GetContext(ctx, store.Executor(ctx, s.db), &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *PriceRepository) Select(ctx context.Context, filter price.Filter) (dest []price.Entity, err error) {
+	var wheres []string
+	var args []any
+
+	columns := []struct {
+		name  string
+		value string
+	}{
+		{"product_id", filter.ProductID},
+		{"list_id", filter.ListID},
+		{"currency", filter.Currency},
+	}
+
+	for _, column := range columns {
+		if column.value != "" {
+			args = append(args, column.value)
+			wheres = append(wheres, fmt.Sprintf("%s=$%d", column.name, len(args)))
+		}
+	}
+
+	wheres = append(wheres, s.listCondition(ctx, &args))
+
+	query := `
+		SELECT` + priceColumns + `
+		FROM prices
+		WHERE ` + strings.Join(wheres, " AND ") + `
+		ORDER BY valid_from DESC, created_at DESC`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
+
+	return
+}
+
+func (s *PriceRepository) Create(ctx context.Context, data price.Entity) (id string, err error) {
+	query := `
+		INSERT INTO prices (product_id, list_id, currency, amount, valid_from, valid_to)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	args := []any{data.ProductID, data.ListID, data.Currency, data.Amount, data.ValidFrom, data.ValidTo}
+
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+func (s *PriceRepository) GetEffective(ctx context.Context, productID, listID, currency string, at time.Time) (dest price.Entity, err error) {
+	args := []any{productID, listID, currency, at}
+	query := `
+		SELECT` + priceColumns + `
+		FROM prices
+		WHERE product_id=$1 AND list_id=$2 AND currency=$3 AND valid_from<=$4 AND (valid_to IS NULL OR valid_to>$4)
+			AND ` + s.listCondition(ctx, &args) + `
+		ORDER BY valid_from DESC, created_at DESC
+		LIMIT 1`
+
+	if err = sqlx.GetContext(ctx, store.Executor(ctx, s.db), &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = price.ErrPriceNotFound
+	}
+
+	return
+}
+
+// listCondition limits the prices to the lists of the tenant of the context, prices have no tenant of their own.
+func (s *PriceRepository) listCondition(ctx context.Context, args *[]any) string {
+	return "list_id IN (SELECT id FROM price_lists WHERE " + tenantCondition(ctx, "tenant_id", args) + ")"
+}
@@ -3,15 +3,29 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"payment-service/internal/domain/product"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"payment-service/pkg/store"
 )
 
+// productColumns maps the products table onto product.Entity, optional columns are read as empty strings.
+const productColumns = `
+	created_at, updated_at, id, tenant_id, version, COALESCE(parent_id::TEXT, '') AS parent_id, category_id, name,
+	COALESCE(description, '') AS description, COALESCE(measure, '') AS measure, COALESCE(image_url, '') AS image_url,
+	COALESCE(country, '') AS country, COALESCE(barcode, '') AS barcode, COALESCE(brand, '') AS brand, attributes, deleted_at,
+	COALESCE(trashed_with::TEXT, '') AS trashed_with`
+
+// liveProduct leaves the products in the trash out
+const liveProduct = "deleted_at IS NULL"
+
 type ProductRepository struct {
 	db *sqlx.DB
 }
@@ -23,37 +37,206 @@ func NewProductRepository(db *sqlx.DB) *ProductRepository {
 }
 
 func (s *ProductRepository) Select(ctx context.Context) (dest []product.Entity, err error) {
+	var args []any
+	query := `
+		SELECT` + productColumns + `
+		FROM products
+		WHERE ` + liveProduct + ` AND ` + tenantCondition(ctx, "tenant_id", &args) + `
+		ORDER BY created_at`
+
+	err = s.db.SelectContext(ctx, &dest, query, args...)
+
+	return
+}
+
+// Search matches the query against the search column, the name weighs the most and the description the least.
+// Pages are read by keyset on the sort key and id, so they stay stable while products are added. The relevance is
+// rounded to six decimals, the cursor carries it back exactly rather than as a float the ranking may not reproduce.
+// It is still scored anew for every page: a product whose text is edited between pages may move across the cursor,
+// to be skipped or read twice.
+func (s *ProductRepository) Search(ctx context.Context, filter product.Filter) (dest product.Page, err error) {
+	conditions := []string{liveProduct}
+	var args []any
+
+	if filter.Deleted {
+		conditions = []string{"deleted_at IS NOT NULL"}
+
+		if !filter.DeletedBefore.IsZero() {
+			args = append(args, filter.DeletedBefore)
+			conditions = append(conditions, fmt.Sprintf("deleted_at<$%d", len(args)))
+		}
+	}
+	conditions = append(conditions, tenantCondition(ctx, "tenant_id", &args))
+
+	rank := "0::NUMERIC"
+	if filter.Query != "" {
+		args = append(args, filter.Query)
+		conditions = append(conditions, fmt.Sprintf("search @@ WEBSEARCH_TO_TSQUERY('simple', $%d)", len(args)))
+		rank = fmt.Sprintf("ROUND(TS_RANK(search, WEBSEARCH_TO_TSQUERY('simple', $%d))::NUMERIC, 6)", len(args))
+	}
+
+	if filter.CategoryID != "" {
+		args = append(args, filter.CategoryID)
+		conditions = append(conditions, fmt.Sprintf("category_id=$%d", len(args)))
+	}
+
+	if len(filter.CategoryIDs) > 0 {
+		args = append(args, pq.Array(filter.CategoryIDs))
+		conditions = append(conditions, fmt.Sprintf("category_id::TEXT=ANY($%d)", len(args)))
+	}
+
+	if filter.Country != "" {
+		args = append(args, filter.Country)
+		conditions = append(conditions, fmt.Sprintf("LOWER(country)=LOWER($%d)", len(args)))
+	}
+
+	if filter.Brand != "" {
+		args = append(args, filter.Brand)
+		conditions = append(conditions, fmt.Sprintf("LOWER(brand)=LOWER($%d)", len(args)))
+	}
+
+	if filter.ParentID != "" {
+		args = append(args, filter.ParentID)
+		conditions = append(conditions, fmt.Sprintf("parent_id=$%d", len(args)))
+	}
+
+	// containment keeps the value types apart and is answered by the GIN index
+	for code, values := range filter.Attributes {
+		var alternatives []string
+		for _, value := range values {
+			object, err := json.Marshal(map[string]any{code: value})
+			if err != nil {
+				return dest, err
+			}
+
+			args = append(args, string(object))
+			alternatives = append(alternatives, fmt.Sprintf("attributes @> $%d::JSONB", len(args)))
+		}
+		conditions = append(conditions, "("+strings.Join(alternatives, " OR ")+")")
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	db := store.Executor(ctx, s.db)
 	query := `
-		SELECT id,category_id, name,description,measure,image_url,country,barcode,brand
-		FROM products`
+		SELECT COUNT(*)
+		FROM products
+		` + where
+
+	if err = sqlx.GetContext(ctx, db, &dest.Total, query, args...); err != nil {
+		return
+	}
+
+	key, direction, operator := rank, "DESC", "<"
+	switch filter.Sort {
+	case product.SortName:
+		key, direction, operator = "name", "ASC", ">"
+	case product.SortNameDesc:
+		key, direction, operator = "name", "DESC", "<"
+	case product.SortCreatedAt:
+		key, direction, operator = "created_at", "ASC", ">"
+	case product.SortCreatedAtDesc:
+		key, direction, operator = "created_at", "DESC", "<"
+	}
+
+	if filter.After != nil {
+		var value any
+		if value, err = cursorValue(*filter.After); err != nil {
+			return
+		}
+
+		args = append(args, value, filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(%[1]s %[2]s $%[3]d OR (%[1]s = $%[3]d AND id > $%[4]d::UUID))",
+			key, operator, len(args)-1, len(args)))
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	limit := ""
+	if filter.Limit > 0 {
+		// one more row tells whether there is a next page
+		args = append(args, filter.Limit+1)
+		limit = fmt.Sprintf("LIMIT $%d", len(args))
+	}
+
+	query = `
+		SELECT` + productColumns + `, ` + rank + ` AS rank
+		FROM products
+		` + where + `
+		ORDER BY ` + key + ` ` + direction + `, id
+		` + limit
+
+	var rows []struct {
+		product.Entity
+		Rank float64 `db:"rank"`
+	}
+	if err = sqlx.SelectContext(ctx, db, &rows, query, args...); err != nil {
+		return
+	}
+
+	if filter.Limit > 0 && len(rows) > filter.Limit {
+		rows = rows[:filter.Limit]
+		last := rows[len(rows)-1]
+		dest.Next = product.NewCursor(filter.Sort, last.Entity, last.Rank)
+	}
 
-	err = s.db.SelectContext(ctx, &dest, query)
+	dest.Items = make([]product.Entity, 0, len(rows))
+	for _, row := range rows {
+		dest.Items = append(dest.Items, row.Entity)
+	}
 
 	return
 }
 
 func (s *ProductRepository) Create(ctx context.Context, data product.Entity) (id string, err error) {
 	query := `
-		INSERT INTO products (id,category_id, name,description,measure )
-		VALUES ($1, $2, $3,$4,$5)
+		INSERT INTO products (parent_id, category_id, name, description, measure, image_url, country, barcode, brand, attributes,
+			tenant_id)
+		VALUES (NULLIF($1, '')::UUID, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, COALESCE(NULLIF($10, '')::JSONB, '{}'), $11)
 		RETURNING id`
 
-	args := []any{data.ID, data.CategoryID, data.Name, data.Description, data.Measure}
+	args := []any{data.ParentID, data.CategoryID, data.Name, data.Description, data.Measure, data.ImageURL, data.Country,
+		data.Barcode, data.Brand, string(data.Attributes), store.ResolveTenant(ctx, data.TenantID)}
 
-	err = s.db.QueryRowContext(ctx, query, args...).Scan(&id)
+	err = store.Executor(ctx, s.db).QueryRowxContext(ctx, query, args...).Scan(&id)
+	err = checkUniqueViolation(err)
 
 	return
 }
 
 func (s *ProductRepository) Get(ctx context.Context, id string) (dest product.Entity, err error) {
+	return s.get(ctx, "id=$1 AND "+liveProduct, id)
+}
+
+func (s *ProductRepository) GetDeleted(ctx context.Context, id string) (dest product.Entity, err error) {
+	return s.get(ctx, "id=$1 AND deleted_at IS NOT NULL", id)
+}
+
+func (s *ProductRepository) GetByBarcode(ctx context.Context, barcode string) (dest product.Entity, err error) {
+	return s.get(ctx, "barcode=$1 AND "+liveProduct, barcode)
+}
+
+func (s *ProductRepository) SelectByBarcodes(ctx context.Context, barcodes []string) (dest []product.Entity, err error) {
+	args := []any{pq.Array(barcodes)}
 	query := `
-		SELECT id,category_id, name,description,measure,image_url,country,barcode,brand
+		SELECT` + productColumns + `
 		FROM products
-		WHERE id=$1`
+		WHERE barcode=ANY($1) AND ` + liveProduct + ` AND ` + tenantCondition(ctx, "tenant_id", &args)
 
-	args := []any{id}
+	dest = []product.Entity{}
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query, args...)
 
-	if err = s.db.GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+	return
+}
+
+// get reads within the transaction of the context, so a unit of work sees the products it has restored.
+func (s *ProductRepository) get(ctx context.Context, condition, value string) (dest product.Entity, err error) {
+	args := []any{value}
+	query := `
+		SELECT` + productColumns + `
+		FROM products
+		WHERE ` + condition + ` AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	if err = sqlx.GetContext(ctx, store.Executor(ctx, s.db), &dest, query, args...); err != nil && err != sql.ErrNoRows {
 		return
 	}
 
@@ -64,56 +247,224 @@ func (s *ProductRepository) Get(ctx context.Context, id string) (dest product.En
 	return
 }
 
-func (s *ProductRepository) Update(ctx context.Context, id string, data product.Entity) (err error) {
-	sets, args := s.prepareArgs(data)
-	if len(args) > 0 {
+// Upsert copies the products into a temporary table and merges it into products by barcode in one statement.
+// The barcodes of the products have to differ, it runs in its own transaction unless the context carries one.
+func (s *ProductRepository) Upsert(ctx context.Context, data []product.Entity) (dest []product.Entity, created, updated int, err error) {
+	tx, ok := store.Executor(ctx, s.db).(*sqlx.Tx)
+	if !ok {
+		if tx, err = s.db.BeginTxx(ctx, nil); err != nil {
+			return
+		}
 
-		args = append(args, id)
-		sets = append(sets, "updated_at=CURRENT_TIMESTAMP")
+		defer func() {
+			if err != nil {
+				tx.Rollback()
+				return
+			}
+			err = tx.Commit()
+		}()
+	}
+
+	query := `
+		CREATE TEMP TABLE product_upserts (
+			category_id UUID, name VARCHAR, description VARCHAR, measure VARCHAR, image_url VARCHAR,
+			country VARCHAR, barcode VARCHAR, brand VARCHAR
+		) ON COMMIT DROP`
 
-		query := fmt.Sprintf("UPDATE products SET %s WHERE id=$%d", strings.Join(sets, ", "), len(args))
-		_, err = s.db.ExecContext(ctx, query, args...)
-		if err != nil && err != sql.ErrNoRows {
+	if _, err = tx.ExecContext(ctx, query); err != nil {
+		return
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("product_upserts",
+		"category_id", "name", "description", "measure", "image_url", "country", "barcode", "brand"))
+	if err != nil {
+		return
+	}
+
+	for _, object := range data {
+		args := []any{object.CategoryID, object.Name, object.Description, object.Measure, object.ImageURL,
+			object.Country, object.Barcode, object.Brand}
+
+		if _, err = stmt.ExecContext(ctx, args...); err != nil {
+			stmt.Close()
 			return
 		}
+	}
+
+	// an empty exec flushes the buffered rows
+	if _, err = stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return
+	}
+
+	if err = stmt.Close(); err != nil {
+		return
+	}
+
+	// xmax is zero for a row the statement inserted and set for a row it updated,
+	// the products are merged by barcode within the tenant they are imported into
+	query = `
+		INSERT INTO products (category_id, name, description, measure, image_url, country, barcode, brand, tenant_id)
+		SELECT category_id, name, description, measure, image_url, country, NULLIF(barcode, ''), brand, $1
+		FROM product_upserts
+		ON CONFLICT (tenant_id, barcode) WHERE deleted_at IS NULL DO UPDATE
+		SET category_id=EXCLUDED.category_id, name=EXCLUDED.name, description=EXCLUDED.description,
+			measure=EXCLUDED.measure, image_url=EXCLUDED.image_url, country=EXCLUDED.country, brand=EXCLUDED.brand,
+			updated_at=CURRENT_TIMESTAMP, version=products.version+1
+		RETURNING` + productColumns + `, (xmax = 0) AS inserted`
 
-		if err == sql.ErrNoRows {
-			err = store.ErrorNotFound
+	args := []any{store.TenantID(ctx)}
+
+	var rows []struct {
+		product.Entity
+		Inserted bool `db:"inserted"`
+	}
+	if err = sqlx.SelectContext(ctx, tx, &rows, query, args...); err != nil {
+		return
+	}
+
+	dest = make([]product.Entity, 0, len(rows))
+	for _, row := range rows {
+		if row.Inserted {
+			created++
+		} else {
+			updated++
 		}
+		dest = append(dest, row.Entity)
 	}
 
+	// a later upsert in the same transaction creates the table again
+	_, err = tx.ExecContext(ctx, `DROP TABLE product_upserts`)
+
 	return
 }
 
-func (s *ProductRepository) Delete(ctx context.Context, id string) (err error) {
+// Update writes the fields that are set, an empty name or category and nil values are left as they are.
+func (s *ProductRepository) Update(ctx context.Context, id string, data product.Entity) (err error) {
+	sets, args := s.prepareArgs(data)
+
+	args = append(args, id, data.Version)
+	sets = append(sets, "updated_at=CURRENT_TIMESTAMP", "version=version+1")
+
+	db := store.Executor(ctx, s.db)
+	query := fmt.Sprintf("UPDATE products SET %s WHERE id=$%d AND %s AND ($%d=0 OR version=$%d)",
+		strings.Join(sets, ", "), len(args)-1, liveProduct, len(args), len(args))
+	query += " AND " + tenantCondition(ctx, "tenant_id", &args)
+	res, err := db.ExecContext(ctx, query, args...)
+	if err = checkUniqueViolation(err); err != nil {
+		return
+	}
+
+	return checkVersion(ctx, db, "products", id, res, liveProduct)
+}
+
+func (s *ProductRepository) Delete(ctx context.Context, id string, version int, trashedWith string) (err error) {
+	args := []any{id, version, trashedWith}
 	query := `
-		DELETE 
-		FROM products
-		WHERE id=$1`
+		UPDATE products
+		SET deleted_at=CURRENT_TIMESTAMP, trashed_with=NULLIF($3, '')::UUID, updated_at=CURRENT_TIMESTAMP,
+			version=version+1
+		WHERE id=$1 AND ` + liveProduct + ` AND ($2=0 OR version=$2) AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	db := store.Executor(ctx, s.db)
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
 
+	return checkVersion(ctx, db, "products", id, res, liveProduct)
+}
+
+func (s *ProductRepository) Restore(ctx context.Context, id string) (err error) {
 	args := []any{id}
+	query := `
+		UPDATE products
+		SET deleted_at=NULL, trashed_with=NULL, updated_at=CURRENT_TIMESTAMP, version=version+1
+		WHERE id=$1 AND deleted_at IS NOT NULL AND ` + tenantCondition(ctx, "tenant_id", &args)
 
-	_, err = s.db.ExecContext(ctx, query, args...)
-	if err != nil && err != sql.ErrNoRows {
+	res, err := store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+	if err = checkUniqueViolation(err); err != nil {
 		return
 	}
 
-	if err == sql.ErrNoRows {
-		err = store.ErrorNotFound
+	return checkRowsAffected(res)
+}
+
+// Purge leaves the removal of images, prices and stock to the foreign keys, order items keep the product.
+func (s *ProductRepository) Purge(ctx context.Context, id string) (err error) {
+	args := []any{id}
+	query := `
+		DELETE
+		FROM products
+		WHERE id=$1 AND deleted_at IS NOT NULL AND ` + tenantCondition(ctx, "tenant_id", &args)
+
+	res, err := store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return product.ErrReferenced
+	}
+	if err != nil {
+		return
+	}
+
+	return checkRowsAffected(res)
+}
+
+// cursorValue parses the sort key of the cursor into the type of its column.
+func cursorValue(cursor product.Cursor) (value any, err error) {
+	switch cursor.Sort {
+	case product.SortName, product.SortNameDesc:
+		value = cursor.Key
+	case product.SortCreatedAt, product.SortCreatedAtDesc:
+		value, err = time.Parse(time.RFC3339Nano, cursor.Key)
+	default:
+		value, err = strconv.ParseFloat(cursor.Key, 64)
+	}
+
+	if err != nil {
+		err = product.ErrInvalidCursor
 	}
 
 	return
 }
 
 func (s *ProductRepository) prepareArgs(data product.Entity) (sets []string, args []any) {
-	if data.Description != nil {
-		args = append(args, data.Description)
-		sets = append(sets, fmt.Sprintf("description=$%d", len(args)))
+	if data.CategoryID != "" {
+		args = append(args, data.CategoryID)
+		sets = append(sets, fmt.Sprintf("category_id=$%d", len(args)))
+	}
+
+	if data.Name != "" {
+		args = append(args, data.Name)
+		sets = append(sets, fmt.Sprintf("name=$%d", len(args)))
+	}
+
+	columns := []struct {
+		name  string
+		value *string
+	}{
+		{"description", data.Description},
+		{"measure", data.Measure},
+		{"image_url", data.ImageURL},
+		{"country", data.Country},
+		{"brand", data.Brand},
+	}
+
+	for _, column := range columns {
+		if column.value != nil {
+			args = append(args, *column.value)
+			sets = append(sets, fmt.Sprintf("%s=$%d", column.name, len(args)))
+		}
+	}
+
+	// products without a barcode keep NULL, the unique index would take an empty one
+	if data.Barcode != nil {
+		args = append(args, *data.Barcode)
+		sets = append(sets, fmt.Sprintf("barcode=NULLIF($%d, '')", len(args)))
 	}
 
-	if data.Measure != nil {
-		args = append(args, data.Measure)
-		sets = append(sets, fmt.Sprintf("measure=$%d", len(args)))
+	if data.Attributes != nil {
+		args = append(args, string(data.Attributes))
+		sets = append(sets, fmt.Sprintf("attributes=$%d::JSONB", len(args)))
 	}
 
 	return
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"payment-service/internal/domain/settlement"
+
+	"github.com/jmoiron/sqlx"
+
+	"payment-service/pkg/store"
+)
+
+// SettlementRepository writes a statement with its rows and discrepancies, Create has to run within store.Transactor.
+type SettlementRepository struct {
+	db *sqlx.DB
+}
+
+func NewSettlementRepository(db *sqlx.DB) *SettlementRepository {
+	return &SettlementRepository{
+		db: db,
+	}
+}
+
+func (s *SettlementRepository) Create(ctx context.Context, data settlement.Statement) (id string, err error) {
+	query := `
+		INSERT INTO settlement_statements (date, filename, rows_total, matched, total_amount, total_fee)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	args := []any{data.Date, data.Filename, data.RowsTotal, data.Matched, data.TotalAmount, data.TotalFee}
+
+	db := store.Executor(ctx, s.db)
+	if err = db.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		return
+	}
+
+	query = `
+		INSERT INTO settlement_rows (statement_id, line, reference, int_reference, invoice_id, amount, fee, billing_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))`
+
+	for _, row := range data.Rows {
+		args = []any{id, row.Line, row.Reference, row.IntReference, row.InvoiceID, row.Amount, row.Fee, row.BillingID}
+
+		if _, err = db.ExecContext(ctx, query, args...); err != nil {
+			return
+		}
+	}
+
+	query = `
+		INSERT INTO settlement_discrepancies (statement_id, kind, line, billing_id, invoice_id, reference, expected, actual)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	for _, discrepancy := range data.Discrepancies {
+		args = []any{id, discrepancy.Kind, discrepancy.Line, discrepancy.BillingID, discrepancy.InvoiceID,
+			discrepancy.Reference, discrepancy.Expected, discrepancy.Actual}
+
+		if _, err = db.ExecContext(ctx, query, args...); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s *SettlementRepository) Select(ctx context.Context) (dest []settlement.Statement, err error) {
+	query := `
+		SELECT created_at, id, date, filename, rows_total, matched, total_amount, total_fee
+		FROM settlement_statements
+		ORDER BY created_at DESC`
+
+	err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &dest, query)
+
+	return
+}
+
+func (s *SettlementRepository) Get(ctx context.Context, id string) (dest settlement.Statement, err error) {
+	query := `
+		SELECT created_at, id, date, filename, rows_total, matched, total_amount, total_fee
+		FROM settlement_statements
+		WHERE id=$1`
+
+	args := []any{id}
+
+	db := store.Executor(ctx, s.db)
+	if err = sqlx.GetContext(ctx, db, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+		return
+	}
+
+	query = `
+		SELECT statement_id, kind, line, billing_id, invoice_id, reference, expected, actual
+		FROM settlement_discrepancies
+		WHERE statement_id=$1
+		ORDER BY kind, line`
+
+	err = sqlx.SelectContext(ctx, db, &dest.Discrepancies, query, args...)
+
+	return
+}
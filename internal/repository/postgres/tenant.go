@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"payment-service/internal/domain/tenant"
+	"payment-service/pkg/store"
+)
+
+type TenantRepository struct {
+	db *sqlx.DB
+}
+
+func NewTenantRepository(db *sqlx.DB) *TenantRepository {
+	return &TenantRepository{
+		db: db,
+	}
+}
+
+const tenantColumns = `
+	created_at, updated_at, id, name, terminal_id, currency, webhook_urls, webhook_secret`
+
+// tenantRow scans the webhook URLs array into the entity.
+type tenantRow struct {
+	tenant.Entity
+	WebhookURLs pq.StringArray `db:"webhook_urls"`
+}
+
+func (s *TenantRepository) Select(ctx context.Context) (dest []tenant.Entity, err error) {
+	query := `
+		SELECT` + tenantColumns + `
+		FROM tenants
+		ORDER BY id`
+
+	var rows []tenantRow
+	if err = sqlx.SelectContext(ctx, store.Executor(ctx, s.db), &rows, query); err != nil {
+		return
+	}
+
+	dest = make([]tenant.Entity, 0, len(rows))
+	for _, row := range rows {
+		row.Entity.WebhookURLs = row.WebhookURLs
+		dest = append(dest, row.Entity)
+	}
+
+	return
+}
+
+func (s *TenantRepository) Get(ctx context.Context, id string) (dest tenant.Entity, err error) {
+	query := `
+		SELECT` + tenantColumns + `
+		FROM tenants
+		WHERE id=$1`
+
+	args := []any{id}
+
+	var row tenantRow
+	if err = sqlx.GetContext(ctx, store.Executor(ctx, s.db), &row, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+		return
+	}
+
+	dest = row.Entity
+	dest.WebhookURLs = row.WebhookURLs
+
+	return
+}
+
+func (s *TenantRepository) Save(ctx context.Context, data tenant.Entity) (err error) {
+	query := `
+		INSERT INTO tenants (id, name, terminal_id, currency, webhook_urls, webhook_secret)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE
+		SET name=EXCLUDED.name, terminal_id=EXCLUDED.terminal_id, currency=EXCLUDED.currency,
+			webhook_urls=EXCLUDED.webhook_urls, webhook_secret=EXCLUDED.webhook_secret, updated_at=CURRENT_TIMESTAMP`
+
+	args := []any{data.ID, data.Name, data.TerminalID, data.Currency, pq.Array(data.WebhookURLs), data.WebhookSecret}
+
+	_, err = store.Executor(ctx, s.db).ExecContext(ctx, query, args...)
+
+	return
+}
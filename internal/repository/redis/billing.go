@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"payment-service/internal/domain/billing"
+	"payment-service/pkg/store"
+)
+
+// BillingCache reads billings through redis, writes made through its Repository drop the cached billings.
+type BillingCache struct {
+	cache
+	repository billing.Repository
+}
+
+func NewBillingCache(client *goredis.Client, repository billing.Repository, ttl time.Duration, codec Codec) *BillingCache {
+	return &BillingCache{
+		cache: cache{
+			client: client,
+			codec:  codec,
+			ttl:    ttl,
+		},
+		repository: repository,
+	}
+}
+
+func (c *BillingCache) Get(ctx context.Context, id string) (dest billing.Entity, err error) {
+	err = c.read(ctx, billingKey(id), &dest, func() (err error) {
+		dest, err = c.repository.Get(ctx, id)
+		return
+	})
+
+	// the cache is shared by the tenants, a billing cached for one is not found by another
+	if err == nil && !store.TenantAllows(ctx, dest.TenantID) {
+		dest, err = billing.Entity{}, store.ErrorNotFound
+	}
+	return
+}
+
+// Repository wraps the cached repository, its reads skip the cache so status changes always see the stored billing.
+func (c *BillingCache) Repository() billing.Repository {
+	return &billingRepository{
+		Repository: c.repository,
+		cache:      c.cache,
+	}
+}
+
+type billingRepository struct {
+	billing.Repository
+	cache cache
+}
+
+func (r *billingRepository) Update(ctx context.Context, id string, data billing.Entity) (err error) {
+	if err = r.Repository.Update(ctx, id, data); err != nil {
+		return
+	}
+	r.cache.invalidate(ctx, billingKey(id))
+
+	return
+}
+
+func (r *billingRepository) Delete(ctx context.Context, id string, version int) (err error) {
+	if err = r.Repository.Delete(ctx, id, version); err != nil {
+		return
+	}
+	r.cache.invalidate(ctx, billingKey(id))
+
+	return
+}
+
+func billingKey(id string) string {
+	return "billing:" + id
+}
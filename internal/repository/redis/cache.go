@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"payment-service/pkg/store"
+)
+
+// cache keeps entities under their keys for the ttl, a failing redis never fails a read: the store answers it.
+type cache struct {
+	client *goredis.Client
+	codec  Codec
+	ttl    time.Duration
+}
+
+// read fills dest from the key, on a miss load fills it from the store and it is kept for the next reads.
+func (c cache) read(ctx context.Context, key string, dest any, load func() error) (err error) {
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err == nil && c.codec.Unmarshal(data, dest) == nil {
+		return
+	}
+
+	if err = load(); err != nil {
+		return
+	}
+
+	if data, err := c.codec.Marshal(dest); err == nil {
+		c.client.Set(ctx, key, data, c.ttl)
+	}
+	return nil
+}
+
+// invalidate drops the keys once the write is committed, so a read in between can't bring the old entity back.
+// A key that fails to drop expires with the ttl.
+func (c cache) invalidate(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	store.AfterCommit(ctx, func() {
+		c.client.Del(ctx, keys...)
+	})
+}
@@ -0,0 +1,150 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/repository/memory"
+	"payment-service/pkg/store"
+)
+
+// redisTestURL names the environment variable with the redis the cache runs against, e.g. redis://localhost:6379/15.
+// The tests are skipped without it.
+const redisTestURL = "REDIS_TEST_URL"
+
+func newTestClient(t *testing.T) *goredis.Client {
+	t.Helper()
+
+	url := os.Getenv(redisTestURL)
+	if url == "" {
+		t.Skip(redisTestURL + " is not set")
+	}
+
+	opt, err := goredis.ParseURL(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := goredis.NewClient(opt)
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func TestBillingCache(t *testing.T) {
+	ctx := store.WithOperator(context.Background())
+	client := newTestClient(t)
+	billings := memory.NewBillingRepository()
+	transactor := memory.NewTransactor()
+
+	codec, err := NewCodec(EncodingMsgpack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewBillingCache(client, billings, time.Minute, codec)
+
+	create := func(t *testing.T) string {
+		t.Helper()
+		id, err := billings.Create(ctx, billing.Entity{TenantID: "tenant", Amount: "100", Currency: "KZT", Status: billing.StatusCreated})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { client.Del(ctx, billingKey(id)) })
+
+		if _, err = c.Get(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	cached := func(t *testing.T, id string) bool {
+		t.Helper()
+		n, err := client.Exists(ctx, billingKey(id)).Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n == 1
+	}
+
+	t.Run("read through", func(t *testing.T) {
+		id := create(t)
+		if !cached(t, id) {
+			t.Fatal("the billing read is not cached")
+		}
+
+		// a change made behind the cache is not seen until the billing expires
+		if err := billings.Update(ctx, id, billing.Entity{Status: billing.StatusPaid}); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := c.Get(ctx, id); err != nil || got.Status != billing.StatusCreated || got.Amount != "100" {
+			t.Errorf("got %+v, err = %v, want the cached billing", got, err)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		if _, err := c.Get(ctx, "missing"); err != store.ErrorNotFound {
+			t.Errorf("err = %v, want %v", err, store.ErrorNotFound)
+		}
+		if cached(t, "missing") {
+			t.Error("a missing billing is cached")
+		}
+	})
+
+	t.Run("other tenant", func(t *testing.T) {
+		id := create(t)
+		if _, err := c.Get(store.WithTenant(ctx, "other"), id); err != store.ErrorNotFound {
+			t.Errorf("err = %v, want %v", err, store.ErrorNotFound)
+		}
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		id := create(t)
+
+		err := transactor.Transact(ctx, func(ctx context.Context) error {
+			if err := c.Repository().Update(ctx, id, billing.Entity{Status: billing.StatusPaid}); err != nil {
+				return err
+			}
+
+			// a read before the commit would bring the old billing back, so the key is dropped after it
+			if !cached(t, id) {
+				t.Error("the billing is dropped before the commit")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if cached(t, id) {
+			t.Error("the billing is still cached after the commit")
+		}
+		if got, err := c.Get(ctx, id); err != nil || got.Status != billing.StatusPaid {
+			t.Errorf("got %+v, err = %v, want the paid billing", got, err)
+		}
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		id := create(t)
+		rollback := errors.New("rollback")
+
+		err := transactor.Transact(ctx, func(ctx context.Context) error {
+			if err := c.Repository().Update(ctx, id, billing.Entity{Status: billing.StatusFailed}); err != nil {
+				return err
+			}
+			return rollback
+		})
+		if err != rollback {
+			t.Fatalf("err = %v, want %v", err, rollback)
+		}
+
+		if !cached(t, id) {
+			t.Error("the billing is dropped although nothing was committed")
+		}
+	})
+}
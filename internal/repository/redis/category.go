@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"payment-service/internal/domain/category"
+	"payment-service/pkg/store"
+)
+
+// CategoryCache reads categories through redis, writes made through its Repository drop the cached categories.
+type CategoryCache struct {
+	cache
+	repository category.Repository
+}
+
+func NewCategoryCache(client *goredis.Client, repository category.Repository, ttl time.Duration, codec Codec) *CategoryCache {
+	return &CategoryCache{
+		cache: cache{
+			client: client,
+			codec:  codec,
+			ttl:    ttl,
+		},
+		repository: repository,
+	}
+}
+
+func (c *CategoryCache) Get(ctx context.Context, id string) (dest category.Entity, err error) {
+	err = c.read(ctx, categoryKey(id), &dest, func() (err error) {
+		dest, err = c.repository.Get(ctx, id)
+		return
+	})
+
+	// the cache is shared by the tenants, a category cached for one is not found by another
+	if err == nil && !store.TenantAllows(ctx, dest.TenantID) {
+		dest, err = category.Entity{}, store.ErrorNotFound
+	}
+	return
+}
+
+// Repository wraps the cached repository, its reads skip the cache so writes always see the stored category.
+func (c *CategoryCache) Repository() category.Repository {
+	return &categoryRepository{
+		Repository: c.repository,
+		cache:      c.cache,
+	}
+}
+
+type categoryRepository struct {
+	category.Repository
+	cache cache
+}
+
+func (r *categoryRepository) Update(ctx context.Context, id string, data category.Entity) (err error) {
+	if err = r.Repository.Update(ctx, id, data); err != nil {
+		return
+	}
+	r.cache.invalidate(ctx, categoryKey(id))
+
+	return
+}
+
+func (r *categoryRepository) Move(ctx context.Context, id, parentID string, version int) (err error) {
+	if err = r.Repository.Move(ctx, id, parentID, version); err != nil {
+		return
+	}
+	r.cache.invalidate(ctx, categoryKey(id))
+
+	return
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, id string, version int, trashedWith string) (err error) {
+	if err = r.Repository.Delete(ctx, id, version, trashedWith); err != nil {
+		return
+	}
+	r.cache.invalidate(ctx, categoryKey(id))
+
+	return
+}
+
+func categoryKey(id string) string {
+	return "category:" + id
+}
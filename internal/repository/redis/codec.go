@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	EncodingJSON    = "json"
+	EncodingMsgpack = "msgpack"
+)
+
+var ErrUnknownEncoding = errors.New("encoding: must be json or msgpack")
+
+// Codec turns the cached entities into the values stored in redis and back.
+type Codec interface {
+	Marshal(value any) ([]byte, error)
+	Unmarshal(data []byte, dest any) error
+}
+
+// NewCodec returns the codec of the encoding, an empty encoding is json.
+func NewCodec(encoding string) (Codec, error) {
+	switch encoding {
+	case "", EncodingJSON:
+		return JSON{}, nil
+	case EncodingMsgpack:
+		return Msgpack{}, nil
+	default:
+		return nil, ErrUnknownEncoding
+	}
+}
+
+type JSON struct{}
+
+func (JSON) Marshal(value any) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSON) Unmarshal(data []byte, dest any) error {
+	return json.Unmarshal(data, dest)
+}
+
+// Msgpack stores the JSON form of a value in the msgpack format, which is more compact for the same structure:
+// field names and values are the ones encoding/json produces, so both codecs read the entities the same way.
+type Msgpack struct{}
+
+func (Msgpack) Marshal(value any) (data []byte, err error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var generic any
+	if err = decoder.Decode(&generic); err != nil {
+		return
+	}
+
+	return appendMsgpack(nil, generic)
+}
+
+func (Msgpack) Unmarshal(data []byte, dest any) (err error) {
+	generic, rest, err := readMsgpack(data)
+	if err != nil {
+		return
+	}
+
+	if len(rest) > 0 {
+		return ErrMalformed
+	}
+
+	raw, err := json.Marshal(generic)
+	if err != nil {
+		return
+	}
+
+	return json.Unmarshal(raw, dest)
+}
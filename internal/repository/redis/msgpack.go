@@ -0,0 +1,243 @@
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var ErrMalformed = errors.New("msgpack: value is malformed")
+
+// appendMsgpack encodes the values encoding/json decodes into with UseNumber.
+func appendMsgpack(buf []byte, value any) (_ []byte, err error) {
+	switch value := value.(type) {
+	case nil:
+		return append(buf, 0xc0), nil
+	case bool:
+		if value {
+			return append(buf, 0xc3), nil
+		}
+		return append(buf, 0xc2), nil
+	case json.Number:
+		if number, err := value.Int64(); err == nil {
+			return appendInt(buf, number), nil
+		}
+
+		number, err := value.Float64()
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, 0xcb)
+		return appendUint(buf, math.Float64bits(number), 8), nil
+	case string:
+		return appendString(buf, value), nil
+	case []any:
+		buf = appendHeader(buf, len(value), 0x90, 0xdc, 0xdd)
+		for _, item := range value {
+			if buf, err = appendMsgpack(buf, item); err != nil {
+				return
+			}
+		}
+		return buf, nil
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buf = appendHeader(buf, len(value), 0x80, 0xde, 0xdf)
+		for _, key := range keys {
+			buf = appendString(buf, key)
+			if buf, err = appendMsgpack(buf, value[key]); err != nil {
+				return
+			}
+		}
+		return buf, nil
+	default:
+		return nil, fmt.Errorf("msgpack: cannot encode %T", value)
+	}
+}
+
+func appendInt(buf []byte, value int64) []byte {
+	switch {
+	case value >= 0 && value <= 0x7f:
+		return append(buf, byte(value))
+	case value < 0 && value >= -32:
+		return append(buf, byte(value))
+	case value >= math.MinInt32 && value <= math.MaxInt32:
+		buf = append(buf, 0xd2)
+		return appendUint(buf, uint64(uint32(value)), 4)
+	default:
+		buf = append(buf, 0xd3)
+		return appendUint(buf, uint64(value), 8)
+	}
+}
+
+func appendString(buf []byte, value string) []byte {
+	switch n := len(value); {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		buf = append(buf, 0xda)
+		buf = appendUint(buf, uint64(uint16(n)), 2)
+	default:
+		buf = append(buf, 0xdb)
+		buf = appendUint(buf, uint64(uint32(n)), 4)
+	}
+	return append(buf, value...)
+}
+
+// appendUint writes the low size bytes of the value in big-endian order.
+func appendUint(buf []byte, value uint64, size int) []byte {
+	for i := size - 1; i >= 0; i-- {
+		buf = append(buf, byte(value>>(8*i)))
+	}
+	return buf
+}
+
+// appendHeader writes the length of an array or a map in its fix, 16 or 32 bit form.
+func appendHeader(buf []byte, n int, fix, code16, code32 byte) []byte {
+	switch {
+	case n < 16:
+		return append(buf, fix|byte(n))
+	case n <= math.MaxUint16:
+		buf = append(buf, code16)
+		return appendUint(buf, uint64(uint16(n)), 2)
+	default:
+		buf = append(buf, code32)
+		return appendUint(buf, uint64(uint32(n)), 4)
+	}
+}
+
+// readMsgpack decodes a single value into what encoding/json can encode and returns the bytes after it.
+func readMsgpack(data []byte) (value any, rest []byte, err error) {
+	if len(data) == 0 {
+		return nil, nil, ErrMalformed
+	}
+
+	code, data := data[0], data[1:]
+	switch {
+	case code <= 0x7f:
+		return int64(code), data, nil
+	case code >= 0xe0:
+		return int64(int8(code)), data, nil
+	case code&0xe0 == 0xa0:
+		return readString(data, int(code&0x1f))
+	case code&0xf0 == 0x90:
+		return readArray(data, int(code&0x0f))
+	case code&0xf0 == 0x80:
+		return readMap(data, int(code&0x0f))
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, data, nil
+	case 0xc2:
+		return false, data, nil
+	case 0xc3:
+		return true, data, nil
+	case 0xca:
+		bits, data, err := readUint(data, 4)
+		return float64(math.Float32frombits(uint32(bits))), data, err
+	case 0xcb:
+		bits, data, err := readUint(data, 8)
+		return math.Float64frombits(bits), data, err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return readUint(data, 1<<(code-0xcc))
+	case 0xd0:
+		number, data, err := readUint(data, 1)
+		return int64(int8(number)), data, err
+	case 0xd1:
+		number, data, err := readUint(data, 2)
+		return int64(int16(number)), data, err
+	case 0xd2:
+		number, data, err := readUint(data, 4)
+		return int64(int32(number)), data, err
+	case 0xd3:
+		number, data, err := readUint(data, 8)
+		return int64(number), data, err
+	case 0xd9, 0xda, 0xdb:
+		n, data, err := readUint(data, 1<<(code-0xd9))
+		if err != nil {
+			return nil, nil, err
+		}
+		return readString(data, int(n))
+	case 0xdc, 0xdd:
+		n, data, err := readUint(data, 2<<(code-0xdc))
+		if err != nil {
+			return nil, nil, err
+		}
+		return readArray(data, int(n))
+	case 0xde, 0xdf:
+		n, data, err := readUint(data, 2<<(code-0xde))
+		if err != nil {
+			return nil, nil, err
+		}
+		return readMap(data, int(n))
+	default:
+		return nil, nil, ErrMalformed
+	}
+}
+
+func readUint(data []byte, size int) (value uint64, rest []byte, err error) {
+	if len(data) < size {
+		return 0, nil, ErrMalformed
+	}
+
+	for _, b := range data[:size] {
+		value = value<<8 | uint64(b)
+	}
+	return value, data[size:], nil
+}
+
+func readString(data []byte, n int) (value any, rest []byte, err error) {
+	if n < 0 || len(data) < n {
+		return nil, nil, ErrMalformed
+	}
+	return string(data[:n]), data[n:], nil
+}
+
+func readArray(data []byte, n int) (value any, rest []byte, err error) {
+	// every item takes a byte at least, a longer header is malformed
+	if n < 0 || len(data) < n {
+		return nil, nil, ErrMalformed
+	}
+
+	items := make([]any, n)
+	for i := range items {
+		if items[i], data, err = readMsgpack(data); err != nil {
+			return
+		}
+	}
+	return items, data, nil
+}
+
+func readMap(data []byte, n int) (value any, rest []byte, err error) {
+	if n < 0 || len(data) < 2*n {
+		return nil, nil, ErrMalformed
+	}
+
+	items := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		var key, item any
+		if key, data, err = readMsgpack(data); err != nil {
+			return
+		}
+
+		name, ok := key.(string)
+		if !ok {
+			return nil, nil, ErrMalformed
+		}
+
+		if item, data, err = readMsgpack(data); err != nil {
+			return
+		}
+		items[name] = item
+	}
+	return items, data, nil
+}
@@ -0,0 +1,164 @@
+package redis
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type cached struct {
+	Amount    decimal.Decimal   `json:"amount"`
+	Rate      float64           `json:"rate"`
+	Count     int64             `json:"count"`
+	PaidAt    time.Time         `json:"paid_at"`
+	DeletedAt *time.Time        `json:"deleted_at"`
+	Name      *string           `json:"name"`
+	Tags      []string          `json:"tags"`
+	Lines     []cachedLine      `json:"lines"`
+	Labels    map[string]string `json:"labels"`
+	Active    bool              `json:"active"`
+}
+
+type cachedLine struct {
+	Line     int             `json:"line"`
+	Quantity decimal.Decimal `json:"quantity"`
+}
+
+func TestCodec(t *testing.T) {
+	name := "Apple juice"
+	paidAt := time.Date(2024, 3, 15, 12, 30, 45, 123456789, time.FixedZone("ALMT", 5*60*60))
+
+	many := make([]string, 70000)
+	labels := make(map[string]string)
+	for i := 0; i < 20; i++ {
+		labels[strings.Repeat("k", i+1)] = strings.Repeat("v", i*20)
+	}
+
+	tests := []struct {
+		name  string
+		value cached
+	}{
+		{name: "zero", value: cached{}},
+		{
+			name: "values",
+			value: cached{
+				Amount: decimal.RequireFromString("12345678901234567890.123456789"),
+				Rate:   0.1,
+				Count:  -33,
+				PaidAt: paidAt,
+				Name:   &name,
+				Tags:   []string{"", "a", strings.Repeat("b", 40), strings.Repeat("c", 300)},
+				Active: true,
+			},
+		},
+		{
+			name: "integers",
+			value: cached{
+				Count: math.MinInt64,
+				Lines: []cachedLine{{Line: 127}, {Line: 128}, {Line: -32}, {Line: -33}, {Line: math.MaxInt32 + 1}},
+			},
+		},
+		{
+			name: "nil pointers and empty arrays",
+			value: cached{
+				DeletedAt: nil,
+				Name:      nil,
+				Tags:      []string{},
+				Lines:     nil,
+			},
+		},
+		{
+			name:  "long arrays and maps",
+			value: cached{Tags: many, Labels: labels, Lines: []cachedLine{{Line: 1, Quantity: decimal.RequireFromString("0.001")}}},
+		},
+	}
+
+	for _, encoding := range []string{EncodingJSON, EncodingMsgpack} {
+		codec, err := NewCodec(encoding)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, tt := range tests {
+			t.Run(encoding+" "+tt.name, func(t *testing.T) {
+				data, err := codec.Marshal(tt.value)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var got cached
+				if err = codec.Unmarshal(data, &got); err != nil {
+					t.Fatal(err)
+				}
+
+				// times and decimals are compared by their value, their representation may differ
+				if !got.PaidAt.Equal(tt.value.PaidAt) || !got.Amount.Equal(tt.value.Amount) {
+					t.Errorf("got %s and %s, want %s and %s", got.PaidAt, got.Amount, tt.value.PaidAt, tt.value.Amount)
+				}
+				got.PaidAt, got.Amount = tt.value.PaidAt, tt.value.Amount
+
+				for i := range got.Lines {
+					if !got.Lines[i].Quantity.Equal(tt.value.Lines[i].Quantity) {
+						t.Errorf("got %s, want %s", got.Lines[i].Quantity, tt.value.Lines[i].Quantity)
+					}
+					got.Lines[i].Quantity = tt.value.Lines[i].Quantity
+				}
+
+				if !reflect.DeepEqual(got, tt.value) {
+					t.Errorf("got %+v, want %+v", got, tt.value)
+				}
+			})
+		}
+	}
+}
+
+func TestMsgpack(t *testing.T) {
+	t.Run("format", func(t *testing.T) {
+		got, err := Msgpack{}.Marshal(map[string]any{"b": []int{1, -1}, "a": nil, "c": true})
+		want := []byte{0x83, 0xa1, 'a', 0xc0, 0xa1, 'b', 0x92, 0x01, 0xff, 0xa1, 'c', 0xc3}
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("got % x, err = %v, want % x", got, err, want)
+		}
+	})
+
+	t.Run("other encoders", func(t *testing.T) {
+		// uint8, uint16, int8, int16 and float32 are not written by the codec but read as other encoders write them
+		data := []byte{0x95, 0xcc, 0xff, 0xcd, 0x01, 0x00, 0xd0, 0x80, 0xd1, 0x80, 0x00, 0xca, 0x3f, 0xc0, 0x00, 0x00}
+		var got []float64
+		if err := (Msgpack{}).Unmarshal(data, &got); err != nil || !reflect.DeepEqual(got, []float64{255, 256, -128, -32768, 1.5}) {
+			t.Errorf("got %v, err = %v", got, err)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		valid, err := Msgpack{}.Marshal(cached{Tags: []string{"a", "b"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, data := range [][]byte{
+			nil,
+			valid[:len(valid)-1],
+			append(append([]byte(nil), valid...), 0xc0),
+			{0xdc, 0xff, 0xff},
+			{0x81, 0x01, 0x01},
+			{0xc1},
+		} {
+			var got cached
+			if err := (Msgpack{}).Unmarshal(data, &got); err != ErrMalformed {
+				t.Errorf("got %v for % x, want %v", err, data, ErrMalformed)
+			}
+		}
+	})
+
+	t.Run("unknown encoding", func(t *testing.T) {
+		if _, err := NewCodec("gob"); err != ErrUnknownEncoding {
+			t.Errorf("err = %v, want %v", err, ErrUnknownEncoding)
+		}
+	})
+}
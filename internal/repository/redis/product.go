@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"payment-service/internal/domain/product"
+	"payment-service/pkg/store"
+)
+
+// ProductCache reads products through redis, writes made through its Repository drop the cached products.
+type ProductCache struct {
+	cache
+	repository product.Repository
+}
+
+func NewProductCache(client *goredis.Client, repository product.Repository, ttl time.Duration, codec Codec) *ProductCache {
+	return &ProductCache{
+		cache: cache{
+			client: client,
+			codec:  codec,
+			ttl:    ttl,
+		},
+		repository: repository,
+	}
+}
+
+func (c *ProductCache) Get(ctx context.Context, id string) (dest product.Entity, err error) {
+	err = c.read(ctx, productKey(id), &dest, func() (err error) {
+		dest, err = c.repository.Get(ctx, id)
+		return
+	})
+
+	// the cache is shared by the tenants, a product cached for one is not found by another
+	if err == nil && !store.TenantAllows(ctx, dest.TenantID) {
+		dest, err = product.Entity{}, store.ErrorNotFound
+	}
+	return
+}
+
+// Repository wraps the cached repository, its reads skip the cache so writes always see the stored product.
+func (c *ProductCache) Repository() product.Repository {
+	return &productRepository{
+		Repository: c.repository,
+		cache:      c.cache,
+	}
+}
+
+type productRepository struct {
+	product.Repository
+	cache cache
+}
+
+// Upsert drops the products it stored, the created ones are not cached and dropping them costs nothing.
+func (r *productRepository) Upsert(ctx context.Context, data []product.Entity) (dest []product.Entity, created, updated int, err error) {
+	dest, created, updated, err = r.Repository.Upsert(ctx, data)
+	if err != nil || updated == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(dest))
+	for _, stored := range dest {
+		keys = append(keys, productKey(stored.ID))
+	}
+	r.cache.invalidate(ctx, keys...)
+
+	return
+}
+
+func (r *productRepository) Update(ctx context.Context, id string, data product.Entity) (err error) {
+	if err = r.Repository.Update(ctx, id, data); err != nil {
+		return
+	}
+	r.cache.invalidate(ctx, productKey(id))
+
+	return
+}
+
+func (r *productRepository) Delete(ctx context.Context, id string, version int, trashedWith string) (err error) {
+	if err = r.Repository.Delete(ctx, id, version, trashedWith); err != nil {
+		return
+	}
+	r.cache.invalidate(ctx, productKey(id))
+
+	return
+}
+
+func productKey(id string) string {
+	return "product:" + id
+}
@@ -1,11 +1,28 @@
 package repository
 
 import (
+	"time"
+
+	"payment-service/internal/domain/apikey"
+	"payment-service/internal/domain/audit"
 	"payment-service/internal/domain/billing"
 	"payment-service/internal/domain/category"
+	"payment-service/internal/domain/dispute"
+	"payment-service/internal/domain/fx"
+	"payment-service/internal/domain/inventory"
+	"payment-service/internal/domain/ledger"
+	"payment-service/internal/domain/order"
+	"payment-service/internal/domain/outbox"
+	"payment-service/internal/domain/price"
 	"payment-service/internal/domain/product"
+	"payment-service/internal/domain/settlement"
+	"payment-service/internal/domain/tenant"
+	"payment-service/internal/repository/audited"
+	"payment-service/internal/repository/encrypted"
 	"payment-service/internal/repository/memory"
 	"payment-service/internal/repository/postgres"
+	"payment-service/internal/repository/redis"
+	"payment-service/pkg/envelope"
 	"payment-service/pkg/store"
 )
 
@@ -15,17 +32,45 @@ type Configuration func(r *Repository) error
 // Repository is an implementation of the Repository
 type Repository struct {
 	postgres *store.Database
+	redis    *store.Redis
+
+	Product    product.Repository
+	Category   category.Repository
+	Attribute  category.AttributeRepository
+	Billing    billing.Repository
+	Outbox     outbox.Repository
+	Ledger     ledger.Repository
+	Settlement settlement.Repository
+	Dispute    dispute.Repository
+	FX         fx.Repository
+	Price      price.Repository
+	Order      order.Repository
+	Inventory  inventory.Repository
+	Import     product.JobRepository
+	Image      product.ImageRepository
+	Audit      audit.Repository
+	APIKey     apikey.Repository
+	Tenant     tenant.Repository
+
+	// ProductCache, CategoryCache and BillingCache read through redis when WithRedisCache is applied, the store otherwise
+	ProductCache  product.Cache
+	CategoryCache category.Cache
+	BillingCache  billing.Cache
 
-	Product  product.Repository
-	Category category.Repository
-	Billing  billing.Repository
+	// BillingRekeyer is set when WithPIIEncryption is applied
+	BillingRekeyer billing.Rekeyer
+	// BillingIndex keys the blind indexes of the payers with the key file when WithPIIEncryption is applied,
+	// it is billing.PlainIndex otherwise
+	BillingIndex billing.Index
+
+	Transactor store.Transactor
 }
 
 // New takes a variable amount of Configuration functions and returns a new Repository
 // Each Configuration will be called in the order they are passed in
 func New(configs ...Configuration) (s *Repository, err error) {
 	// Create the repository
-	s = &Repository{}
+	s = &Repository{BillingIndex: billing.PlainIndex}
 
 	// Apply all Configurations passed in
 	for _, cfg := range configs {
@@ -44,6 +89,10 @@ func (r *Repository) Close() {
 	if r.postgres != nil {
 		r.postgres.Client.Close()
 	}
+
+	if r.redis != nil {
+		r.redis.Client.Close()
+	}
 }
 
 // WithMemoryStore applies a memory store to the Repository
@@ -51,8 +100,29 @@ func WithMemoryStore() Configuration {
 	return func(s *Repository) (err error) {
 		// Create the memory store, if we needed parameters, such as connection strings they could be inputted here
 		s.Category = memory.NewCategoryRepository()
+		s.Attribute = memory.NewAttributeRepository()
 		s.Billing = memory.NewBillingRepository()
 		s.Product = memory.NewProductRepository()
+		s.Outbox = memory.NewOutboxRepository()
+		s.Ledger = memory.NewLedgerRepository()
+		s.Settlement = memory.NewSettlementRepository()
+		s.Dispute = memory.NewDisputeRepository()
+		s.FX = memory.NewFXRepository()
+		s.Price = memory.NewPriceRepository()
+		s.Order = memory.NewOrderRepository()
+		s.Inventory = memory.NewInventoryRepository()
+		s.Import = memory.NewImportRepository()
+		s.Image = memory.NewImageRepository()
+		s.Audit = memory.NewAuditRepository()
+		s.APIKey = memory.NewAPIKeyRepository()
+		s.Tenant = memory.NewTenantRepository()
+
+		s.Transactor = memory.NewTransactor()
+		s.withAudit()
+
+		s.ProductCache = s.Product
+		s.CategoryCache = s.Category
+		s.BillingCache = s.Billing
 
 		return
 	}
@@ -73,7 +143,97 @@ func WithPostgresStore(schema, dataSourceName string) Configuration {
 		}
 
 		s.Category = postgres.NewCategoryRepository(s.postgres.Client)
+		s.Attribute = postgres.NewAttributeRepository(s.postgres.Client)
 		s.Product = postgres.NewProductRepository(s.postgres.Client)
+		s.Billing = postgres.NewBillingRepository(s.postgres.Client)
+		s.Outbox = postgres.NewOutboxRepository(s.postgres.Client)
+		s.Ledger = postgres.NewLedgerRepository(s.postgres.Client)
+		s.Settlement = postgres.NewSettlementRepository(s.postgres.Client)
+		s.Dispute = postgres.NewDisputeRepository(s.postgres.Client)
+		s.FX = postgres.NewFXRepository(s.postgres.Client)
+		s.Price = postgres.NewPriceRepository(s.postgres.Client)
+		s.Order = postgres.NewOrderRepository(s.postgres.Client)
+		s.Inventory = postgres.NewInventoryRepository(s.postgres.Client)
+		s.Import = postgres.NewImportRepository(s.postgres.Client)
+		s.Image = postgres.NewImageRepository(s.postgres.Client)
+		s.Audit = postgres.NewAuditRepository(s.postgres.Client)
+		s.APIKey = postgres.NewAPIKeyRepository(s.postgres.Client)
+		s.Tenant = postgres.NewTenantRepository(s.postgres.Client)
+
+		s.Transactor = s.postgres
+		s.withAudit()
+
+		s.ProductCache = s.Product
+		s.CategoryCache = s.Category
+		s.BillingCache = s.Billing
+
+		return
+	}
+}
+
+// withAudit wraps the repositories of the catalogue and the billings, so their writes are recorded in the audit trail.
+func (s *Repository) withAudit() {
+	recorder := audited.NewRecorder(s.Audit, s.Transactor)
+
+	s.Product = audited.NewProductRepository(s.Product, recorder)
+	s.Image = audited.NewImageRepository(s.Image, recorder)
+	s.Category = audited.NewCategoryRepository(s.Category, recorder)
+	s.Attribute = audited.NewAttributeRepository(s.Attribute, recorder)
+	s.Price = audited.NewPriceRepository(s.Price, recorder)
+	s.Billing = audited.NewBillingRepository(s.Billing, recorder)
+	s.Dispute = audited.NewDisputeRepository(s.Dispute, recorder)
+}
+
+// WithRedisCache puts a redis read-through cache in front of products, categories and billings of the store,
+// so it has to be applied after the store. Entities are kept for the ttl in the json or msgpack encoding.
+// The store stays uncached when url is empty or ttl is not positive.
+func WithRedisCache(url string, ttl time.Duration, encoding string) Configuration {
+	return func(s *Repository) (err error) {
+		if url == "" || ttl <= 0 {
+			return
+		}
+
+		codec, err := redis.NewCodec(encoding)
+		if err != nil {
+			return
+		}
+
+		s.redis, err = store.NewRedis(url)
+		if err != nil {
+			return
+		}
+
+		products := redis.NewProductCache(s.redis.Client, s.Product, ttl, codec)
+		s.Product, s.ProductCache = products.Repository(), products
+
+		categories := redis.NewCategoryCache(s.redis.Client, s.Category, ttl, codec)
+		s.Category, s.CategoryCache = categories.Repository(), categories
+
+		billings := redis.NewBillingCache(s.redis.Client, s.Billing, ttl, codec)
+		s.Billing, s.BillingCache = billings.Repository(), billings
+
+		return
+	}
+}
+
+// WithPIIEncryption encrypts the personal data of the billings with the keys of the key file, the store, the audit
+// trail and the cache only see it encrypted, so it has to be applied after them. The billings stay unencrypted
+// when keyFile is empty.
+func WithPIIEncryption(keyFile string) Configuration {
+	return func(s *Repository) (err error) {
+		if keyFile == "" {
+			return
+		}
+
+		keyring, err := envelope.Load(keyFile)
+		if err != nil {
+			return
+		}
+
+		billings := encrypted.NewBillingRepository(s.Billing, keyring)
+		s.Billing, s.BillingRekeyer, s.BillingIndex = billings, billings, keyring.Index
+		s.BillingCache = encrypted.NewBillingCache(s.BillingCache, keyring)
+
 		return
 	}
 }
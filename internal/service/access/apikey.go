@@ -0,0 +1,122 @@
+package access
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"payment-service/internal/domain/apikey"
+	"payment-service/internal/domain/tenant"
+	"payment-service/pkg/auth"
+	"payment-service/pkg/store"
+)
+
+// keyPrefix marks the keys issued by the service, so a leaked one is easy to recognize.
+const keyPrefix = "psk_"
+
+func (s *Service) ListKeys(ctx context.Context) (res []apikey.Response, err error) {
+	data, err := s.apiKeyRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	res = apikey.ParseFromEntities(data)
+
+	return
+}
+
+// IssueKey generates a key for the source service, the response is the only place the key itself is shown.
+// The tenant of the key has to have its settings saved first, a caller scoped to a tenant only issues keys
+// of its own tenant.
+func (s *Service) IssueKey(ctx context.Context, req apikey.Request) (res apikey.Response, err error) {
+	req.TenantID = store.ResolveTenant(ctx, req.TenantID)
+	if req.TenantID != "" && apikey.HasScope(req.Scopes, apikey.ScopeAdmin) {
+		return res, apikey.ErrTenantAdmin
+	}
+
+	if req.TenantID != "" {
+		if _, err = s.tenantRepository.Get(ctx, req.TenantID); err == store.ErrorNotFound {
+			err = tenant.ErrNotFound
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return
+	}
+	key := keyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	data := apikey.Entity{
+		TenantID: req.TenantID,
+		Name:     req.Name,
+		Hash:     hashKey(key),
+		Scopes:   req.Scopes,
+	}
+
+	if data.ID, err = s.apiKeyRepository.Create(ctx, data); err != nil {
+		return
+	}
+
+	if data, err = s.apiKeyRepository.Get(ctx, data.ID); err != nil {
+		return
+	}
+	res = apikey.ParseFromEntity(data)
+	res.Key = key
+
+	return
+}
+
+func (s *Service) RevokeKey(ctx context.Context, id string) (err error) {
+	return s.apiKeyRepository.Revoke(ctx, id)
+}
+
+// EnsureKey stores a key given by the configuration unless it is stored already, e.g. the key of the first admin.
+func (s *Service) EnsureKey(ctx context.Context, name, key string, scopes []string) (err error) {
+	data := apikey.Entity{
+		Name:   name,
+		Hash:   hashKey(key),
+		Scopes: scopes,
+	}
+
+	if _, err = s.apiKeyRepository.Create(ctx, data); err == store.ErrorAlreadyExists {
+		err = nil
+	}
+
+	return
+}
+
+// Lookup implements auth.KeyStore, the caller of a key is the source service it was issued to
+// acting for the tenant of the key. Only admin keys act for every tenant, a key issued without a tenant
+// before tenants were introduced is refused until it is reissued. The key is looked up among those of every tenant,
+// the caller is not known yet.
+func (s *Service) Lookup(ctx context.Context, key string) (principal auth.Principal, err error) {
+	data, err := s.apiKeyRepository.GetByHash(store.WithOperator(ctx), hashKey(key))
+	if err == store.ErrorNotFound {
+		return principal, auth.ErrInvalidCredentials
+	}
+	if err != nil {
+		return
+	}
+
+	if data.TenantID == "" && !apikey.HasScope(data.Scopes, apikey.ScopeAdmin) {
+		return principal, auth.ErrInvalidCredentials
+	}
+
+	principal = auth.Principal{
+		Subject: data.Name,
+		Tenant:  data.TenantID,
+		Scopes:  data.Scopes,
+	}
+
+	return
+}
+
+// hashKey returns the SHA-256 of the key, keys are random enough for a plain hash to keep them safe.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
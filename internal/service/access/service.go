@@ -0,0 +1,51 @@
+package access
+
+import (
+	"payment-service/internal/domain/apikey"
+	"payment-service/internal/domain/tenant"
+)
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service is an implementation of the Service
+type Service struct {
+	apiKeyRepository apikey.Repository
+	tenantRepository tenant.Repository
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Create the service
+	s = &Service{}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WithAPIKeyRepository applies a given API key repository to the Service
+func WithAPIKeyRepository(apiKeyRepository apikey.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.apiKeyRepository = apiKeyRepository
+		return nil
+	}
+}
+
+// WithTenantRepository applies a given tenant repository to the Service
+func WithTenantRepository(tenantRepository tenant.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.tenantRepository = tenantRepository
+		return nil
+	}
+}
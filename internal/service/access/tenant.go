@@ -0,0 +1,48 @@
+package access
+
+import (
+	"context"
+
+	"payment-service/internal/domain/tenant"
+	"payment-service/pkg/store"
+)
+
+func (s *Service) ListTenants(ctx context.Context) (res []tenant.Response, err error) {
+	data, err := s.tenantRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	res = tenant.ParseFromEntities(data)
+
+	return
+}
+
+func (s *Service) GetTenant(ctx context.Context, id string) (res tenant.Response, err error) {
+	data, err := s.tenantRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+	res = tenant.ParseFromEntity(data)
+
+	return
+}
+
+// SaveTenant replaces the settings of the tenant, the tenant is created by its first save. A caller scoped
+// to a tenant only saves the settings of its own tenant.
+func (s *Service) SaveTenant(ctx context.Context, id string, req tenant.Request) (res tenant.Response, err error) {
+	id = store.ResolveTenant(ctx, id)
+	data := tenant.Entity{
+		ID:            id,
+		Name:          req.Name,
+		TerminalID:    req.TerminalID,
+		Currency:      req.Currency,
+		WebhookURLs:   req.WebhookURLs,
+		WebhookSecret: req.WebhookSecret,
+	}
+
+	if err = s.tenantRepository.Save(ctx, data); err != nil {
+		return
+	}
+
+	return s.GetTenant(ctx, id)
+}
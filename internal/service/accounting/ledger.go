@@ -0,0 +1,41 @@
+package accounting
+
+import (
+	"context"
+	"payment-service/internal/domain/ledger"
+)
+
+func (s *Service) ListAccounts(ctx context.Context) (res []ledger.AccountResponse, err error) {
+	data, err := s.ledgerRepository.SelectAccounts(ctx)
+	if err != nil {
+		return
+	}
+	res = ledger.ParseFromAccounts(data)
+
+	return
+}
+
+func (s *Service) ListEntries(ctx context.Context, billingID string) (res []ledger.EntryResponse, err error) {
+	data, err := s.ledgerRepository.SelectEntries(ctx, billingID)
+	if err != nil {
+		return
+	}
+	res = ledger.ParseFromEntries(data)
+
+	return
+}
+
+func (s *Service) GetBalance(ctx context.Context, code string, req ledger.BalanceRequest) (res ledger.BalanceResponse, err error) {
+	account, err := s.ledgerRepository.GetAccountByCode(ctx, code)
+	if err != nil {
+		return
+	}
+
+	data, err := s.ledgerRepository.Balance(ctx, account.ID, req.Currency, req.From, req.To)
+	if err != nil {
+		return
+	}
+	res = ledger.ParseFromBalance(account, req.Currency, data)
+
+	return
+}
@@ -0,0 +1,99 @@
+package accounting
+
+import (
+	"context"
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/ledger"
+	"payment-service/pkg/epay"
+
+	"github.com/shopspring/decimal"
+)
+
+// PostCapture posts the captured amount of a paid billing and the fee the gateway kept for it.
+// The fee is the bonus amount ePay reports on the invoice plus the configured fee rate of the amount.
+func (s *Service) PostCapture(ctx context.Context, data billing.Entity, invoice epay.Invoice) (err error) {
+	amount := invoice.Amount
+	if amount.IsZero() {
+		if amount, err = decimal.NewFromString(data.Amount); err != nil {
+			return
+		}
+	}
+
+	currency := data.Currency
+	if currency == "" {
+		currency = invoice.Currency
+	}
+
+	err = s.post(ctx, ledger.Entry{
+		TenantID:    data.TenantID,
+		Kind:        ledger.KindCapture,
+		BillingID:   data.ID,
+		Description: "capture of invoice " + data.InvoiceID,
+		DedupKey:    ledger.KindCapture + ":" + data.ID,
+	}, data.TerminalID, currency, amount, ledger.AccountGateway, ledger.AccountSales)
+	if err != nil {
+		return
+	}
+
+	fee := invoice.AmountBonus.Add(amount.Mul(s.feeRate)).Round(2)
+	if !fee.IsPositive() {
+		return
+	}
+
+	return s.post(ctx, ledger.Entry{
+		TenantID:    data.TenantID,
+		Kind:        ledger.KindFee,
+		BillingID:   data.ID,
+		Description: "gateway fee for invoice " + data.InvoiceID,
+		DedupKey:    ledger.KindFee + ":" + data.ID,
+	}, data.TerminalID, currency, fee, ledger.AccountFees, ledger.AccountGateway)
+}
+
+// PostRefund posts the amount returned to the cardholder for the billing, reference tells refunds of one billing apart.
+func (s *Service) PostRefund(ctx context.Context, data billing.Entity, amount decimal.Decimal, reference string) (err error) {
+	return s.post(ctx, ledger.Entry{
+		TenantID:    data.TenantID,
+		Kind:        ledger.KindRefund,
+		BillingID:   data.ID,
+		Description: "refund of invoice " + data.InvoiceID,
+		DedupKey:    ledger.KindRefund + ":" + reference,
+	}, data.TerminalID, data.Currency, amount, ledger.AccountRefunds, ledger.AccountGateway)
+}
+
+// PostChargeback posts the amount lost to a dispute, reference tells disputes of one billing apart.
+func (s *Service) PostChargeback(ctx context.Context, data billing.Entity, amount decimal.Decimal, reference string) (err error) {
+	return s.post(ctx, ledger.Entry{
+		TenantID:    data.TenantID,
+		Kind:        ledger.KindChargeback,
+		BillingID:   data.ID,
+		Description: "chargeback of invoice " + data.InvoiceID,
+		DedupKey:    ledger.KindChargeback + ":" + reference,
+	}, data.TerminalID, data.Currency, amount, ledger.AccountChargebacks, ledger.AccountGateway)
+}
+
+// post debits one account of the terminal and credits the other one with the amount.
+func (s *Service) post(ctx context.Context, entry ledger.Entry, terminalID, currency string, amount decimal.Decimal, debit, credit string) (err error) {
+	return s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		debitID, err := s.ledgerRepository.EnsureAccount(ctx, ledger.NewAccount(debit, terminalID))
+		if err != nil {
+			return
+		}
+
+		creditID, err := s.ledgerRepository.EnsureAccount(ctx, ledger.NewAccount(credit, terminalID))
+		if err != nil {
+			return
+		}
+
+		entry.Postings = []ledger.Posting{
+			{AccountID: debitID, AccountCode: debit + ":" + terminalID, Amount: amount, Currency: currency},
+			{AccountID: creditID, AccountCode: credit + ":" + terminalID, Amount: amount.Neg(), Currency: currency},
+		}
+		if err = entry.Validate(); err != nil {
+			return
+		}
+
+		_, err = s.ledgerRepository.CreateEntry(ctx, entry)
+
+		return
+	})
+}
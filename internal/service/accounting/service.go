@@ -0,0 +1,66 @@
+package accounting
+
+import (
+	"github.com/shopspring/decimal"
+
+	"payment-service/internal/domain/ledger"
+	"payment-service/pkg/store"
+)
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service is an implementation of the Service
+type Service struct {
+	ledgerRepository ledger.Repository
+
+	transactor store.Transactor
+
+	feeRate decimal.Decimal
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Create the service
+	s = &Service{}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WithLedgerRepository applies a given ledger repository to the Service
+func WithLedgerRepository(ledgerRepository ledger.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.ledgerRepository = ledgerRepository
+		return nil
+	}
+}
+
+// WithTransactor applies a given transactor to the Service, an entry and its postings are written through it
+func WithTransactor(transactor store.Transactor) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.transactor = transactor
+		return nil
+	}
+}
+
+// WithFeeRate applies the share of a captured amount the gateway charges as a fee, e.g. 0.025 for 2.5%
+func WithFeeRate(feeRate float64) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.feeRate = decimal.NewFromFloat(feeRate)
+		return nil
+	}
+}
@@ -0,0 +1,138 @@
+package catalogue
+
+import (
+	"context"
+	"encoding/json"
+
+	"payment-service/internal/domain/category"
+	"payment-service/internal/domain/product"
+	"payment-service/pkg/store"
+)
+
+// ListCategoryAttributes returns the attributes products of the category have, including those of its ancestors.
+func (s *Service) ListCategoryAttributes(ctx context.Context, categoryID string) (res []category.AttributeResponse, err error) {
+	schema, err := s.categorySchema(ctx, categoryID)
+	if err != nil {
+		return
+	}
+	res = category.ParseFromAttributes(schema.Attributes())
+
+	return
+}
+
+// AddCategoryAttribute defines an attribute on the category, products created from then on are checked against it.
+func (s *Service) AddCategoryAttribute(ctx context.Context, categoryID string, req category.AttributeRequest) (res category.AttributeResponse, err error) {
+	owner, err := s.categoryRepository.Get(ctx, categoryID)
+	if err != nil {
+		return
+	}
+
+	data := category.Attribute{
+		TenantID:   owner.TenantID,
+		CategoryID: categoryID,
+		Code:       req.Code,
+		Name:       req.Name,
+		Type:       req.Type,
+		Options:    req.Options,
+		Required:   req.Required,
+	}
+
+	data.ID, err = s.attributeRepository.CreateAttribute(ctx, data)
+	if err != nil {
+		return
+	}
+	res = category.ParseFromAttribute(data)
+
+	return
+}
+
+// DeleteCategoryAttribute looks the category up first, so only attributes of a category the caller sees are removed.
+func (s *Service) DeleteCategoryAttribute(ctx context.Context, categoryID, code string) (err error) {
+	if _, err = s.categoryRepository.Get(ctx, categoryID); err != nil {
+		return
+	}
+
+	return s.attributeRepository.DeleteAttribute(ctx, categoryID, code)
+}
+
+// categorySchema collects the attributes of the category and of its ancestors.
+func (s *Service) categorySchema(ctx context.Context, categoryID string) (schema category.Schema, err error) {
+	ancestors, err := s.categoryRepository.SelectAncestors(ctx, categoryID)
+	if err != nil {
+		return
+	}
+
+	ids := make([]string, 0, len(ancestors))
+	for _, data := range ancestors {
+		ids = append(ids, data.ID)
+	}
+
+	attributes, err := s.attributeRepository.SelectAttributes(ctx, ids)
+	if err != nil {
+		return
+	}
+	schema = category.NewSchema(ancestors, attributes)
+
+	return
+}
+
+// prepareProduct checks the parent of a variant and the attribute values against the category schema,
+// a variant takes the category of its parent and the attribute values of the parent it doesn't set.
+func (s *Service) prepareProduct(ctx context.Context, data *product.Entity, values map[string]any) (err error) {
+	if data.ParentID != "" {
+		parent, err := s.productRepository.Get(ctx, data.ParentID)
+		if err == store.ErrorNotFound {
+			return product.ErrParentNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if parent.ParentID != "" {
+			return product.ErrNestedVariant
+		}
+
+		if data.CategoryID == "" {
+			data.CategoryID = parent.CategoryID
+		}
+
+		if data.CategoryID != parent.CategoryID {
+			return product.ErrVariantCategory
+		}
+
+		if values, err = inheritAttributes(parent.Attributes, values); err != nil {
+			return err
+		}
+	}
+
+	schema, err := s.categorySchema(ctx, data.CategoryID)
+	if err == store.ErrorNotFound {
+		return product.ErrCategoryNotFound
+	}
+	if err != nil {
+		return
+	}
+
+	if values, err = schema.Validate(values); err != nil {
+		return
+	}
+	data.Attributes, err = json.Marshal(values)
+
+	return
+}
+
+// inheritAttributes lays the values of the variant over the values of its parent.
+func inheritAttributes(parent json.RawMessage, values map[string]any) (dest map[string]any, err error) {
+	dest = make(map[string]any)
+	if len(parent) > 0 {
+		if err = json.Unmarshal(parent, &dest); err != nil {
+			return
+		}
+	}
+
+	for code, value := range values {
+		dest[code] = value
+	}
+
+	return
+}
@@ -3,6 +3,9 @@ package catalogue
 import (
 	"context"
 	"payment-service/internal/domain/category"
+	"payment-service/internal/domain/product"
+	"payment-service/pkg/mergepatch"
+	"payment-service/pkg/store"
 )
 
 func (s *Service) ListCategories(ctx context.Context) (res []category.Response, err error) {
@@ -15,6 +18,17 @@ func (s *Service) ListCategories(ctx context.Context) (res []category.Response,
 	return
 }
 
+// ListDeletedCategories returns the categories in the trash, the latest deleted first.
+func (s *Service) ListDeletedCategories(ctx context.Context) (res []category.Response, err error) {
+	data, err := s.categoryRepository.SelectDeleted(ctx)
+	if err != nil {
+		return
+	}
+	res = category.ParseFromEntities(data)
+
+	return
+}
+
 func (s *Service) AddCategory(ctx context.Context, req category.Request) (res category.Response, err error) {
 	data := category.Entity{
 		ParentID: req.ParentID,
@@ -31,7 +45,7 @@ func (s *Service) AddCategory(ctx context.Context, req category.Request) (res ca
 }
 
 func (s *Service) GetCategory(ctx context.Context, id string) (res category.Response, err error) {
-	parent, err := s.categoryRepository.Get(ctx, id)
+	parent, err := s.categoryCache.Get(ctx, id)
 	if err != nil {
 		return
 	}
@@ -46,13 +60,251 @@ func (s *Service) GetCategory(ctx context.Context, id string) (res category.Resp
 	return
 }
 
-func (s *Service) UpdateCategory(ctx context.Context, id string, req category.Request) (err error) {
+// CategoryTree nests every category under its parent, or the subtree of the category when rootID is set.
+func (s *Service) CategoryTree(ctx context.Context, rootID string) (res []category.Response, err error) {
+	data, err := s.categoryRepository.Tree(ctx, rootID)
+	if err != nil {
+		return
+	}
+	res = category.ParseFromTree(data)
+
+	return
+}
+
+// ListAncestors returns the ancestors of the category from the root down, a root has none.
+func (s *Service) ListAncestors(ctx context.Context, id string) (res []category.Response, err error) {
+	data, err := s.categoryRepository.SelectAncestors(ctx, id)
+	if err != nil {
+		return
+	}
+	res = category.ParseFromEntities(data[:len(data)-1])
+
+	return
+}
+
+// GetBreadcrumb returns the path from the root down to the category itself.
+func (s *Service) GetBreadcrumb(ctx context.Context, id string) (res category.BreadcrumbResponse, err error) {
+	data, err := s.categoryRepository.SelectAncestors(ctx, id)
+	if err != nil {
+		return
+	}
+	res = category.ParseFromAncestors(data)
+
+	return
+}
+
+// ListSubtreeProducts searches the products of the category and all its descendants.
+func (s *Service) ListSubtreeProducts(ctx context.Context, id string, req product.SearchRequest) (res product.SearchResponse, err error) {
+	subtree, err := s.categoryRepository.Tree(ctx, id)
+	if err != nil {
+		return
+	}
+
+	req.CategoryIDs = make([]string, 0, len(subtree))
+	for _, data := range subtree {
+		req.CategoryIDs = append(req.CategoryIDs, data.ID)
+	}
+
+	return s.ListProducts(ctx, req)
+}
+
+// MoveCategory puts the category under a new parent together with its subtree.
+// A non-zero version has to match the stored one.
+func (s *Service) MoveCategory(ctx context.Context, id string, version int, req category.MoveRequest) (err error) {
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		return s.categoryRepository.Move(ctx, id, req.ParentID, version)
+	})
+}
+
+// UpdateCategory fails with store.ErrorStaleVersion when a non-zero version is not the stored one.
+func (s *Service) UpdateCategory(ctx context.Context, id string, version int, req category.Request) (err error) {
 	data := category.Entity{
-		Name: &req.Name,
+		Name:    &req.Name,
+		Version: version,
 	}
 	return s.categoryRepository.Update(ctx, id, data)
 }
 
-func (s *Service) DeleteCategory(ctx context.Context, id string) (err error) {
-	return s.categoryRepository.Delete(ctx, id)
+// PatchCategory applies a JSON merge patch to the category, a changed parentID moves it with its subtree.
+// A non-zero version has to match the stored one.
+func (s *Service) PatchCategory(ctx context.Context, id string, version int, patch []byte) (res category.Response, err error) {
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		current, err := s.categoryRepository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if version != 0 && current.Version != version {
+			return store.ErrorStaleVersion
+		}
+
+		req := category.NewRequest(current)
+		if err = mergepatch.Apply(&req, patch); err != nil {
+			return
+		}
+
+		data := category.Entity{
+			ID:       id,
+			ParentID: req.ParentID,
+			Name:     &req.Name,
+			Version:  current.Version,
+		}
+
+		// the move takes a version of its own, the update expects the one it leaves
+		if req.ParentID != current.ParentID {
+			if err = s.categoryRepository.Move(ctx, id, req.ParentID, data.Version); err != nil {
+				return
+			}
+			data.Version++
+		}
+
+		if err = s.categoryRepository.Update(ctx, id, data); err != nil {
+			return
+		}
+		data.Version++
+		res = category.ParseFromEntity(data)
+
+		return
+	})
+
+	return
+}
+
+// DeleteCategory moves the category to the trash. A category with subcategories or products fails with
+// category.ErrNotEmpty unless cascade moves them to the trash along with it. A non-zero version has to match the stored one.
+func (s *Service) DeleteCategory(ctx context.Context, id string, version int, cascade bool) (err error) {
+	return s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		subtree, err := s.categoryRepository.Tree(ctx, id)
+		if err != nil {
+			return
+		}
+
+		ids := make([]string, 0, len(subtree))
+		for _, data := range subtree {
+			if data.ID == id && version != 0 && data.Version != version {
+				return store.ErrorStaleVersion
+			}
+			ids = append(ids, data.ID)
+		}
+
+		filter := product.Filter{CategoryIDs: ids, Sort: product.SortCreatedAt}
+		if !cascade {
+			filter.Limit = 1
+		}
+
+		products, err := s.productRepository.Search(ctx, filter)
+		if err != nil {
+			return
+		}
+
+		if !cascade && (len(subtree) > 1 || products.Total > 0) {
+			return category.ErrNotEmpty
+		}
+
+		// variants go to the trash with their products, they share the category
+		for _, data := range products.Items {
+			if data.ParentID != "" {
+				continue
+			}
+
+			if err = s.trashProduct(ctx, data.ID, 0, id); err != nil {
+				return
+			}
+		}
+
+		for _, data := range subtree {
+			if data.ID == id {
+				continue
+			}
+
+			if err = s.categoryRepository.Delete(ctx, data.ID, 0, id); err != nil {
+				return
+			}
+		}
+
+		return s.categoryRepository.Delete(ctx, id, version, "")
+	})
+}
+
+// RestoreCategory takes the category out of the trash, it fails with category.ErrParentNotFound while its parent is
+// still there. Cascade restores the subcategories and products its deletion took along as well, those deleted before
+// stay in the trash.
+func (s *Service) RestoreCategory(ctx context.Context, id string, cascade bool) (res category.Response, err error) {
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		data, err := s.categoryRepository.GetDeleted(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if data.ParentID != "" {
+			if _, err = s.categoryRepository.Get(ctx, data.ParentID); err == store.ErrorNotFound {
+				return category.ErrParentNotFound
+			}
+			if err != nil {
+				return
+			}
+		}
+
+		if err = s.categoryRepository.Restore(ctx, id); err != nil {
+			return
+		}
+
+		if cascade {
+			if err = s.restoreSubtree(ctx, id); err != nil {
+				return
+			}
+		}
+
+		if data, err = s.categoryRepository.Get(ctx, id); err != nil {
+			return
+		}
+		res = category.ParseFromEntity(data)
+
+		return
+	})
+
+	return
+}
+
+// restoreSubtree restores the descendants of the category trashed with it from the top down, then their products.
+// A subcategory deleted on its own keeps its subtree in the trash.
+func (s *Service) restoreSubtree(ctx context.Context, id string) (err error) {
+	trash, err := s.categoryRepository.SelectDeleted(ctx)
+	if err != nil {
+		return
+	}
+
+	ids := []string{id}
+	for i := 0; i < len(ids); i++ {
+		for _, data := range trash {
+			if data.ParentID != ids[i] || data.TrashedWith != id {
+				continue
+			}
+
+			if err = s.categoryRepository.Restore(ctx, data.ID); err != nil {
+				return
+			}
+			ids = append(ids, data.ID)
+		}
+	}
+
+	products, err := s.productRepository.Search(ctx, product.Filter{Deleted: true, CategoryIDs: ids, Sort: product.SortCreatedAt})
+	if err != nil {
+		return
+	}
+
+	// products come back before their variants
+	for _, variants := range []bool{false, true} {
+		for _, data := range products.Items {
+			if (data.ParentID != "") != variants || data.TrashedWith != id {
+				continue
+			}
+
+			if err = s.productRepository.Restore(ctx, data.ID); err != nil {
+				return
+			}
+		}
+	}
+
+	return
 }
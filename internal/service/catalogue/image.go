@@ -0,0 +1,184 @@
+package catalogue
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/domain/product"
+	"payment-service/pkg/imaging"
+	"payment-service/pkg/store"
+)
+
+// maxImagePixels refuses pictures that would take over 160MB of memory once decoded
+const maxImagePixels = 40_000_000
+
+// extensions names the stored files by their content type
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+func (s *Service) ListProductImages(ctx context.Context, productID string) (res []product.ImageResponse, err error) {
+	if _, err = s.productCache.Get(ctx, productID); err != nil {
+		return
+	}
+
+	data, err := s.imageRepository.SelectImages(ctx, productID)
+	if err != nil {
+		return
+	}
+	res = product.ParseFromImages(data)
+
+	return
+}
+
+// AddProductImages stores the files with their thumbnails after the images the product already has.
+// Every file is checked before the first one is stored, so a bad file doesn't leave half of the upload behind.
+func (s *Service) AddProductImages(ctx context.Context, productID string, uploads []product.Upload) (res []product.ImageResponse, err error) {
+	owner, err := s.productRepository.Get(ctx, productID)
+	if err != nil {
+		return
+	}
+
+	pictures := make([]imaging.Picture, len(uploads))
+	for i, upload := range uploads {
+		if int64(len(upload.Data)) > s.maxImageSize {
+			return nil, product.ErrImageTooLarge
+		}
+
+		pictures[i], err = imaging.Inspect(upload.Data, maxImagePixels)
+		switch err {
+		case nil:
+		case imaging.ErrTooLarge:
+			return nil, product.ErrImageTooLarge
+		default:
+			return nil, product.ErrUnsupportedImage
+		}
+	}
+
+	res = make([]product.ImageResponse, 0, len(uploads))
+	for i, upload := range uploads {
+		var data product.Image
+		if data, err = s.storeImage(ctx, owner, upload, pictures[i]); err != nil {
+			return
+		}
+		res = append(res, product.ParseFromImage(data))
+	}
+
+	return
+}
+
+// ReorderProductImages puts the images of the product in the order of the ids, every image has to be listed once.
+func (s *Service) ReorderProductImages(ctx context.Context, productID string, req product.OrderRequest) (res []product.ImageResponse, err error) {
+	if _, err = s.productRepository.Get(ctx, productID); err != nil {
+		return
+	}
+
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		images, err := s.imageRepository.SelectImages(ctx, productID)
+		if err != nil {
+			return
+		}
+
+		if !sameImages(images, req.IDs) {
+			return product.ErrImageOrder
+		}
+
+		return s.imageRepository.ReorderImages(ctx, productID, req.IDs)
+	})
+	if err != nil {
+		return
+	}
+
+	return s.ListProductImages(ctx, productID)
+}
+
+// DeleteProductImage looks the product up first, so only images of a product the caller sees are removed.
+func (s *Service) DeleteProductImage(ctx context.Context, productID, imageID string) (err error) {
+	if _, err = s.productRepository.Get(ctx, productID); err != nil {
+		return
+	}
+
+	data, err := s.imageRepository.GetImage(ctx, imageID)
+	if err != nil {
+		return
+	}
+
+	if data.ProductID != productID {
+		return store.ErrorNotFound
+	}
+
+	if err = s.imageRepository.DeleteImage(ctx, imageID); err != nil {
+		return
+	}
+	s.deleteFiles(ctx, data.Key, data.ThumbnailKey)
+
+	return
+}
+
+// storeImage puts the file and its thumbnail into the storage and records the image under the tenant of its product,
+// the files are removed again when the image can't be recorded.
+func (s *Service) storeImage(ctx context.Context, owner product.Entity, upload product.Upload, picture imaging.Picture) (data product.Image, err error) {
+	thumbnail, thumbnailType, err := imaging.Thumbnail(upload.Data, s.thumbnailSize)
+	if err != nil {
+		return
+	}
+
+	name := "products/" + owner.ID + "/" + uuid.NewString()
+	data = product.Image{
+		TenantID:     owner.TenantID,
+		ProductID:    owner.ID,
+		ContentType:  picture.ContentType,
+		Size:         int64(len(upload.Data)),
+		Width:        picture.Width,
+		Height:       picture.Height,
+		Key:          name + extensions[picture.ContentType],
+		ThumbnailKey: name + "_thumb" + extensions[thumbnailType],
+	}
+
+	if data.URL, err = s.storage.Put(ctx, data.Key, data.ContentType, upload.Data); err != nil {
+		return
+	}
+
+	if data.ThumbnailURL, err = s.storage.Put(ctx, data.ThumbnailKey, thumbnailType, thumbnail); err != nil {
+		s.deleteFiles(ctx, data.Key)
+		return
+	}
+
+	id, err := s.imageRepository.CreateImage(ctx, data)
+	if err != nil {
+		s.deleteFiles(ctx, data.Key, data.ThumbnailKey)
+		return
+	}
+
+	return s.imageRepository.GetImage(ctx, id)
+}
+
+// deleteFiles removes image files whose image is gone, a file left behind is never served so failures are ignored.
+func (s *Service) deleteFiles(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		s.storage.Delete(ctx, key)
+	}
+}
+
+// sameImages tells whether ids lists every image exactly once.
+func sameImages(images []product.Image, ids []string) bool {
+	if len(images) != len(ids) {
+		return false
+	}
+
+	listed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		listed[id] = true
+	}
+
+	for _, data := range images {
+		if !listed[data.ID] {
+			return false
+		}
+	}
+
+	return len(listed) == len(images)
+}
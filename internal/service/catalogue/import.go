@@ -0,0 +1,222 @@
+package catalogue
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"payment-service/internal/domain/audit"
+	"payment-service/internal/domain/product"
+	"payment-service/pkg/store"
+)
+
+const (
+	// importBatchSize rows are upserted per transaction, a failed batch keeps the batches saved before it
+	importBatchSize = 1000
+
+	// maxJobErrors bounds the row errors kept per job, Failed still counts all of them
+	maxJobErrors = 1000
+
+	// exportPageSize products are read per query while an export streams
+	exportPageSize = 500
+)
+
+// ImportProducts records a pending job for the parsed file, the valid rows are upserted by barcode in the background.
+// Rows that failed to parse are reported by the job along with the rows whose category doesn't exist.
+func (s *Service) ImportProducts(ctx context.Context, format string, rows []product.Row, rowErrs []product.RowError) (res product.JobResponse, err error) {
+	job := product.Job{
+		Format: format,
+		Status: product.JobPending,
+		Total:  len(rows) + len(rowErrs),
+	}
+
+	if job.ID, err = s.importRepository.CreateJob(ctx, job); err != nil {
+		return
+	}
+
+	s.imports.Add(1)
+	go func() {
+		defer s.imports.Done()
+		s.runImport(audit.Detach(ctx), job, rows, rowErrs)
+	}()
+
+	return s.GetImport(ctx, job.ID)
+}
+
+// GetImport returns the job when it was started by the tenant of the caller.
+func (s *Service) GetImport(ctx context.Context, id string) (res product.JobResponse, err error) {
+	data, err := s.importRepository.GetJob(ctx, id)
+	if err != nil {
+		return
+	}
+	res = product.ParseFromJob(data)
+
+	return
+}
+
+// WaitImports blocks until the imports running in the background finish or the context is done.
+func (s *Service) WaitImports(ctx context.Context) (err error) {
+	done := make(chan struct{})
+	go func() {
+		s.imports.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	return
+}
+
+// runImport upserts the rows batch by batch and keeps the job counters up to date, it stops at the first failed batch.
+func (s *Service) runImport(ctx context.Context, job product.Job, rows []product.Row, rowErrs []product.RowError) {
+	job.Status = product.JobRunning
+	if err := s.importRepository.UpdateJob(ctx, job.ID, job); err != nil {
+		return
+	}
+
+	rows, categoryErrs, err := s.checkCategories(ctx, rows)
+	if err != nil {
+		s.failImport(ctx, job, err)
+		return
+	}
+	rowErrs = append(rowErrs, categoryErrs...)
+
+	job.Failed = len(rowErrs)
+	if len(rowErrs) > maxJobErrors {
+		rowErrs = rowErrs[:maxJobErrors]
+	}
+
+	if err = s.importRepository.AddJobErrors(ctx, job.ID, rowErrs); err != nil {
+		s.failImport(ctx, job, err)
+		return
+	}
+
+	for start := 0; start < len(rows); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		batch := make([]product.Entity, 0, end-start)
+		for _, row := range rows[start:end] {
+			batch = append(batch, newProduct(row.Request))
+		}
+
+		var created, updated int
+		err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+			_, created, updated, err = s.productRepository.Upsert(ctx, batch)
+			return
+		})
+		if err != nil {
+			s.failImport(ctx, job, err)
+			return
+		}
+
+		job.Created += created
+		job.Updated += updated
+		if err = s.importRepository.UpdateJob(ctx, job.ID, job); err != nil {
+			return
+		}
+	}
+
+	job.Status = product.JobCompleted
+	s.importRepository.UpdateJob(ctx, job.ID, job)
+}
+
+// checkCategories rejects the rows whose category doesn't exist.
+func (s *Service) checkCategories(ctx context.Context, rows []product.Row) (valid []product.Row, rowErrs []product.RowError, err error) {
+	exists := make(map[string]bool)
+	for _, row := range rows {
+		found, checked := exists[row.CategoryID]
+		if !checked {
+			_, err = s.categoryRepository.Get(ctx, row.CategoryID)
+			if err != nil && err != store.ErrorNotFound {
+				return
+			}
+			found, err = err == nil, nil
+			exists[row.CategoryID] = found
+		}
+
+		if !found {
+			rowErrs = append(rowErrs, product.RowError{Line: row.Line, Message: product.ErrCategoryNotFound.Error()})
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	return
+}
+
+func (s *Service) failImport(ctx context.Context, job product.Job, err error) {
+	job.Status = product.JobFailed
+	job.Message = err.Error()
+	s.importRepository.UpdateJob(ctx, job.ID, job)
+}
+
+// ExportProducts streams every product in the order of creation, page by page so the catalogue is never held in memory.
+func (s *Service) ExportProducts(ctx context.Context, format string, w io.Writer) (err error) {
+	var write func(data product.Response) error
+	var flush func() error
+
+	switch format {
+	case product.FormatCSV:
+		writer := csv.NewWriter(w)
+		if err = writer.Write(product.Header()); err != nil {
+			return
+		}
+		write = func(data product.Response) error { return writer.Write(data.Record()) }
+		flush = func() error { writer.Flush(); return writer.Error() }
+	case product.FormatNDJSON:
+		encoder := json.NewEncoder(w)
+		write = func(data product.Response) error { return encoder.Encode(data) }
+		flush = func() error { return nil }
+	default:
+		return product.ErrUnknownFormat
+	}
+
+	filter := product.Filter{Sort: product.SortCreatedAt, Limit: exportPageSize}
+	for {
+		page, err := s.productRepository.Search(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		for _, data := range page.Items {
+			if err = write(product.ParseFromEntity(data)); err != nil {
+				return err
+			}
+		}
+
+		if err = flush(); err != nil {
+			return err
+		}
+
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		if filter.After = page.Next; page.Next == nil {
+			return nil
+		}
+	}
+}
+
+// newProduct maps a product request onto the entity, the way AddProduct stores it.
+func newProduct(req product.Request) product.Entity {
+	return product.Entity{
+		CategoryID:  req.CategoryID,
+		Name:        req.Name,
+		Description: &req.Description,
+		Measure:     &req.Measure,
+		ImageURL:    &req.ImageURL,
+		Country:     &req.Country,
+		Barcode:     &req.Barcode,
+		Brand:       &req.Brand,
+	}
+}
@@ -0,0 +1,124 @@
+package catalogue
+
+import (
+	"context"
+	"payment-service/internal/domain/price"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func (s *Service) ListPriceLists(ctx context.Context) (res []price.ListResponse, err error) {
+	data, err := s.priceRepository.SelectLists(ctx)
+	if err != nil {
+		return
+	}
+	res = price.ParseFromLists(data)
+
+	return
+}
+
+func (s *Service) AddPriceList(ctx context.Context, req price.ListRequest) (res price.ListResponse, err error) {
+	data := price.List{
+		Code:       req.Code,
+		Name:       req.Name,
+		Kind:       req.Kind,
+		MerchantID: req.MerchantID,
+	}
+
+	data.ID, err = s.priceRepository.CreateList(ctx, data)
+	if err != nil {
+		return
+	}
+	res = price.ParseFromList(data)
+
+	return
+}
+
+// SetProductPrice records a new price of the product in the list, it supersedes the current one from its ValidFrom.
+func (s *Service) SetProductPrice(ctx context.Context, productID string, req price.Request) (res price.Response, err error) {
+	if _, err = s.productRepository.Get(ctx, productID); err != nil {
+		return
+	}
+
+	list, err := s.priceRepository.GetList(ctx, req.List)
+	if err != nil {
+		return
+	}
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return
+	}
+
+	data := price.Entity{
+		ProductID: productID,
+		ListID:    list.ID,
+		Currency:  req.Currency,
+		Amount:    amount,
+		ValidFrom: time.Now().UTC(),
+	}
+	if req.ValidFrom != nil {
+		data.ValidFrom = req.ValidFrom.UTC()
+	}
+	if req.ValidTo != nil {
+		validTo := req.ValidTo.UTC()
+		data.ValidTo = &validTo
+	}
+
+	data.ID, err = s.priceRepository.Create(ctx, data)
+	if err != nil {
+		return
+	}
+	res = price.ParseFromEntity(data)
+
+	return
+}
+
+// ListProductPrices returns the price history of the product, the latest first.
+func (s *Service) ListProductPrices(ctx context.Context, productID string, req price.HistoryRequest) (res []price.Response, err error) {
+	if _, err = s.productCache.Get(ctx, productID); err != nil {
+		return
+	}
+
+	filter := price.Filter{
+		ProductID: productID,
+		Currency:  req.Currency,
+	}
+
+	if req.List != "" {
+		list, err := s.priceRepository.GetList(ctx, req.List)
+		if err != nil {
+			return nil, err
+		}
+		filter.ListID = list.ID
+	}
+
+	data, err := s.priceRepository.Select(ctx, filter)
+	if err != nil {
+		return
+	}
+	res = price.ParseFromEntities(data)
+
+	return
+}
+
+// GetEffectivePrice returns the price of the product in the list and currency effective at the requested time.
+func (s *Service) GetEffectivePrice(ctx context.Context, productID string, req price.EffectiveRequest) (res price.Response, err error) {
+	if _, err = s.productCache.Get(ctx, productID); err != nil {
+		return
+	}
+
+	list, err := s.priceRepository.GetList(ctx, req.List)
+	if err != nil {
+		return
+	}
+
+	data, err := s.priceRepository.GetEffective(ctx, productID, list.ID, req.Currency, req.At.UTC())
+	if err != nil {
+		return
+	}
+	res = price.ParseFromEntity(data)
+
+	return
+}
@@ -3,21 +3,26 @@ package catalogue
 import (
 	"context"
 	"payment-service/internal/domain/product"
+	"payment-service/pkg/mergepatch"
+	"payment-service/pkg/store"
 )
 
-func (s *Service) ListProducts(ctx context.Context) (res []product.Response, err error) {
-	data, err := s.productRepository.Select(ctx)
+// ListProducts returns a page of the products matching the search request with the number of all of them.
+func (s *Service) ListProducts(ctx context.Context, req product.SearchRequest) (res product.SearchResponse, err error) {
+	data, err := s.productRepository.Search(ctx, req.Filter)
 	if err != nil {
 		return
 	}
-	res = product.ParseFromEntities(data)
+	res = product.ParseFromPage(data)
 
 	return
 }
 
+// AddProduct creates the product, or a variant when the request names a parent.
 func (s *Service) AddProduct(ctx context.Context, req product.Request) (res product.Response, err error) {
 	data := product.Entity{
 		ID:          req.ID,
+		ParentID:    req.ParentID,
 		CategoryID:  req.CategoryID,
 		Name:        req.Name,
 		Description: &req.Description,
@@ -28,6 +33,10 @@ func (s *Service) AddProduct(ctx context.Context, req product.Request) (res prod
 		Brand:       &req.Brand,
 	}
 
+	if err = s.prepareProduct(ctx, &data, req.Attributes); err != nil {
+		return
+	}
+
 	data.ID, err = s.productRepository.Create(ctx, data)
 	if err != nil {
 		return
@@ -38,7 +47,23 @@ func (s *Service) AddProduct(ctx context.Context, req product.Request) (res prod
 }
 
 func (s *Service) GetProduct(ctx context.Context, id string) (res product.Response, err error) {
-	data, err := s.productRepository.Get(ctx, id)
+	data, err := s.productCache.Get(ctx, id)
+	if err != nil {
+		return
+	}
+	res = product.ParseFromEntity(data)
+
+	return
+}
+
+// GetProductByBarcode finds the product by a scanned code, a UPC-A code finds the product stored with its EAN-13 form.
+func (s *Service) GetProductByBarcode(ctx context.Context, code string) (res product.Response, err error) {
+	barcode, err := product.NormalizeBarcode(code)
+	if err != nil {
+		return
+	}
+
+	data, err := s.productRepository.GetByBarcode(ctx, barcode)
 	if err != nil {
 		return
 	}
@@ -47,18 +72,205 @@ func (s *Service) GetProduct(ctx context.Context, id string) (res product.Respon
 	return
 }
 
-func (s *Service) UpdateProduct(ctx context.Context, id string, req product.Request) (err error) {
+// ListVariants returns the variants of the product in the order they were added.
+func (s *Service) ListVariants(ctx context.Context, id string) (res []product.Response, err error) {
+	if _, err = s.productCache.Get(ctx, id); err != nil {
+		return
+	}
+
+	data, err := s.productRepository.Search(ctx, product.Filter{ParentID: id, Sort: product.SortCreatedAt})
+	if err != nil {
+		return
+	}
+	res = product.ParseFromEntities(data.Items)
+
+	return
+}
+
+// AddVariant creates a variant of the product, it has a barcode and prices of its own.
+func (s *Service) AddVariant(ctx context.Context, id string, req product.Request) (res product.Response, err error) {
+	req.ParentID = id
+
+	return s.AddProduct(ctx, req)
+}
+
+// UpdateProduct checks the attribute values again when the request sets them.
+// A non-zero version has to match the stored one.
+func (s *Service) UpdateProduct(ctx context.Context, id string, version int, req product.Request) (err error) {
 	data := product.Entity{
+		Name:        req.Name,
 		Description: &req.Description,
 		Measure:     &req.Measure,
 		ImageURL:    &req.ImageURL,
 		Country:     &req.Country,
 		Barcode:     &req.Barcode,
 		Brand:       &req.Brand,
+		Version:     version,
+	}
+
+	if req.Attributes != nil {
+		current, err := s.productRepository.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		data.ParentID, data.CategoryID = current.ParentID, current.CategoryID
+		if err = s.prepareProduct(ctx, &data, req.Attributes); err != nil {
+			return err
+		}
 	}
+
 	return s.productRepository.Update(ctx, id, data)
 }
 
-func (s *Service) DeleteProduct(ctx context.Context, id string) (err error) {
-	return s.productRepository.Delete(ctx, id)
+// PatchProduct applies a JSON merge patch to the product, the fields the patch doesn't name keep their values.
+// The attribute values are checked again and a product with variants keeps its category.
+// A non-zero version has to match the stored one.
+func (s *Service) PatchProduct(ctx context.Context, id string, version int, patch []byte) (res product.Response, err error) {
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		current, err := s.productRepository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if version != 0 && current.Version != version {
+			return store.ErrorStaleVersion
+		}
+
+		req := product.NewRequest(current)
+		if err = mergepatch.Apply(&req, patch); err != nil {
+			return
+		}
+
+		if req.ID != current.ID || req.ParentID != current.ParentID {
+			return product.ErrReadOnly
+		}
+
+		if req.CategoryID != current.CategoryID && current.ParentID == "" {
+			variants, err := s.productRepository.Search(ctx, product.Filter{ParentID: id, Sort: product.SortCreatedAt, Limit: 1})
+			if err != nil {
+				return err
+			}
+
+			if variants.Total > 0 {
+				return product.ErrHasVariants
+			}
+		}
+
+		data := newProduct(req)
+		data.ParentID = current.ParentID
+		data.Version = current.Version
+		if err = s.prepareProduct(ctx, &data, req.Attributes); err != nil {
+			return
+		}
+
+		if err = s.productRepository.Update(ctx, id, data); err != nil {
+			return
+		}
+		data.ID = id
+		data.Version++
+		res = product.ParseFromEntity(data)
+
+		return
+	})
+
+	return
+}
+
+// DeleteProduct moves the product with its variants to the trash, their images are kept until the trash is purged.
+// A non-zero version has to match the stored one, it is checked before the variants go.
+func (s *Service) DeleteProduct(ctx context.Context, id string, version int) (err error) {
+	return s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		if version != 0 {
+			current, err := s.productRepository.Get(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			if current.Version != version {
+				return store.ErrorStaleVersion
+			}
+		}
+
+		return s.trashProduct(ctx, id, version, "")
+	})
+}
+
+// trashProduct moves the variants of the product to the trash and then the product itself, trashedWith is the category
+// whose deletion takes them along. Without one the variants are marked as trashed with the product.
+func (s *Service) trashProduct(ctx context.Context, id string, version int, trashedWith string) (err error) {
+	variants, err := s.productRepository.Search(ctx, product.Filter{ParentID: id, Sort: product.SortCreatedAt})
+	if err != nil {
+		return
+	}
+
+	cascade := trashedWith
+	if cascade == "" {
+		cascade = id
+	}
+
+	for _, data := range variants.Items {
+		if err = s.productRepository.Delete(ctx, data.ID, 0, cascade); err != nil {
+			return
+		}
+	}
+
+	return s.productRepository.Delete(ctx, id, version, trashedWith)
+}
+
+// RestoreProduct takes the product out of the trash together with the variants its deletion took along, variants
+// deleted on their own stay there. It fails with
+// product.ErrCategoryNotFound or product.ErrParentNotFound while the category or the parent product is in the trash.
+func (s *Service) RestoreProduct(ctx context.Context, id string) (res product.Response, err error) {
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		data, err := s.productRepository.GetDeleted(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if _, err = s.categoryRepository.Get(ctx, data.CategoryID); err == store.ErrorNotFound {
+			return product.ErrCategoryNotFound
+		}
+		if err != nil {
+			return
+		}
+
+		if data.ParentID != "" {
+			if _, err = s.productRepository.Get(ctx, data.ParentID); err == store.ErrorNotFound {
+				return product.ErrParentNotFound
+			}
+			if err != nil {
+				return
+			}
+		}
+
+		if err = s.productRepository.Restore(ctx, id); err != nil {
+			return
+		}
+
+		variants, err := s.productRepository.Search(ctx, product.Filter{Deleted: true, ParentID: id, Sort: product.SortCreatedAt})
+		if err != nil {
+			return
+		}
+
+		// variants taken along by the deletion of the category share the mark of the product
+		for _, variant := range variants.Items {
+			if variant.TrashedWith != id && (data.TrashedWith == "" || variant.TrashedWith != data.TrashedWith) {
+				continue
+			}
+
+			if err = s.productRepository.Restore(ctx, variant.ID); err != nil {
+				return
+			}
+		}
+
+		if data, err = s.productRepository.Get(ctx, id); err != nil {
+			return
+		}
+		res = product.ParseFromEntity(data)
+
+		return
+	})
+
+	return
 }
@@ -1,8 +1,20 @@
 package catalogue
 
 import (
+	"sync"
+
 	"payment-service/internal/domain/category"
+	"payment-service/internal/domain/price"
 	"payment-service/internal/domain/product"
+	"payment-service/pkg/store"
+)
+
+const (
+	// defaultMaxImageSize keeps uploads to what a product photo from a phone takes
+	defaultMaxImageSize = 5 << 20
+
+	// defaultThumbnailSize fits thumbnails in a 256 by 256 box
+	defaultThumbnailSize = 256
 )
 
 // Configuration is an alias for a function that will take in a pointer to a Service and modify it
@@ -10,18 +22,34 @@ type Configuration func(s *Service) error
 
 // Service is an implementation of the Service
 type Service struct {
-	categoryRepository category.Repository
-	productRepository  product.Repository
+	categoryRepository  category.Repository
+	attributeRepository category.AttributeRepository
+	productRepository   product.Repository
+	priceRepository     price.Repository
+	importRepository    product.JobRepository
+	imageRepository     product.ImageRepository
 
 	categoryCache category.Cache
 	productCache  product.Cache
+
+	storage       product.Storage
+	maxImageSize  int64
+	thumbnailSize int
+
+	transactor store.Transactor
+
+	// imports tracks the imports running in the background
+	imports sync.WaitGroup
 }
 
 // New takes a variable amount of Configuration functions and returns a new Service
 // Each Configuration will be called in the order they are passed in
 func New(configs ...Configuration) (s *Service, err error) {
 	// Create the service
-	s = &Service{}
+	s = &Service{
+		maxImageSize:  defaultMaxImageSize,
+		thumbnailSize: defaultThumbnailSize,
+	}
 
 	// Apply all Configurations passed in
 	for _, cfg := range configs {
@@ -43,6 +71,16 @@ func WithCategoryRepository(categoryRepository category.Repository) Configuratio
 	}
 }
 
+// WithAttributeRepository applies a given category attribute repository to the Service
+func WithAttributeRepository(attributeRepository category.AttributeRepository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.attributeRepository = attributeRepository
+		return nil
+	}
+}
+
 // WithProductRepository applies a given product repository to the Service
 func WithProductRepository(productRepository product.Repository) Configuration {
 	// Create the product repository, if we needed parameters, such as connection strings they could be inputted here
@@ -52,7 +90,71 @@ func WithProductRepository(productRepository product.Repository) Configuration {
 	}
 }
 
-// WithCategoryCache applies a given category cache to the Service
+// WithPriceRepository applies a given price repository to the Service
+func WithPriceRepository(priceRepository price.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.priceRepository = priceRepository
+		return nil
+	}
+}
+
+// WithImportRepository applies a given import job repository to the Service
+func WithImportRepository(importRepository product.JobRepository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.importRepository = importRepository
+		return nil
+	}
+}
+
+// WithImageRepository applies a given product image repository to the Service
+func WithImageRepository(imageRepository product.ImageRepository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.imageRepository = imageRepository
+		return nil
+	}
+}
+
+// WithStorage applies the blob storage product images are kept in
+func WithStorage(storage product.Storage) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.storage = storage
+		return nil
+	}
+}
+
+// WithMaxImageSize applies the largest image file accepted, in bytes
+func WithMaxImageSize(size int64) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		if size > 0 {
+			s.maxImageSize = size
+		}
+		return nil
+	}
+}
+
+// WithThumbnailSize applies the side of the box thumbnails are scaled down to fit, in pixels
+func WithThumbnailSize(size int) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		if size > 0 {
+			s.thumbnailSize = size
+		}
+		return nil
+	}
+}
+
+// WithCategoryCache applies a given category cache to the Service, categories are read through it
 func WithCategoryCache(categoryCache category.Cache) Configuration {
 	// return a function that matches the Configuration alias,
 	// You need to return this so that the parent function can take in all the needed parameters
@@ -62,7 +164,7 @@ func WithCategoryCache(categoryCache category.Cache) Configuration {
 	}
 }
 
-// WithProductCache applies a given product cache to the Service
+// WithProductCache applies a given product cache to the Service, lookups that write nothing read products through it
 func WithProductCache(productCache product.Cache) Configuration {
 	// return a function that matches the Configuration alias,
 	// You need to return this so that the parent function can take in all the needed parameters
@@ -71,3 +173,13 @@ func WithProductCache(productCache product.Cache) Configuration {
 		return nil
 	}
 }
+
+// WithTransactor applies a given transactor to the Service, category moves are checked and written through it
+func WithTransactor(transactor store.Transactor) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.transactor = transactor
+		return nil
+	}
+}
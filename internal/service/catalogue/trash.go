@@ -0,0 +1,66 @@
+package catalogue
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"payment-service/internal/domain/product"
+	"payment-service/pkg/store"
+)
+
+// PurgeTrash removes the products and categories deleted before the time for good, along with the image files
+// of the products. Products orders still refer to stay in the trash and so do their categories.
+func (s *Service) PurgeTrash(ctx context.Context, before time.Time) (products, categories int, err error) {
+	trash, err := s.productRepository.Search(ctx, product.Filter{Deleted: true, DeletedBefore: before, Sort: product.SortCreatedAt})
+	if err != nil {
+		return
+	}
+
+	// variants go before the products they belong to
+	sort.SliceStable(trash.Items, func(i, j int) bool {
+		return trash.Items[i].ParentID != "" && trash.Items[j].ParentID == ""
+	})
+
+	for _, data := range trash.Items {
+		purged, err := s.purgeProduct(ctx, data.ID)
+		if err != nil {
+			return products, categories, err
+		}
+
+		if purged {
+			products++
+		}
+	}
+
+	categories, err = s.categoryRepository.Purge(ctx, before)
+
+	return
+}
+
+// purgeProduct removes the product with its images and their files, purged is false when it has to stay.
+func (s *Service) purgeProduct(ctx context.Context, id string) (purged bool, err error) {
+	images, err := s.imageRepository.SelectImages(ctx, id)
+	if err != nil {
+		return
+	}
+
+	// the product may have been restored since the trash was read
+	switch err = s.productRepository.Purge(ctx, id); err {
+	case nil:
+	case product.ErrReferenced, store.ErrorNotFound:
+		return false, nil
+	default:
+		return
+	}
+
+	for _, data := range images {
+		// postgres has removed the rows along with the product already
+		if err = s.imageRepository.DeleteImage(ctx, data.ID); err != nil && err != store.ErrorNotFound {
+			return
+		}
+		s.deleteFiles(ctx, data.Key, data.ThumbnailKey)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,344 @@
+package catalogue
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"payment-service/internal/domain/category"
+	"payment-service/internal/domain/product"
+	"payment-service/internal/repository/memory"
+	"payment-service/pkg/store"
+)
+
+// deletedFiles is a storage that records the keys deleted from it.
+type deletedFiles struct {
+	keys []string
+}
+
+func (s *deletedFiles) Put(ctx context.Context, key, contentType string, data []byte) (url string, err error) {
+	return "https://example.com/" + key, nil
+}
+
+func (s *deletedFiles) Delete(ctx context.Context, key string) (err error) {
+	s.keys = append(s.keys, key)
+	return
+}
+
+type trashFixture struct {
+	s          *Service
+	categories *memory.CategoryRepository
+	products   *memory.ProductRepository
+	images     *memory.ImageRepository
+	storage    *deletedFiles
+}
+
+func newTrashFixture(t *testing.T) trashFixture {
+	t.Helper()
+	f := trashFixture{
+		categories: memory.NewCategoryRepository(),
+		products:   memory.NewProductRepository(),
+		images:     memory.NewImageRepository(),
+		storage:    &deletedFiles{},
+	}
+
+	s, err := New(
+		WithCategoryRepository(f.categories),
+		WithProductRepository(f.products),
+		WithImageRepository(f.images),
+		WithStorage(f.storage),
+		WithTransactor(memory.NewTransactor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.s = s
+
+	return f
+}
+
+func (f trashFixture) category(t *testing.T, ctx context.Context, parentID, name string) string {
+	t.Helper()
+	id, err := f.categories.Create(ctx, category.Entity{ParentID: parentID, Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func (f trashFixture) product(t *testing.T, ctx context.Context, categoryID, parentID, name string) string {
+	t.Helper()
+	empty := ""
+	id, err := f.products.Create(ctx, product.Entity{CategoryID: categoryID, ParentID: parentID, Name: name,
+		Description: &empty, Measure: &empty, ImageURL: &empty, Country: &empty, Barcode: &empty, Brand: &empty})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// checkTrash compares the ids of the categories and products in the trash.
+func (f trashFixture) checkTrash(t *testing.T, ctx context.Context, categories, products []string) {
+	t.Helper()
+	deleted, err := f.categories.SelectDeleted(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotCategories []string
+	for _, data := range deleted {
+		gotCategories = append(gotCategories, data.ID)
+	}
+
+	page, err := f.products.Search(ctx, product.Filter{Deleted: true, Sort: product.SortCreatedAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotProducts []string
+	for _, data := range page.Items {
+		gotProducts = append(gotProducts, data.ID)
+	}
+
+	if !sameIDs(gotCategories, categories) || !sameIDs(gotProducts, products) {
+		t.Errorf("got categories %v and products %v in the trash, want %v and %v",
+			gotCategories, gotProducts, categories, products)
+	}
+}
+
+func sameIDs(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	got, want = append([]string(nil), got...), append([]string(nil), want...)
+	sort.Strings(got)
+	sort.Strings(want)
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCategoryTrash(t *testing.T) {
+	ctx := store.WithOperator(context.Background())
+
+	t.Run("not empty", func(t *testing.T) {
+		f := newTrashFixture(t)
+		root := f.category(t, ctx, "", "Drinks")
+		f.category(t, ctx, root, "Juices")
+
+		if err := f.s.DeleteCategory(ctx, root, 0, false); err != category.ErrNotEmpty {
+			t.Errorf("err = %v, want %v", err, category.ErrNotEmpty)
+		}
+		f.checkTrash(t, ctx, nil, nil)
+	})
+
+	t.Run("cascade", func(t *testing.T) {
+		f := newTrashFixture(t)
+		root := f.category(t, ctx, "", "Drinks")
+		juices := f.category(t, ctx, root, "Juices")
+		nectars := f.category(t, ctx, juices, "Nectars")
+		water := f.category(t, ctx, root, "Water")
+		apple := f.product(t, ctx, juices, "", "Apple juice")
+		small := f.product(t, ctx, juices, apple, "Apple juice 0.2 l")
+		peach := f.product(t, ctx, nectars, "", "Peach nectar")
+
+		// deleted before the cascade, they stay in the trash when it is restored
+		if err := f.s.DeleteCategory(ctx, water, 0, false); err != nil {
+			t.Fatal(err)
+		}
+		large := f.product(t, ctx, juices, apple, "Apple juice 1 l")
+		if err := f.s.DeleteProduct(ctx, large, 0); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.s.DeleteCategory(ctx, root, 0, true); err != nil {
+			t.Fatal(err)
+		}
+		f.checkTrash(t, ctx, []string{root, juices, nectars, water}, []string{apple, small, large, peach})
+
+		if _, err := f.s.RestoreCategory(ctx, nectars, false); err != category.ErrParentNotFound {
+			t.Errorf("err = %v, want %v", err, category.ErrParentNotFound)
+		}
+
+		res, err := f.s.RestoreCategory(ctx, root, true)
+		if err != nil || res.ID != root {
+			t.Fatalf("got %+v, err = %v, want the root restored", res, err)
+		}
+		f.checkTrash(t, ctx, []string{water}, []string{large})
+
+		for _, id := range []string{juices, nectars} {
+			if data, err := f.categories.Get(ctx, id); err != nil || data.TrashedWith != "" {
+				t.Errorf("got %+v, err = %v, want the category out of the trash", data, err)
+			}
+		}
+	})
+
+	t.Run("restore alone", func(t *testing.T) {
+		f := newTrashFixture(t)
+		root := f.category(t, ctx, "", "Drinks")
+		juices := f.category(t, ctx, root, "Juices")
+		apple := f.product(t, ctx, juices, "", "Apple juice")
+
+		if err := f.s.DeleteCategory(ctx, root, 0, true); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := f.s.RestoreCategory(ctx, root, false); err != nil {
+			t.Fatal(err)
+		}
+		f.checkTrash(t, ctx, []string{juices}, []string{apple})
+
+		// the mark is kept, a later cascade still finds what the first deletion took along
+		if err := f.s.DeleteCategory(ctx, root, 0, true); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.s.RestoreCategory(ctx, root, true); err != nil {
+			t.Fatal(err)
+		}
+		f.checkTrash(t, ctx, nil, nil)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		f := newTrashFixture(t)
+		root := f.category(t, ctx, "", "Drinks")
+
+		if err := f.s.DeleteCategory(ctx, root, 2, true); err != store.ErrorStaleVersion {
+			t.Errorf("err = %v, want %v", err, store.ErrorStaleVersion)
+		}
+		f.checkTrash(t, ctx, nil, nil)
+	})
+}
+
+func TestProductTrash(t *testing.T) {
+	ctx := store.WithOperator(context.Background())
+
+	t.Run("variants", func(t *testing.T) {
+		f := newTrashFixture(t)
+		juices := f.category(t, ctx, "", "Juices")
+		apple := f.product(t, ctx, juices, "", "Apple juice")
+		small := f.product(t, ctx, juices, apple, "Apple juice 0.2 l")
+		large := f.product(t, ctx, juices, apple, "Apple juice 1 l")
+
+		if err := f.s.DeleteProduct(ctx, large, 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.s.DeleteProduct(ctx, apple, 0); err != nil {
+			t.Fatal(err)
+		}
+		f.checkTrash(t, ctx, nil, []string{apple, small, large})
+
+		if _, err := f.s.RestoreProduct(ctx, small); err != product.ErrParentNotFound {
+			t.Errorf("err = %v, want %v", err, product.ErrParentNotFound)
+		}
+
+		if res, err := f.s.RestoreProduct(ctx, apple); err != nil || res.ID != apple {
+			t.Fatalf("got %+v, err = %v, want the product restored", res, err)
+		}
+		f.checkTrash(t, ctx, nil, []string{large})
+	})
+
+	t.Run("taken along by a category", func(t *testing.T) {
+		f := newTrashFixture(t)
+		root := f.category(t, ctx, "", "Drinks")
+		juices := f.category(t, ctx, root, "Juices")
+		apple := f.product(t, ctx, juices, "", "Apple juice")
+		small := f.product(t, ctx, juices, apple, "Apple juice 0.2 l")
+
+		if err := f.s.DeleteCategory(ctx, root, 0, true); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := f.s.RestoreProduct(ctx, apple); err != product.ErrCategoryNotFound {
+			t.Errorf("err = %v, want %v", err, product.ErrCategoryNotFound)
+		}
+
+		if _, err := f.s.RestoreCategory(ctx, root, false); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.s.RestoreCategory(ctx, juices, false); err != nil {
+			t.Fatal(err)
+		}
+
+		// the variants share the mark of the product
+		if _, err := f.s.RestoreProduct(ctx, apple); err != nil {
+			t.Fatal(err)
+		}
+		f.checkTrash(t, ctx, nil, nil)
+
+		if data, err := f.products.Get(ctx, small); err != nil || data.TrashedWith != "" {
+			t.Errorf("got %+v, err = %v, want the variant out of the trash", data, err)
+		}
+	})
+}
+
+func TestPurgeTrash(t *testing.T) {
+	ctx := store.WithOperator(context.Background())
+
+	t.Run("retention", func(t *testing.T) {
+		f := newTrashFixture(t)
+		old := f.category(t, ctx, "", "Drinks")
+		juices := f.category(t, ctx, old, "Juices")
+		apple := f.product(t, ctx, juices, "", "Apple juice")
+		f.product(t, ctx, juices, apple, "Apple juice 0.2 l")
+		recent := f.category(t, ctx, "", "Water")
+		still := f.product(t, ctx, recent, "", "Still water")
+
+		if _, err := f.images.CreateImage(ctx, product.Image{ProductID: apple, Key: "apple.png", ThumbnailKey: "apple_thumb.png"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.s.DeleteCategory(ctx, old, 0, true); err != nil {
+			t.Fatal(err)
+		}
+		cutoff := time.Now()
+		if err := f.s.DeleteCategory(ctx, recent, 0, true); err != nil {
+			t.Fatal(err)
+		}
+
+		// nothing was deleted an hour ago
+		products, categories, err := f.s.PurgeTrash(ctx, cutoff.Add(-time.Hour))
+		if err != nil || products != 0 || categories != 0 {
+			t.Errorf("got %d products and %d categories, err = %v, want none", products, categories, err)
+		}
+
+		// the products go first, their categories are leaves by the time they are purged
+		products, categories, err = f.s.PurgeTrash(ctx, cutoff)
+		if err != nil || products != 2 || categories != 2 {
+			t.Errorf("got %d products and %d categories, err = %v, want 2 and 2", products, categories, err)
+		}
+		f.checkTrash(t, ctx, []string{recent}, []string{still})
+
+		if images, err := f.images.SelectImages(ctx, apple); err != nil || len(images) != 0 {
+			t.Errorf("got %v, err = %v, want the images removed", images, err)
+		}
+		if !sameIDs(f.storage.keys, []string{"apple.png", "apple_thumb.png"}) {
+			t.Errorf("got %v deleted, want the files of the image", f.storage.keys)
+		}
+	})
+
+	t.Run("restored", func(t *testing.T) {
+		f := newTrashFixture(t)
+		juices := f.category(t, ctx, "", "Juices")
+		apple := f.product(t, ctx, juices, "", "Apple juice")
+
+		if err := f.s.DeleteProduct(ctx, apple, 0); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.s.RestoreProduct(ctx, apple); err != nil {
+			t.Fatal(err)
+		}
+
+		products, categories, err := f.s.PurgeTrash(ctx, time.Now().Add(time.Hour))
+		if err != nil || products != 0 || categories != 0 {
+			t.Errorf("got %d products and %d categories, err = %v, want none", products, categories, err)
+		}
+		if _, err = f.products.Get(ctx, apple); err != nil {
+			t.Errorf("err = %v, want the product kept", err)
+		}
+	})
+}
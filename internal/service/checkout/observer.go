@@ -0,0 +1,39 @@
+package checkout
+
+import (
+	"context"
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/order"
+
+	"payment-service/pkg/store"
+)
+
+// BillingObserver keeps the status of orders in step with their billings.
+type BillingObserver struct {
+	orderRepository order.Repository
+}
+
+func NewBillingObserver(orderRepository order.Repository) *BillingObserver {
+	return &BillingObserver{
+		orderRepository: orderRepository,
+	}
+}
+
+// BillingChanged moves the order of the billing to the matching status, billings without an order are skipped.
+func (o *BillingObserver) BillingChanged(ctx context.Context, data billing.Entity) (err error) {
+	status, ok := order.StatusOf(data.Status)
+	if !ok {
+		return
+	}
+
+	current, err := o.orderRepository.GetByBillingID(ctx, data.ID)
+	if err == store.ErrorNotFound {
+		return nil
+	}
+
+	if err != nil || current.Status == status {
+		return
+	}
+
+	return o.orderRepository.Update(ctx, current.ID, order.Entity{Status: status})
+}
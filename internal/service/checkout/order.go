@@ -0,0 +1,218 @@
+package checkout
+
+import (
+	"context"
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/inventory"
+	"payment-service/internal/domain/order"
+	"payment-service/internal/domain/price"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"payment-service/pkg/store"
+)
+
+const (
+	// orderSource is the billing source of orders that don't name their own
+	orderSource = "order"
+
+	// maxDescriptionLength keeps the billing description within what the gateway shows on the pay page
+	maxDescriptionLength = 250
+)
+
+func (s *Service) ListOrders(ctx context.Context) (res []order.Response, err error) {
+	data, err := s.orderRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	res = order.ParseFromEntities(data)
+
+	return
+}
+
+func (s *Service) GetOrder(ctx context.Context, id string) (res order.Response, err error) {
+	data, err := s.orderRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+	res = order.ParseFromEntity(data)
+
+	return
+}
+
+// PlaceOrder prices the items from the price list, stores the order and creates the billing that charges its total.
+// With a stock service the items are reserved first, the order fails with inventory.ErrInsufficientStock when they can't be.
+func (s *Service) PlaceOrder(ctx context.Context, req order.Request) (res order.Response, err error) {
+	data := order.Entity{
+		Status:   order.StatusCreated,
+		Currency: req.Currency,
+	}
+
+	now := time.Now()
+	for _, itemReq := range req.Items {
+		item, listID, err := s.priceItem(ctx, req, itemReq, now)
+		if err != nil {
+			return res, err
+		}
+
+		data.PriceListID = listID
+		data.Items = append(data.Items, item)
+	}
+
+	discount := decimal.Zero
+	if req.Discount != "" {
+		if discount, err = decimal.NewFromString(req.Discount); err != nil {
+			return
+		}
+	}
+
+	if err = data.Calculate(discount, s.tax); err != nil {
+		return
+	}
+
+	var link string
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		var reservationID string
+		if s.stockService != nil {
+			if reservationID, err = s.stockService.Reserve(ctx, reservedItems(data)); err != nil {
+				return
+			}
+		}
+
+		if data.ID, err = s.orderRepository.Create(ctx, data); err != nil {
+			return
+		}
+
+		billingRes, err := s.paymentService.AddBilling(ctx, newBillingRequest(data, req))
+		if err != nil {
+			return
+		}
+		data.BillingID, link = billingRes.ID, billingRes.Link
+
+		if err = s.orderRepository.Update(ctx, data.ID, order.Entity{BillingID: data.BillingID}); err != nil {
+			return
+		}
+
+		if reservationID != "" {
+			err = s.stockService.Attach(ctx, reservationID, data.ID, data.BillingID)
+		}
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	if data, err = s.orderRepository.Get(ctx, data.ID); err != nil {
+		return
+	}
+	res = order.ParseFromEntity(data)
+	res.PaymentLink = link
+
+	return
+}
+
+// priceItem looks the product up and prices the item with the product's price effective now.
+func (s *Service) priceItem(ctx context.Context, req order.Request, itemReq order.ItemRequest, now time.Time) (item order.Item, listID string, err error) {
+	product, err := s.catalogueService.GetProduct(ctx, itemReq.ProductID)
+	if err == store.ErrorNotFound {
+		err = order.ErrProductNotFound
+	}
+	if err != nil {
+		return
+	}
+
+	effective, err := s.catalogueService.GetEffectivePrice(ctx, itemReq.ProductID, price.EffectiveRequest{
+		List:     req.PriceList,
+		Currency: req.Currency,
+		At:       now,
+	})
+	switch err {
+	case nil:
+	case store.ErrorNotFound:
+		err = order.ErrPriceListNotFound
+		return
+	case price.ErrPriceNotFound:
+		err = order.ErrProductNotPriced
+		return
+	default:
+		return
+	}
+
+	item = order.Item{
+		ProductID: product.ID,
+		Name:      product.Name,
+		Discount:  decimal.Zero,
+	}
+
+	if item.Quantity, err = decimal.NewFromString(itemReq.Quantity); err != nil {
+		return
+	}
+
+	if item.UnitPrice, err = decimal.NewFromString(effective.Amount); err != nil {
+		return
+	}
+
+	if itemReq.Discount != "" {
+		if item.Discount, err = decimal.NewFromString(itemReq.Discount); err != nil {
+			return
+		}
+	}
+	listID = effective.ListID
+
+	return
+}
+
+// reservedItems lists the product quantities of the order to reserve.
+func reservedItems(data order.Entity) []inventory.Item {
+	items := make([]inventory.Item, 0, len(data.Items))
+	for _, item := range data.Items {
+		items = append(items, inventory.Item{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	return items
+}
+
+// newBillingRequest charges the order total, the order id correlates the billing with the order.
+func newBillingRequest(data order.Entity, req order.Request) billing.Request {
+	source := req.Source
+	if source == "" {
+		source = orderSource
+	}
+
+	return billing.Request{
+		CorrelationID:   data.ID,
+		Source:          source,
+		Amount:          data.Total.StringFixed(2),
+		Currency:        data.Currency,
+		Name:            req.Name,
+		TerminalID:      req.TerminalID,
+		InvoiceID:       req.InvoiceID,
+		Description:     describe(data),
+		AccountID:       req.AccountID,
+		Email:           req.Email,
+		Phone:           req.Phone,
+		Backlink:        req.Backlink,
+		FailureBacklink: req.FailureBacklink,
+		PostLink:        req.PostLink,
+		FailurePostLink: req.FailurePostLink,
+		Language:        req.Language,
+		PaymentType:     req.PaymentType,
+	}
+}
+
+// describe lists the items of the order, e.g. "Order 1f0c…: Apple juice x2, Bread x1".
+func describe(data order.Entity) string {
+	names := make([]string, 0, len(data.Items))
+	for _, item := range data.Items {
+		names = append(names, item.Name+" x"+item.Quantity.String())
+	}
+
+	description := "Order " + data.ID + ": " + strings.Join(names, ", ")
+	if runes := []rune(description); len(runes) > maxDescriptionLength {
+		description = string(runes[:maxDescriptionLength-1]) + "…"
+	}
+
+	return description
+}
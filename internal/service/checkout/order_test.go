@@ -0,0 +1,59 @@
+package checkout
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+
+	"payment-service/internal/domain/order"
+)
+
+func TestNewBillingRequest(t *testing.T) {
+	data := order.Entity{
+		ID:       "42",
+		Currency: "KZT",
+		Total:    decimal.RequireFromString("25.5"),
+		Items: []order.Item{
+			{Name: "Apple juice", Quantity: decimal.RequireFromString("2")},
+			{Name: "Cheese", Quantity: decimal.RequireFromString("0.35")},
+		},
+	}
+
+	t.Run("order", func(t *testing.T) {
+		got := newBillingRequest(data, order.Request{TerminalID: "terminal", InvoiceID: "000000000042", Email: "a@example.com"})
+
+		if got.Amount != "25.50" || got.Currency != "KZT" || got.CorrelationID != "42" || got.Source != orderSource {
+			t.Errorf("got %s %s for %s from %s, want 25.50 KZT for 42 from %s", got.Amount, got.Currency,
+				got.CorrelationID, got.Source, orderSource)
+		}
+		if got.TerminalID != "terminal" || got.InvoiceID != "000000000042" || got.Email != "a@example.com" {
+			t.Errorf("got %+v, want the payer details of the request", got)
+		}
+
+		want := "Order 42: Apple juice x2, Cheese x0.35"
+		if got.Description != want {
+			t.Errorf("got %q, want %q", got.Description, want)
+		}
+	})
+
+	t.Run("source", func(t *testing.T) {
+		if got := newBillingRequest(data, order.Request{Source: "kiosk"}); got.Source != "kiosk" {
+			t.Errorf("got %s, want kiosk", got.Source)
+		}
+	})
+
+	t.Run("long description", func(t *testing.T) {
+		long := data
+		long.Items = nil
+		for i := 0; i < 50; i++ {
+			long.Items = append(long.Items, order.Item{Name: "Яблочный сок", Quantity: decimal.NewFromInt(1)})
+		}
+
+		got := newBillingRequest(long, order.Request{}).Description
+		if utf8.RuneCountInString(got) != maxDescriptionLength || !strings.HasSuffix(got, "…") || !utf8.ValidString(got) {
+			t.Errorf("got %q of %d runes, want it cut to %d", got, utf8.RuneCountInString(got), maxDescriptionLength)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package checkout
+
+import (
+	"payment-service/internal/domain/order"
+	"payment-service/internal/service/catalogue"
+	"payment-service/internal/service/payment"
+	"payment-service/internal/service/stock"
+	"payment-service/pkg/store"
+
+	"github.com/shopspring/decimal"
+)
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service is an implementation of the Service
+type Service struct {
+	orderRepository order.Repository
+
+	catalogueService *catalogue.Service
+	paymentService   *payment.Service
+	stockService     *stock.Service
+
+	transactor store.Transactor
+
+	tax order.Tax
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Create the service
+	s = &Service{}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WithOrderRepository applies a given order repository to the Service
+func WithOrderRepository(orderRepository order.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.orderRepository = orderRepository
+		return nil
+	}
+}
+
+// WithCatalogueService applies a given catalogue service to the Service, order items are priced through it
+func WithCatalogueService(catalogueService *catalogue.Service) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.catalogueService = catalogueService
+		return nil
+	}
+}
+
+// WithPaymentService applies a given payment service to the Service, orders are billed through it
+func WithPaymentService(paymentService *payment.Service) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.paymentService = paymentService
+		return nil
+	}
+}
+
+// WithStockService applies a given stock service to the Service, order items are reserved through it until the billing settles
+func WithStockService(stockService *stock.Service) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.stockService = stockService
+		return nil
+	}
+}
+
+// WithTransactor applies a given transactor to the Service, an order and its billing are written through it
+func WithTransactor(transactor store.Transactor) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.transactor = transactor
+		return nil
+	}
+}
+
+// WithTax applies the tax rate charged on order items, included tells catalogue prices already contain it
+func WithTax(rate float64, included bool) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.tax = order.Tax{
+			Rate:     decimal.NewFromFloat(rate),
+			Included: included,
+		}
+		return nil
+	}
+}
@@ -0,0 +1,18 @@
+package compliance
+
+import (
+	"context"
+
+	"payment-service/internal/domain/audit"
+)
+
+// ListAuditRecords returns the audit trail of the entity, the oldest record first.
+func (s *Service) ListAuditRecords(ctx context.Context, entityID string) (res []audit.Response, err error) {
+	data, err := s.auditRepository.Select(ctx, entityID)
+	if err != nil {
+		return
+	}
+	res = audit.ParseFromEntities(data)
+
+	return
+}
@@ -0,0 +1,90 @@
+package compliance
+
+import (
+	"payment-service/internal/domain/audit"
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/order"
+	"payment-service/pkg/store"
+)
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service is an implementation of the Service
+type Service struct {
+	auditRepository   audit.Repository
+	billingRepository billing.Repository
+	orderRepository   order.Repository
+
+	transactor store.Transactor
+
+	// subjectIndex computes the subject keys of the billings, see billing.SubjectKeys
+	subjectIndex billing.Index
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Create the service
+	s = &Service{subjectIndex: billing.PlainIndex}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WithAuditRepository applies a given audit repository to the Service
+func WithAuditRepository(auditRepository audit.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.auditRepository = auditRepository
+		return nil
+	}
+}
+
+// WithBillingRepository applies a given billing repository to the Service, the data subjects are found in the billings
+func WithBillingRepository(billingRepository billing.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.billingRepository = billingRepository
+		return nil
+	}
+}
+
+// WithOrderRepository applies a given order repository to the Service
+func WithOrderRepository(orderRepository order.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.orderRepository = orderRepository
+		return nil
+	}
+}
+
+// WithTransactor applies a given transactor to the Service, an erasure and its audit record are written through it
+func WithTransactor(transactor store.Transactor) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.transactor = transactor
+		return nil
+	}
+}
+
+// WithSubjectIndex applies the blind index of the identifiers of the payers to the Service, it has to be the one
+// of the billing repository, billing.PlainIndex by default
+func WithSubjectIndex(subjectIndex billing.Index) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.subjectIndex = subjectIndex
+		return nil
+	}
+}
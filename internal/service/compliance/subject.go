@@ -0,0 +1,131 @@
+package compliance
+
+import (
+	"context"
+	"time"
+
+	"payment-service/internal/domain/audit"
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/order"
+	"payment-service/internal/domain/subject"
+	"payment-service/pkg/store"
+)
+
+// ExportSubject bundles the billings of the data subject with the orders paid by them, the export is recorded
+// in the audit trail.
+func (s *Service) ExportSubject(ctx context.Context, req subject.Request) (res subject.Bundle, err error) {
+	billings, err := s.subjectBillings(ctx, req)
+	if err != nil {
+		return
+	}
+
+	res = subject.Bundle{
+		SubjectID:  req.ID(),
+		ExportedAt: time.Now(),
+		Billings:   make([]subject.Billing, 0, len(billings)),
+		Orders:     make([]order.Response, 0),
+	}
+
+	var billingIDs, orderIDs []string
+	for _, data := range billings {
+		res.Billings = append(res.Billings, subject.ParseBilling(data))
+		billingIDs = append(billingIDs, data.ID)
+
+		paid, err := s.orderRepository.GetByBillingID(ctx, data.ID)
+		if err == store.ErrorNotFound {
+			continue
+		}
+		if err != nil {
+			return res, err
+		}
+		res.Orders = append(res.Orders, order.ParseFromEntity(paid))
+		orderIDs = append(orderIDs, paid.ID)
+	}
+
+	err = s.recordSubject(ctx, req, audit.ActionExport, billingIDs, orderIDs)
+
+	return
+}
+
+// EraseSubject anonymizes the personal data of the billings of the data subject and redacts it from their audit
+// trail. The billings themselves, their orders and their ledger entries are kept as the financial records require,
+// the erasure is recorded in the audit trail.
+func (s *Service) EraseSubject(ctx context.Context, req subject.Request) (res subject.ErasureResponse, err error) {
+	res = subject.ErasureResponse{
+		SubjectID: req.ID(),
+		Billings:  make([]string, 0),
+	}
+
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		billings, err := s.subjectBillings(ctx, req)
+		if err != nil {
+			return
+		}
+
+		for _, data := range billings {
+			if err = s.billingRepository.Update(ctx, data.ID, subject.Erasure(data)); err != nil {
+				return
+			}
+			res.Billings = append(res.Billings, data.ID)
+		}
+
+		// the trail of the billings keeps their personal data as it was before the erasure, masked or not
+		if len(res.Billings) > 0 {
+			err = s.auditRepository.Redact(ctx, audit.EntityBilling, res.Billings, subject.PersonalFields)
+			if err != nil {
+				return
+			}
+		}
+
+		return s.recordSubject(ctx, req, audit.ActionErase, res.Billings, nil)
+	})
+
+	return
+}
+
+// subjectBillings looks the billings of the subject up by the blind indexes of its identifiers, the personal data
+// is encrypted with data keys of its own and can't be looked up by value. The billings found are matched against
+// the identifiers once decrypted.
+func (s *Service) subjectBillings(ctx context.Context, req subject.Request) (dest []billing.Entity, err error) {
+	keys := req.Keys(s.subjectIndex)
+	if len(keys) == 0 {
+		return
+	}
+
+	data, err := s.billingRepository.SelectByFilter(ctx, billing.Filter{SubjectKeys: keys})
+	if err != nil {
+		return
+	}
+
+	for _, object := range data {
+		if req.Matches(object) {
+			dest = append(dest, object)
+		}
+	}
+
+	return
+}
+
+// recordSubject keeps the export or erasure in the audit trail under the id of the subject rather than its
+// identifiers, with the billings and orders it covered.
+func (s *Service) recordSubject(ctx context.Context, req subject.Request, action string, billings, orders []string) (err error) {
+	diff, err := audit.Diff(nil, struct {
+		Billings []string
+		Orders   []string
+	}{billings, orders})
+	if err != nil {
+		return
+	}
+
+	data := audit.Entity{
+		EntityType: audit.EntitySubject,
+		EntityID:   req.ID(),
+		Action:     action,
+		Diff:       diff,
+	}
+	data.Actor, data.RequestID = audit.Source(ctx)
+
+	_, err = s.auditRepository.Create(ctx, data)
+
+	return
+}
@@ -0,0 +1,144 @@
+package compliance
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"payment-service/internal/domain/audit"
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/order"
+	"payment-service/internal/domain/subject"
+	"payment-service/internal/repository/memory"
+	"payment-service/pkg/store"
+)
+
+func TestSubject(t *testing.T) {
+	ctx := store.WithOperator(context.Background())
+	audits := memory.NewAuditRepository()
+	billings := memory.NewBillingRepository()
+	orders := memory.NewOrderRepository()
+
+	s, err := New(
+		WithAuditRepository(audits),
+		WithBillingRepository(billings),
+		WithOrderRepository(orders),
+		WithTransactor(memory.NewTransactor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	create := func(name, email, phone string) string {
+		t.Helper()
+		id, err := billings.Create(ctx, billing.Entity{Amount: "100", Currency: "KZT", InvoiceID: "inv-" + phone,
+			Status: billing.StatusPaid, Name: name, Email: email, Phone: phone,
+			SubjectKeys: billing.SubjectKeys(billing.PlainIndex, email, phone, "")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	byEmail := create("John Doe", "John@Example.com", "+7 701 123-45-67")
+	byPhone := create("J. Doe", "", "87011234567")
+	other := create("Jane Doe", "jane@example.com", "+77019876543")
+
+	if _, err = orders.Create(ctx, order.Entity{BillingID: byEmail}); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := audit.Diff(nil, billing.Entity{Email: "John@Example.com", Status: billing.StatusPaid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = audits.Create(ctx, audit.Entity{EntityType: audit.EntityBilling, EntityID: byEmail, Action: audit.ActionCreate, Diff: diff}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the phone matches the digits of the second billing only, the email is matched case-insensitively
+	req := subject.Request{Email: " john@example.com ", Phone: "8 (701) 123-45-67"}
+	if err = req.Bind(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("export", func(t *testing.T) {
+		res, err := s.ExportSubject(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !sameIDs(billingIDs(res.Billings), byEmail, byPhone) || len(res.Orders) != 1 || res.SubjectID != req.ID() {
+			t.Errorf("got %+v, want the billings %s and %s with the order of the first", res, byEmail, byPhone)
+		}
+
+		if records, _ := audits.Select(ctx, req.ID()); len(records) != 1 || records[0].Action != audit.ActionExport {
+			t.Errorf("got %+v, want the export recorded under the subject", records)
+		}
+	})
+
+	t.Run("erase", func(t *testing.T) {
+		res, err := s.EraseSubject(ctx, req)
+		if err != nil || !sameIDs(res.Billings, byEmail, byPhone) {
+			t.Fatalf("got %+v, err = %v", res, err)
+		}
+
+		erased, err := billings.Get(ctx, byEmail)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if erased.Name != subject.Erased || erased.Email != subject.Erased || erased.Phone != subject.Erased ||
+			erased.AccountID != "" || len(erased.SubjectKeys) != 0 || erased.Amount != "100" || erased.Status != billing.StatusPaid {
+			t.Errorf("got %+v, want the personal data erased and the payment kept", erased)
+		}
+
+		trail, err := audits.Select(ctx, byEmail)
+		if err != nil || len(trail) != 1 || strings.Contains(string(trail[0].Diff), "Example.com") ||
+			!strings.Contains(string(trail[0].Diff), billing.StatusPaid) {
+			t.Errorf("got %+v, err = %v, want the email redacted from the trail of the billing", trail, err)
+		}
+
+		kept, err := billings.Get(ctx, other)
+		if err != nil || kept.Email != "jane@example.com" {
+			t.Errorf("got %+v, err = %v, want the billing of another payer untouched", kept, err)
+		}
+
+		// the erased billings are no longer found by the identifiers of the subject
+		export, err := s.ExportSubject(ctx, req)
+		if err != nil || len(export.Billings) != 0 {
+			t.Errorf("got %+v, err = %v, want nothing left to export", export, err)
+		}
+
+		records, _ := audits.Select(ctx, req.ID())
+		if len(records) != 3 || records[1].Action != audit.ActionErase {
+			t.Errorf("got %+v, want the erasure recorded between the exports", records)
+		}
+	})
+}
+
+func billingIDs(billings []subject.Billing) (ids []string) {
+	for _, data := range billings {
+		ids = append(ids, data.ID)
+	}
+
+	return
+}
+
+func sameIDs(got []string, want ...string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	seen := make(map[string]bool, len(got))
+	for _, id := range got {
+		seen[id] = true
+	}
+	for _, id := range want {
+		if !seen[id] {
+			return false
+		}
+	}
+
+	return true
+}
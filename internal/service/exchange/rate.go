@@ -0,0 +1,74 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/domain/fx"
+	"time"
+)
+
+var ErrNoProvider = errors.New("fx: no rate provider is configured")
+
+// BaseCurrency returns the currency reporting totals are converted to.
+func (s *Service) BaseCurrency() string {
+	return s.baseCurrency
+}
+
+// Rate returns the rate of the currency to the base currency effective at the time.
+func (s *Service) Rate(ctx context.Context, currency string, at time.Time) (rate fx.Rate, err error) {
+	currency = fx.NormalizeCurrency(currency)
+	if currency == s.baseCurrency {
+		return fx.Identity(s.baseCurrency, fx.Day(at)), nil
+	}
+
+	return s.rateRepository.Get(ctx, currency, s.baseCurrency, fx.Day(at))
+}
+
+func (s *Service) ListRates(ctx context.Context, req fx.Request) (res []fx.Response, err error) {
+	data, err := s.rateRepository.Select(ctx, s.baseCurrency, fx.Day(req.Date))
+	if err != nil {
+		return
+	}
+	res = fx.ParseFromEntities(data)
+
+	return
+}
+
+// ImportRates stores the rates of a CSV feed uploaded by hand.
+func (s *Service) ImportRates(ctx context.Context, records [][]string, source string) (res []fx.Response, err error) {
+	data, err := fx.ParseRates(records, s.baseCurrency, time.Now())
+	if err != nil {
+		return
+	}
+
+	for i := range data {
+		data[i].Source = source
+	}
+
+	if err = s.rateRepository.Save(ctx, data); err != nil {
+		return
+	}
+	res = fx.ParseFromEntities(data)
+
+	return
+}
+
+// RefreshRates loads the current rates from the configured provider.
+func (s *Service) RefreshRates(ctx context.Context) (res []fx.Response, err error) {
+	if s.provider == nil {
+		err = ErrNoProvider
+		return
+	}
+
+	data, err := s.provider.Fetch(ctx, s.baseCurrency)
+	if err != nil {
+		return
+	}
+
+	if err = s.rateRepository.Save(ctx, data); err != nil {
+		return
+	}
+	res = fx.ParseFromEntities(data)
+
+	return
+}
@@ -0,0 +1,113 @@
+package exchange
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"payment-service/internal/domain/fx"
+	"payment-service/internal/repository/memory"
+)
+
+// fakeProvider answers with the rates it keeps, whatever the base.
+type fakeProvider []fx.Rate
+
+func (p fakeProvider) Fetch(ctx context.Context, base string) ([]fx.Rate, error) {
+	return p, nil
+}
+
+func TestRate(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time {
+		return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	s, err := New(
+		WithRateRepository(memory.NewFXRepository()),
+		WithBaseCurrency("KZT"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = s.ImportRates(ctx, [][]string{
+		{"currency", "rate", "date"},
+		{"USD", "450", "2024-03-10"},
+		{"USD", "455,25", "2024-03-14"},
+		{"EUR", "490", "2024-03-12"},
+	}, "upload"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		currency string
+		at       time.Time
+		rate     string
+		amount   string
+		base     string
+	}{
+		{name: "effective day", currency: "USD", at: day(10), rate: "450", amount: "10.01", base: "4504.5"},
+		{name: "between rates", currency: "usd", at: day(13).Add(23 * time.Hour), rate: "450", amount: "1", base: "450"},
+		{name: "later rate", currency: "USD", at: day(20), rate: "455.25", amount: "0.333", base: "151.6"},
+		{name: "other currency", currency: "EUR", at: day(12), rate: "490", amount: "2", base: "980"},
+		{name: "base currency", currency: " kzt", at: day(1), rate: "1", amount: "99.99", base: "99.99"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := s.Rate(ctx, tt.currency, tt.at)
+			if err != nil || !rate.Rate.Equal(decimal.RequireFromString(tt.rate)) {
+				t.Fatalf("got %s, err = %v, want %s", rate.Rate, err, tt.rate)
+			}
+
+			if got := rate.Convert(decimal.RequireFromString(tt.amount)); got.String() != tt.base {
+				t.Errorf("got %s, want %s", got, tt.base)
+			}
+		})
+	}
+
+	t.Run("before any rate", func(t *testing.T) {
+		if _, err := s.Rate(ctx, "USD", day(9)); err != fx.ErrRateNotFound {
+			t.Errorf("err = %v, want %v", err, fx.ErrRateNotFound)
+		}
+		if _, err := s.Rate(ctx, "CNY", day(20)); err != fx.ErrRateNotFound {
+			t.Errorf("err = %v, want %v", err, fx.ErrRateNotFound)
+		}
+	})
+}
+
+func TestRefreshRates(t *testing.T) {
+	ctx := context.Background()
+	rates := memory.NewFXRepository()
+
+	s, err := New(WithRateRepository(rates), WithBaseCurrency("KZT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = s.RefreshRates(ctx); err != ErrNoProvider {
+		t.Errorf("err = %v, want %v", err, ErrNoProvider)
+	}
+
+	today := fx.Day(time.Now())
+	s, err = New(
+		WithRateRepository(rates),
+		WithProvider(fakeProvider{fx.Quote("USD", "KZT", decimal.RequireFromString("0.002"), today)}),
+		WithBaseCurrency("KZT"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = s.RefreshRates(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	rate, err := s.Rate(ctx, "USD", time.Now())
+	if err != nil || !rate.Rate.Equal(decimal.NewFromInt(500)) {
+		t.Errorf("got %s, err = %v, want 500", rate.Rate, err)
+	}
+}
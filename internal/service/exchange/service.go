@@ -0,0 +1,62 @@
+package exchange
+
+import (
+	"payment-service/internal/domain/fx"
+)
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service is an implementation of the Service
+type Service struct {
+	rateRepository fx.Repository
+	provider       fx.Provider
+
+	baseCurrency string
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Create the service
+	s = &Service{}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WithRateRepository applies a given rate repository to the Service
+func WithRateRepository(rateRepository fx.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.rateRepository = rateRepository
+		return nil
+	}
+}
+
+// WithProvider applies a given rate provider to the Service, RefreshRates loads rates from it
+func WithProvider(provider fx.Provider) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.provider = provider
+		return nil
+	}
+}
+
+// WithBaseCurrency applies the currency reporting totals are converted to
+func WithBaseCurrency(baseCurrency string) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.baseCurrency = fx.NormalizeCurrency(baseCurrency)
+		return nil
+	}
+}
@@ -3,6 +3,13 @@ package payment
 import (
 	"context"
 	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/outbox"
+	"payment-service/pkg/mergepatch"
+	"payment-service/pkg/store"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 func (s *Service) AddBilling(ctx context.Context, req billing.Request) (res billing.Response, err error) {
@@ -24,8 +31,67 @@ func (s *Service) AddBilling(ctx context.Context, req billing.Request) (res bill
 		FailurePostLink: req.FailurePostLink,
 		Language:        req.Language,
 		PaymentType:     req.PaymentType,
+		Status:          billing.StatusCreated,
+	}
+	data.SubjectKeys = billing.SubjectKeys(s.subjectIndex, data.Email, data.Phone, data.AccountID)
+
+	if err = s.applyTenant(ctx, &data); err != nil {
+		return
+	}
+
+	// the rate quoted when the payment starts, it is taken again once the billing is paid
+	if err = s.snapshotRate(ctx, &data, time.Now()); err != nil {
+		return
+	}
+
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		data.ID, err = s.billingRepository.Create(ctx, data)
+		if err != nil {
+			return
+		}
+
+		if err = s.notify(ctx, data); err != nil {
+			return
+		}
+
+		return s.publish(ctx, billing.EventCreated, billing.NewEvent(data), "")
+	})
+	if err != nil {
+		return
+	}
+	res = billing.ParseFromEntity(data)
+
+	return
+}
+
+// applyTenant stamps the billing with the tenant of the caller and fills the terminal and currency
+// the request left empty with the settings of the tenant.
+func (s *Service) applyTenant(ctx context.Context, data *billing.Entity) (err error) {
+	data.TenantID = store.TenantID(ctx)
+	if data.TenantID == "" || s.tenantRepository == nil {
+		return
+	}
+
+	settings, err := s.tenantRepository.Get(ctx, data.TenantID)
+	if err == store.ErrorNotFound {
+		return nil
+	}
+	if err != nil {
+		return
+	}
+
+	if data.TerminalID == "" {
+		data.TerminalID = settings.TerminalID
+	}
+	if data.Currency == "" {
+		data.Currency = settings.Currency
 	}
-	data.ID, err = s.billingRepository.Create(ctx, data)
+
+	return
+}
+
+func (s *Service) GetBilling(ctx context.Context, id string) (res billing.Response, err error) {
+	data, err := s.billingCache.Get(ctx, id)
 	if err != nil {
 		return
 	}
@@ -33,3 +99,166 @@ func (s *Service) AddBilling(ctx context.Context, req billing.Request) (res bill
 
 	return
 }
+
+// PatchBilling applies a JSON merge patch to the contact and redirect details of a billing that is not paid yet.
+// A non-zero version has to match the stored one.
+func (s *Service) PatchBilling(ctx context.Context, id string, version int, patch []byte) (res billing.Response, err error) {
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		data, err := s.billingRepository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if version != 0 && data.Version != version {
+			return store.ErrorStaleVersion
+		}
+
+		if data.Status != billing.StatusCreated {
+			return billing.ErrInvalidStatus
+		}
+
+		req := billing.NewPatchRequest(data)
+		if err = mergepatch.Apply(&req, patch); err != nil {
+			return
+		}
+
+		if err = req.Apply(&data); err != nil {
+			return &mergepatch.Error{Err: err}
+		}
+		data.SubjectKeys = billing.SubjectKeys(s.subjectIndex, data.Email, data.Phone, data.AccountID)
+
+		if err = s.billingRepository.Update(ctx, id, data); err != nil {
+			return
+		}
+		data.Version++
+		res = billing.ParseFromEntity(data)
+
+		return
+	})
+
+	return
+}
+
+// RefundBilling refunds a paid billing through the payment gateway, then records the refund and posts it to the ledger.
+// A billing can be refunded in parts, as long as the refunds don't add up to more than its amount.
+func (s *Service) RefundBilling(ctx context.Context, id string, req billing.RefundRequest) (err error) {
+	data, err := s.billingRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	_, left, err := refundable(data)
+	if err != nil {
+		return
+	}
+
+	amount := left
+	if req.Amount != "" {
+		if amount, err = decimal.NewFromString(req.Amount); err != nil {
+			return
+		}
+	}
+
+	if !amount.IsPositive() || amount.GreaterThan(left) {
+		return billing.ErrInvalidAmount
+	}
+
+	// the gateway is asked outside of the transaction, it is a network call, and only a refund it made is recorded
+	if err = s.gateway.RefundInvoice(ctx, data.InvoiceID, amount); err != nil {
+		return
+	}
+
+	return s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		// the billing is read again, another refund may have been recorded since the check above
+		data, err := s.billingRepository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		refunded, left, err := refundable(data)
+		if err != nil {
+			return
+		}
+
+		if amount.GreaterThan(left) {
+			return billing.ErrInvalidAmount
+		}
+
+		// every refund moves the version on, so the version it was made at tells it from the other refunds
+		reference := data.ID + ":" + strconv.Itoa(data.Version)
+
+		data.Refunded = refunded.Add(amount).String()
+		data.Status = billing.StatusPartiallyRefunded
+		if amount.Equal(left) {
+			data.Status = billing.StatusRefunded
+		}
+
+		// the version read above fails a refund that raced another refund or status change
+		if err = s.billingRepository.Update(ctx, data.ID, data); err != nil {
+			return
+		}
+
+		if err = s.accountingService.PostRefund(ctx, data, amount, reference); err != nil {
+			return
+		}
+
+		if err = s.notify(ctx, data); err != nil {
+			return
+		}
+
+		event := billing.NewEvent(data)
+		event.Refunded = amount.String()
+
+		return s.publish(ctx, billing.EventRefunded, event, reference)
+	})
+}
+
+// refundable tells how much of the billing amount is refunded and how much is left, a billing is refunded
+// only once it is paid.
+func refundable(data billing.Entity) (refunded, left decimal.Decimal, err error) {
+	if data.Status != billing.StatusPaid && data.Status != billing.StatusPartiallyRefunded {
+		return refunded, left, billing.ErrInvalidStatus
+	}
+
+	total, err := decimal.NewFromString(data.Amount)
+	if err != nil {
+		return
+	}
+
+	refunded = decimal.Zero
+	if data.Refunded != "" {
+		if refunded, err = decimal.NewFromString(data.Refunded); err != nil {
+			return
+		}
+	}
+
+	return refunded, total.Sub(refunded), nil
+}
+
+// notify tells the observers about the billing status change, it must be called within the transaction that changes the billing.
+func (s *Service) notify(ctx context.Context, data billing.Entity) (err error) {
+	for _, observer := range s.observers {
+		if err = observer.BillingChanged(ctx, data); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// publish records the billing event in the outbox, it must be called within the transaction that changes the billing.
+// The event is kept for the tenant of the billing, whichever scope it is changed in. Reference tells the events
+// a billing may publish more than once apart, e.g. its refunds, it is empty for the transitions made once.
+func (s *Service) publish(ctx context.Context, eventType string, event billing.Event, reference string) (err error) {
+	data, err := outbox.New(billing.Aggregate, event.ID, eventType, event)
+	if err != nil {
+		return
+	}
+	data.TenantID = event.TenantID
+	if reference != "" {
+		data.DedupKey = eventType + ":" + reference
+	}
+	_, err = s.outboxRepository.Create(ctx, data)
+
+	return
+}
@@ -0,0 +1,126 @@
+package payment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/ledger"
+	"payment-service/internal/repository/memory"
+	"payment-service/internal/service/accounting"
+	"payment-service/pkg/epay"
+	"payment-service/pkg/store"
+)
+
+func TestRefundBilling(t *testing.T) {
+	ctx := store.WithOperator(context.Background())
+	billings := memory.NewBillingRepository()
+	entries := memory.NewLedgerRepository()
+	events := memory.NewOutboxRepository()
+	transactor := memory.NewTransactor()
+
+	accountingService, err := accounting.New(
+		accounting.WithLedgerRepository(entries),
+		accounting.WithTransactor(transactor),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := New(
+		WithBillingRepository(billings),
+		WithOutboxRepository(events),
+		WithAccountingService(accountingService),
+		WithGateway(fakeGateway{"000000000042": {StatusName: epay.StatusCharge}}),
+		WithTransactor(transactor),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paidAt := time.Now()
+	id, err := billings.Create(ctx, billing.Entity{Amount: "100.50", Currency: "KZT", InvoiceID: "000000000042",
+		TerminalID: "terminal", Status: billing.StatusPaid, PaidAt: &paidAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(status, refunded string) {
+		t.Helper()
+		data, err := billings.Get(ctx, id)
+		if err != nil || data.Status != status || data.Refunded != refunded {
+			t.Errorf("got %s with %s refunded, err = %v, want %s with %s", data.Status, data.Refunded, err, status, refunded)
+		}
+	}
+
+	if err = s.RefundBilling(ctx, id, billing.RefundRequest{Amount: "30"}); err != nil {
+		t.Fatal(err)
+	}
+	check(billing.StatusPartiallyRefunded, "30")
+
+	// a refund the gateway declines is not recorded
+	declined, err := billings.Create(ctx, billing.Entity{Amount: "10", Currency: "KZT", InvoiceID: "000000000043",
+		TerminalID: "terminal", Status: billing.StatusPaid, PaidAt: &paidAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.RefundBilling(ctx, declined, billing.RefundRequest{}); err != errRefundDeclined {
+		t.Errorf("err = %v, want %v", err, errRefundDeclined)
+	}
+	if data, err := billings.Get(ctx, declined); err != nil || data.Status != billing.StatusPaid || data.Refunded != "" {
+		t.Errorf("got %s with %s refunded, err = %v, want the billing left paid", data.Status, data.Refunded, err)
+	}
+
+	// a part refunded can't be refunded again
+	if err = s.RefundBilling(ctx, id, billing.RefundRequest{Amount: "70.51"}); err != billing.ErrInvalidAmount {
+		t.Errorf("err = %v, want %v", err, billing.ErrInvalidAmount)
+	}
+	check(billing.StatusPartiallyRefunded, "30")
+
+	if err = s.RefundBilling(ctx, id, billing.RefundRequest{Amount: "20.25"}); err != nil {
+		t.Fatal(err)
+	}
+	check(billing.StatusPartiallyRefunded, "50.25")
+
+	// an empty amount refunds what is left
+	if err = s.RefundBilling(ctx, id, billing.RefundRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	check(billing.StatusRefunded, "100.5")
+
+	if err = s.RefundBilling(ctx, id, billing.RefundRequest{Amount: "1"}); err != billing.ErrInvalidStatus {
+		t.Errorf("err = %v, want %v", err, billing.ErrInvalidStatus)
+	}
+
+	posted, err := entries.SelectEntries(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total := decimal.Zero
+	keys := make(map[string]bool)
+	for _, entry := range posted {
+		if entry.Kind != ledger.KindRefund || keys[entry.DedupKey] {
+			t.Errorf("got %+v, want refunds told apart", entry)
+		}
+		keys[entry.DedupKey] = true
+		total = total.Add(entry.Postings[0].Amount)
+	}
+
+	if len(posted) != 3 || !total.Equal(decimal.RequireFromString("100.5")) {
+		t.Errorf("got %d entries of %s, want the three refunds of 100.5 posted", len(posted), total)
+	}
+
+	published, err := events.Claim(ctx, 10, time.Minute)
+	if err != nil || len(published) != 3 {
+		t.Errorf("got %d events, err = %v, want one per refund", len(published), err)
+	}
+	for _, event := range published {
+		if event.EventType != billing.EventRefunded {
+			t.Errorf("got %s, want %s", event.EventType, billing.EventRefunded)
+		}
+	}
+}
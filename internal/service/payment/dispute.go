@@ -0,0 +1,211 @@
+package payment
+
+import (
+	"context"
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/dispute"
+	"payment-service/internal/domain/outbox"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func (s *Service) ListDisputes(ctx context.Context, status string) (res []dispute.Response, err error) {
+	data, err := s.disputeRepository.Select(ctx, status)
+	if err != nil {
+		return
+	}
+	res = dispute.ParseFromEntities(data)
+
+	return
+}
+
+// ListDueDisputes returns opened disputes whose evidence deadline falls within the request window.
+func (s *Service) ListDueDisputes(ctx context.Context, req dispute.DueRequest) (res []dispute.Response, err error) {
+	data, err := s.disputeRepository.SelectDue(ctx, time.Now().Add(req.Within))
+	if err != nil {
+		return
+	}
+	res = dispute.ParseFromEntities(data)
+
+	return
+}
+
+func (s *Service) GetDispute(ctx context.Context, id string) (res dispute.Response, err error) {
+	data, err := s.disputeRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+	res = dispute.ParseFromEntity(data)
+
+	return
+}
+
+// OpenDispute records a cardholder dispute against a paid or refunded billing.
+func (s *Service) OpenDispute(ctx context.Context, req dispute.Request) (res dispute.Response, err error) {
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return
+	}
+
+	data := dispute.Entity{
+		BillingID:  req.BillingID,
+		ReasonCode: req.ReasonCode,
+		Amount:     amount,
+		Status:     dispute.StatusOpened,
+		DueAt:      req.DueAt,
+	}
+
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		billingData, err := s.billingRepository.Get(ctx, req.BillingID)
+		if err != nil {
+			return
+		}
+
+		switch billingData.Status {
+		case billing.StatusPaid, billing.StatusPartiallyRefunded, billing.StatusRefunded:
+		default:
+			return billing.ErrInvalidStatus
+		}
+
+		total, err := decimal.NewFromString(billingData.Amount)
+		if err != nil {
+			return
+		}
+
+		if amount.GreaterThan(total) {
+			return dispute.ErrInvalidAmount
+		}
+		data.Currency = billingData.Currency
+		data.TenantID = billingData.TenantID
+
+		if data.ID, err = s.disputeRepository.Create(ctx, data); err != nil {
+			return
+		}
+
+		return s.publishDispute(ctx, dispute.EventOpened, data)
+	})
+	if err != nil {
+		return
+	}
+	res = dispute.ParseFromEntity(data)
+
+	return
+}
+
+// AddDisputeEvidence attaches evidence to a dispute that has not been submitted yet.
+func (s *Service) AddDisputeEvidence(ctx context.Context, id string, req dispute.EvidenceRequest) (res dispute.Response, err error) {
+	data, err := s.disputeRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if data.Status != dispute.StatusOpened {
+		err = dispute.ErrInvalidStatus
+		return
+	}
+
+	_, err = s.disputeRepository.AddEvidence(ctx, dispute.Evidence{
+		DisputeID:   id,
+		Name:        req.Name,
+		URL:         req.URL,
+		ContentType: req.ContentType,
+		Note:        req.Note,
+	})
+	if err != nil {
+		return
+	}
+
+	return s.GetDispute(ctx, id)
+}
+
+// SubmitDispute marks the evidence of the dispute as sent to the bank.
+func (s *Service) SubmitDispute(ctx context.Context, id string) (res dispute.Response, err error) {
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		data, err := s.disputeRepository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if len(data.Evidence) == 0 {
+			return dispute.ErrNoEvidence
+		}
+
+		if err = s.transitDispute(ctx, &data, dispute.StatusEvidenceSubmitted); err != nil {
+			return
+		}
+
+		if err = s.publishDispute(ctx, dispute.EventEvidenceSubmitted, data); err != nil {
+			return
+		}
+		res = dispute.ParseFromEntity(data)
+
+		return
+	})
+
+	return
+}
+
+// ResolveDispute records the outcome of the dispute, a lost one posts the chargeback to the ledger.
+func (s *Service) ResolveDispute(ctx context.Context, id string, req dispute.ResolveRequest) (res dispute.Response, err error) {
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		data, err := s.disputeRepository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if err = s.transitDispute(ctx, &data, req.Outcome); err != nil {
+			return
+		}
+
+		eventType := dispute.EventWon
+		if data.Status == dispute.StatusLost {
+			eventType = dispute.EventLost
+
+			billingData, err := s.billingRepository.Get(ctx, data.BillingID)
+			if err != nil {
+				return err
+			}
+
+			if err = s.accountingService.PostChargeback(ctx, billingData, data.Amount, data.ID); err != nil {
+				return err
+			}
+		}
+
+		if err = s.publishDispute(ctx, eventType, data); err != nil {
+			return
+		}
+		res = dispute.ParseFromEntity(data)
+
+		return
+	})
+
+	return
+}
+
+// transitDispute moves the dispute to the status, won and lost ones are stamped as resolved.
+func (s *Service) transitDispute(ctx context.Context, data *dispute.Entity, status string) (err error) {
+	if !data.CanTransition(status) {
+		return dispute.ErrInvalidStatus
+	}
+
+	data.Status = status
+	if status == dispute.StatusWon || status == dispute.StatusLost {
+		now := time.Now()
+		data.ResolvedAt = &now
+	}
+
+	return s.disputeRepository.Update(ctx, data.ID, *data)
+}
+
+// publishDispute records the dispute event in the outbox, it must be called within the transaction that changes the dispute.
+func (s *Service) publishDispute(ctx context.Context, eventType string, data dispute.Entity) (err error) {
+	event, err := outbox.New(dispute.Aggregate, data.ID, eventType, dispute.NewEvent(data))
+	if err != nil {
+		return
+	}
+	event.TenantID = data.TenantID
+	_, err = s.outboxRepository.Create(ctx, event)
+
+	return
+}
@@ -0,0 +1,193 @@
+package payment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/dispute"
+	"payment-service/internal/domain/ledger"
+	"payment-service/internal/repository/memory"
+	"payment-service/internal/service/accounting"
+	"payment-service/pkg/store"
+)
+
+func TestDispute(t *testing.T) {
+	ctx := store.WithOperator(context.Background())
+	billings := memory.NewBillingRepository()
+	disputes := memory.NewDisputeRepository()
+	entries := memory.NewLedgerRepository()
+	transactor := memory.NewTransactor()
+
+	accountingService, err := accounting.New(
+		accounting.WithLedgerRepository(entries),
+		accounting.WithTransactor(transactor),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := New(
+		WithBillingRepository(billings),
+		WithDisputeRepository(disputes),
+		WithOutboxRepository(memory.NewOutboxRepository()),
+		WithAccountingService(accountingService),
+		WithTransactor(transactor),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paidAt := time.Now()
+	paid, err := billings.Create(ctx, billing.Entity{Amount: "100", Currency: "KZT", InvoiceID: "000000000042",
+		TerminalID: "terminal", Status: billing.StatusPaid, PaidAt: &paidAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	open := func(t *testing.T, amount string, due time.Duration) dispute.Response {
+		t.Helper()
+		res, err := s.OpenDispute(ctx, dispute.Request{BillingID: paid, ReasonCode: "4837", Amount: amount,
+			DueAt: time.Now().Add(due)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	submit := func(t *testing.T, id string) {
+		t.Helper()
+		_, err := s.AddDisputeEvidence(ctx, id, dispute.EvidenceRequest{Name: "receipt.pdf", URL: "https://example.com/receipt.pdf"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = s.SubmitDispute(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chargebacks := func(t *testing.T) (dest []ledger.Entry) {
+		t.Helper()
+		posted, err := entries.SelectEntries(ctx, paid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range posted {
+			if entry.Kind == ledger.KindChargeback {
+				dest = append(dest, entry)
+			}
+		}
+		return
+	}
+
+	t.Run("won", func(t *testing.T) {
+		res := open(t, "40", 48*time.Hour)
+		submit(t, res.ID)
+
+		res, err := s.ResolveDispute(ctx, res.ID, dispute.ResolveRequest{Outcome: dispute.StatusWon})
+		if err != nil || res.Status != dispute.StatusWon || res.ResolvedAt == nil {
+			t.Errorf("got %+v, err = %v, want it won and resolved", res, err)
+		}
+
+		if posted := chargebacks(t); len(posted) != 0 {
+			t.Errorf("got %d chargebacks, want none for a won dispute", len(posted))
+		}
+	})
+
+	t.Run("lost", func(t *testing.T) {
+		res := open(t, "60", 48*time.Hour)
+		submit(t, res.ID)
+
+		res, err := s.ResolveDispute(ctx, res.ID, dispute.ResolveRequest{Outcome: dispute.StatusLost})
+		if err != nil || res.Status != dispute.StatusLost {
+			t.Errorf("got %+v, err = %v, want it lost", res, err)
+		}
+
+		posted := chargebacks(t)
+		if len(posted) != 1 {
+			t.Fatalf("got %d chargebacks, want the lost dispute posted once", len(posted))
+		}
+
+		want := decimal.RequireFromString("60")
+		for _, posting := range posted[0].Postings {
+			if !posting.Amount.Abs().Equal(want) {
+				t.Errorf("got %s posted, want %s", posting.Amount, want)
+			}
+		}
+		if code := posted[0].Postings[0].AccountCode; code != ledger.AccountChargebacks+":terminal" {
+			t.Errorf("got %s debited, want %s", code, ledger.AccountChargebacks)
+		}
+	})
+
+	t.Run("transitions", func(t *testing.T) {
+		res := open(t, "10", 48*time.Hour)
+
+		// a dispute can't be submitted without evidence, nor resolved before it is submitted
+		if _, err := s.SubmitDispute(ctx, res.ID); err != dispute.ErrNoEvidence {
+			t.Errorf("err = %v, want %v", err, dispute.ErrNoEvidence)
+		}
+		if _, err := s.ResolveDispute(ctx, res.ID, dispute.ResolveRequest{Outcome: dispute.StatusLost}); err != dispute.ErrInvalidStatus {
+			t.Errorf("err = %v, want %v", err, dispute.ErrInvalidStatus)
+		}
+
+		submit(t, res.ID)
+
+		// the evidence is closed once it is submitted
+		_, err := s.AddDisputeEvidence(ctx, res.ID, dispute.EvidenceRequest{Name: "late.pdf", URL: "https://example.com/late.pdf"})
+		if err != dispute.ErrInvalidStatus {
+			t.Errorf("err = %v, want %v", err, dispute.ErrInvalidStatus)
+		}
+		if _, err = s.SubmitDispute(ctx, res.ID); err != dispute.ErrInvalidStatus {
+			t.Errorf("err = %v, want %v", err, dispute.ErrInvalidStatus)
+		}
+
+		if _, err = s.ResolveDispute(ctx, res.ID, dispute.ResolveRequest{Outcome: dispute.StatusWon}); err != nil {
+			t.Fatal(err)
+		}
+
+		// a resolved dispute stays resolved
+		if _, err = s.ResolveDispute(ctx, res.ID, dispute.ResolveRequest{Outcome: dispute.StatusLost}); err != dispute.ErrInvalidStatus {
+			t.Errorf("err = %v, want %v", err, dispute.ErrInvalidStatus)
+		}
+	})
+
+	t.Run("open", func(t *testing.T) {
+		if _, err := s.OpenDispute(ctx, dispute.Request{BillingID: paid, ReasonCode: "4837", Amount: "100.01",
+			DueAt: time.Now()}); err != dispute.ErrInvalidAmount {
+			t.Errorf("err = %v, want %v", err, dispute.ErrInvalidAmount)
+		}
+
+		created, err := billings.Create(ctx, billing.Entity{Amount: "100", Currency: "KZT", InvoiceID: "000000000043",
+			TerminalID: "terminal", Status: billing.StatusCreated})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = s.OpenDispute(ctx, dispute.Request{BillingID: created, ReasonCode: "4837", Amount: "1",
+			DueAt: time.Now()}); err != billing.ErrInvalidStatus {
+			t.Errorf("err = %v, want %v", err, billing.ErrInvalidStatus)
+		}
+	})
+
+	t.Run("due", func(t *testing.T) {
+		soon := open(t, "1", time.Hour)
+		later := open(t, "1", 24*time.Hour)
+		open(t, "1", 30*24*time.Hour)
+
+		// a submitted dispute is no longer waiting for evidence, however close its deadline is
+		submitted := open(t, "1", 2*time.Hour)
+		submit(t, submitted.ID)
+
+		res, err := s.ListDueDisputes(ctx, dispute.DueRequest{Within: 72 * time.Hour})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the disputes of the subtests above are resolved by now, so the ones due are opened here
+		if len(res) != 2 || res[0].ID != soon.ID || res[1].ID != later.ID {
+			t.Errorf("got %+v, want %s and %s in the order of their deadline", res, soon.ID, later.ID)
+		}
+	})
+}
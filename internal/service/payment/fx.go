@@ -0,0 +1,118 @@
+package payment
+
+import (
+	"context"
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/fx"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// snapshotRate stores the rate to the base currency effective at the time on the billing. A missing rate
+// leaves the billing without a snapshot rather than failing the payment, reports convert it later.
+func (s *Service) snapshotRate(ctx context.Context, data *billing.Entity, at time.Time) (err error) {
+	amount, err := decimal.NewFromString(data.Amount)
+	if err != nil {
+		return
+	}
+
+	rate, err := s.exchangeService.Rate(ctx, data.Currency, at)
+	if err == fx.ErrRateNotFound {
+		return nil
+	}
+
+	if err != nil {
+		return
+	}
+
+	data.FXRate = rate.Rate.String()
+	data.BaseAmount = rate.Convert(amount).String()
+	data.BaseCurrency = rate.Base
+
+	return
+}
+
+// BillingTotals sums billings per currency and converts them to the base currency, either with the rate
+// snapshot at payment time or, for billings without one, with the rate effective on their creation date.
+func (s *Service) BillingTotals(ctx context.Context, req billing.TotalsRequest) (res billing.TotalsResponse, err error) {
+	data, err := s.billingRepository.SelectByFilter(ctx, req.Filter)
+	if err != nil {
+		return
+	}
+
+	type total struct {
+		billing.CurrencyTotal
+		amount, base decimal.Decimal
+	}
+
+	baseCurrency := s.exchangeService.BaseCurrency()
+	totals := make(map[string]*total)
+	baseTotal := decimal.Zero
+
+	for _, object := range data {
+		amount, err := decimal.NewFromString(object.Amount)
+		if err != nil {
+			return res, err
+		}
+
+		currency := fx.NormalizeCurrency(object.Currency)
+		t, ok := totals[currency]
+		if !ok {
+			t = &total{CurrencyTotal: billing.CurrencyTotal{Currency: currency}}
+			totals[currency] = t
+		}
+		t.Count++
+		t.amount = t.amount.Add(amount)
+
+		base, ok, err := s.baseAmount(ctx, object, amount, baseCurrency)
+		if err != nil {
+			return res, err
+		}
+
+		if !ok {
+			t.Unconverted++
+			continue
+		}
+		t.base = t.base.Add(base)
+		baseTotal = baseTotal.Add(base)
+	}
+
+	res = billing.TotalsResponse{
+		BaseCurrency: baseCurrency,
+		BaseAmount:   baseTotal.StringFixed(2),
+		Currencies:   make([]billing.CurrencyTotal, 0, len(totals)),
+	}
+
+	for _, t := range totals {
+		t.Amount = t.amount.StringFixed(2)
+		t.BaseAmount = t.base.StringFixed(2)
+		res.Currencies = append(res.Currencies, t.CurrencyTotal)
+	}
+
+	sort.Slice(res.Currencies, func(i, j int) bool {
+		return res.Currencies[i].Currency < res.Currencies[j].Currency
+	})
+
+	return
+}
+
+// baseAmount returns the billing amount in the base currency, ok is false when no rate is known for it.
+func (s *Service) baseAmount(ctx context.Context, data billing.Entity, amount decimal.Decimal, baseCurrency string) (base decimal.Decimal, ok bool, err error) {
+	if data.BaseAmount != "" && data.BaseCurrency == baseCurrency {
+		base, err = decimal.NewFromString(data.BaseAmount)
+		return base, err == nil, err
+	}
+
+	rate, err := s.exchangeService.Rate(ctx, data.Currency, data.CreatedAt)
+	if err == fx.ErrRateNotFound {
+		return base, false, nil
+	}
+
+	if err != nil {
+		return
+	}
+
+	return rate.Convert(amount), true, nil
+}
@@ -1 +1,122 @@
 package payment
+
+import (
+	"context"
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/fx"
+	"payment-service/pkg/epay"
+	"payment-service/pkg/store"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// invoiceSucceeded is the code ePay reports on the post link for an approved payment.
+const invoiceSucceeded = "ok"
+
+// ProcessInvoice applies the result ePay posted for the billing's invoice. The post link is not authenticated,
+// so only its invoice id is taken from it: the status, amount and references are read back from ePay, and
+// an approved payment whose amount or currency differs from the billing is rejected. A billing that has
+// already left the created state is left as is, since ePay may deliver the same result twice.
+// The billing is found among those of every tenant, the rest is done within the tenant of the billing.
+func (s *Service) ProcessInvoice(ctx context.Context, invoice epay.Invoice) (err error) {
+	ctx = store.WithOperator(ctx)
+	data, err := s.billingRepository.GetByInvoiceID(ctx, invoice.InvoiceID)
+	if err != nil || data.Status != billing.StatusCreated {
+		return
+	}
+	ctx = store.WithTenant(ctx, data.TenantID)
+
+	// the gateway is asked outside of the transaction, it is a network call
+	transaction, err := s.gateway.CheckInvoice(ctx, invoice.InvoiceID)
+	if err != nil {
+		return
+	}
+	if transaction.Pending() {
+		return billing.ErrInvoicePending
+	}
+	invoice = confirmedInvoice(invoice, transaction)
+
+	return s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		data, err := s.billingRepository.GetByInvoiceID(ctx, invoice.InvoiceID)
+		if err != nil {
+			return
+		}
+
+		if data.Status != billing.StatusCreated {
+			return
+		}
+
+		eventType := billing.EventPaid
+		data.Status = billing.StatusPaid
+		data.Reference = invoice.Reference
+		data.IntReference = invoice.IntReference
+		if invoice.Code != invoiceSucceeded {
+			eventType = billing.EventFailed
+			data.Status = billing.StatusFailed
+		}
+
+		if data.Status == billing.StatusPaid {
+			if err = matchInvoice(data, invoice); err != nil {
+				return
+			}
+			paidAt := time.Now()
+			data.PaidAt = &paidAt
+			if err = s.snapshotRate(ctx, &data, paidAt); err != nil {
+				return
+			}
+		}
+
+		// the version read above fails a postlink that raced another status change
+		if err = s.billingRepository.Update(ctx, data.ID, data); err != nil {
+			return
+		}
+
+		if data.Status == billing.StatusPaid {
+			if err = s.accountingService.PostCapture(ctx, data, invoice); err != nil {
+				return
+			}
+		}
+
+		if err = s.notify(ctx, data); err != nil {
+			return
+		}
+
+		event := billing.NewEvent(data)
+		event.Reference = invoice.Reference
+		event.Reason = invoice.Reason
+
+		return s.publish(ctx, eventType, event, "")
+	})
+}
+
+// confirmedInvoice replaces what the post link claims with the state ePay keeps for the invoice.
+func confirmedInvoice(invoice epay.Invoice, transaction epay.Transaction) epay.Invoice {
+	invoice.Amount = transaction.Amount
+	invoice.AmountBonus = transaction.AmountBonus
+	invoice.Currency = transaction.Currency
+	invoice.Reference = transaction.Reference
+	invoice.IntReference = transaction.IntReference
+	invoice.Reason = transaction.Reason
+	invoice.ReasonCode = transaction.ReasonCode
+	invoice.Code = transaction.StatusName
+	if transaction.Approved() {
+		invoice.Code = invoiceSucceeded
+	}
+
+	return invoice
+}
+
+// matchInvoice checks that the payment ePay approved is the one the billing asked for.
+func matchInvoice(data billing.Entity, invoice epay.Invoice) error {
+	amount, err := decimal.NewFromString(data.Amount)
+	if err != nil {
+		return err
+	}
+
+	if !invoice.Amount.Equal(amount) || fx.NormalizeCurrency(invoice.Currency) != fx.NormalizeCurrency(data.Currency) {
+		return billing.ErrInvoiceMismatch
+	}
+
+	return nil
+}
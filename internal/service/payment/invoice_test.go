@@ -0,0 +1,158 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/ledger"
+	"payment-service/internal/repository/memory"
+	"payment-service/internal/service/accounting"
+	"payment-service/internal/service/exchange"
+	"payment-service/pkg/epay"
+	"payment-service/pkg/store"
+)
+
+// fakeGateway reports the transactions it keeps by invoice id, and refunds those that went through.
+type fakeGateway map[string]epay.Transaction
+
+var errRefundDeclined = errors.New("refund declined")
+
+func (g fakeGateway) CheckInvoice(ctx context.Context, invoiceID string) (epay.Transaction, error) {
+	return g[invoiceID], nil
+}
+
+func (g fakeGateway) RefundInvoice(ctx context.Context, invoiceID string, amount decimal.Decimal) error {
+	if !g[invoiceID].Approved() {
+		return errRefundDeclined
+	}
+	return nil
+}
+
+func TestProcessInvoice(t *testing.T) {
+	ctx := store.WithOperator(context.Background())
+	billings := memory.NewBillingRepository()
+	entries := memory.NewLedgerRepository()
+	events := memory.NewOutboxRepository()
+	transactor := memory.NewTransactor()
+
+	accountingService, err := accounting.New(
+		accounting.WithLedgerRepository(entries),
+		accounting.WithTransactor(transactor),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exchangeService, err := exchange.New(
+		exchange.WithRateRepository(memory.NewFXRepository()),
+		exchange.WithBaseCurrency("KZT"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gateway := fakeGateway{
+		"000000000001": {Amount: decimal.RequireFromString("100"), Currency: "KZT", Reference: "ref-1", StatusName: epay.StatusCharge},
+		"000000000002": {Amount: decimal.RequireFromString("100"), Currency: "KZT", StatusName: epay.StatusReject, Reason: "declined"},
+		"000000000003": {StatusName: epay.Status3D},
+		"000000000004": {Amount: decimal.RequireFromString("1"), Currency: "KZT", StatusName: epay.StatusAuth},
+		"000000000005": {Amount: decimal.RequireFromString("100"), Currency: "USD", StatusName: epay.StatusAuth},
+	}
+
+	s, err := New(
+		WithBillingRepository(billings),
+		WithOutboxRepository(events),
+		WithGateway(gateway),
+		WithAccountingService(accountingService),
+		WithExchangeService(exchangeService),
+		WithTransactor(transactor),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make(map[string]string)
+	for invoiceID := range gateway {
+		ids[invoiceID], err = billings.Create(ctx, billing.Entity{TenantID: "tenant", Amount: "100", Currency: "KZT",
+			InvoiceID: invoiceID, TerminalID: "terminal", Status: billing.StatusCreated})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// every post link claims a payment of the amount asked for, only ePay is trusted with the result;
+	// post links come without credentials, so they are processed out of any tenant scope
+	post := func(invoiceID string) error {
+		return s.ProcessInvoice(context.Background(), epay.Invoice{InvoiceID: invoiceID, Amount: decimal.RequireFromString("100"),
+			Currency: "KZT", Code: invoiceSucceeded, Reference: "forged"})
+	}
+
+	for _, tt := range []struct {
+		name, invoiceID string
+		err             error
+		status          string
+	}{
+		{"approved", "000000000001", nil, billing.StatusPaid},
+		{"rejected", "000000000002", nil, billing.StatusFailed},
+		{"pending", "000000000003", billing.ErrInvoicePending, billing.StatusCreated},
+		{"amount differs", "000000000004", billing.ErrInvoiceMismatch, billing.StatusCreated},
+		{"currency differs", "000000000005", billing.ErrInvoiceMismatch, billing.StatusCreated},
+	} {
+		if err = post(tt.invoiceID); err != tt.err {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.err)
+		}
+
+		data, err := billings.Get(ctx, ids[tt.invoiceID])
+		if err != nil || data.Status != tt.status {
+			t.Errorf("%s: got %s, err = %v, want %s", tt.name, data.Status, err, tt.status)
+		}
+	}
+
+	paid, err := billings.Get(ctx, ids["000000000001"])
+	if err != nil || paid.Reference != "ref-1" || paid.PaidAt == nil {
+		t.Errorf("got %+v, err = %v, want the reference ePay keeps", paid, err)
+	}
+
+	// ePay delivers the result again, the billing and its capture are left as they are
+	if err = post("000000000001"); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := billings.Get(ctx, paid.ID)
+	if err != nil || again.Version != paid.Version {
+		t.Errorf("got version %d, err = %v, want %d", again.Version, err, paid.Version)
+	}
+
+	captured, err := entries.SelectEntries(ctx, ids["000000000001"])
+	if err != nil || len(captured) != 1 || captured[0].Kind != ledger.KindCapture {
+		t.Errorf("got %+v, err = %v, want the capture posted once", captured, err)
+	}
+
+	for _, invoiceID := range []string{"000000000002", "000000000004"} {
+		if posted, _ := entries.SelectEntries(ctx, ids[invoiceID]); len(posted) != 0 {
+			t.Errorf("%s: got %+v, want nothing posted", invoiceID, posted)
+		}
+	}
+
+	published, err := events.Claim(ctx, 10, time.Minute)
+	if err != nil || len(published) != 2 {
+		t.Fatalf("got %d events, err = %v, want the paid and the failed ones", len(published), err)
+	}
+
+	for _, data := range published {
+		var event billing.Event
+		if err = json.Unmarshal(data.Payload, &event); err != nil {
+			t.Fatal(err)
+		}
+
+		if event.ID == ids["000000000001"] && (data.EventType != billing.EventPaid || event.Reference != "ref-1") {
+			t.Errorf("got %s %+v, want the payment with the reference ePay keeps", data.EventType, event)
+		}
+	}
+}
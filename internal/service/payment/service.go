@@ -2,6 +2,12 @@ package payment
 
 import (
 	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/dispute"
+	"payment-service/internal/domain/outbox"
+	"payment-service/internal/domain/tenant"
+	"payment-service/internal/service/accounting"
+	"payment-service/internal/service/exchange"
+	"payment-service/pkg/store"
 )
 
 // Configuration is an alias for a function that will take in a pointer to a Service and modify it
@@ -11,13 +17,28 @@ type Configuration func(s *Service) error
 type Service struct {
 	billingRepository billing.Repository
 	billingCache      billing.Cache
+	outboxRepository  outbox.Repository
+	disputeRepository dispute.Repository
+	tenantRepository  tenant.Repository
+
+	gateway billing.Gateway
+
+	accountingService *accounting.Service
+	exchangeService   *exchange.Service
+
+	observers []billing.Observer
+
+	transactor store.Transactor
+
+	// subjectIndex computes the subject keys of the billings, see billing.SubjectKeys
+	subjectIndex billing.Index
 }
 
 // New takes a variable amount of Configuration functions and returns a new Service
 // Each Configuration will be called in the order they are passed in
 func New(configs ...Configuration) (s *Service, err error) {
 	// Create the service
-	s = &Service{}
+	s = &Service{subjectIndex: billing.PlainIndex}
 
 	// Apply all Configurations passed in
 	for _, cfg := range configs {
@@ -39,7 +60,7 @@ func WithBillingRepository(billingRepository billing.Repository) Configuration {
 	}
 }
 
-// WithBillingCache applies a given category cache to the Service
+// WithBillingCache applies a given billing cache to the Service, billings are read through it
 func WithBillingCache(billingCache billing.Cache) Configuration {
 	// return a function that matches the Configuration alias,
 	// You need to return this so that the parent function can take in all the needed parameters
@@ -48,3 +69,94 @@ func WithBillingCache(billingCache billing.Cache) Configuration {
 		return nil
 	}
 }
+
+// WithOutboxRepository applies a given outbox repository to the Service
+func WithOutboxRepository(outboxRepository outbox.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.outboxRepository = outboxRepository
+		return nil
+	}
+}
+
+// WithDisputeRepository applies a given dispute repository to the Service
+func WithDisputeRepository(disputeRepository dispute.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.disputeRepository = disputeRepository
+		return nil
+	}
+}
+
+// WithTenantRepository applies a given tenant repository to the Service, billings take the defaults of their tenant
+func WithTenantRepository(tenantRepository tenant.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.tenantRepository = tenantRepository
+		return nil
+	}
+}
+
+// WithGateway applies a given payment gateway to the Service, the results posted for invoices are confirmed through it
+func WithGateway(gateway billing.Gateway) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.gateway = gateway
+		return nil
+	}
+}
+
+// WithTransactor applies a given transactor to the Service, billing changes and their events are written through it
+func WithTransactor(transactor store.Transactor) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.transactor = transactor
+		return nil
+	}
+}
+
+// WithAccountingService applies a given accounting service to the Service, billing changes are posted to the ledger through it
+func WithAccountingService(accountingService *accounting.Service) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.accountingService = accountingService
+		return nil
+	}
+}
+
+// WithExchangeService applies a given exchange service to the Service, billings snapshot the rate to the base currency through it
+func WithExchangeService(exchangeService *exchange.Service) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.exchangeService = exchangeService
+		return nil
+	}
+}
+
+// WithObserver adds a given observer to the Service, it is notified of every billing status change
+func WithObserver(observer billing.Observer) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.observers = append(s.observers, observer)
+		return nil
+	}
+}
+
+// WithSubjectIndex applies the blind index of the identifiers of the payers to the Service, it has to be the one
+// of the billing repository, billing.PlainIndex by default
+func WithSubjectIndex(subjectIndex billing.Index) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.subjectIndex = subjectIndex
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package reconciliation
+
+import (
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/settlement"
+	"payment-service/pkg/store"
+)
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service is an implementation of the Service
+type Service struct {
+	settlementRepository settlement.Repository
+	billingRepository    billing.Repository
+
+	transactor store.Transactor
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Create the service
+	s = &Service{}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WithSettlementRepository applies a given settlement repository to the Service
+func WithSettlementRepository(settlementRepository settlement.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.settlementRepository = settlementRepository
+		return nil
+	}
+}
+
+// WithBillingRepository applies a given billing repository to the Service
+func WithBillingRepository(billingRepository billing.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.billingRepository = billingRepository
+		return nil
+	}
+}
+
+// WithTransactor applies a given transactor to the Service, a statement and its report are written through it
+func WithTransactor(transactor store.Transactor) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.transactor = transactor
+		return nil
+	}
+}
@@ -0,0 +1,192 @@
+package reconciliation
+
+import (
+	"context"
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/settlement"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ImportStatement matches the statement rows to billings by reference, internal reference and invoice id,
+// in that order, and stores the statement with the discrepancies found.
+// Billings paid on the statement day that no row matched are reported as missing, whenever they were created,
+// and a row matching a billing another row matched already is reported as a duplicate.
+func (s *Service) ImportStatement(ctx context.Context, req settlement.Request, records [][]string) (res settlement.Response, err error) {
+	rows, err := settlement.ParseRows(records)
+	if err != nil {
+		return
+	}
+
+	data := settlement.Statement{
+		Date:      req.Date,
+		Filename:  req.Filename,
+		RowsTotal: len(rows),
+	}
+
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		for _, key := range []string{row.Reference, row.IntReference, row.InvoiceID} {
+			if key != "" {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	candidates, err := s.billingRepository.SelectByFilter(ctx, billing.Filter{Keys: keys})
+	if err != nil {
+		return
+	}
+
+	expected, err := s.billingRepository.SelectByFilter(ctx, billing.Filter{
+		Statuses: []string{billing.StatusPaid, billing.StatusPartiallyRefunded, billing.StatusRefunded},
+		PaidFrom: req.Date,
+		PaidTo:   req.Date.Add(24 * time.Hour),
+	})
+	if err != nil {
+		return
+	}
+
+	index := newBillingIndex(candidates)
+
+	matched := make(map[string]bool)
+	for i, row := range rows {
+		data.TotalAmount = data.TotalAmount.Add(row.Amount)
+		data.TotalFee = data.TotalFee.Add(row.Fee)
+
+		found, ok := index.lookup(row)
+		if !ok {
+			data.Discrepancies = append(data.Discrepancies, settlement.Discrepancy{
+				Kind:      settlement.KindExtra,
+				Line:      row.Line,
+				InvoiceID: row.InvoiceID,
+				Reference: row.Reference,
+				Actual:    row.Amount,
+			})
+			continue
+		}
+
+		rows[i].BillingID = found.ID
+		amount, _ := decimal.NewFromString(found.Amount)
+
+		// the bank settling a payment twice is worth a look whatever the amounts
+		if matched[found.ID] {
+			data.Discrepancies = append(data.Discrepancies, settlement.Discrepancy{
+				Kind:      settlement.KindDuplicate,
+				Line:      row.Line,
+				BillingID: found.ID,
+				InvoiceID: found.InvoiceID,
+				Reference: row.Reference,
+				Expected:  amount,
+				Actual:    row.Amount,
+			})
+			continue
+		}
+		matched[found.ID] = true
+		data.Matched++
+
+		if !amount.Equal(row.Amount) {
+			data.Discrepancies = append(data.Discrepancies, settlement.Discrepancy{
+				Kind:      settlement.KindAmountMismatch,
+				Line:      row.Line,
+				BillingID: found.ID,
+				InvoiceID: found.InvoiceID,
+				Reference: row.Reference,
+				Expected:  amount,
+				Actual:    row.Amount,
+			})
+		}
+	}
+
+	for _, paid := range expected {
+		if matched[paid.ID] {
+			continue
+		}
+
+		amount, _ := decimal.NewFromString(paid.Amount)
+		data.Discrepancies = append(data.Discrepancies, settlement.Discrepancy{
+			Kind:      settlement.KindMissing,
+			BillingID: paid.ID,
+			InvoiceID: paid.InvoiceID,
+			Reference: paid.Reference,
+			Expected:  amount,
+		})
+	}
+	data.Rows = rows
+
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		data.ID, err = s.settlementRepository.Create(ctx, data)
+		return
+	})
+	if err != nil {
+		return
+	}
+	data.CreatedAt = time.Now()
+	res = settlement.ParseFromEntity(data)
+
+	return
+}
+
+func (s *Service) ListStatements(ctx context.Context) (res []settlement.Response, err error) {
+	data, err := s.settlementRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	res = settlement.ParseFromEntities(data)
+
+	return
+}
+
+func (s *Service) GetStatement(ctx context.Context, id string) (res settlement.Response, err error) {
+	data, err := s.settlementRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+	res = settlement.ParseFromEntity(data)
+
+	return
+}
+
+// billingIndex finds the billing of a statement row by any of its keys.
+type billingIndex struct {
+	byReference    map[string]billing.Entity
+	byIntReference map[string]billing.Entity
+	byInvoiceID    map[string]billing.Entity
+}
+
+func newBillingIndex(data []billing.Entity) billingIndex {
+	index := billingIndex{
+		byReference:    make(map[string]billing.Entity),
+		byIntReference: make(map[string]billing.Entity),
+		byInvoiceID:    make(map[string]billing.Entity),
+	}
+
+	for _, object := range data {
+		if object.Reference != "" {
+			index.byReference[object.Reference] = object
+		}
+		if object.IntReference != "" {
+			index.byIntReference[object.IntReference] = object
+		}
+		if object.InvoiceID != "" {
+			index.byInvoiceID[object.InvoiceID] = object
+		}
+	}
+
+	return index
+}
+
+func (i billingIndex) lookup(row settlement.Row) (data billing.Entity, ok bool) {
+	if data, ok = i.byReference[row.Reference]; ok {
+		return
+	}
+
+	if data, ok = i.byIntReference[row.IntReference]; ok {
+		return
+	}
+
+	data, ok = i.byInvoiceID[row.InvoiceID]
+
+	return
+}
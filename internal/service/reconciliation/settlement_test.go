@@ -0,0 +1,78 @@
+package reconciliation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/settlement"
+	"payment-service/internal/repository/memory"
+	"payment-service/pkg/store"
+)
+
+func TestImportStatement(t *testing.T) {
+	ctx := store.WithOperator(context.Background())
+	billings := memory.NewBillingRepository()
+
+	s, err := New(
+		WithSettlementRepository(memory.NewSettlementRepository()),
+		WithBillingRepository(billings),
+		WithTransactor(memory.NewTransactor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	paid := func(reference, amount string, paidAt time.Time) string {
+		id, err := billings.Create(ctx, billing.Entity{Amount: amount, Currency: "KZT", InvoiceID: "inv-" + reference,
+			Reference: reference, Status: billing.StatusPaid, PaidAt: &paidAt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	// the billings are created today, they are expected on the day they were paid
+	matched := paid("R1", "100", day.Add(2*time.Hour))
+	mismatched := paid("R2", "50", day.Add(3*time.Hour))
+	missing := paid("R3", "70", day.Add(23*time.Hour))
+	paid("R4", "80", day.Add(-time.Hour))
+
+	records := [][]string{
+		{"reference", "amount", "fee"},
+		{"R1", "100", "1"},
+		{"R2", "45", "0.5"},
+		{"R1", "100", "1"},
+		{"X9", "10", "0"},
+	}
+
+	res, err := s.ImportStatement(ctx, settlement.Request{Date: day, Filename: "statement.csv"}, records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.RowsTotal != 4 || res.Matched != 2 || res.TotalAmount != "255" || res.TotalFee != "2.5" {
+		t.Errorf("got %+v", res)
+	}
+
+	want := map[string]settlement.DiscrepancyResponse{
+		settlement.KindAmountMismatch: {Line: 3, BillingID: mismatched, Expected: "50", Actual: "45"},
+		settlement.KindDuplicate:      {Line: 4, BillingID: matched, Expected: "100", Actual: "100"},
+		settlement.KindExtra:          {Line: 5, Actual: "10"},
+		settlement.KindMissing:        {BillingID: missing, Expected: "70"},
+	}
+
+	if len(res.Discrepancies) != len(want) {
+		t.Fatalf("got %+v, want %d discrepancies", res.Discrepancies, len(want))
+	}
+
+	for _, got := range res.Discrepancies {
+		expected, ok := want[got.Kind]
+		if !ok || got.Line != expected.Line || got.BillingID != expected.BillingID ||
+			got.Expected != expected.Expected || got.Actual != expected.Actual {
+			t.Errorf("got %+v, want %+v", got, expected)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package stock
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/inventory"
+	"payment-service/pkg/store"
+)
+
+// Reserve holds the items until the reservation expires, all of them or none with inventory.ErrInsufficientStock.
+// The reservation is attached to its order and billing once they exist.
+func (s *Service) Reserve(ctx context.Context, items []inventory.Item) (id string, err error) {
+	err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+		allocations, err := s.allocate(ctx, items)
+		if err != nil {
+			return
+		}
+
+		id, err = s.inventoryRepository.CreateReservation(ctx, inventory.Reservation{
+			Status:      inventory.StatusActive,
+			ExpiresAt:   time.Now().Add(s.reservationTTL),
+			Allocations: allocations,
+		})
+		return
+	})
+
+	return
+}
+
+// Attach links the reservation to the order and the billing that settles it.
+func (s *Service) Attach(ctx context.Context, id, orderID, billingID string) (err error) {
+	return s.inventoryRepository.UpdateReservation(ctx, id, inventory.Reservation{
+		OrderID:   orderID,
+		BillingID: billingID,
+	})
+}
+
+// BillingChanged commits the reservation of a paid billing and releases the one of a failed billing,
+// billings without a reservation are skipped. It runs within the transaction that changed the billing.
+func (s *Service) BillingChanged(ctx context.Context, data billing.Entity) (err error) {
+	if data.Status != billing.StatusPaid && data.Status != billing.StatusFailed {
+		return
+	}
+
+	reservation, err := s.inventoryRepository.GetReservationByBillingID(ctx, data.ID)
+	if err == store.ErrorNotFound {
+		return nil
+	}
+
+	if err != nil {
+		return
+	}
+
+	switch {
+	case reservation.Status == inventory.StatusActive && data.Status == billing.StatusPaid:
+		return s.settle(ctx, reservation, inventory.StatusCommitted)
+
+	case reservation.Status == inventory.StatusActive && data.Status == billing.StatusFailed:
+		return s.settle(ctx, reservation, inventory.StatusReleased)
+
+	case reservation.Status == inventory.StatusReleased && data.Status == billing.StatusPaid:
+		return s.recommit(ctx, reservation)
+	}
+
+	return
+}
+
+// ReleaseExpired returns the stock of active reservations whose billing wasn't paid in time.
+func (s *Service) ReleaseExpired(ctx context.Context) (count int, err error) {
+	reservations, err := s.inventoryRepository.SelectExpiredReservations(ctx, time.Now())
+	if err != nil {
+		return
+	}
+
+	for _, expired := range reservations {
+		released := false
+		err = s.transactor.Transact(ctx, func(ctx context.Context) (err error) {
+			// the billing may have been paid since the reservations were selected
+			reservation, err := s.inventoryRepository.GetReservation(ctx, expired.ID)
+			if err != nil || reservation.Status != inventory.StatusActive {
+				return
+			}
+			released = true
+
+			return s.settle(ctx, reservation, inventory.StatusReleased)
+		})
+		if err != nil {
+			return
+		}
+
+		if released {
+			count++
+		}
+	}
+
+	return
+}
+
+// settle takes the reserved stock off hand when committed or returns it when released.
+func (s *Service) settle(ctx context.Context, reservation inventory.Reservation, status string) (err error) {
+	commit := status == inventory.StatusCommitted
+	if err = s.inventoryRepository.Deallocate(ctx, reservation.Allocations, commit); err != nil {
+		return
+	}
+
+	return s.inventoryRepository.UpdateReservation(ctx, reservation.ID, inventory.Reservation{Status: status})
+}
+
+// recommit reserves the stock of a billing paid after its reservation expired again and commits it.
+// The payment stands when the stock ran out in the meantime, the reservation is marked backordered instead.
+func (s *Service) recommit(ctx context.Context, reservation inventory.Reservation) (err error) {
+	items := make([]inventory.Item, 0, len(reservation.Allocations))
+	for _, allocation := range reservation.Allocations {
+		items = append(items, inventory.Item{ProductID: allocation.ProductID, Quantity: allocation.Quantity})
+	}
+
+	allocations, err := s.allocate(ctx, items)
+	if err == inventory.ErrInsufficientStock {
+		return s.inventoryRepository.UpdateReservation(ctx, reservation.ID, inventory.Reservation{Status: inventory.StatusBackordered})
+	}
+
+	if err != nil {
+		return
+	}
+	reservation.Allocations = allocations
+
+	return s.settle(ctx, reservation, inventory.StatusCommitted)
+}
+
+// allocate reserves every product of the items. Products are allocated in key order so that concurrent
+// reservations lock stock rows in the same order, allocations already made are returned when one fails.
+func (s *Service) allocate(ctx context.Context, items []inventory.Item) (allocations []inventory.Allocation, err error) {
+	quantities := make(map[string]decimal.Decimal)
+	for _, item := range items {
+		quantities[item.ProductID] = quantities[item.ProductID].Add(item.Quantity)
+	}
+
+	productIDs := make([]string, 0, len(quantities))
+	for productID := range quantities {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Strings(productIDs)
+
+	for _, productID := range productIDs {
+		allocated, err := s.inventoryRepository.Allocate(ctx, productID, quantities[productID])
+		if err != nil {
+			if len(allocations) > 0 {
+				if returnErr := s.inventoryRepository.Deallocate(ctx, allocations, false); returnErr != nil {
+					return nil, returnErr
+				}
+			}
+			return nil, err
+		}
+		allocations = append(allocations, allocated...)
+	}
+
+	return
+}
@@ -0,0 +1,278 @@
+package stock
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"payment-service/internal/domain/billing"
+	"payment-service/internal/domain/inventory"
+	"payment-service/internal/domain/product"
+	"payment-service/internal/repository/memory"
+	"payment-service/pkg/store"
+)
+
+// newStock sets up the service with the products in stock, every product has its quantities spread across warehouses.
+func newStock(t *testing.T, ctx context.Context, ttl time.Duration, stock map[string][]string) (*Service, *memory.InventoryRepository, map[string]string) {
+	t.Helper()
+	inventories := memory.NewInventoryRepository()
+	products := memory.NewProductRepository()
+
+	s, err := New(
+		WithInventoryRepository(inventories),
+		WithProductRepository(products),
+		WithTransactor(memory.NewTransactor()),
+		WithReservationTTL(ttl),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var warehouses []string
+	productIDs := make(map[string]string)
+	for name, quantities := range stock {
+		productID, err := products.Create(ctx, product.Entity{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		productIDs[name] = productID
+
+		for i, onHand := range quantities {
+			if i == len(warehouses) {
+				warehouse, err := s.AddWarehouse(ctx, inventory.WarehouseRequest{Code: string(rune('A' + i))})
+				if err != nil {
+					t.Fatal(err)
+				}
+				warehouses = append(warehouses, warehouse.ID)
+			}
+
+			err = s.SetStock(ctx, inventory.StockRequest{ProductID: productID, WarehouseID: warehouses[i], OnHand: onHand})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	return s, inventories, productIDs
+}
+
+// checkStock compares the quantities on hand and reserved of the product summed across its warehouses.
+func checkStock(t *testing.T, ctx context.Context, s *Service, productID, onHand, reserved string) {
+	t.Helper()
+	stocks, err := s.ListStock(ctx, productID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotOnHand, gotReserved := decimal.Zero, decimal.Zero
+	for _, data := range stocks {
+		gotOnHand = gotOnHand.Add(decimal.RequireFromString(data.OnHand))
+		gotReserved = gotReserved.Add(decimal.RequireFromString(data.Reserved))
+	}
+
+	if !gotOnHand.Equal(decimal.RequireFromString(onHand)) || !gotReserved.Equal(decimal.RequireFromString(reserved)) {
+		t.Errorf("got %s on hand with %s reserved, want %s with %s", gotOnHand, gotReserved, onHand, reserved)
+	}
+}
+
+func TestReserve(t *testing.T) {
+	ctx := store.WithTenant(context.Background(), "tenant")
+
+	t.Run("across warehouses", func(t *testing.T) {
+		s, _, ids := newStock(t, ctx, time.Hour, map[string][]string{"juice": {"3", "5"}, "bread": {"2"}})
+
+		_, err := s.Reserve(ctx, []inventory.Item{
+			{ProductID: ids["juice"], Quantity: decimal.NewFromInt(4)},
+			{ProductID: ids["bread"], Quantity: decimal.NewFromInt(1)},
+			{ProductID: ids["juice"], Quantity: decimal.NewFromInt(3)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkStock(t, ctx, s, ids["juice"], "8", "7")
+		checkStock(t, ctx, s, ids["bread"], "2", "1")
+	})
+
+	t.Run("all or none", func(t *testing.T) {
+		s, _, ids := newStock(t, ctx, time.Hour, map[string][]string{"juice": {"3"}, "bread": {"2"}})
+
+		// the juice is allocated first and returned once the bread runs short
+		_, err := s.Reserve(ctx, []inventory.Item{
+			{ProductID: ids["juice"], Quantity: decimal.NewFromInt(2)},
+			{ProductID: ids["bread"], Quantity: decimal.NewFromInt(3)},
+		})
+		if err != inventory.ErrInsufficientStock {
+			t.Errorf("err = %v, want %v", err, inventory.ErrInsufficientStock)
+		}
+		checkStock(t, ctx, s, ids["juice"], "3", "0")
+		checkStock(t, ctx, s, ids["bread"], "2", "0")
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		s, _, ids := newStock(t, ctx, time.Hour, map[string][]string{"juice": {"6", "4"}, "bread": {"7"}})
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		reserved, short := 0, 0
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := s.Reserve(ctx, []inventory.Item{
+					{ProductID: ids["juice"], Quantity: decimal.NewFromInt(1)},
+					{ProductID: ids["bread"], Quantity: decimal.NewFromInt(1)},
+				})
+
+				mu.Lock()
+				defer mu.Unlock()
+				switch err {
+				case nil:
+					reserved++
+				case inventory.ErrInsufficientStock:
+					short++
+				default:
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		// the bread runs out first, the juice allocated to the reservations it failed is returned
+		if reserved != 7 || short != 13 {
+			t.Errorf("got %d reserved and %d short, want 7 and 13", reserved, short)
+		}
+		checkStock(t, ctx, s, ids["juice"], "10", "7")
+		checkStock(t, ctx, s, ids["bread"], "7", "7")
+	})
+}
+
+func TestBillingChanged(t *testing.T) {
+	ctx := store.WithTenant(context.Background(), "tenant")
+
+	reserve := func(t *testing.T, s *Service, productID, billingID string) string {
+		t.Helper()
+		id, err := s.Reserve(ctx, []inventory.Item{{ProductID: productID, Quantity: decimal.NewFromInt(2)}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = s.Attach(ctx, id, "order", billingID); err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	checkStatus := func(t *testing.T, inventories *memory.InventoryRepository, id, status string) {
+		t.Helper()
+		if data, err := inventories.GetReservation(ctx, id); err != nil || data.Status != status {
+			t.Errorf("got %s, err = %v, want %s", data.Status, err, status)
+		}
+	}
+
+	t.Run("paid", func(t *testing.T) {
+		s, inventories, ids := newStock(t, ctx, time.Hour, map[string][]string{"juice": {"5"}})
+		id := reserve(t, s, ids["juice"], "billing")
+
+		// a billing that is not settled yet keeps the stock reserved
+		if err := s.BillingChanged(ctx, billing.Entity{ID: "billing", Status: billing.StatusCreated}); err != nil {
+			t.Fatal(err)
+		}
+		checkStatus(t, inventories, id, inventory.StatusActive)
+
+		if err := s.BillingChanged(ctx, billing.Entity{ID: "billing", Status: billing.StatusPaid}); err != nil {
+			t.Fatal(err)
+		}
+		checkStatus(t, inventories, id, inventory.StatusCommitted)
+		checkStock(t, ctx, s, ids["juice"], "3", "0")
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		s, inventories, ids := newStock(t, ctx, time.Hour, map[string][]string{"juice": {"5"}})
+		id := reserve(t, s, ids["juice"], "billing")
+
+		if err := s.BillingChanged(ctx, billing.Entity{ID: "billing", Status: billing.StatusFailed}); err != nil {
+			t.Fatal(err)
+		}
+		checkStatus(t, inventories, id, inventory.StatusReleased)
+		checkStock(t, ctx, s, ids["juice"], "5", "0")
+	})
+
+	t.Run("no reservation", func(t *testing.T) {
+		s, _, _ := newStock(t, ctx, time.Hour, nil)
+		if err := s.BillingChanged(ctx, billing.Entity{ID: "other", Status: billing.StatusPaid}); err != nil {
+			t.Errorf("err = %v, want billings without a reservation skipped", err)
+		}
+	})
+
+	t.Run("paid after expiry", func(t *testing.T) {
+		s, inventories, ids := newStock(t, ctx, time.Nanosecond, map[string][]string{"juice": {"5"}})
+		id := reserve(t, s, ids["juice"], "billing")
+
+		count, err := s.ReleaseExpired(ctx)
+		if err != nil || count != 1 {
+			t.Fatalf("got %d released, err = %v, want 1", count, err)
+		}
+		checkStatus(t, inventories, id, inventory.StatusReleased)
+		checkStock(t, ctx, s, ids["juice"], "5", "0")
+
+		// the stock is still there, so it is taken again
+		if err = s.BillingChanged(ctx, billing.Entity{ID: "billing", Status: billing.StatusPaid}); err != nil {
+			t.Fatal(err)
+		}
+		checkStatus(t, inventories, id, inventory.StatusCommitted)
+		checkStock(t, ctx, s, ids["juice"], "3", "0")
+	})
+
+	t.Run("paid after the stock ran out", func(t *testing.T) {
+		s, inventories, ids := newStock(t, ctx, time.Nanosecond, map[string][]string{"juice": {"3"}})
+		id := reserve(t, s, ids["juice"], "billing")
+
+		if _, err := s.ReleaseExpired(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		// another order takes the stock while the first billing is still being paid
+		reserve(t, s, ids["juice"], "other")
+
+		if err := s.BillingChanged(ctx, billing.Entity{ID: "billing", Status: billing.StatusPaid}); err != nil {
+			t.Fatal(err)
+		}
+		checkStatus(t, inventories, id, inventory.StatusBackordered)
+		checkStock(t, ctx, s, ids["juice"], "3", "2")
+	})
+}
+
+func TestReleaseExpired(t *testing.T) {
+	ctx := store.WithTenant(context.Background(), "tenant")
+	s, inventories, ids := newStock(t, ctx, time.Hour, map[string][]string{"juice": {"5"}})
+
+	active, err := s.Reserve(ctx, []inventory.Item{{ProductID: ids["juice"], Quantity: decimal.NewFromInt(1)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.reservationTTL = time.Nanosecond
+	expired, err := s.Reserve(ctx, []inventory.Item{{ProductID: ids["juice"], Quantity: decimal.NewFromInt(2)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := s.ReleaseExpired(ctx)
+	if err != nil || count != 1 {
+		t.Fatalf("got %d released, err = %v, want 1", count, err)
+	}
+	checkStock(t, ctx, s, ids["juice"], "5", "1")
+
+	for id, status := range map[string]string{active: inventory.StatusActive, expired: inventory.StatusReleased} {
+		if data, err := inventories.GetReservation(ctx, id); err != nil || data.Status != status {
+			t.Errorf("got %s, err = %v, want %s", data.Status, err, status)
+		}
+	}
+
+	// a released reservation is not released twice
+	if count, err = s.ReleaseExpired(ctx); err != nil || count != 0 {
+		t.Errorf("got %d released, err = %v, want none", count, err)
+	}
+}
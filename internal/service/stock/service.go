@@ -0,0 +1,86 @@
+package stock
+
+import (
+	"time"
+
+	"payment-service/internal/domain/inventory"
+	"payment-service/internal/domain/product"
+	"payment-service/pkg/store"
+)
+
+// defaultReservationTTL holds stock for about as long as a customer stays on the pay page
+const defaultReservationTTL = 30 * time.Minute
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service is an implementation of the Service
+type Service struct {
+	inventoryRepository inventory.Repository
+	productRepository   product.Repository
+
+	transactor store.Transactor
+
+	reservationTTL time.Duration
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Create the service
+	s = &Service{
+		reservationTTL: defaultReservationTTL,
+	}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WithInventoryRepository applies a given inventory repository to the Service
+func WithInventoryRepository(inventoryRepository inventory.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.inventoryRepository = inventoryRepository
+		return nil
+	}
+}
+
+// WithProductRepository applies a given product repository to the Service, stock is only set for a product
+// the caller sees
+func WithProductRepository(productRepository product.Repository) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.productRepository = productRepository
+		return nil
+	}
+}
+
+// WithTransactor applies a given transactor to the Service, a reservation and its stock are written through it
+func WithTransactor(transactor store.Transactor) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		s.transactor = transactor
+		return nil
+	}
+}
+
+// WithReservationTTL applies how long stock stays reserved for an unpaid billing
+func WithReservationTTL(ttl time.Duration) Configuration {
+	// return a function that matches the Configuration alias,
+	// You need to return this so that the parent function can take in all the needed parameters
+	return func(s *Service) error {
+		if ttl > 0 {
+			s.reservationTTL = ttl
+		}
+		return nil
+	}
+}
@@ -0,0 +1,66 @@
+package stock
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+
+	"payment-service/internal/domain/inventory"
+)
+
+func (s *Service) ListWarehouses(ctx context.Context) (res []inventory.WarehouseResponse, err error) {
+	data, err := s.inventoryRepository.SelectWarehouses(ctx)
+	if err != nil {
+		return
+	}
+	res = inventory.ParseFromWarehouses(data)
+
+	return
+}
+
+func (s *Service) AddWarehouse(ctx context.Context, req inventory.WarehouseRequest) (res inventory.WarehouseResponse, err error) {
+	data := inventory.Warehouse{
+		Code: req.Code,
+		Name: req.Name,
+	}
+
+	data.ID, err = s.inventoryRepository.CreateWarehouse(ctx, data)
+	if err != nil {
+		return
+	}
+	res = inventory.ParseFromWarehouse(data)
+
+	return
+}
+
+// ListStock returns the stock of the product in every warehouse, or of all products when productID is empty.
+func (s *Service) ListStock(ctx context.Context, productID string) (res []inventory.StockResponse, err error) {
+	data, err := s.inventoryRepository.SelectStock(ctx, productID)
+	if err != nil {
+		return
+	}
+	res = inventory.ParseFromStocks(data)
+
+	return
+}
+
+// SetStock records the counted quantity of the product in the warehouse, it can't drop below what is reserved.
+// The product and the warehouse have to belong to the tenant of the caller.
+func (s *Service) SetStock(ctx context.Context, req inventory.StockRequest) (err error) {
+	onHand, err := decimal.NewFromString(req.OnHand)
+	if err != nil {
+		return
+	}
+
+	data, err := s.productRepository.Get(ctx, req.ProductID)
+	if err != nil {
+		return
+	}
+
+	return s.inventoryRepository.SetStock(ctx, inventory.Stock{
+		TenantID:    data.TenantID,
+		ProductID:   req.ProductID,
+		WarehouseID: req.WarehouseID,
+		OnHand:      onHand,
+	})
+}
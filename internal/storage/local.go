@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("storage: key must be a relative path without dot segments")
+
+// Local keeps files in a directory, the service serves the directory itself under baseURL.
+type Local struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocal(dir, baseURL string) *Local {
+	return &Local{
+		dir:     dir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Put writes the file next to its final path first and renames it, so a reader never sees half of it.
+func (s *Local) Put(ctx context.Context, key, contentType string, data []byte) (url string, err error) {
+	path, err := s.path(key)
+	if err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(file.Name())
+
+	if _, err = file.Write(data); err != nil {
+		file.Close()
+		return
+	}
+
+	if err = file.Close(); err != nil {
+		return
+	}
+
+	if err = os.Chmod(file.Name(), 0o644); err != nil {
+		return
+	}
+
+	if err = os.Rename(file.Name(), path); err != nil {
+		return
+	}
+
+	return s.baseURL + "/" + key, nil
+}
+
+func (s *Local) Delete(ctx context.Context, key string) (err error) {
+	path, err := s.path(key)
+	if err != nil {
+		return
+	}
+
+	if err = os.Remove(path); errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+
+	return
+}
+
+func (s *Local) path(key string) (path string, err error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "..") {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewLocal(dir, "https://example.com/files/")
+
+	t.Run("put", func(t *testing.T) {
+		url, err := s.Put(ctx, "products/42/photo.png", "image/png", []byte("picture"))
+		if err != nil || url != "https://example.com/files/products/42/photo.png" {
+			t.Fatalf("got %s, err = %v, want the url of the file", url, err)
+		}
+
+		path := filepath.Join(dir, "products", "42", "photo.png")
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != "picture" {
+			t.Errorf("got %q, err = %v, want the picture written", data, err)
+		}
+
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o644 {
+			t.Errorf("got %v, err = %v, want it readable by the server", info.Mode(), err)
+		}
+
+		// the temporary file is renamed into place, nothing else is left behind
+		entries, err := os.ReadDir(filepath.Dir(path))
+		if err != nil || len(entries) != 1 {
+			t.Errorf("got %d files, err = %v, want only the picture", len(entries), err)
+		}
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		for _, data := range []string{"first", "second"} {
+			if _, err := s.Put(ctx, "products/43.png", "image/png", []byte(data)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if data, err := os.ReadFile(filepath.Join(dir, "products", "43.png")); err != nil || string(data) != "second" {
+			t.Errorf("got %q, err = %v, want the second picture", data, err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if _, err := s.Put(ctx, "products/44.png", "image/png", []byte("picture")); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := s.Delete(ctx, "products/44.png"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "products", "44.png")); !os.IsNotExist(err) {
+			t.Errorf("err = %v, want the picture removed", err)
+		}
+
+		// a missing file is deleted as well
+		if err := s.Delete(ctx, "products/44.png"); err != nil {
+			t.Errorf("err = %v, want nil", err)
+		}
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		for _, key := range []string{"", "/etc/passwd", "../outside.png", "products/../../outside.png"} {
+			if _, err := s.Put(ctx, key, "image/png", []byte("picture")); err != ErrInvalidKey {
+				t.Errorf("got %v for %q, want %v", err, key, ErrInvalidKey)
+			}
+			if err := s.Delete(ctx, key); err != ErrInvalidKey {
+				t.Errorf("got %v for %q, want %v", err, key, ErrInvalidKey)
+			}
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// S3Config points at a bucket of an S3-compatible service, e.g. http://localhost:9000 for a local MinIO.
+// Objects are addressed path-style as Endpoint/Bucket/key, PublicURL replaces Endpoint/Bucket in the returned URLs
+// when the files are served through a CDN or a public host.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string
+}
+
+// S3 keeps files in a bucket, requests are signed with AWS Signature Version 4.
+type S3 struct {
+	client *http.Client
+	config S3Config
+}
+
+func NewS3(config S3Config) *S3 {
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	if config.PublicURL == "" {
+		config.PublicURL = config.Endpoint + "/" + config.Bucket
+	}
+	config.PublicURL = strings.TrimRight(config.PublicURL, "/")
+
+	return &S3{
+		client: &http.Client{Timeout: 30 * time.Second},
+		config: config,
+	}
+}
+
+func (s *S3) Put(ctx context.Context, key, contentType string, data []byte) (url string, err error) {
+	req, err := s.request(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, data, time.Now())
+
+	if err = s.do(req); err != nil {
+		return
+	}
+
+	return s.config.PublicURL + "/" + escapePath(key), nil
+}
+
+// Delete succeeds for a missing object too, as S3 does.
+func (s *S3) Delete(ctx context.Context, key string) (err error) {
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return
+	}
+	s.sign(req, nil, time.Now())
+
+	return s.do(req)
+}
+
+func (s *S3) request(ctx context.Context, method, key string, data []byte) (*http.Request, error) {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return nil, ErrInvalidKey
+	}
+
+	url := s.config.Endpoint + "/" + escapePath(s.config.Bucket+"/"+key)
+
+	return http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+}
+
+func (s *S3) do(req *http.Request) (err error) {
+	res, err := s.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		err = fmt.Errorf("s3: %s %s: unexpected status %d", req.Method, req.URL.Path, res.StatusCode)
+	}
+
+	return
+}
+
+// sign adds the Authorization header of Signature Version 4, the payload hash is signed along with the headers.
+func (s *S3) sign(req *http.Request, data []byte, now time.Time) {
+	now = now.UTC()
+	date := now.Format("20060102")
+	timestamp := now.Format("20060102T150405Z")
+
+	payloadHash := sha256.Sum256(data)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	req.Header.Set("X-Amz-Date", timestamp)
+
+	names := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := []string{req.URL.Host, req.Header.Get("X-Amz-Content-Sha256"), timestamp}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		names = append([]string{"content-type"}, names...)
+		values = append([]string{contentType}, values...)
+	}
+
+	var headers strings.Builder
+	for i, name := range names {
+		headers.WriteString(name + ":" + strings.TrimSpace(values[i]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		headers.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + timestamp + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+// escapePath percent-encodes every byte of the path but the unreserved characters and slashes, as Signature Version 4 expects.
+func escapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 keeps the objects of one bucket in memory, it checks that every request is signed for its payload.
+type fakeS3 struct {
+	bucket  string
+	objects map[string]fakeObject
+	sync.Mutex
+}
+
+type fakeObject struct {
+	contentType string
+	data        []byte
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	key := strings.TrimPrefix(r.URL.EscapedPath(), "/"+s.bucket+"/")
+	if key == r.URL.EscapedPath() {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		object, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Write(object.data)
+		return
+	}
+
+	data, _ := io.ReadAll(r.Body)
+	hash := sha256.Sum256(data)
+	authorization := r.Header.Get("Authorization")
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(hash[:]) || r.Header.Get("X-Amz-Date") == "" ||
+		!strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=access/") ||
+		!strings.Contains(authorization, "/eu-central-1/s3/aws4_request, SignedHeaders=") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if !strings.Contains(authorization, "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date,") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		s.objects[key] = fakeObject{contentType: r.Header.Get("Content-Type"), data: data}
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3(t *testing.T) {
+	ctx := context.Background()
+	bucket := &fakeS3{bucket: "images", objects: make(map[string]fakeObject)}
+	server := httptest.NewServer(bucket)
+	defer server.Close()
+
+	s := NewS3(S3Config{Endpoint: server.URL + "/", Region: "eu-central-1", Bucket: "images", AccessKey: "access", SecretKey: "secret"})
+
+	get := func(t *testing.T, url string) (status int, contentType, body string) {
+		t.Helper()
+		res, err := http.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		data, _ := io.ReadAll(res.Body)
+		return res.StatusCode, res.Header.Get("Content-Type"), string(data)
+	}
+
+	t.Run("put", func(t *testing.T) {
+		url, err := s.Put(ctx, "products/42/photo 1.png", "image/png", []byte("picture"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := server.URL + "/images/products/42/photo%201.png"; url != want {
+			t.Errorf("got %s, want %s", url, want)
+		}
+
+		if status, contentType, body := get(t, url); status != http.StatusOK || contentType != "image/png" || body != "picture" {
+			t.Errorf("got %d %s %q, want the picture stored", status, contentType, body)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		url, err := s.Put(ctx, "products/43.jpg", "image/jpeg", []byte("picture"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = s.Delete(ctx, "products/43.jpg"); err != nil {
+			t.Fatal(err)
+		}
+		if status, _, _ := get(t, url); status != http.StatusNotFound {
+			t.Errorf("got %d, want the picture deleted", status)
+		}
+
+		// a missing object is deleted as well
+		if err = s.Delete(ctx, "products/43.jpg"); err != nil {
+			t.Errorf("err = %v, want nil", err)
+		}
+	})
+
+	t.Run("public url", func(t *testing.T) {
+		s := NewS3(S3Config{Endpoint: server.URL, Region: "eu-central-1", Bucket: "images", AccessKey: "access",
+			SecretKey: "secret", PublicURL: "https://cdn.example.com/"})
+
+		url, err := s.Put(ctx, "products/44.png", "image/png", []byte("picture"))
+		if err != nil || url != "https://cdn.example.com/products/44.png" {
+			t.Errorf("got %s, err = %v, want the picture served from the CDN", url, err)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		s := NewS3(S3Config{Endpoint: server.URL, Region: "eu-central-1", Bucket: "other", AccessKey: "access", SecretKey: "secret"})
+		if _, err := s.Put(ctx, "products/45.png", "image/png", []byte("picture")); err == nil {
+			t.Error("err = nil, want the status reported")
+		}
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		for _, key := range []string{"", "/products/46.png"} {
+			if _, err := s.Put(ctx, key, "image/png", nil); err != ErrInvalidKey {
+				t.Errorf("got %v for %q, want %v", err, key, ErrInvalidKey)
+			}
+		}
+	})
+}
+
+func TestS3Sign(t *testing.T) {
+	s := NewS3(S3Config{Endpoint: "http://localhost:9000", Region: "us-east-1", Bucket: "images", AccessKey: "access", SecretKey: "secret"})
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	sign := func(key string) string {
+		req, err := s.request(context.Background(), http.MethodPut, key, []byte("picture"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "image/png")
+		s.sign(req, []byte("picture"), now)
+		return req.Header.Get("Authorization")
+	}
+
+	// the signature is stable for the same request and changes with the object
+	first, again, other := sign("a.png"), sign("a.png"), sign("b.png")
+	if first != again || first == other {
+		t.Errorf("got %s, %s and %s, want the signature to follow the request", first, again, other)
+	}
+
+	if !strings.HasPrefix(first, "AWS4-HMAC-SHA256 Credential=access/20240315/us-east-1/s3/aws4_request, ") {
+		t.Errorf("got %s, want the credential scoped to the day and region", first)
+	}
+}
@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"payment-service/internal/domain/billing"
+	"payment-service/pkg/store"
+)
+
+// PIIRekeyer periodically encrypts the personal data of the billings kept under a retired key under the primary one,
+// so a retired key can be removed from the key file once the rekeyer has run.
+type PIIRekeyer struct {
+	billingRekeyer billing.Rekeyer
+
+	interval time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewPIIRekeyer(billingRekeyer billing.Rekeyer, interval time.Duration) *PIIRekeyer {
+	return &PIIRekeyer{
+		billingRekeyer: billingRekeyer,
+		interval:       interval,
+	}
+}
+
+// Run starts the rekeyer in a goroutine, it doesn't block.
+func (p *PIIRekeyer) Run(logger *zap.Logger) {
+	var ctx context.Context
+	ctx, p.cancel = context.WithCancel(store.WithOperator(context.Background()))
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			count, err := p.billingRekeyer.Rekey(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Error("ERR_REKEY_PII", zap.Error(err))
+				}
+			} else if count > 0 {
+				logger.Info("pii rekeyed", zap.Int("billings", count))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("pii rekeyer started")
+}
+
+// Stop cancels the rekeying in flight, each billing is rekeyed on its own, and waits for the rekeyer to exit.
+func (p *PIIRekeyer) Stop(ctx context.Context) (err error) {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	return
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"payment-service/internal/service/exchange"
+	"payment-service/pkg/store"
+)
+
+// RateLoader periodically refreshes exchange rates from the provider of the exchange service.
+type RateLoader struct {
+	exchangeService *exchange.Service
+
+	interval time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewRateLoader(exchangeService *exchange.Service, interval time.Duration) *RateLoader {
+	return &RateLoader{
+		exchangeService: exchangeService,
+		interval:        interval,
+	}
+}
+
+// Run starts the loader in a goroutine, it doesn't block.
+func (l *RateLoader) Run(logger *zap.Logger) {
+	var ctx context.Context
+	ctx, l.cancel = context.WithCancel(store.WithOperator(context.Background()))
+	l.done = make(chan struct{})
+
+	go func() {
+		defer close(l.done)
+
+		ticker := time.NewTicker(l.interval)
+		defer ticker.Stop()
+
+		for {
+			if rates, err := l.exchangeService.RefreshRates(ctx); err != nil {
+				if ctx.Err() == nil {
+					logger.Error("ERR_REFRESH_RATES", zap.Error(err))
+				}
+			} else {
+				logger.Info("exchange rates refreshed", zap.Int("count", len(rates)))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("rate loader started")
+}
+
+// Stop cancels a refresh in flight, the next run loads the rates again, and waits for the loader to exit.
+func (l *RateLoader) Stop(ctx context.Context) (err error) {
+	if l.cancel == nil {
+		return
+	}
+	l.cancel()
+
+	select {
+	case <-l.done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	return
+}
@@ -0,0 +1,128 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"payment-service/internal/domain/outbox"
+	"payment-service/pkg/store"
+)
+
+// Relay periodically drains the outbox to the publishers. An event is marked as published only
+// after every publisher accepted it, otherwise it is retried once its lease expires.
+type Relay struct {
+	outboxRepository outbox.Repository
+	publishers       []outbox.Publisher
+
+	interval  time.Duration
+	batchSize int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+func NewRelay(outboxRepository outbox.Repository, publishers []outbox.Publisher, interval time.Duration, batchSize int) *Relay {
+	return &Relay{
+		outboxRepository: outboxRepository,
+		publishers:       publishers,
+		interval:         interval,
+		batchSize:        batchSize,
+	}
+}
+
+// Run starts the relay in a goroutine, it doesn't block.
+func (r *Relay) Run(logger *zap.Logger) {
+	r.ctx, r.cancel = context.WithCancel(store.WithOperator(context.Background()))
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			r.drain(r.ctx, logger)
+
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("outbox relay started")
+}
+
+// Stop lets the relay finish the event in flight and waits for it to exit.
+// When ctx expires first, the delivery in flight is cancelled and retried by the next run.
+func (r *Relay) Stop(ctx context.Context) (err error) {
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		r.cancel()
+		<-r.done
+		err = ctx.Err()
+	}
+	r.cancel()
+
+	return
+}
+
+func (r *Relay) stopping() bool {
+	select {
+	case <-r.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *Relay) drain(ctx context.Context, logger *zap.Logger) {
+	// the lease outlives a batch, so a relay that stops mid-batch leaves the rest for the next run
+	events, err := r.outboxRepository.Claim(ctx, r.batchSize, 2*r.interval+time.Minute)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("ERR_CLAIM_OUTBOX", zap.Error(err))
+		}
+		return
+	}
+
+	for _, event := range events {
+		if r.stopping() || ctx.Err() != nil {
+			return
+		}
+
+		if err = r.publish(ctx, event); err != nil {
+			logger.Warn("ERR_PUBLISH_OUTBOX", zap.String("id", event.ID), zap.String("dedup_key", event.DedupKey), zap.Error(err))
+			if err = r.outboxRepository.MarkFailed(store.WithOperator(context.Background()), event.ID, err.Error()); err != nil {
+				logger.Error("ERR_MARK_OUTBOX", zap.String("id", event.ID), zap.Error(err))
+			}
+			continue
+		}
+
+		if err = r.outboxRepository.MarkPublished(store.WithOperator(context.Background()), event.ID); err != nil {
+			logger.Error("ERR_MARK_OUTBOX", zap.String("id", event.ID), zap.Error(err))
+		}
+	}
+}
+
+func (r *Relay) publish(ctx context.Context, event outbox.Entity) (err error) {
+	for _, publisher := range r.publishers {
+		if err = publisher.Publish(ctx, event); err != nil {
+			return
+		}
+	}
+
+	return
+}
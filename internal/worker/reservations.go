@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"payment-service/internal/service/stock"
+	"payment-service/pkg/store"
+)
+
+// ReservationReaper periodically returns the stock of reservations whose billing wasn't paid in time.
+type ReservationReaper struct {
+	stockService *stock.Service
+
+	interval time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewReservationReaper(stockService *stock.Service, interval time.Duration) *ReservationReaper {
+	return &ReservationReaper{
+		stockService: stockService,
+		interval:     interval,
+	}
+}
+
+// Run starts the reaper in a goroutine, it doesn't block.
+func (p *ReservationReaper) Run(logger *zap.Logger) {
+	var ctx context.Context
+	ctx, p.cancel = context.WithCancel(store.WithOperator(context.Background()))
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			if count, err := p.stockService.ReleaseExpired(ctx); err != nil {
+				if ctx.Err() == nil {
+					logger.Error("ERR_RELEASE_RESERVATIONS", zap.Error(err))
+				}
+			} else if count > 0 {
+				logger.Info("expired reservations released", zap.Int("count", count))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("reservation reaper started")
+}
+
+// Stop cancels the release in flight, each reservation is released in its own transaction, and waits for the reaper to exit.
+func (p *ReservationReaper) Stop(ctx context.Context) (err error) {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	return
+}
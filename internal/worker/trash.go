@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"payment-service/internal/service/catalogue"
+	"payment-service/pkg/store"
+)
+
+// TrashPurger periodically removes the products and categories that have been in the trash longer than the retention.
+type TrashPurger struct {
+	catalogueService *catalogue.Service
+
+	retention time.Duration
+	interval  time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewTrashPurger(catalogueService *catalogue.Service, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		catalogueService: catalogueService,
+		retention:        retention,
+		interval:         interval,
+	}
+}
+
+// Run starts the purger in a goroutine, it doesn't block.
+func (p *TrashPurger) Run(logger *zap.Logger) {
+	var ctx context.Context
+	ctx, p.cancel = context.WithCancel(store.WithOperator(context.Background()))
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			products, categories, err := p.catalogueService.PurgeTrash(ctx, time.Now().Add(-p.retention))
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Error("ERR_PURGE_TRASH", zap.Error(err))
+				}
+			} else if products > 0 || categories > 0 {
+				logger.Info("trash purged", zap.Int("products", products), zap.Int("categories", categories))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("trash purger started")
+}
+
+// Stop cancels the purge in flight, each product is purged on its own, and waits for the purger to exit.
+func (p *TrashPurger) Stop(ctx context.Context) (err error) {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	return
+}
@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"payment-service/internal/domain/category"
+	"payment-service/internal/repository/memory"
+	"payment-service/internal/service/catalogue"
+	"payment-service/pkg/store"
+)
+
+func TestTrashPurger(t *testing.T) {
+	ctx := store.WithOperator(context.Background())
+	categories := memory.NewCategoryRepository()
+
+	s, err := catalogue.New(
+		catalogue.WithCategoryRepository(categories),
+		catalogue.WithProductRepository(memory.NewProductRepository()),
+		catalogue.WithImageRepository(memory.NewImageRepository()),
+		catalogue.WithTransactor(memory.NewTransactor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	create := func(t *testing.T, name string) string {
+		t.Helper()
+		id, err := categories.Create(ctx, category.Entity{Name: &name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = s.DeleteCategory(ctx, id, 0, false); err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	// purged waits for the category to leave the trash
+	purged := func(t *testing.T, id string) bool {
+		t.Helper()
+		for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if _, err := categories.GetDeleted(ctx, id); err == store.ErrorNotFound {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("retention", func(t *testing.T) {
+		id := create(t, "Drinks")
+
+		p := NewTrashPurger(s, time.Hour, 10*time.Millisecond)
+		p.Run(zap.NewNop())
+
+		if purged(t, id) {
+			t.Error("the category is purged before the retention is over")
+		}
+		if err := p.Stop(ctx); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		// a negative retention puts the cutoff ahead, everything in the trash is old enough
+		p := NewTrashPurger(s, -time.Hour, 10*time.Millisecond)
+		p.Run(zap.NewNop())
+		defer p.Stop(ctx)
+
+		if id := create(t, "Juices"); !purged(t, id) {
+			t.Error("the category is still in the trash")
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		if err := NewTrashPurger(s, time.Hour, time.Hour).Stop(ctx); err != nil {
+			t.Errorf("err = %v, want nil for a purger that never ran", err)
+		}
+
+		p := NewTrashPurger(s, time.Hour, time.Hour)
+		p.Run(zap.NewNop())
+		if err := p.Stop(ctx); err != nil {
+			t.Errorf("err = %v, want nil", err)
+		}
+	})
+}
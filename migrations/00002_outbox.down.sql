@@ -0,0 +1,5 @@
+BEGIN;
+    DROP TABLE IF EXISTS outbox CASCADE;
+    ALTER TABLE billings DROP COLUMN IF EXISTS status;
+    ALTER TABLE billings DROP COLUMN IF EXISTS payment_type;
+END;
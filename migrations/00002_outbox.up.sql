@@ -0,0 +1,19 @@
+ALTER TABLE billings
+    ADD COLUMN IF NOT EXISTS payment_type   VARCHAR NULL,
+    ADD COLUMN IF NOT EXISTS status         VARCHAR NOT NULL DEFAULT 'created';
+
+CREATE TABLE IF NOT EXISTS outbox (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    published_at        TIMESTAMP NULL,
+    locked_until        TIMESTAMP NULL,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    aggregate           VARCHAR NOT NULL,
+    aggregate_id        VARCHAR NOT NULL,
+    event_type          VARCHAR NOT NULL,
+    dedup_key           VARCHAR NOT NULL UNIQUE,
+    payload             JSONB NOT NULL,
+    attempts            INTEGER NOT NULL DEFAULT 0,
+    last_error          VARCHAR NULL
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (created_at) WHERE published_at IS NULL;
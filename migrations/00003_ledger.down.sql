@@ -0,0 +1,7 @@
+BEGIN;
+    DROP TABLE IF EXISTS ledger_postings CASCADE;
+    DROP TABLE IF EXISTS ledger_entries CASCADE;
+    DROP TABLE IF EXISTS ledger_accounts CASCADE;
+    DROP FUNCTION IF EXISTS ledger_check_balance();
+    DROP FUNCTION IF EXISTS ledger_forbid_change();
+END;
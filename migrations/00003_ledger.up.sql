@@ -0,0 +1,64 @@
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    code                VARCHAR NOT NULL UNIQUE,
+    type                VARCHAR NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    kind                VARCHAR NOT NULL,
+    billing_id          VARCHAR NOT NULL,
+    description         VARCHAR NOT NULL,
+    dedup_key           VARCHAR NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS ledger_entries_billing_id_idx ON ledger_entries (billing_id);
+
+CREATE TABLE IF NOT EXISTS ledger_postings (
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    entry_id            UUID NOT NULL REFERENCES ledger_entries (id),
+    account_id          UUID NOT NULL REFERENCES ledger_accounts (id),
+    amount              NUMERIC NOT NULL,
+    currency            VARCHAR NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS ledger_postings_entry_id_idx ON ledger_postings (entry_id);
+CREATE INDEX IF NOT EXISTS ledger_postings_account_id_idx ON ledger_postings (account_id, currency);
+
+-- the ledger is append-only: entries and postings can't be changed once written
+CREATE OR REPLACE FUNCTION ledger_forbid_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger: % on % is not allowed', TG_OP, TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_entries_append_only
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_forbid_change();
+
+CREATE TRIGGER ledger_postings_append_only
+    BEFORE UPDATE OR DELETE ON ledger_postings
+    FOR EACH ROW EXECUTE FUNCTION ledger_forbid_change();
+
+-- every entry has to balance to zero per currency by the time its transaction commits
+CREATE OR REPLACE FUNCTION ledger_check_balance() RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1
+        FROM ledger_postings
+        WHERE entry_id = NEW.entry_id
+        GROUP BY currency
+        HAVING SUM(amount) <> 0
+    ) THEN
+        RAISE EXCEPTION 'ledger: entry % does not balance to zero', NEW.entry_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_postings_balanced
+    AFTER INSERT ON ledger_postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_check_balance();
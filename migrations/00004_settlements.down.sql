@@ -0,0 +1,7 @@
+BEGIN;
+    DROP TABLE IF EXISTS settlement_discrepancies CASCADE;
+    DROP TABLE IF EXISTS settlement_rows CASCADE;
+    DROP TABLE IF EXISTS settlement_statements CASCADE;
+    ALTER TABLE billings DROP COLUMN IF EXISTS int_reference;
+    ALTER TABLE billings DROP COLUMN IF EXISTS reference;
+END;
@@ -0,0 +1,42 @@
+ALTER TABLE billings
+    ADD COLUMN IF NOT EXISTS reference      VARCHAR NULL,
+    ADD COLUMN IF NOT EXISTS int_reference  VARCHAR NULL;
+
+CREATE INDEX IF NOT EXISTS billings_reference_idx ON billings (reference);
+CREATE INDEX IF NOT EXISTS billings_int_reference_idx ON billings (int_reference);
+
+CREATE TABLE IF NOT EXISTS settlement_statements (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    date                DATE NOT NULL,
+    filename            VARCHAR NOT NULL,
+    rows_total          INTEGER NOT NULL,
+    matched             INTEGER NOT NULL,
+    total_amount        NUMERIC NOT NULL,
+    total_fee           NUMERIC NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS settlement_rows (
+    statement_id        UUID NOT NULL REFERENCES settlement_statements (id) ON DELETE CASCADE,
+    line                INTEGER NOT NULL,
+    reference           VARCHAR NOT NULL,
+    int_reference       VARCHAR NOT NULL,
+    invoice_id          VARCHAR NOT NULL,
+    amount              NUMERIC NOT NULL,
+    fee                 NUMERIC NOT NULL,
+    billing_id          UUID NULL,
+    PRIMARY KEY (statement_id, line)
+);
+
+CREATE TABLE IF NOT EXISTS settlement_discrepancies (
+    statement_id        UUID NOT NULL REFERENCES settlement_statements (id) ON DELETE CASCADE,
+    kind                VARCHAR NOT NULL,
+    line                INTEGER NOT NULL,
+    billing_id          VARCHAR NOT NULL,
+    invoice_id          VARCHAR NOT NULL,
+    reference           VARCHAR NOT NULL,
+    expected            NUMERIC NOT NULL,
+    actual              NUMERIC NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS settlement_discrepancies_statement_id_idx ON settlement_discrepancies (statement_id);
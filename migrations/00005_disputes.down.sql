@@ -0,0 +1,4 @@
+BEGIN;
+    DROP TABLE IF EXISTS dispute_evidence CASCADE;
+    DROP TABLE IF EXISTS disputes CASCADE;
+END;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS disputes (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    billing_id          UUID NOT NULL REFERENCES billings (id),
+    reason_code         VARCHAR NOT NULL,
+    amount              NUMERIC NOT NULL CHECK (amount > 0),
+    currency            VARCHAR NOT NULL,
+    status              VARCHAR NOT NULL DEFAULT 'opened',
+    due_at              TIMESTAMP NOT NULL,
+    resolved_at         TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS disputes_billing_id_idx ON disputes (billing_id);
+CREATE INDEX IF NOT EXISTS disputes_due_at_idx ON disputes (due_at) WHERE status = 'opened';
+
+CREATE TABLE IF NOT EXISTS dispute_evidence (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    dispute_id          UUID NOT NULL REFERENCES disputes (id) ON DELETE CASCADE,
+    name                VARCHAR NOT NULL,
+    url                 VARCHAR NOT NULL,
+    content_type        VARCHAR NOT NULL,
+    note                VARCHAR NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS dispute_evidence_dispute_id_idx ON dispute_evidence (dispute_id);
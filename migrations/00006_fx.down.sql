@@ -0,0 +1,6 @@
+BEGIN;
+    ALTER TABLE billings DROP COLUMN IF EXISTS base_currency;
+    ALTER TABLE billings DROP COLUMN IF EXISTS base_amount;
+    ALTER TABLE billings DROP COLUMN IF EXISTS fx_rate;
+    DROP TABLE IF EXISTS fx_rates CASCADE;
+END;
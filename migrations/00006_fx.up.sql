@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS fx_rates (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    currency            VARCHAR(3) NOT NULL,
+    base                VARCHAR(3) NOT NULL,
+    rate                NUMERIC NOT NULL CHECK (rate > 0),
+    date                DATE NOT NULL,
+    source              VARCHAR NOT NULL,
+    PRIMARY KEY (currency, base, date)
+);
+
+ALTER TABLE billings
+    ADD COLUMN IF NOT EXISTS fx_rate        NUMERIC NULL,
+    ADD COLUMN IF NOT EXISTS base_amount    NUMERIC NULL,
+    ADD COLUMN IF NOT EXISTS base_currency  VARCHAR(3) NULL;
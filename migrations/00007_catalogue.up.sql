@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS categories (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    parent_id           UUID NULL REFERENCES categories (id),
+    name                VARCHAR NOT NULL,
+    CHECK (parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS categories_parent_id_idx ON categories (parent_id);
+
+CREATE TABLE IF NOT EXISTS products (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    category_id         UUID NOT NULL REFERENCES categories (id),
+    name                VARCHAR NOT NULL,
+    description         VARCHAR NULL,
+    measure             VARCHAR NULL,
+    image_url           VARCHAR NULL,
+    country             VARCHAR NULL,
+    barcode             VARCHAR NULL,
+    brand               VARCHAR NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS products_barcode_key ON products (barcode);
+CREATE INDEX IF NOT EXISTS products_category_id_idx ON products (category_id);
+CREATE INDEX IF NOT EXISTS products_brand_idx ON products (brand);
@@ -0,0 +1,5 @@
+BEGIN;
+    DROP TABLE IF EXISTS prices CASCADE;
+    DROP FUNCTION IF EXISTS prices_immutable();
+    DROP TABLE IF EXISTS price_lists CASCADE;
+END;
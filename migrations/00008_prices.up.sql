@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS price_lists (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    code                VARCHAR NOT NULL UNIQUE,
+    name                VARCHAR NOT NULL,
+    kind                VARCHAR NOT NULL CHECK (kind IN ('retail', 'wholesale', 'merchant')),
+    merchant_id         VARCHAR NULL,
+    CHECK ((kind = 'merchant') = (merchant_id IS NOT NULL))
+);
+
+CREATE TABLE IF NOT EXISTS prices (
+    created_at          TIMESTAMP DEFAULT CLOCK_TIMESTAMP(),
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    product_id          UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    list_id             UUID NOT NULL REFERENCES price_lists (id),
+    currency            VARCHAR(3) NOT NULL,
+    amount              NUMERIC NOT NULL CHECK (amount >= 0),
+    valid_from          TIMESTAMP NOT NULL,
+    valid_to            TIMESTAMP NULL,
+    CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS prices_effective_idx ON prices (product_id, list_id, currency, valid_from DESC);
+
+-- prices make up the price history, a new price supersedes the previous one instead of changing it
+CREATE OR REPLACE FUNCTION prices_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'prices are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER prices_immutable BEFORE UPDATE ON prices
+    FOR EACH ROW EXECUTE FUNCTION prices_immutable();
@@ -0,0 +1,4 @@
+BEGIN;
+    DROP TABLE IF EXISTS order_items CASCADE;
+    DROP TABLE IF EXISTS orders CASCADE;
+END;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS orders (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    status              VARCHAR NOT NULL DEFAULT 'created',
+    currency            VARCHAR(3) NOT NULL,
+    price_list_id       UUID NOT NULL REFERENCES price_lists (id),
+    subtotal            NUMERIC NOT NULL,
+    discount            NUMERIC NOT NULL,
+    tax                 NUMERIC NOT NULL,
+    total               NUMERIC NOT NULL,
+    billing_id          UUID NULL UNIQUE REFERENCES billings (id)
+);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    order_id            UUID NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    line                INTEGER NOT NULL,
+    product_id          UUID NOT NULL REFERENCES products (id),
+    name                VARCHAR NOT NULL,
+    quantity            NUMERIC NOT NULL CHECK (quantity > 0),
+    unit_price          NUMERIC NOT NULL,
+    subtotal            NUMERIC NOT NULL,
+    discount            NUMERIC NOT NULL,
+    tax_rate            NUMERIC NOT NULL,
+    tax                 NUMERIC NOT NULL,
+    total               NUMERIC NOT NULL,
+    PRIMARY KEY (order_id, line)
+);
+
+CREATE INDEX IF NOT EXISTS order_items_product_id_idx ON order_items (product_id);
@@ -0,0 +1,6 @@
+BEGIN;
+    DROP TABLE IF EXISTS reservation_items CASCADE;
+    DROP TABLE IF EXISTS reservations CASCADE;
+    DROP TABLE IF EXISTS stock CASCADE;
+    DROP TABLE IF EXISTS warehouses CASCADE;
+END;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS warehouses (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    code                VARCHAR NOT NULL UNIQUE,
+    name                VARCHAR NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS stock (
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    product_id          UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    warehouse_id        UUID NOT NULL REFERENCES warehouses (id),
+    on_hand             NUMERIC NOT NULL DEFAULT 0,
+    reserved            NUMERIC NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    PRIMARY KEY (product_id, warehouse_id),
+    CHECK (on_hand >= reserved)
+);
+
+CREATE TABLE IF NOT EXISTS reservations (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    order_id            UUID NULL REFERENCES orders (id),
+    billing_id          UUID NULL UNIQUE REFERENCES billings (id),
+    status              VARCHAR NOT NULL DEFAULT 'active',
+    expires_at          TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS reservations_expires_at_idx ON reservations (expires_at) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS reservation_items (
+    reservation_id      UUID NOT NULL REFERENCES reservations (id) ON DELETE CASCADE,
+    product_id          UUID NOT NULL,
+    warehouse_id        UUID NOT NULL,
+    quantity            NUMERIC NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (reservation_id, product_id, warehouse_id)
+);
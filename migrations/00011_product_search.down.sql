@@ -0,0 +1,6 @@
+BEGIN;
+    DROP INDEX IF EXISTS products_lower_country_idx;
+    DROP INDEX IF EXISTS products_lower_brand_idx;
+    DROP INDEX IF EXISTS products_search_idx;
+    ALTER TABLE products DROP COLUMN IF EXISTS search;
+END;
@@ -0,0 +1,9 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search TSVECTOR GENERATED ALWAYS AS (
+    SETWEIGHT(TO_TSVECTOR('simple', COALESCE(name, '')), 'A') ||
+    SETWEIGHT(TO_TSVECTOR('simple', COALESCE(brand, '')), 'B') ||
+    SETWEIGHT(TO_TSVECTOR('simple', COALESCE(description, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS products_search_idx ON products USING GIN (search);
+CREATE INDEX IF NOT EXISTS products_lower_brand_idx ON products (LOWER(brand));
+CREATE INDEX IF NOT EXISTS products_lower_country_idx ON products (LOWER(country));
@@ -0,0 +1,4 @@
+BEGIN;
+    DROP TABLE IF EXISTS product_import_errors CASCADE;
+    DROP TABLE IF EXISTS product_imports CASCADE;
+END;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS product_imports (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    format              VARCHAR NOT NULL,
+    status              VARCHAR NOT NULL DEFAULT 'pending',
+    total               INTEGER NOT NULL DEFAULT 0,
+    created             INTEGER NOT NULL DEFAULT 0,
+    updated             INTEGER NOT NULL DEFAULT 0,
+    failed              INTEGER NOT NULL DEFAULT 0,
+    message             VARCHAR NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS product_import_errors (
+    job_id              UUID NOT NULL REFERENCES product_imports (id) ON DELETE CASCADE,
+    line                INTEGER NOT NULL,
+    message             VARCHAR NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS product_import_errors_job_id_idx ON product_import_errors (job_id, line);
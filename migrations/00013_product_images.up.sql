@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS product_images (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    product_id          UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    position            INTEGER NOT NULL DEFAULT 0,
+    content_type        VARCHAR NOT NULL,
+    size                BIGINT NOT NULL,
+    width               INTEGER NOT NULL,
+    height              INTEGER NOT NULL,
+    key                 VARCHAR NOT NULL,
+    url                 VARCHAR NOT NULL,
+    thumbnail_key       VARCHAR NOT NULL,
+    thumbnail_url       VARCHAR NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS product_images_product_id_idx ON product_images (product_id, position);
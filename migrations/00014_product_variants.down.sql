@@ -0,0 +1,7 @@
+BEGIN;
+    DROP TABLE IF EXISTS category_attributes CASCADE;
+    DROP INDEX IF EXISTS products_attributes_idx;
+    DROP INDEX IF EXISTS products_parent_id_idx;
+    ALTER TABLE products DROP COLUMN IF EXISTS attributes;
+    ALTER TABLE products DROP COLUMN IF EXISTS parent_id;
+END;
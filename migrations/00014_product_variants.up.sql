@@ -0,0 +1,17 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS parent_id UUID NULL REFERENCES products (id);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS products_parent_id_idx ON products (parent_id);
+CREATE INDEX IF NOT EXISTS products_attributes_idx ON products USING GIN (attributes JSONB_PATH_OPS);
+
+CREATE TABLE IF NOT EXISTS category_attributes (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    category_id         UUID NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    code                VARCHAR NOT NULL,
+    name                VARCHAR NOT NULL,
+    type                VARCHAR NOT NULL,
+    required            BOOLEAN NOT NULL DEFAULT FALSE,
+    options             VARCHAR[] NOT NULL DEFAULT '{}',
+    UNIQUE (category_id, code)
+);
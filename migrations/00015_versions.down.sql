@@ -0,0 +1,5 @@
+BEGIN;
+    ALTER TABLE billings DROP COLUMN IF EXISTS version;
+    ALTER TABLE categories DROP COLUMN IF EXISTS version;
+    ALTER TABLE products DROP COLUMN IF EXISTS version;
+END;
@@ -0,0 +1,10 @@
+BEGIN;
+    DROP INDEX IF EXISTS products_deleted_at_idx;
+    DROP INDEX IF EXISTS categories_deleted_at_idx;
+    DROP INDEX IF EXISTS products_barcode_key;
+    CREATE UNIQUE INDEX IF NOT EXISTS products_barcode_key ON products (barcode);
+    ALTER TABLE products DROP COLUMN IF EXISTS trashed_with;
+    ALTER TABLE categories DROP COLUMN IF EXISTS trashed_with;
+    ALTER TABLE products DROP COLUMN IF EXISTS deleted_at;
+    ALTER TABLE categories DROP COLUMN IF EXISTS deleted_at;
+END;
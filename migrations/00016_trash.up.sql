@@ -0,0 +1,13 @@
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+
+-- the category or product whose deletion took the row along, restoring it brings back only these rows
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS trashed_with UUID NULL;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS trashed_with UUID NULL;
+
+-- products in the trash give their barcodes up
+DROP INDEX IF EXISTS products_barcode_key;
+CREATE UNIQUE INDEX IF NOT EXISTS products_barcode_key ON products (barcode) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS categories_deleted_at_idx ON categories (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS products_deleted_at_idx ON products (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,4 @@
+BEGIN;
+    DROP TABLE IF EXISTS audit_records CASCADE;
+    DROP FUNCTION IF EXISTS audit_forbid_change();
+END;
@@ -0,0 +1,28 @@
+-- created_at is the clock time rather than the start of the transaction, so records of one unit of work keep their order
+CREATE TABLE IF NOT EXISTS audit_records (
+    created_at          TIMESTAMP DEFAULT CLOCK_TIMESTAMP(),
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    entity_type         VARCHAR NOT NULL,
+    entity_id           VARCHAR NOT NULL,
+    action              VARCHAR NOT NULL,
+    actor               VARCHAR NOT NULL,
+    request_id          VARCHAR NOT NULL DEFAULT '',
+    diff                JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS audit_records_entity_id_idx ON audit_records (entity_id, created_at);
+
+-- the audit trail is append-only: records can't be changed once written
+CREATE OR REPLACE FUNCTION audit_forbid_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit: % on % is not allowed', TG_OP, TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_records_append_only
+    BEFORE UPDATE OR DELETE ON audit_records
+    FOR EACH ROW EXECUTE FUNCTION audit_forbid_change();
+
+CREATE TRIGGER audit_records_no_truncate
+    BEFORE TRUNCATE ON audit_records
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_forbid_change();
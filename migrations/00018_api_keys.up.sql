@@ -0,0 +1,9 @@
+-- only the SHA-256 hash of a key is stored, the key itself is shown once when it is issued
+CREATE TABLE IF NOT EXISTS api_keys (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  UUID PRIMARY KEY DEFAULT GEN_RANDOM_UUID(),
+    name                VARCHAR NOT NULL,
+    hash                VARCHAR NOT NULL UNIQUE,
+    scopes              VARCHAR[] NOT NULL DEFAULT '{}',
+    revoked_at          TIMESTAMP NULL
+);
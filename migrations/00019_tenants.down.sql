@@ -0,0 +1,55 @@
+BEGIN;
+    DROP POLICY IF EXISTS order_items_tenant ON order_items;
+    ALTER TABLE order_items NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE order_items DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS dispute_evidence_tenant ON dispute_evidence;
+    ALTER TABLE dispute_evidence NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE dispute_evidence DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS product_images_tenant ON product_images;
+    ALTER TABLE product_images NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE product_images DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS prices_tenant ON prices;
+    ALTER TABLE prices NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE prices DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS category_attributes_tenant ON category_attributes;
+    ALTER TABLE category_attributes NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE category_attributes DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS orders_tenant ON orders;
+    ALTER TABLE orders NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE orders DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS disputes_tenant ON disputes;
+    ALTER TABLE disputes NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE disputes DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS billings_tenant ON billings;
+    ALTER TABLE billings NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE billings DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS price_lists_tenant ON price_lists;
+    ALTER TABLE price_lists NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE price_lists DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS products_tenant ON products;
+    ALTER TABLE products NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE products DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS categories_tenant ON categories;
+    ALTER TABLE categories NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE categories DISABLE ROW LEVEL SECURITY;
+    DROP FUNCTION IF EXISTS tenant_visible(VARCHAR);
+    DROP FUNCTION IF EXISTS current_tenant();
+    DROP INDEX IF EXISTS price_lists_code_key;
+    ALTER TABLE price_lists ADD CONSTRAINT price_lists_code_key UNIQUE (code);
+    DROP INDEX IF EXISTS products_barcode_key;
+    CREATE UNIQUE INDEX IF NOT EXISTS products_barcode_key ON products (barcode) WHERE deleted_at IS NULL;
+    DROP INDEX IF EXISTS orders_tenant_id_idx;
+    DROP INDEX IF EXISTS disputes_tenant_id_idx;
+    DROP INDEX IF EXISTS billings_tenant_id_idx;
+    DROP INDEX IF EXISTS products_tenant_id_idx;
+    DROP INDEX IF EXISTS categories_tenant_id_idx;
+    ALTER TABLE outbox DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE orders DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE disputes DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE billings DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE price_lists DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE products DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE categories DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+    DROP TABLE IF EXISTS tenants CASCADE;
+END;
@@ -0,0 +1,95 @@
+-- the settings of the tenants sharing the deployment, the id is the tenant id API keys and tokens carry
+CREATE TABLE IF NOT EXISTS tenants (
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                  VARCHAR PRIMARY KEY,
+    name                VARCHAR NOT NULL,
+    terminal_id         VARCHAR NOT NULL DEFAULT '',
+    currency            VARCHAR NOT NULL DEFAULT '',
+    webhook_urls        VARCHAR[] NOT NULL DEFAULT '{}',
+    webhook_secret      VARCHAR NOT NULL DEFAULT ''
+);
+
+-- rows written before tenants were introduced belong to no tenant and are only seen by operators
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE price_lists ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE billings ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE disputes ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS categories_tenant_id_idx ON categories (tenant_id);
+CREATE INDEX IF NOT EXISTS products_tenant_id_idx ON products (tenant_id);
+CREATE INDEX IF NOT EXISTS billings_tenant_id_idx ON billings (tenant_id, created_at);
+CREATE INDEX IF NOT EXISTS disputes_tenant_id_idx ON disputes (tenant_id);
+CREATE INDEX IF NOT EXISTS orders_tenant_id_idx ON orders (tenant_id);
+
+-- barcodes and price list codes only have to differ within a tenant
+DROP INDEX IF EXISTS products_barcode_key;
+CREATE UNIQUE INDEX IF NOT EXISTS products_barcode_key ON products (tenant_id, barcode) WHERE deleted_at IS NULL;
+
+ALTER TABLE price_lists DROP CONSTRAINT IF EXISTS price_lists_code_key;
+CREATE UNIQUE INDEX IF NOT EXISTS price_lists_code_key ON price_lists (tenant_id, code);
+
+-- the service sets app.tenant_id before every statement, to the tenant of the caller or to '*' for operators
+-- and background work; a session that did not set it, e.g. another client of the database, sees no tenant rows
+CREATE OR REPLACE FUNCTION current_tenant() RETURNS VARCHAR AS $$
+    SELECT COALESCE(CURRENT_SETTING('app.tenant_id', TRUE), '')
+$$ LANGUAGE SQL STABLE;
+
+CREATE OR REPLACE FUNCTION tenant_visible(tenant_id VARCHAR) RETURNS BOOLEAN AS $$
+    SELECT current_tenant() = '*' OR (current_tenant() <> '' AND current_tenant() = tenant_id)
+$$ LANGUAGE SQL STABLE;
+
+-- row-level security backs the tenant conditions of the queries up, it is forced so the owner is held to it too
+ALTER TABLE categories ENABLE ROW LEVEL SECURITY;
+ALTER TABLE categories FORCE ROW LEVEL SECURITY;
+CREATE POLICY categories_tenant ON categories USING (tenant_visible(tenant_id));
+
+ALTER TABLE products ENABLE ROW LEVEL SECURITY;
+ALTER TABLE products FORCE ROW LEVEL SECURITY;
+CREATE POLICY products_tenant ON products USING (tenant_visible(tenant_id));
+
+ALTER TABLE price_lists ENABLE ROW LEVEL SECURITY;
+ALTER TABLE price_lists FORCE ROW LEVEL SECURITY;
+CREATE POLICY price_lists_tenant ON price_lists USING (tenant_visible(tenant_id));
+
+ALTER TABLE billings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE billings FORCE ROW LEVEL SECURITY;
+CREATE POLICY billings_tenant ON billings USING (tenant_visible(tenant_id));
+
+ALTER TABLE disputes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE disputes FORCE ROW LEVEL SECURITY;
+CREATE POLICY disputes_tenant ON disputes USING (tenant_visible(tenant_id));
+
+ALTER TABLE orders ENABLE ROW LEVEL SECURITY;
+ALTER TABLE orders FORCE ROW LEVEL SECURITY;
+CREATE POLICY orders_tenant ON orders USING (tenant_visible(tenant_id));
+
+-- the rows of a parent belong to its tenant, the parent is only visible to it
+ALTER TABLE category_attributes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE category_attributes FORCE ROW LEVEL SECURITY;
+CREATE POLICY category_attributes_tenant ON category_attributes
+    USING (EXISTS (SELECT 1 FROM categories WHERE categories.id = category_attributes.category_id));
+
+ALTER TABLE prices ENABLE ROW LEVEL SECURITY;
+ALTER TABLE prices FORCE ROW LEVEL SECURITY;
+CREATE POLICY prices_tenant ON prices
+    USING (EXISTS (SELECT 1 FROM products WHERE products.id = prices.product_id));
+
+ALTER TABLE product_images ENABLE ROW LEVEL SECURITY;
+ALTER TABLE product_images FORCE ROW LEVEL SECURITY;
+CREATE POLICY product_images_tenant ON product_images
+    USING (EXISTS (SELECT 1 FROM products WHERE products.id = product_images.product_id));
+
+ALTER TABLE dispute_evidence ENABLE ROW LEVEL SECURITY;
+ALTER TABLE dispute_evidence FORCE ROW LEVEL SECURITY;
+CREATE POLICY dispute_evidence_tenant ON dispute_evidence
+    USING (EXISTS (SELECT 1 FROM disputes WHERE disputes.id = dispute_evidence.dispute_id));
+
+ALTER TABLE order_items ENABLE ROW LEVEL SECURITY;
+ALTER TABLE order_items FORCE ROW LEVEL SECURITY;
+CREATE POLICY order_items_tenant ON order_items
+    USING (EXISTS (SELECT 1 FROM orders WHERE orders.id = order_items.order_id));
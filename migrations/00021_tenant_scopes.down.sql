@@ -0,0 +1,57 @@
+BEGIN;
+    DROP POLICY IF EXISTS settlement_discrepancies_operator ON settlement_discrepancies;
+    ALTER TABLE settlement_discrepancies NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE settlement_discrepancies DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS settlement_rows_operator ON settlement_rows;
+    ALTER TABLE settlement_rows NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE settlement_rows DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS settlement_statements_operator ON settlement_statements;
+    ALTER TABLE settlement_statements NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE settlement_statements DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS ledger_postings_tenant ON ledger_postings;
+    ALTER TABLE ledger_postings NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE ledger_postings DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS product_import_errors_tenant ON product_import_errors;
+    ALTER TABLE product_import_errors NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE product_import_errors DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS reservation_items_tenant ON reservation_items;
+    ALTER TABLE reservation_items NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE reservation_items DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS category_attributes_tenant ON category_attributes;
+    CREATE POLICY category_attributes_tenant ON category_attributes
+        USING (EXISTS (SELECT 1 FROM categories WHERE categories.id = category_attributes.category_id));
+    DROP POLICY IF EXISTS product_images_tenant ON product_images;
+    CREATE POLICY product_images_tenant ON product_images
+        USING (EXISTS (SELECT 1 FROM products WHERE products.id = product_images.product_id));
+    DROP POLICY IF EXISTS audit_records_tenant ON audit_records;
+    ALTER TABLE audit_records NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE audit_records DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS ledger_entries_tenant ON ledger_entries;
+    ALTER TABLE ledger_entries NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE ledger_entries DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS product_imports_tenant ON product_imports;
+    ALTER TABLE product_imports NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE product_imports DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS reservations_tenant ON reservations;
+    ALTER TABLE reservations NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE reservations DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS stock_tenant ON stock;
+    ALTER TABLE stock NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE stock DISABLE ROW LEVEL SECURITY;
+    DROP POLICY IF EXISTS warehouses_tenant ON warehouses;
+    ALTER TABLE warehouses NO FORCE ROW LEVEL SECURITY;
+    ALTER TABLE warehouses DISABLE ROW LEVEL SECURITY;
+    DROP INDEX IF EXISTS warehouses_code_key;
+    ALTER TABLE warehouses ADD CONSTRAINT warehouses_code_key UNIQUE (code);
+    DROP INDEX IF EXISTS ledger_entries_tenant_id_idx;
+    DROP INDEX IF EXISTS reservations_tenant_id_idx;
+    DROP INDEX IF EXISTS stock_tenant_id_idx;
+    ALTER TABLE audit_records DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE ledger_entries DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE category_attributes DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE product_images DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE product_imports DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE reservations DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE stock DROP COLUMN IF EXISTS tenant_id;
+    ALTER TABLE warehouses DROP COLUMN IF EXISTS tenant_id;
+END;
@@ -0,0 +1,101 @@
+-- the migration sees every tenant, so the rows below can be backfilled under row-level security
+SELECT SET_CONFIG('app.tenant_id', '*', FALSE);
+
+-- rows of child tables carry the tenant of their parent, rows that have none are only seen by operators
+ALTER TABLE warehouses ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE stock ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE product_imports ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE category_attributes ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE audit_records ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+
+UPDATE stock SET tenant_id = products.tenant_id FROM products WHERE products.id = stock.product_id;
+-- a warehouse stocked by several tenants goes to one of them, operators can split it up afterwards
+UPDATE warehouses SET tenant_id = stock.tenant_id FROM stock WHERE stock.warehouse_id = warehouses.id;
+UPDATE reservations SET tenant_id = orders.tenant_id FROM orders WHERE orders.id = reservations.order_id;
+UPDATE reservations SET tenant_id = billings.tenant_id FROM billings
+    WHERE billings.id = reservations.billing_id AND reservations.order_id IS NULL;
+UPDATE product_images SET tenant_id = products.tenant_id FROM products WHERE products.id = product_images.product_id;
+UPDATE category_attributes SET tenant_id = categories.tenant_id
+    FROM categories WHERE categories.id = category_attributes.category_id;
+
+-- the ledger stays append-only, the trigger only steps aside for the backfill of the new column
+ALTER TABLE ledger_entries DISABLE TRIGGER ledger_entries_append_only;
+UPDATE ledger_entries SET tenant_id = billings.tenant_id FROM billings WHERE billings.id::TEXT = ledger_entries.billing_id;
+ALTER TABLE ledger_entries ENABLE TRIGGER ledger_entries_append_only;
+
+CREATE INDEX IF NOT EXISTS stock_tenant_id_idx ON stock (tenant_id);
+CREATE INDEX IF NOT EXISTS reservations_tenant_id_idx ON reservations (tenant_id);
+CREATE INDEX IF NOT EXISTS ledger_entries_tenant_id_idx ON ledger_entries (tenant_id, created_at);
+
+-- warehouse codes only have to differ within a tenant
+ALTER TABLE warehouses DROP CONSTRAINT IF EXISTS warehouses_code_key;
+CREATE UNIQUE INDEX IF NOT EXISTS warehouses_code_key ON warehouses (tenant_id, code);
+
+ALTER TABLE warehouses ENABLE ROW LEVEL SECURITY;
+ALTER TABLE warehouses FORCE ROW LEVEL SECURITY;
+CREATE POLICY warehouses_tenant ON warehouses USING (tenant_visible(tenant_id));
+
+ALTER TABLE stock ENABLE ROW LEVEL SECURITY;
+ALTER TABLE stock FORCE ROW LEVEL SECURITY;
+CREATE POLICY stock_tenant ON stock USING (tenant_visible(tenant_id));
+
+ALTER TABLE reservations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE reservations FORCE ROW LEVEL SECURITY;
+CREATE POLICY reservations_tenant ON reservations USING (tenant_visible(tenant_id));
+
+ALTER TABLE product_imports ENABLE ROW LEVEL SECURITY;
+ALTER TABLE product_imports FORCE ROW LEVEL SECURITY;
+CREATE POLICY product_imports_tenant ON product_imports USING (tenant_visible(tenant_id));
+
+ALTER TABLE ledger_entries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE ledger_entries FORCE ROW LEVEL SECURITY;
+CREATE POLICY ledger_entries_tenant ON ledger_entries USING (tenant_visible(tenant_id));
+
+ALTER TABLE audit_records ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_records FORCE ROW LEVEL SECURITY;
+CREATE POLICY audit_records_tenant ON audit_records USING (tenant_visible(tenant_id));
+
+-- images and attributes are held to their own tenant on top of the one of their parent
+DROP POLICY IF EXISTS product_images_tenant ON product_images;
+CREATE POLICY product_images_tenant ON product_images USING (tenant_visible(tenant_id)
+    AND EXISTS (SELECT 1 FROM products WHERE products.id = product_images.product_id));
+
+DROP POLICY IF EXISTS category_attributes_tenant ON category_attributes;
+CREATE POLICY category_attributes_tenant ON category_attributes USING (tenant_visible(tenant_id)
+    AND EXISTS (SELECT 1 FROM categories WHERE categories.id = category_attributes.category_id));
+
+-- the rows of a parent belong to its tenant, the parent is only visible to it
+ALTER TABLE reservation_items ENABLE ROW LEVEL SECURITY;
+ALTER TABLE reservation_items FORCE ROW LEVEL SECURITY;
+CREATE POLICY reservation_items_tenant ON reservation_items
+    USING (EXISTS (SELECT 1 FROM reservations WHERE reservations.id = reservation_items.reservation_id));
+
+ALTER TABLE product_import_errors ENABLE ROW LEVEL SECURITY;
+ALTER TABLE product_import_errors FORCE ROW LEVEL SECURITY;
+CREATE POLICY product_import_errors_tenant ON product_import_errors
+    USING (EXISTS (SELECT 1 FROM product_imports WHERE product_imports.id = product_import_errors.job_id));
+
+ALTER TABLE ledger_postings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE ledger_postings FORCE ROW LEVEL SECURITY;
+CREATE POLICY ledger_postings_tenant ON ledger_postings
+    USING (EXISTS (SELECT 1 FROM ledger_entries WHERE ledger_entries.id = ledger_postings.entry_id));
+
+-- settlement statements cover the whole terminal of the acquirer, they are only read by operators;
+-- ledger accounts are shared by the entries of every tenant and hold no amounts of their own
+ALTER TABLE settlement_statements ENABLE ROW LEVEL SECURITY;
+ALTER TABLE settlement_statements FORCE ROW LEVEL SECURITY;
+CREATE POLICY settlement_statements_operator ON settlement_statements USING (current_tenant() = '*');
+
+ALTER TABLE settlement_rows ENABLE ROW LEVEL SECURITY;
+ALTER TABLE settlement_rows FORCE ROW LEVEL SECURITY;
+CREATE POLICY settlement_rows_operator ON settlement_rows
+    USING (EXISTS (SELECT 1 FROM settlement_statements WHERE settlement_statements.id = settlement_rows.statement_id));
+
+ALTER TABLE settlement_discrepancies ENABLE ROW LEVEL SECURITY;
+ALTER TABLE settlement_discrepancies FORCE ROW LEVEL SECURITY;
+CREATE POLICY settlement_discrepancies_operator ON settlement_discrepancies
+    USING (EXISTS (SELECT 1 FROM settlement_statements
+        WHERE settlement_statements.id = settlement_discrepancies.statement_id));
@@ -0,0 +1,3 @@
+BEGIN;
+    ALTER TABLE billings DROP COLUMN IF EXISTS refunded;
+END;
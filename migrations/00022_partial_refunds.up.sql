@@ -0,0 +1,6 @@
+SELECT SET_CONFIG('app.tenant_id', '*', FALSE);
+
+-- the total refunded so far, a billing refunded before partial refunds were tracked was refunded in full
+ALTER TABLE billings ADD COLUMN IF NOT EXISTS refunded NUMERIC NULL;
+
+UPDATE billings SET refunded = amount WHERE status = 'refunded';
@@ -0,0 +1,4 @@
+BEGIN;
+    DROP INDEX IF EXISTS billings_paid_at_idx;
+    ALTER TABLE billings DROP COLUMN IF EXISTS paid_at;
+END;
@@ -0,0 +1,8 @@
+SELECT SET_CONFIG('app.tenant_id', '*', FALSE);
+
+-- settlements are matched on the day a billing was paid, the last change of a billing paid before is the closest
+ALTER TABLE billings ADD COLUMN IF NOT EXISTS paid_at TIMESTAMP NULL;
+
+UPDATE billings SET paid_at = updated_at WHERE status IN ('paid', 'partially_refunded', 'refunded');
+
+CREATE INDEX IF NOT EXISTS billings_paid_at_idx ON billings (paid_at) WHERE paid_at IS NOT NULL;
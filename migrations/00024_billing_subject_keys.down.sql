@@ -0,0 +1,4 @@
+BEGIN;
+    DROP INDEX IF EXISTS billings_subject_keys_idx;
+    ALTER TABLE billings DROP COLUMN IF EXISTS subject_keys;
+END;
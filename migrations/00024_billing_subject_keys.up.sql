@@ -0,0 +1,22 @@
+SELECT SET_CONFIG('app.tenant_id', '*', FALSE);
+
+-- the blind indexes of the email, phone and account id of the payer, the billings of a data subject are looked up by them
+ALTER TABLE billings ADD COLUMN IF NOT EXISTS subject_keys VARCHAR[] NULL;
+
+-- the keys of the billings stored unencrypted are their SHA-256, as billing.PlainIndex computes them; the encrypted
+-- ones are keyed with the index key of the key file and are left to the rekeying of the service
+UPDATE billings
+SET subject_keys = ARRAY_REMOVE(ARRAY [
+    CASE WHEN NULLIF(LOWER(TRIM(email)), '') IS NOT NULL AND email <> '[erased]'
+        THEN ENCODE(SHA256(CONVERT_TO('email:' || LOWER(TRIM(email)), 'UTF8')), 'hex') END,
+    CASE WHEN NULLIF(REGEXP_REPLACE(phone, '[^0-9]', '', 'g'), '') IS NOT NULL AND phone <> '[erased]'
+        THEN ENCODE(SHA256(CONVERT_TO('phone:' || REGEXP_REPLACE(phone, '[^0-9]', '', 'g'), 'UTF8')), 'hex') END,
+    CASE WHEN NULLIF(TRIM(account_id), '') IS NOT NULL AND account_id <> '[erased]'
+        THEN ENCODE(SHA256(CONVERT_TO('account_id:' || TRIM(account_id), 'UTF8')), 'hex') END
+    ], NULL)::VARCHAR[]
+WHERE subject_keys IS NULL
+  AND COALESCE(email, '') NOT LIKE 'enc:%'
+  AND COALESCE(phone, '') NOT LIKE 'enc:%'
+  AND COALESCE(account_id, '') NOT LIKE 'enc:%';
+
+CREATE INDEX IF NOT EXISTS billings_subject_keys_idx ON billings USING GIN (subject_keys);
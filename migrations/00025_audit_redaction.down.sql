@@ -0,0 +1,17 @@
+BEGIN;
+    CREATE OR REPLACE FUNCTION audit_forbid_change() RETURNS TRIGGER AS $$
+    BEGIN
+        RAISE EXCEPTION 'audit: % on % is not allowed', TG_OP, TG_TABLE_NAME;
+    END;
+    $$ LANGUAGE plpgsql;
+    GRANT audit_redactor TO CURRENT_USER;
+    DROP FUNCTION IF EXISTS audit_redact(VARCHAR, VARCHAR[], VARCHAR[], TEXT);
+    REVOKE audit_redactor FROM CURRENT_USER;
+    GRANT UPDATE ON audit_records TO CURRENT_USER;
+    REVOKE SELECT, UPDATE ON audit_records FROM audit_redactor;
+    DO $$
+    BEGIN
+        EXECUTE FORMAT('REVOKE USAGE ON SCHEMA %I FROM audit_redactor', CURRENT_SCHEMA());
+    END;
+    $$;
+END;
@@ -0,0 +1,53 @@
+-- the audit trail stays append-only, but the personal data of an erased data subject has to go from it as well:
+-- only audit_redactor may change a record, and it only does through audit_redact, which it owns
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'audit_redactor') THEN
+        CREATE ROLE audit_redactor NOLOGIN;
+    END IF;
+    EXECUTE FORMAT('GRANT USAGE ON SCHEMA %I TO audit_redactor', CURRENT_SCHEMA());
+END;
+$$;
+
+GRANT SELECT, UPDATE ON audit_records TO audit_redactor;
+REVOKE UPDATE ON audit_records FROM PUBLIC;
+REVOKE UPDATE ON audit_records FROM CURRENT_USER;
+
+-- audit_redact replaces the before and after values of the fields of the records of the entities with the marker,
+-- it runs as audit_redactor whoever calls it; the search path is pinned so the caller can't slip in other tables
+CREATE OR REPLACE FUNCTION audit_redact(kind VARCHAR, ids VARCHAR[], fields VARCHAR[], marker TEXT) RETURNS INTEGER AS $$
+DECLARE
+    redacted INTEGER;
+BEGIN
+    UPDATE audit_records
+    SET diff = (
+        SELECT JSONB_OBJECT_AGG(field.key, CASE WHEN field.key = ANY (fields)
+            THEN (SELECT JSONB_OBJECT_AGG(side.key, TO_JSONB(marker)) FROM JSONB_EACH(field.value) side)
+            ELSE field.value END)
+        FROM JSONB_EACH(audit_records.diff) field)
+    WHERE entity_type = kind AND entity_id = ANY (ids) AND diff ?| fields;
+    GET DIAGNOSTICS redacted = ROW_COUNT;
+
+    RETURN redacted;
+END;
+$$ LANGUAGE plpgsql SECURITY DEFINER SET search_path FROM CURRENT;
+
+-- the owner has to be a member of the role to hand the function over, it doesn't stay one
+GRANT audit_redactor TO CURRENT_USER;
+ALTER FUNCTION audit_redact(VARCHAR, VARCHAR[], VARCHAR[], TEXT) OWNER TO audit_redactor;
+REVOKE audit_redactor FROM CURRENT_USER;
+
+-- a record can't be changed once written, but for its diff by audit_redactor, i.e. within audit_redact
+CREATE OR REPLACE FUNCTION audit_forbid_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND CURRENT_USER = 'audit_redactor'
+        AND (NEW.created_at, NEW.id, NEW.tenant_id, NEW.entity_type, NEW.entity_id, NEW.action, NEW.actor, NEW.request_id)
+            IS NOT DISTINCT FROM
+            (OLD.created_at, OLD.id, OLD.tenant_id, OLD.entity_type, OLD.entity_id, OLD.action, OLD.actor, OLD.request_id)
+    THEN
+        RETURN NEW;
+    END IF;
+
+    RAISE EXCEPTION 'audit: % on % is not allowed', TG_OP, TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrNoCredentials is returned by an Authenticator when the request carries no credentials of its kind
+	ErrNoCredentials = errors.New("auth: credentials are required")
+	// ErrInvalidCredentials is returned when the credentials are unknown, expired or revoked
+	ErrInvalidCredentials = errors.New("auth: credentials are invalid")
+	// ErrForbidden is returned when the principal lacks the scope a route requires
+	ErrForbidden = errors.New("auth: insufficient scope")
+	// ErrOperatorOnly is returned when a principal of a tenant calls a route that sees every tenant
+	ErrOperatorOnly = errors.New("auth: route is reserved to operators")
+)
+
+// Authenticator finds the caller of the request from its credentials.
+// It returns ErrNoCredentials when the request has none of its kind, so the next authenticator can try.
+type Authenticator interface {
+	Authenticate(r *http.Request) (principal Principal, err error)
+}
+
+// APIKeyHeader carries the API key of a source service.
+const APIKeyHeader = "X-API-Key"
+
+// KeyStore finds the principal an API key was issued to, it fails with ErrInvalidCredentials for an unknown or revoked key.
+type KeyStore interface {
+	Lookup(ctx context.Context, key string) (principal Principal, err error)
+}
+
+// APIKey authenticates source services by the key in the X-API-Key header.
+type APIKey struct {
+	store KeyStore
+}
+
+func NewAPIKey(store KeyStore) *APIKey {
+	return &APIKey{
+		store: store,
+	}
+}
+
+func (a *APIKey) Authenticate(r *http.Request) (principal Principal, err error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return principal, ErrNoCredentials
+	}
+
+	return a.store.Lookup(r.Context(), key)
+}
+
+// bearer returns the token of the Authorization header, it is empty when the header holds no bearer token.
+func bearer(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
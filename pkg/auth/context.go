@@ -0,0 +1,46 @@
+package auth
+
+import "context"
+
+type principalKey struct{}
+
+// Principal is the authenticated caller of a request: a source service holding an API key or the subject of a token.
+// Tenant is the tenant the caller acts for, it is empty for operators who see every tenant.
+type Principal struct {
+	Subject string
+	Tenant  string
+	Scopes  []string
+}
+
+// HasScope tells whether the principal was granted the scope.
+func (p Principal) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Operator tells whether the principal acts for every tenant rather than one.
+func (p Principal) Operator() bool {
+	return p.Tenant == ""
+}
+
+// WithPrincipal puts the authenticated caller into the context.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the caller put into the context, ok is false for anonymous requests and background work.
+func FromContext(ctx context.Context) (principal Principal, ok bool) {
+	principal, ok = ctx.Value(principalKey{}).(Principal)
+	return
+}
+
+// Actor returns the subject of the caller put into the context, it is empty for anonymous requests and background work.
+func Actor(ctx context.Context) string {
+	principal, _ := FromContext(ctx)
+	return principal.Subject
+}
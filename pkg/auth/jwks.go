@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// minReload keeps an unknown key id from reloading the key set more often than this.
+const minReload = 30 * time.Second
+
+var (
+	ErrUnknownKey = errors.New("auth: signing key not found")
+	ErrNoKeys     = errors.New("auth: key set has no usable keys")
+)
+
+// JWKS is the set of public keys tokens are signed with, read from a JSON Web Key Set file or URL.
+// The set is reloaded once it is older than the refresh interval or a token names a key it doesn't have.
+type JWKS struct {
+	source  string
+	refresh time.Duration
+	client  *http.Client
+
+	mu     sync.RWMutex
+	keys   map[string]crypto.PublicKey
+	loaded time.Time
+}
+
+func NewJWKS(source string, refresh time.Duration) *JWKS {
+	return &JWKS{
+		source:  source,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Key returns the public key with the id, an empty id matches the only key of the set.
+func (s *JWKS) Key(ctx context.Context, id string) (key crypto.PublicKey, err error) {
+	s.mu.RLock()
+	key, ok := s.find(id)
+	fresh := time.Since(s.loaded) < s.refresh
+	recent := time.Since(s.loaded) < minReload
+	s.mu.RUnlock()
+
+	if ok && fresh {
+		return key, nil
+	}
+
+	if !recent {
+		if err = s.load(ctx); err != nil && !ok {
+			return
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if key, ok = s.find(id); !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+func (s *JWKS) find(id string) (key crypto.PublicKey, ok bool) {
+	if id == "" && len(s.keys) == 1 {
+		for _, key = range s.keys {
+			return key, true
+		}
+	}
+
+	key, ok = s.keys[id]
+	return
+}
+
+func (s *JWKS) load(ctx context.Context) (err error) {
+	data, err := s.read(ctx)
+	if err != nil {
+		return
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err = json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("auth: key set: %w", err)
+	}
+
+	// a key of a type or curve that isn't supported, or a broken one, is skipped, so a provider publishing a new
+	// kind of key doesn't lock every caller out
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, object := range set.Keys {
+		if object.Use != "" && object.Use != "sig" {
+			continue
+		}
+
+		key, err := object.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[object.ID] = key
+	}
+
+	if len(keys) == 0 {
+		return ErrNoKeys
+	}
+
+	s.mu.Lock()
+	s.keys, s.loaded = keys, time.Now()
+	s.mu.Unlock()
+
+	return
+}
+
+// read fetches an http(s) source, anything else is a file path.
+func (s *JWKS) read(ctx context.Context) (data []byte, err error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		return os.ReadFile(s.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("auth: key set: unexpected status %d", res.StatusCode)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+// jwk is a public key of the set, RSA and EC keys are supported.
+type jwk struct {
+	ID    string `json:"kid"`
+	Type  string `json:"kty"`
+	Use   string `json:"use"`
+	N     string `json:"n"`
+	E     string `json:"e"`
+	Curve string `json:"crv"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+}
+
+func (k jwk) publicKey() (key crypto.PublicKey, err error) {
+	switch k.Type {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() || e.Int64() < 2 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Type)
+}
+
+func decodeInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// leeway is the clock skew allowed when the expiry and not-before claims are checked.
+const leeway = time.Minute
+
+var algorithms = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// curves is the curve each ES algorithm is defined on, RFC 7518 pairs them one to one.
+var curves = map[string]string{
+	"ES256": "P-256",
+	"ES384": "P-384",
+	"ES512": "P-521",
+}
+
+// JWT authenticates the bearer tokens of the Authorization header signed with a key of the set.
+// The issuer and audience are checked when set. The scopes come from the space-separated scope claim
+// or the scp claim, the principal is the subject of the token acting for the tenant of the tenant_id claim.
+// Only the operator issuers may leave the tenant out to act for every tenant, they are accepted
+// besides the issuer.
+type JWT struct {
+	keys            *JWKS
+	issuer          string
+	audience        string
+	operatorIssuers []string
+}
+
+func NewJWT(keys *JWKS, issuer, audience string, operatorIssuers ...string) *JWT {
+	return &JWT{
+		keys:            keys,
+		issuer:          issuer,
+		audience:        audience,
+		operatorIssuers: operatorIssuers,
+	}
+}
+
+type claims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+	Scope     string          `json:"scope"`
+	Scp       json.RawMessage `json:"scp"`
+	TenantID  string          `json:"tenant_id"`
+}
+
+func (a *JWT) Authenticate(r *http.Request) (principal Principal, err error) {
+	token := bearer(r)
+	if token == "" {
+		return principal, ErrNoCredentials
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return principal, ErrInvalidCredentials
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+	if err = decodeSegment(parts[0], &header); err != nil {
+		return principal, ErrInvalidCredentials
+	}
+
+	hash, ok := algorithms[header.Algorithm]
+	if !ok {
+		return principal, ErrInvalidCredentials
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return principal, ErrInvalidCredentials
+	}
+
+	key, err := a.keys.Key(r.Context(), header.KeyID)
+	if err != nil {
+		return principal, ErrInvalidCredentials
+	}
+
+	digest := hash.New()
+	digest.Write([]byte(parts[0] + "." + parts[1]))
+	if !verify(key, header.Algorithm, hash, digest.Sum(nil), signature) {
+		return principal, ErrInvalidCredentials
+	}
+
+	var data claims
+	if err = decodeSegment(parts[1], &data); err != nil {
+		return principal, ErrInvalidCredentials
+	}
+
+	if !a.valid(data, time.Now()) {
+		return principal, ErrInvalidCredentials
+	}
+
+	principal = Principal{
+		Subject: data.Subject,
+		Tenant:  data.TenantID,
+		Scopes:  strings.Fields(data.Scope),
+	}
+
+	var scp []string
+	if json.Unmarshal(data.Scp, &scp) != nil {
+		var value string
+		json.Unmarshal(data.Scp, &value)
+		scp = strings.Fields(value)
+	}
+	principal.Scopes = append(principal.Scopes, scp...)
+
+	return principal, nil
+}
+
+func (a *JWT) valid(data claims, now time.Time) bool {
+	if data.Subject == "" {
+		return false
+	}
+
+	if data.ExpiresAt == nil || now.Add(-leeway).After(unix(*data.ExpiresAt)) {
+		return false
+	}
+
+	if data.NotBefore != nil && now.Add(leeway).Before(unix(*data.NotBefore)) {
+		return false
+	}
+
+	operator := a.operator(data.Issuer)
+	if a.issuer != "" && data.Issuer != a.issuer && !operator {
+		return false
+	}
+
+	if data.TenantID == "" && !operator {
+		return false
+	}
+
+	if a.audience != "" {
+		var audiences []string
+		if json.Unmarshal(data.Audience, &audiences) != nil {
+			var audience string
+			if json.Unmarshal(data.Audience, &audience) != nil {
+				return false
+			}
+			audiences = []string{audience}
+		}
+
+		for _, audience := range audiences {
+			if audience == a.audience {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// operator tells whether the issuer is trusted to issue tokens acting for every tenant.
+func (a *JWT) operator(issuer string) bool {
+	for _, operator := range a.operatorIssuers {
+		if issuer != "" && issuer == operator {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verify checks the signature with the key, the kind of the key, and the curve of an EC key, have to match
+// the algorithm of the token.
+func verify(key crypto.PublicKey, algorithm string, hash crypto.Hash, digest, signature []byte) bool {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(algorithm, "RS") && rsa.VerifyPKCS1v15(key, hash, digest, signature) == nil
+
+	case *ecdsa.PublicKey:
+		if curves[algorithm] != key.Curve.Params().Name {
+			return false
+		}
+
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return false
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(key, digest, r, s)
+	}
+
+	return false
+}
+
+func decodeSegment(segment string, dest any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, dest)
+}
+
+func unix(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
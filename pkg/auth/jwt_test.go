@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testOperator = "https://operator.example.com"
+	testAudience = "payment-service"
+)
+
+func TestJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := writeKeySet(t, []map[string]string{rsaJWK("rsa", &rsaKey.PublicKey), ecJWK("p256", &p256.PublicKey)})
+	a := NewJWT(NewJWKS(keys, time.Hour), testIssuer, testAudience, testOperator)
+
+	valid := func() map[string]any {
+		return map[string]any{
+			"sub":       "alice",
+			"iss":       testIssuer,
+			"aud":       []string{"other", testAudience},
+			"exp":       time.Now().Add(time.Hour).Unix(),
+			"scope":     "catalogue:read catalogue:write",
+			"scp":       []string{"billing:read"},
+			"tenant_id": "acme",
+		}
+	}
+
+	t.Run("rsa", func(t *testing.T) {
+		principal, err := authenticate(a, sign(t, "RS256", "rsa", rsaKey, valid()))
+		want := Principal{Subject: "alice", Tenant: "acme", Scopes: []string{"catalogue:read", "catalogue:write", "billing:read"}}
+		if err != nil || !reflect.DeepEqual(principal, want) {
+			t.Errorf("got %+v, err = %v, want %+v", principal, err, want)
+		}
+	})
+
+	t.Run("ec", func(t *testing.T) {
+		claims := valid()
+		claims["aud"], claims["scp"] = testAudience, "billing:read"
+
+		principal, err := authenticate(a, sign(t, "ES256", "p256", p256, claims))
+		if err != nil || principal.Subject != "alice" || !principal.HasScope("billing:read") {
+			t.Errorf("got %+v, err = %v", principal, err)
+		}
+	})
+
+	t.Run("operator", func(t *testing.T) {
+		claims := valid()
+		claims["iss"] = testOperator
+		delete(claims, "tenant_id")
+
+		principal, err := authenticate(a, sign(t, "RS256", "rsa", rsaKey, claims))
+		if err != nil || !principal.Operator() {
+			t.Errorf("got %+v, err = %v, want an operator", principal, err)
+		}
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		if _, err := authenticate(a, ""); err != ErrNoCredentials {
+			t.Errorf("err = %v, want %v", err, ErrNoCredentials)
+		}
+	})
+
+	rejected := map[string]func(t *testing.T) string{
+		"expired": func(t *testing.T) string {
+			claims := valid()
+			claims["exp"] = time.Now().Add(-2 * leeway).Unix()
+			return sign(t, "RS256", "rsa", rsaKey, claims)
+		},
+		"not yet valid": func(t *testing.T) string {
+			claims := valid()
+			claims["nbf"] = time.Now().Add(2 * leeway).Unix()
+			return sign(t, "RS256", "rsa", rsaKey, claims)
+		},
+		"without expiry": func(t *testing.T) string {
+			claims := valid()
+			delete(claims, "exp")
+			return sign(t, "RS256", "rsa", rsaKey, claims)
+		},
+		"other issuer": func(t *testing.T) string {
+			claims := valid()
+			claims["iss"] = "https://attacker.example.com"
+			return sign(t, "RS256", "rsa", rsaKey, claims)
+		},
+		"other audience": func(t *testing.T) string {
+			claims := valid()
+			claims["aud"] = "other"
+			return sign(t, "RS256", "rsa", rsaKey, claims)
+		},
+		"tenant left out by the issuer": func(t *testing.T) string {
+			claims := valid()
+			delete(claims, "tenant_id")
+			return sign(t, "RS256", "rsa", rsaKey, claims)
+		},
+		"tampered claims": func(t *testing.T) string {
+			parts := strings.Split(sign(t, "RS256", "rsa", rsaKey, valid()), ".")
+			claims := valid()
+			claims["tenant_id"] = "globex"
+			return parts[0] + "." + segment(t, claims) + "." + parts[2]
+		},
+		"unknown key": func(t *testing.T) string {
+			return sign(t, "RS256", "missing", rsaKey, valid())
+		},
+		"rsa algorithm with an ec key": func(t *testing.T) string {
+			return sign(t, "RS256", "p256", rsaKey, valid())
+		},
+		"ec algorithm of another curve": func(t *testing.T) string {
+			return sign(t, "ES384", "p256", p256, valid())
+		},
+		"unsigned": func(t *testing.T) string {
+			return segment(t, map[string]string{"alg": "none"}) + "." + segment(t, valid()) + "."
+		},
+	}
+
+	for name, token := range rejected {
+		t.Run(name, func(t *testing.T) {
+			if principal, err := authenticate(a, token(t)); err != ErrInvalidCredentials {
+				t.Errorf("got %+v, err = %v, want %v", principal, err, ErrInvalidCredentials)
+			}
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	ctx := context.Background()
+
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("unusable keys are skipped", func(t *testing.T) {
+		keys := writeKeySet(t, []map[string]string{
+			{"kid": "okp", "kty": "OKP", "crv": "Ed25519", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+			{"kid": "p224", "kty": "EC", "crv": "P-224", "x": "AQ", "y": "AQ"},
+			{"kid": "off-curve", "kty": "EC", "crv": "P-256", "x": "AQ", "y": "AQ"},
+			{"kid": "exponent", "kty": "RSA", "n": "AQAB", "e": "AQ"},
+			{"kid": "encryption", "kty": "EC", "use": "enc", "crv": "P-256", "x": "AQ", "y": "AQ"},
+			ecJWK("p256", &p256.PublicKey),
+		})
+		set := NewJWKS(keys, time.Hour)
+
+		if key, err := set.Key(ctx, "p256"); err != nil || !p256.PublicKey.Equal(key) {
+			t.Errorf("got %v, err = %v, want the p256 key", key, err)
+		}
+
+		// the only usable key is the one of a token without a key id
+		if key, err := set.Key(ctx, ""); err != nil || !p256.PublicKey.Equal(key) {
+			t.Errorf("got %v, err = %v, want the p256 key", key, err)
+		}
+
+		if _, err := set.Key(ctx, "p224"); err != ErrUnknownKey {
+			t.Errorf("err = %v, want %v", err, ErrUnknownKey)
+		}
+	})
+
+	t.Run("no usable key", func(t *testing.T) {
+		keys := writeKeySet(t, []map[string]string{{"kid": "p224", "kty": "EC", "crv": "P-224", "x": "AQ", "y": "AQ"}})
+
+		if _, err := NewJWKS(keys, time.Hour).Key(ctx, "p224"); err != ErrNoKeys {
+			t.Errorf("err = %v, want %v", err, ErrNoKeys)
+		}
+	})
+}
+
+func authenticate(a Authenticator, token string) (Principal, error) {
+	r := httptest.NewRequest("GET", "/", nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return a.Authenticate(r)
+}
+
+// sign returns the token of the claims signed with the key, the header names the algorithm and key id.
+func sign(t *testing.T, algorithm, keyID string, key crypto.Signer, claims map[string]any) string {
+	t.Helper()
+
+	payload := segment(t, map[string]string{"alg": algorithm, "kid": keyID}) + "." + segment(t, claims)
+
+	hash := algorithms[algorithm]
+	digest := hash.New()
+	digest.Write([]byte(payload))
+
+	var signature []byte
+	var err error
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, hash, digest.Sum(nil))
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, key, digest.Sum(nil))
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func segment(t *testing.T, value any) string {
+	t.Helper()
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func rsaJWK(id string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kid": id,
+		"kty": "RSA",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(id string, key *ecdsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kid": id,
+		"kty": "EC",
+		"crv": key.Curve.Params().Name,
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	}
+}
+
+func writeKeySet(t *testing.T, keys []map[string]string) string {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
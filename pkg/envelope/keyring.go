@@ -0,0 +1,250 @@
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// prefix marks the encrypted values, values without it were stored before the encryption and are read as they are.
+const prefix = "enc:v1:"
+
+var (
+	ErrUnknownKey = errors.New("envelope: value is encrypted under a key missing from the key file")
+	ErrMalformed  = errors.New("envelope: value is not a valid encrypted value")
+)
+
+// file is the key file, a JSON object of the base64 AES-256 key encryption keys by id and the id of the primary key,
+// e.g. {"primary": "2026-10", "keys": {"2026-04": "...", "2026-10": "..."}, "index": "..."}. A key is rotated
+// by adding a new one and making it primary, the retired ones are kept until no value is encrypted under them.
+// The index key keys the blind indexes, it is not rotated: the indexes computed with it would no longer match.
+type file struct {
+	Primary string            `json:"primary"`
+	Keys    map[string]string `json:"keys"`
+	Index   string            `json:"index"`
+}
+
+// Keyring encrypts values with a data key of their own, the data key is encrypted with the primary key and kept
+// with the value. A value is read with whichever key of the ring encrypted its data key.
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+	index   []byte
+}
+
+// Load reads the key file at path.
+func Load(path string) (keyring *Keyring, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	var data file
+	if err = json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("envelope: key file: %w", err)
+	}
+
+	keyring = &Keyring{
+		primary: data.Primary,
+		keys:    make(map[string]cipher.AEAD, len(data.Keys)),
+	}
+
+	for id, encoded := range data.Keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("envelope: key id %q must be non-empty and without colons", id)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("envelope: key %q must be 32 bytes in base64", id)
+		}
+
+		if keyring.keys[id], err = newAEAD(key); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := keyring.keys[keyring.primary]; !ok {
+		return nil, fmt.Errorf("envelope: primary key %q is not in the key file", keyring.primary)
+	}
+
+	if keyring.index, err = base64.StdEncoding.DecodeString(data.Index); err != nil || len(keyring.index) != 32 {
+		return nil, errors.New("envelope: index key must be 32 bytes in base64")
+	}
+
+	return
+}
+
+// Index returns the HMAC-SHA256 of the value under the index key, a blind index the value is looked up by
+// while it is stored encrypted. Equal values have equal indexes, whichever key encrypted them.
+func (k *Keyring) Index(value string) string {
+	mac := hmac.New(sha256.New, k.index)
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Encrypt returns the value encrypted under the primary key as enc:v1:<key id>:<data key>:<value>, an empty value
+// stays empty.
+func (k *Keyring) Encrypt(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+
+	wrapped, err := seal(k.keys[k.primary], dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := seal(aead, []byte(value))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + k.primary + ":" + encode(wrapped) + ":" + encode(sealed), nil
+}
+
+// Decrypt returns the value Encrypt encrypted, values that are not encrypted are returned as they are.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	id, wrapped, sealed, err := k.split(value)
+	if err != nil || id == "" {
+		return value, err
+	}
+
+	dataKey, err := open(k.keys[id], wrapped)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	plain, err := open(aead, sealed)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plain), nil
+}
+
+// Rewrap encrypts the data key of the value under the primary key, the value itself is left as it is.
+// Values that are not encrypted yet are encrypted.
+func (k *Keyring) Rewrap(value string) (string, error) {
+	id, wrapped, sealed, err := k.split(value)
+	if err != nil {
+		return "", err
+	}
+
+	if id == "" {
+		return k.Encrypt(value)
+	}
+
+	if id == k.primary {
+		return value, nil
+	}
+
+	dataKey, err := open(k.keys[id], wrapped)
+	if err != nil {
+		return "", err
+	}
+
+	if wrapped, err = seal(k.keys[k.primary], dataKey); err != nil {
+		return "", err
+	}
+
+	return prefix + k.primary + ":" + encode(wrapped) + ":" + encode(sealed), nil
+}
+
+// Stale tells whether the value is to be rewrapped, it is not encrypted or its data key is under a retired key.
+func (k *Keyring) Stale(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	id, _, _, err := k.split(value)
+
+	return err == nil && id != k.primary
+}
+
+// split takes the encrypted value apart, the id is empty for a value that is not encrypted.
+func (k *Keyring) split(value string) (id string, wrapped, sealed []byte, err error) {
+	if !strings.HasPrefix(value, prefix) {
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", nil, nil, ErrMalformed
+	}
+
+	if _, ok := k.keys[parts[0]]; !ok {
+		return "", nil, nil, ErrUnknownKey
+	}
+
+	if wrapped, err = base64.RawStdEncoding.DecodeString(parts[1]); err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+
+	if sealed, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+
+	return parts[0], wrapped, sealed, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts the plain text with a random nonce put in front of it.
+func seal(aead cipher.AEAD, plain []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+func open(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	return plain, nil
+}
+
+func encode(data []byte) string {
+	return base64.RawStdEncoding.EncodeToString(data)
+}
@@ -0,0 +1,180 @@
+package envelope
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKeyring(t *testing.T) {
+	index := newKey(t)
+	old := newKey(t)
+	current := newKey(t)
+
+	retired := load(t, map[string]any{"primary": "old", "keys": map[string]string{"old": old}, "index": index})
+	keyring := load(t, map[string]any{"primary": "new", "keys": map[string]string{"old": old, "new": current}, "index": index})
+
+	t.Run("encrypt", func(t *testing.T) {
+		value, err := keyring.Encrypt("john@example.com")
+		if err != nil || !strings.HasPrefix(value, "enc:v1:new:") || strings.Contains(value, "john") {
+			t.Fatalf("got %q, err = %v", value, err)
+		}
+
+		// every value has a data key of its own
+		if again, _ := keyring.Encrypt("john@example.com"); again == value {
+			t.Error("the same value is encrypted the same twice")
+		}
+
+		if plain, err := keyring.Decrypt(value); err != nil || plain != "john@example.com" {
+			t.Errorf("got %q, err = %v", plain, err)
+		}
+
+		if value, err = keyring.Encrypt(""); err != nil || value != "" {
+			t.Errorf("got %q, err = %v, want an empty value kept empty", value, err)
+		}
+	})
+
+	t.Run("values stored before the encryption", func(t *testing.T) {
+		if plain, err := keyring.Decrypt("john@example.com"); err != nil || plain != "john@example.com" {
+			t.Errorf("got %q, err = %v", plain, err)
+		}
+
+		if !keyring.Stale("john@example.com") || keyring.Stale("") {
+			t.Error("a value stored before the encryption is not stale")
+		}
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		value, err := retired.Encrypt("+77011234567")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !keyring.Stale(value) || retired.Stale(value) {
+			t.Errorf("%q is stale for the retired keyring or fresh for the current one", value)
+		}
+
+		rewrapped, err := keyring.Rewrap(value)
+		if err != nil || !strings.HasPrefix(rewrapped, "enc:v1:new:") || keyring.Stale(rewrapped) {
+			t.Fatalf("got %q, err = %v", rewrapped, err)
+		}
+
+		// only the data key is encrypted again, the value keeps its encryption
+		if rewrapped[strings.LastIndex(rewrapped, ":"):] != value[strings.LastIndex(value, ":"):] {
+			t.Errorf("rewrapped %q into %q, want the value left as it is", value, rewrapped)
+		}
+
+		if plain, err := keyring.Decrypt(rewrapped); err != nil || plain != "+77011234567" {
+			t.Errorf("got %q, err = %v", plain, err)
+		}
+
+		if _, err = retired.Decrypt(rewrapped); err != ErrUnknownKey {
+			t.Errorf("err = %v, want %v", err, ErrUnknownKey)
+		}
+
+		if again, err := keyring.Rewrap(rewrapped); err != nil || again != rewrapped {
+			t.Errorf("got %q, err = %v, want a fresh value left as it is", again, err)
+		}
+
+		if plain, err := keyring.Rewrap("plain"); err != nil || !strings.HasPrefix(plain, "enc:v1:new:") {
+			t.Errorf("got %q, err = %v, want the value encrypted", plain, err)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		value, err := keyring.Encrypt("John Doe")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// a character well inside the sealed value carries six bits of it
+		i := len(value) - 10
+		flipped := "A"
+		if value[i] == 'A' {
+			flipped = "B"
+		}
+		tampered := value[:i] + flipped + value[i+1:]
+
+		for _, value := range []string{tampered, "enc:v1:new:AAAA", "enc:v1:new:!!:!!"} {
+			if plain, err := keyring.Decrypt(value); err != ErrMalformed {
+				t.Errorf("Decrypt(%q) = %q, %v, want %v", value, plain, err, ErrMalformed)
+			}
+		}
+	})
+
+	t.Run("index", func(t *testing.T) {
+		if keyring.Index("email:john@example.com") != retired.Index("email:john@example.com") {
+			t.Error("the index changes with the primary key")
+		}
+
+		if keyring.Index("email:john@example.com") == keyring.Index("email:jane@example.com") {
+			t.Error("two values share an index")
+		}
+
+		other := load(t, map[string]any{"primary": "new", "keys": map[string]string{"new": current}, "index": newKey(t)})
+		if other.Index("email:john@example.com") == keyring.Index("email:john@example.com") {
+			t.Error("the index doesn't depend on the index key")
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	key := newKey(t)
+
+	for name, file := range map[string]map[string]any{
+		"primary missing":   {"primary": "b", "keys": map[string]string{"a": key}, "index": key},
+		"id with a colon":   {"primary": "a:b", "keys": map[string]string{"a:b": key}, "index": key},
+		"short key":         {"primary": "a", "keys": map[string]string{"a": "c2hvcnQ="}, "index": key},
+		"index key missing": {"primary": "a", "keys": map[string]string{"a": key}},
+	} {
+		if _, err := Load(write(t, file)); err == nil {
+			t.Errorf("%s: the key file is accepted", name)
+		}
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("a missing key file is accepted")
+	}
+}
+
+func newKey(t *testing.T) string {
+	t.Helper()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func load(t *testing.T, file map[string]any) *Keyring {
+	t.Helper()
+
+	keyring, err := Load(write(t, file))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return keyring
+}
+
+func write(t *testing.T, file map[string]any) string {
+	t.Helper()
+
+	content, err := json.Marshal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err = os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
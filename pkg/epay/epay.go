@@ -12,6 +12,7 @@ import (
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 )
@@ -302,6 +303,109 @@ func (s *Client) PayByCardID(cardID, insuranceID string, payment *Payment) (*Inv
 	}
 }
 
+// Transaction is the state of an invoice as ePay keeps it, StatusName is one of the Status constants.
+type Transaction struct {
+	ID           string          `json:"id"`
+	InvoiceID    string          `json:"invoiceID"`
+	Amount       decimal.Decimal `json:"amount"`
+	AmountBonus  decimal.Decimal `json:"amountBonus"`
+	Currency     string          `json:"currency"`
+	Terminal     string          `json:"terminal"`
+	CardMask     string          `json:"cardMask"`
+	Reference    string          `json:"reference"`
+	IntReference string          `json:"intReference"`
+	Reason       string          `json:"reason"`
+	ReasonCode   string          `json:"reasonCode"`
+	StatusName   string          `json:"statusName"`
+}
+
+const (
+	StatusNew       = "NEW"
+	Status3D        = "3D"
+	StatusAuth      = "AUTH"
+	StatusCharge    = "CHARGE"
+	StatusCancel    = "CANCEL"
+	StatusCancelOld = "CANCEL_OLD"
+	StatusRefund    = "REFUND"
+	StatusReject    = "REJECT"
+	StatusFailed    = "FAILED"
+)
+
+// Approved tells whether the payment of the invoice went through, it is authorized or already charged.
+func (t Transaction) Approved() bool {
+	return t.StatusName == StatusAuth || t.StatusName == StatusCharge
+}
+
+// Pending tells whether the cardholder has not finished the payment yet.
+func (t Transaction) Pending() bool {
+	return t.StatusName == StatusNew || t.StatusName == Status3D || t.StatusName == ""
+}
+
+// resultSucceeded is the result code of a status check that found the invoice.
+const resultSucceeded = "100"
+
+// CheckStatus reads the state of the invoice from ePay, the post link only says that it changed.
+func (s *Client) CheckStatus(invoiceID string) (*Transaction, error) {
+	token, err := s.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// setup request
+	path := s.credential.Endpoint + "/check-status/payment/transaction/" + url.PathEscape(invoiceID)
+	respBytes, code, err := s.handler("GET", path, nil, nil, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// check response code
+	switch code {
+	case 200:
+		// unmarshal response data
+		status := struct {
+			ResultCode    string      `json:"resultCode"`
+			ResultMessage string      `json:"resultMessage"`
+			Transaction   Transaction `json:"transaction"`
+		}{}
+		err = json.Unmarshal(respBytes, &status)
+		if err != nil {
+			return nil, err
+		}
+
+		if status.ResultCode != resultSucceeded {
+			return nil, fmt.Errorf("epay: status of invoice %s: %s %s", invoiceID, status.ResultCode, status.ResultMessage)
+		}
+
+		return &status.Transaction, nil
+	default:
+		return nil, errors.New(string(respBytes))
+	}
+}
+
+// Refund returns the amount of the transaction to the cardholder, in full or in part. ePay keeps the refunds
+// of a transaction from adding up to more than its amount.
+func (s *Client) Refund(transactionID string, amount decimal.Decimal) error {
+	token, err := s.GetToken()
+	if err != nil {
+		return err
+	}
+
+	// setup request
+	path := s.credential.Endpoint + "/operation/" + url.PathEscape(transactionID) + "/refund?amount=" + amount.String()
+	respBytes, code, err := s.handler("POST", path, nil, nil, token)
+	if err != nil {
+		return err
+	}
+
+	// check response code
+	switch code {
+	case 200:
+		return nil
+	default:
+		return fmt.Errorf("epay: refund of transaction %s: %s", transactionID, string(respBytes))
+	}
+}
+
 func (s *Client) handler(method string, url string, body []byte, writer *multipart.Writer, token *Token) ([]byte, int, error) {
 	// setup request
 	req, err := http.NewRequest(method, url, bytes.NewReader(body))
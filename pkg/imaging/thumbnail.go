@@ -0,0 +1,123 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	// the decoders register themselves with the image package
+	_ "image/gif"
+)
+
+const jpegQuality = 85
+
+var (
+	ErrUnsupported = errors.New("imaging: unsupported picture format")
+	ErrTooLarge    = errors.New("imaging: picture has too many pixels")
+)
+
+// Picture describes an image file without its pixels.
+type Picture struct {
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Inspect sniffs the content type of the file and reads its dimensions from the header, the pixels are not decoded.
+// Pictures over maxPixels are refused before anything allocates memory for them.
+func Inspect(data []byte, maxPixels int) (p Picture, err error) {
+	p.ContentType = http.DetectContentType(data)
+	switch p.ContentType {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return p, ErrUnsupported
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || "image/"+format != p.ContentType {
+		return p, ErrUnsupported
+	}
+	p.Width, p.Height = config.Width, config.Height
+
+	if p.Width <= 0 || p.Height <= 0 {
+		return p, ErrUnsupported
+	}
+
+	if p.Width*p.Height > maxPixels {
+		return p, ErrTooLarge
+	}
+
+	return
+}
+
+// Thumbnail scales the picture down to fit a size by size box keeping its proportions, smaller pictures keep their size.
+// JPEG pictures give a JPEG thumbnail, the others a PNG one so transparency survives.
+func Thumbnail(data []byte, size int) (thumb []byte, contentType string, err error) {
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupported
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, atLeast(1, height*size/width)
+		} else {
+			width, height = atLeast(1, width*size/height), size
+		}
+	}
+	dst := scale(src, width, height)
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality})
+		contentType = "image/jpeg"
+	} else {
+		err = png.Encode(&buf, dst)
+		contentType = "image/png"
+	}
+
+	return buf.Bytes(), contentType, err
+}
+
+// scale averages the source pixels every destination pixel covers, which keeps downscaled pictures free of aliasing.
+func scale(src image.Image, width, height int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	bounds := src.Bounds()
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := atLeast(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := atLeast(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+
+	return dst
+}
+
+func atLeast(min, value int) int {
+	if value < min {
+		return min
+	}
+
+	return value
+}
@@ -0,0 +1,102 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// picture encodes a width by height picture in the format, png, jpeg or gif.
+func picture(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestThumbnail(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        string
+		width, height int
+		size          int
+		wantWidth     int
+		wantHeight    int
+		contentType   string
+	}{
+		{name: "landscape", format: "png", width: 400, height: 200, size: 100, wantWidth: 100, wantHeight: 50, contentType: "image/png"},
+		{name: "portrait", format: "jpeg", width: 100, height: 300, size: 100, wantWidth: 33, wantHeight: 100, contentType: "image/jpeg"},
+		{name: "square", format: "png", width: 256, height: 256, size: 64, wantWidth: 64, wantHeight: 64, contentType: "image/png"},
+		{name: "smaller", format: "jpeg", width: 20, height: 10, size: 100, wantWidth: 20, wantHeight: 10, contentType: "image/jpeg"},
+		{name: "thin", format: "png", width: 1000, height: 2, size: 100, wantWidth: 100, wantHeight: 1, contentType: "image/png"},
+		{name: "gif", format: "gif", width: 200, height: 100, size: 50, wantWidth: 50, wantHeight: 25, contentType: "image/png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thumb, contentType, err := Thumbnail(picture(t, tt.format, tt.width, tt.height), tt.size)
+			if err != nil || contentType != tt.contentType {
+				t.Fatalf("got %s, err = %v, want %s", contentType, err, tt.contentType)
+			}
+
+			p, err := Inspect(thumb, tt.size*tt.size)
+			if err != nil || p.ContentType != tt.contentType || p.Width != tt.wantWidth || p.Height != tt.wantHeight {
+				t.Errorf("got %+v, err = %v, want %dx%d %s", p, err, tt.wantWidth, tt.wantHeight, tt.contentType)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		if _, _, err := Thumbnail([]byte("not a picture"), 100); err != ErrUnsupported {
+			t.Errorf("err = %v, want %v", err, ErrUnsupported)
+		}
+	})
+}
+
+func TestInspect(t *testing.T) {
+	t.Run("picture", func(t *testing.T) {
+		p, err := Inspect(picture(t, "jpeg", 40, 30), 1200)
+		if err != nil || p != (Picture{ContentType: "image/jpeg", Width: 40, Height: 30}) {
+			t.Errorf("got %+v, err = %v, want a 40x30 jpeg", p, err)
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		if _, err := Inspect(picture(t, "png", 40, 30), 1199); err != ErrTooLarge {
+			t.Errorf("err = %v, want %v", err, ErrTooLarge)
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		// a truncated picture is sniffed as one but its header can't be read
+		data := picture(t, "png", 40, 30)
+		for _, data := range [][]byte{[]byte("<svg></svg>"), data[:16]} {
+			if _, err := Inspect(data, 1200); err != ErrUnsupported {
+				t.Errorf("got %v for %q, want %v", err, data, ErrUnsupported)
+			}
+		}
+	})
+}
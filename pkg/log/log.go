@@ -5,14 +5,20 @@ import (
 
 	"go.elastic.co/apm/module/apmzap"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func New(version, description string) *zap.Logger {
 	var log *zap.Logger
 	var err error
-	var wrappedCore = zap.WrapCore((&apmzap.Core{
+	var apmCore = &apmzap.Core{
 		FatalFlushTimeout: 10000,
-	}).WrapCore)
+	}
+	// personal data is redacted before the entries reach the output and APM alike, each of them is wrapped on its
+	// own so the sampling of the output doesn't apply to APM and APM doesn't see what the output dropped
+	var wrappedCore = zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(newRedactCore(core), newRedactCore(apmCore))
+	})
 
 	if os.Getenv("DEBUG") != "" {
 		cfg := zap.NewDevelopmentConfig()
@@ -0,0 +1,96 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"payment-service/pkg/pii"
+)
+
+// sensitiveKeys name the fields that hold personal data as a whole, they are logged redacted whatever their value.
+var sensitiveKeys = map[string]bool{
+	"email":      true,
+	"phone":      true,
+	"account_id": true,
+	"card":       true,
+	"card_mask":  true,
+	"card_id":    true,
+}
+
+// redactCore keeps personal data out of the logs: emails, phones and card numbers or masks are redacted from
+// the message and the string, error, stringer and object fields, the sensitive fields are redacted whole.
+type redactCore struct {
+	zapcore.Core
+}
+
+func newRedactCore(core zapcore.Core) zapcore.Core {
+	return redactCore{Core: core}
+}
+
+func (c redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return redactCore{Core: c.Core.With(redactFields(fields))}
+}
+
+// Check asks the wrapped core whether it writes the entry, so its levels and sampling still apply, and adds the
+// redacting core in its place, so the entry is redacted before the wrapped core writes it. The wrapped core has to
+// write whatever it accepted, a tee is wrapped child by child instead.
+func (c redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Check(entry, nil) != nil {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+func (c redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = pii.Redact(entry.Message)
+
+	return c.Core.Write(entry, redactFields(fields))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = redactField(field)
+	}
+
+	return redacted
+}
+
+func redactField(field zapcore.Field) zapcore.Field {
+	text, ok := fieldText(field)
+	if !ok {
+		return field
+	}
+
+	if sensitiveKeys[field.Key] {
+		return zap.String(field.Key, pii.Redacted)
+	}
+
+	return zap.String(field.Key, pii.Redact(text))
+}
+
+// fieldText returns the text the field is logged as, numbers, times and the like carry no personal data.
+func fieldText(field zapcore.Field) (text string, ok bool) {
+	switch field.Type {
+	case zapcore.StringType:
+		return field.String, true
+	case zapcore.ByteStringType:
+		return string(field.Interface.([]byte)), true
+	case zapcore.ErrorType:
+		return field.Interface.(error).Error(), true
+	case zapcore.StringerType:
+		return fmt.Sprint(field.Interface), true
+	case zapcore.ReflectType:
+		content, err := json.Marshal(field.Interface)
+		if err != nil {
+			return fmt.Sprintf("%+v", field.Interface), true
+		}
+		return string(content), true
+	}
+
+	return "", false
+}
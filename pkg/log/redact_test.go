@@ -0,0 +1,45 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRedactCore(t *testing.T) {
+	var buf bytes.Buffer
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(&buf), zapcore.InfoLevel,
+	)
+	logger := zap.New(newRedactCore(core)).With(zap.String("email", "john@example.com"))
+
+	logger.Info("billing paid by +7 701 123-45-67",
+		zap.String("account_id", "KZ12345"),
+		zap.Error(errors.New("card 440043******1234 declined")),
+		zap.Any("payer", map[string]string{"contact": "jane@example.com"}),
+		zap.String("billing_id", "1234567890"),
+		zap.Int("amount", 2500),
+	)
+	logger.Debug("john@example.com is below the level")
+
+	out := buf.String()
+	for _, leaked := range []string{"john@example.com", "123-45-67", "KZ12345", "440043", "jane@example.com"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("%q is logged: %s", leaked, out)
+		}
+	}
+
+	for _, kept := range []string{`"billing_id":"1234567890"`, `"amount":2500`, `"email":"[redacted]"`} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("%s is missing: %s", kept, out)
+		}
+	}
+
+	if lines := strings.Count(out, "\n"); lines != 1 {
+		t.Errorf("%d entries are logged, want the debug one dropped by the wrapped core", lines)
+	}
+}
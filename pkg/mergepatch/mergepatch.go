@@ -0,0 +1,95 @@
+// Package mergepatch applies JSON Merge Patch documents (RFC 7386) to the JSON form of values.
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// ContentType is the media type of a merge patch document.
+const ContentType = "application/merge-patch+json"
+
+var ErrNotObject = errors.New("must be a JSON object")
+
+// Error tells the patch doesn't apply to the value: it is malformed, names a field the value doesn't have
+// or leaves the value invalid.
+type Error struct {
+	Err error
+}
+
+func (e *Error) Error() string {
+	return "patch: " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Apply patches the value dest points to: fields the patch names are replaced, null removes them
+// and objects are merged recursively. The value is checked with its Validate method when it has one.
+func Apply(dest any, patch []byte) (err error) {
+	var changes any
+	if err = json.Unmarshal(patch, &changes); err != nil {
+		return &Error{Err: err}
+	}
+
+	if _, ok := changes.(map[string]any); !ok {
+		return &Error{Err: ErrNotObject}
+	}
+
+	data, err := json.Marshal(dest)
+	if err != nil {
+		return
+	}
+
+	var doc any
+	if err = json.Unmarshal(data, &doc); err != nil {
+		return
+	}
+
+	if data, err = json.Marshal(merge(doc, changes)); err != nil {
+		return
+	}
+
+	// removed fields have to come out zero, decoding over the old value would keep them
+	value := reflect.ValueOf(dest).Elem()
+	value.Set(reflect.Zero(value.Type()))
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(dest); err != nil {
+		return &Error{Err: err}
+	}
+
+	if validator, ok := dest.(interface{ Validate() error }); ok {
+		if err = validator.Validate(); err != nil {
+			return &Error{Err: err}
+		}
+	}
+
+	return
+}
+
+func merge(doc, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	fields, ok := doc.(map[string]any)
+	if !ok {
+		fields = make(map[string]any)
+	}
+
+	for key, value := range changes {
+		if value == nil {
+			delete(fields, key)
+			continue
+		}
+		fields[key] = merge(fields[key], value)
+	}
+
+	return fields
+}
@@ -0,0 +1,89 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestMerge runs the examples of RFC 7386, Appendix A.
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	decode := func(t *testing.T, data string) (value any) {
+		t.Helper()
+		if err := json.Unmarshal([]byte(data), &value); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.doc+" "+tt.patch, func(t *testing.T) {
+			got := merge(decode(t, tt.doc), decode(t, tt.patch))
+			if want := decode(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+type patched struct {
+	Name    string            `json:"name"`
+	Country *string           `json:"country,omitempty"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels"`
+}
+
+func (p *patched) Validate() error {
+	if p.Name == "" {
+		return errors.New("name: cannot be blank")
+	}
+	return nil
+}
+
+func TestApply(t *testing.T) {
+	country := "KZ"
+	original := patched{Name: "Juice", Country: &country, Tags: []string{"a", "b"}, Labels: map[string]string{"x": "1", "y": "2"}}
+
+	t.Run("patch", func(t *testing.T) {
+		got := original
+		if err := Apply(&got, []byte(`{"country":null,"tags":["c"],"labels":{"x":null,"z":"3"}}`)); err != nil {
+			t.Fatal(err)
+		}
+
+		want := patched{Name: "Juice", Tags: []string{"c"}, Labels: map[string]string{"y": "2", "z": "3"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, patch := range []string{`{"name":`, `["c"]`, `null`, `{"price":1}`, `{"name":null}`, `{"tags":"c"}`} {
+			got := original
+			var patchErr *Error
+			if err := Apply(&got, []byte(patch)); !errors.As(err, &patchErr) {
+				t.Errorf("got %v for %s, want a patch error", err, patch)
+			}
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package pii
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redacted replaces the personal data found in free text.
+const Redacted = "[redacted]"
+
+var (
+	// the @ and + may be escaped in URLs
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+(?:@|%40)[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// phones are only told from other numbers by the leading plus, e.g. +7 701 123-45-67
+	phonePattern = regexp.MustCompile(`(?:\+|%2B)\d[\d\s()\-]{7,}\d`)
+	// card numbers, also written in groups, and the masks of saved cards, e.g. 440043******1234
+	cardPattern = regexp.MustCompile(`\b\d{4,6}[\d*Xx\-\s]{3,11}\d{4}\b`)
+)
+
+// Redact removes emails, phones and card numbers or masks from the text.
+func Redact(text string) string {
+	text = emailPattern.ReplaceAllString(text, Redacted)
+	text = phonePattern.ReplaceAllString(text, Redacted)
+	text = cardPattern.ReplaceAllStringFunc(text, func(match string) string {
+		// ids and amounts made of digits only are kept unless they are long enough to be a card number
+		if !strings.ContainsAny(match, "*Xx") && digits(match) < 13 {
+			return match
+		}
+		return Redacted
+	})
+
+	return text
+}
+
+// MaskEmail keeps the first letter of the mailbox and the domain, e.g. j***@example.com.
+func MaskEmail(value string) string {
+	at := strings.LastIndex(value, "@")
+	if at < 1 {
+		return Mask(value)
+	}
+
+	return first(value[:at]) + "***" + value[at:]
+}
+
+// MaskPhone keeps the last four digits, e.g. ********4567.
+func MaskPhone(value string) string {
+	return Mask(value)
+}
+
+// MaskName keeps the first letter of every word, e.g. J*** D***.
+func MaskName(value string) string {
+	words := strings.Fields(value)
+	for i, word := range words {
+		words[i] = first(word) + "***"
+	}
+
+	return strings.Join(words, " ")
+}
+
+// Mask keeps the last four characters of a value longer than eight, the shorter ones are masked whole.
+func Mask(value string) string {
+	count := utf8.RuneCountInString(value)
+	if count == 0 {
+		return ""
+	}
+
+	if count <= 8 {
+		return strings.Repeat("*", count)
+	}
+
+	runes := []rune(value)
+
+	return strings.Repeat("*", count-4) + string(runes[count-4:])
+}
+
+func first(value string) string {
+	r, _ := utf8.DecodeRuneInString(value)
+	if r == utf8.RuneError {
+		return ""
+	}
+
+	return string(r)
+}
+
+func digits(value string) (count int) {
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			count++
+		}
+	}
+
+	return
+}
@@ -0,0 +1,38 @@
+package pii
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	for text, want := range map[string]string{
+		"payment failed for john.doe@example.com":        "payment failed for [redacted]",
+		"GET /billings?email=john%40example.com":         "GET /billings?email=[redacted]",
+		"sms to +7 701 123-45-67 was not sent":           "sms to [redacted] was not sent",
+		"card 440043******1234 declined":                 "card [redacted] declined",
+		"card 4400 4300 1234 5678 declined":              "card [redacted] declined",
+		"billing 1234567890 of 2500.00 KZT is paid":      "billing 1234567890 of 2500.00 KZT is paid",
+		"order 77011234567 without a plus is not phoned": "order 77011234567 without a plus is not phoned",
+	} {
+		if got := Redact(text); got != want {
+			t.Errorf("Redact(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	for _, tt := range []struct {
+		mask        func(string) string
+		value, want string
+	}{
+		{MaskEmail, "john@example.com", "j***@example.com"},
+		{MaskEmail, "@example.com", "********.com"},
+		{MaskPhone, "+77011234567", "********4567"},
+		{MaskName, "John  Doe", "J*** D***"},
+		{MaskName, "Айгерим", "А***"},
+		{Mask, "secret", "******"},
+		{Mask, "", ""},
+	} {
+		if got := tt.mask(tt.value); got != tt.want {
+			t.Errorf("mask(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
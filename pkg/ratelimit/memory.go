@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often the buckets refilled to full are dropped, a dropped bucket is created full again.
+const pruneInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	at     time.Time
+	limit  Limit
+}
+
+// Memory keeps the buckets in the process, every replica limits the callers on its own.
+type Memory struct {
+	mu       sync.Mutex
+	buckets  map[string]*bucket
+	prunedAt time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		buckets:  make(map[string]*bucket),
+		prunedAt: time.Now(),
+	}
+}
+
+func (s *Memory) Take(ctx context.Context, key string, limit Limit) (res Result, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.prunedAt) > pruneInterval {
+		s.prune(now)
+	}
+
+	data, ok := s.buckets[key]
+	if !ok {
+		data = &bucket{tokens: float64(limit.Count), at: now}
+		s.buckets[key] = data
+	}
+	data.limit = limit
+
+	data.tokens, res = take(refill(data.tokens, now.Sub(data.at), limit), limit)
+	data.at = now
+
+	return
+}
+
+func (s *Memory) prune(now time.Time) {
+	for key, data := range s.buckets {
+		if now.Sub(data.at) >= data.limit.Period {
+			delete(s.buckets, key)
+		}
+	}
+	s.prunedAt = now
+}
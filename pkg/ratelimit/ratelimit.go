@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrLimited = errors.New("ratelimit: too many requests, retry later")
+
+// Limit lets Count requests through per Period. Every caller has a bucket of Count tokens refilled evenly
+// over the Period, a request takes a token, so a caller quiet for a while may send a burst of Count requests.
+type Limit struct {
+	Count  int
+	Period time.Duration
+}
+
+// ParseLimit reads a limit written as count/period, e.g. 30/1m or 5/s.
+func ParseLimit(value string) (limit Limit, err error) {
+	count, period, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return limit, fmt.Errorf("ratelimit: limit %q is not count/period", value)
+	}
+
+	if limit.Count, err = strconv.Atoi(count); err != nil || limit.Count < 0 {
+		return limit, fmt.Errorf("ratelimit: count of %q is not a number", value)
+	}
+
+	// a bare unit is one of it, s is 1s
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	if limit.Period, err = time.ParseDuration(period); err != nil || limit.Period <= 0 {
+		return limit, fmt.Errorf("ratelimit: period of %q is not a duration", value)
+	}
+
+	return limit, nil
+}
+
+// Enabled tells whether the limit lets only some requests through, a zero limit lets through all of them.
+func (l Limit) Enabled() bool {
+	return l.Count > 0 && l.Period > 0
+}
+
+// rate is the number of tokens refilled per nanosecond.
+func (l Limit) rate() float64 {
+	return float64(l.Count) / float64(l.Period)
+}
+
+// Result tells whether the request took a token. Reset is the time until the bucket is full again,
+// RetryAfter the time until the next token when the request was turned away.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets of the callers.
+type Store interface {
+	// Take takes a token from the bucket of the key, the bucket is created full.
+	Take(ctx context.Context, key string, limit Limit) (res Result, err error)
+}
+
+// refill returns the tokens of the bucket holding tokens elapsed ago.
+func refill(tokens float64, elapsed time.Duration, limit Limit) float64 {
+	if elapsed > 0 {
+		tokens += float64(elapsed) * limit.rate()
+	}
+
+	return math.Min(tokens, float64(limit.Count))
+}
+
+// take takes a token if the bucket has one and describes the bucket left.
+func take(tokens float64, limit Limit) (left float64, res Result) {
+	res = Result{Limit: limit.Count}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration(math.Ceil((1 - tokens) / limit.rate()))
+	}
+
+	res.Remaining = int(tokens)
+	res.Reset = time.Duration(math.Ceil((float64(limit.Count) - tokens) / limit.rate()))
+
+	return tokens, res
+}
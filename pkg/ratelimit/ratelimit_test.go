@@ -0,0 +1,137 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	for value, want := range map[string]Limit{
+		"30/1m":   {Count: 30, Period: time.Minute},
+		"5/s":     {Count: 5, Period: time.Second},
+		" 100/h ": {Count: 100, Period: time.Hour},
+		"0/1m":    {Count: 0, Period: time.Minute},
+	} {
+		if got, err := ParseLimit(value); err != nil || got != want {
+			t.Errorf("ParseLimit(%q) = %+v, %v, want %+v", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{"", "30", "-1/1m", "x/1m", "30/", "30/0s", "30/fortnight"} {
+		if got, err := ParseLimit(value); err == nil {
+			t.Errorf("ParseLimit(%q) = %+v, want an error", value, got)
+		}
+	}
+
+	if (Limit{Count: 0, Period: time.Minute}).Enabled() || !(Limit{Count: 1, Period: time.Minute}).Enabled() {
+		t.Error("only a positive count enables the limit")
+	}
+}
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemory()
+	limit := Limit{Count: 3, Period: time.Hour}
+
+	// a new bucket is full, the burst takes all of it
+	for i := 0; i < limit.Count; i++ {
+		res, err := s.Take(ctx, "alice", limit)
+		if err != nil || !res.Allowed || res.Remaining != limit.Count-i-1 || res.Limit != limit.Count {
+			t.Fatalf("request %d: got %+v, err = %v", i, res, err)
+		}
+	}
+
+	res, err := s.Take(ctx, "alice", limit)
+	if err != nil || res.Allowed || res.Remaining != 0 {
+		t.Errorf("got %+v, err = %v, want the request turned away", res, err)
+	}
+
+	// a token is refilled every period/count
+	if res.RetryAfter <= 0 || res.RetryAfter > limit.Period/time.Duration(limit.Count) {
+		t.Errorf("retry after %s, want up to %s", res.RetryAfter, limit.Period/time.Duration(limit.Count))
+	}
+
+	if res.Reset <= limit.Period-time.Second || res.Reset > limit.Period {
+		t.Errorf("reset %s, want about %s", res.Reset, limit.Period)
+	}
+
+	if res, err = s.Take(ctx, "bob", limit); err != nil || !res.Allowed {
+		t.Errorf("got %+v, err = %v, want the bucket of another caller untouched", res, err)
+	}
+}
+
+func TestRefill(t *testing.T) {
+	limit := Limit{Count: 10, Period: 10 * time.Second}
+
+	if got := refill(0, 3*time.Second, limit); got != 3 {
+		t.Errorf("refilled %v, want 3", got)
+	}
+
+	if got := refill(8, time.Minute, limit); got != 10 {
+		t.Errorf("refilled %v, want the bucket full at 10", got)
+	}
+
+	if got := refill(5, -time.Second, limit); got != 5 {
+		t.Errorf("refilled %v, want a clock going back to refill nothing", got)
+	}
+
+	left, res := take(0.5, limit)
+	if res.Allowed || left != 0.5 || res.RetryAfter != 500*time.Millisecond {
+		t.Errorf("got %v left, %+v, want a retry after the half token missing", left, res)
+	}
+}
+
+func TestRules(t *testing.T) {
+	rules, err := ParseRules(map[string]string{
+		"/api/v1/*":                          "100/1m",
+		"POST /api/v1/billings":              "30/1m",
+		"/api/v1/billings/{id}":              "60/1m",
+		"post /api/v1/billings/{id}/refunds": "5/1m",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var routes []string
+	for _, rule := range rules {
+		routes = append(routes, rule.Route())
+	}
+
+	want := []string{"POST /api/v1/billings/{id}/refunds", "/api/v1/billings/{id}", "POST /api/v1/billings", "/api/v1/*"}
+	if len(routes) != len(want) {
+		t.Fatalf("routes = %q, want %q", routes, want)
+	}
+	for i := range want {
+		if routes[i] != want[i] {
+			t.Errorf("routes = %q, want %q", routes, want)
+			break
+		}
+	}
+
+	match := func(method, path string) string {
+		for _, rule := range rules {
+			if rule.Match(method, path) {
+				return rule.Route()
+			}
+		}
+		return ""
+	}
+
+	for _, tt := range []struct{ method, path, route string }{
+		{"POST", "/api/v1/billings/42/refunds", "POST /api/v1/billings/{id}/refunds"},
+		{"GET", "/api/v1/billings/42/refunds", "/api/v1/*"},
+		{"GET", "/api/v1/billings/42", "/api/v1/billings/{id}"},
+		{"POST", "/api/v1/billings", "POST /api/v1/billings"},
+		{"GET", "/api/v1/billings", "/api/v1/*"},
+		{"GET", "/healthz", ""},
+	} {
+		if got := match(tt.method, tt.path); got != tt.route {
+			t.Errorf("%s %s matched %q, want %q", tt.method, tt.path, got, tt.route)
+		}
+	}
+
+	if _, err = ParseRules(map[string]string{"api/v1/billings": "1/s"}); err == nil {
+		t.Error("a route without a leading slash is accepted")
+	}
+}
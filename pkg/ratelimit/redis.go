@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills and takes from the bucket in one step and returns the tokens it had before the take, the clock
+// of redis is used so the replicas agree on it. The bucket expires once it would be full again, which is the same
+// as not having one.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000000 + tonumber(clock[2])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'at')
+local tokens = tonumber(state[1]) or capacity
+local at = tonumber(state[2]) or now
+
+tokens = math.min(capacity, tokens + math.max(0, now - at) * capacity / period)
+local refilled = tokens
+if tokens >= 1 then
+	tokens = tokens - 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'at', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(period / 1000))
+
+return tostring(refilled)
+`)
+
+// Redis keeps the buckets in redis, the replicas share them.
+type Redis struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedis(client *redis.Client, prefix string) *Redis {
+	return &Redis{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *Redis) Take(ctx context.Context, key string, limit Limit) (res Result, err error) {
+	period := limit.Period.Microseconds()
+	if period < 1 {
+		period = 1
+	}
+
+	value, err := takeScript.Run(ctx, s.client, []string{s.prefix + key}, limit.Count, period).Text()
+	if err != nil {
+		return
+	}
+
+	refilled, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+
+	_, res = take(refilled, limit)
+
+	return
+}
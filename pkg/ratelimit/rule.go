@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Rule limits a route on its own. Method is empty for any method, in Pattern a {name} segment matches any segment
+// and a trailing * matches the rest of the path, e.g. /api/v1/billings/{id} or /api/v1/products/*.
+type Rule struct {
+	Method  string
+	Pattern string
+	Limit   Limit
+}
+
+// ParseRules reads the routes keyed by [METHOD ]pattern with count/period limits, e.g. POST /api/v1/billings: 30/1m.
+// The most specific rule comes first.
+func ParseRules(routes map[string]string) (rules []Rule, err error) {
+	for route, value := range routes {
+		rule := Rule{Pattern: strings.TrimSpace(route)}
+		if method, pattern, ok := strings.Cut(rule.Pattern, " "); ok {
+			rule.Method, rule.Pattern = strings.ToUpper(method), strings.TrimSpace(pattern)
+		}
+
+		if !strings.HasPrefix(rule.Pattern, "/") {
+			return nil, fmt.Errorf("ratelimit: route %q is not [METHOD ]/path", route)
+		}
+
+		if rule.Limit, err = ParseLimit(value); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		if a, b := segments(rules[i].Pattern), segments(rules[j].Pattern); a != b {
+			return a > b
+		}
+		if rules[i].Pattern != rules[j].Pattern {
+			return rules[i].Pattern < rules[j].Pattern
+		}
+		return rules[i].Method > rules[j].Method
+	})
+
+	return rules, nil
+}
+
+// Route names the bucket of the rule.
+func (r Rule) Route() string {
+	if r.Method == "" {
+		return r.Pattern
+	}
+
+	return r.Method + " " + r.Pattern
+}
+
+func (r Rule) Match(method, path string) bool {
+	if r.Method != "" && r.Method != method {
+		return false
+	}
+
+	patterns := strings.Split(strings.Trim(r.Pattern, "/"), "/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i, pattern := range patterns {
+		if pattern == "*" && i == len(patterns)-1 {
+			return true
+		}
+
+		if i >= len(parts) {
+			return false
+		}
+
+		if !strings.HasPrefix(pattern, "{") && pattern != parts[i] {
+			return false
+		}
+	}
+
+	return len(parts) == len(patterns)
+}
+
+// segments weighs the literal segments of the pattern above the placeholders, a wildcard weighs nothing.
+func segments(pattern string) (weight int) {
+	for _, segment := range strings.Split(strings.Trim(pattern, "/"), "/") {
+		switch {
+		case segment == "*":
+		case strings.HasPrefix(segment, "{"):
+			weight += 1
+		default:
+			weight += 2
+		}
+	}
+
+	return
+}
@@ -22,6 +22,16 @@ func OK(w http.ResponseWriter, r *http.Request, data any) {
 	render.JSON(w, r, v)
 }
 
+func Accepted(w http.ResponseWriter, r *http.Request, data any) {
+	render.Status(r, http.StatusAccepted)
+
+	v := Object{
+		Success: true,
+		Data:    data,
+	}
+	render.JSON(w, r, v)
+}
+
 func NoContent(w http.ResponseWriter, r *http.Request) {
 	render.Status(r, http.StatusOK)
 
@@ -42,6 +52,26 @@ func BadRequest(w http.ResponseWriter, r *http.Request, err error, data any) {
 	render.JSON(w, r, v)
 }
 
+func Unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	render.Status(r, http.StatusUnauthorized)
+
+	v := Object{
+		Success: false,
+		Message: err.Error(),
+	}
+	render.JSON(w, r, v)
+}
+
+func Forbidden(w http.ResponseWriter, r *http.Request, err error) {
+	render.Status(r, http.StatusForbidden)
+
+	v := Object{
+		Success: false,
+		Message: err.Error(),
+	}
+	render.JSON(w, r, v)
+}
+
 func NotFound(w http.ResponseWriter, r *http.Request, err error) {
 	render.Status(r, http.StatusNotFound)
 
@@ -52,6 +82,36 @@ func NotFound(w http.ResponseWriter, r *http.Request, err error) {
 	render.JSON(w, r, v)
 }
 
+func Conflict(w http.ResponseWriter, r *http.Request, err error) {
+	render.Status(r, http.StatusConflict)
+
+	v := Object{
+		Success: false,
+		Message: err.Error(),
+	}
+	render.JSON(w, r, v)
+}
+
+func PreconditionFailed(w http.ResponseWriter, r *http.Request, err error) {
+	render.Status(r, http.StatusPreconditionFailed)
+
+	v := Object{
+		Success: false,
+		Message: err.Error(),
+	}
+	render.JSON(w, r, v)
+}
+
+func PreconditionRequired(w http.ResponseWriter, r *http.Request, err error) {
+	render.Status(r, http.StatusPreconditionRequired)
+
+	v := Object{
+		Success: false,
+		Message: err.Error(),
+	}
+	render.JSON(w, r, v)
+}
+
 func InternalServerError(w http.ResponseWriter, r *http.Request, err error) {
 	render.Status(r, http.StatusInternalServerError)
 
@@ -61,3 +121,13 @@ func InternalServerError(w http.ResponseWriter, r *http.Request, err error) {
 	}
 	render.JSON(w, r, v)
 }
+
+func TooManyRequests(w http.ResponseWriter, r *http.Request, err error) {
+	render.Status(r, http.StatusTooManyRequests)
+
+	v := Object{
+		Success: false,
+		Message: err.Error(),
+	}
+	render.JSON(w, r, v)
+}
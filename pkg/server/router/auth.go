@@ -0,0 +1,94 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+
+	"payment-service/pkg/auth"
+	"payment-service/pkg/server/response"
+	"payment-service/pkg/store"
+)
+
+// Authenticate finds the caller of the request with the first authenticator that recognizes its credentials and puts
+// it into the context, the context is scoped to the tenant of the caller or to every tenant for an operator.
+// Requests without credentials pass on anonymous and scoped to no tenant for RequireScope to turn away, invalid
+// credentials are answered with 401 right away.
+func Authenticate(authenticators ...auth.Authenticator) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, authenticator := range authenticators {
+				principal, err := authenticator.Authenticate(r)
+				switch err {
+				case nil:
+					ctx := auth.WithPrincipal(r.Context(), principal)
+					if principal.Operator() {
+						ctx = store.WithOperator(ctx)
+					} else {
+						ctx = store.WithTenant(ctx, principal.Tenant)
+					}
+					next.ServeHTTP(w, r.WithContext(ctx))
+					return
+				case auth.ErrNoCredentials:
+					continue
+				case auth.ErrInvalidCredentials:
+					unauthorized(w, r, err)
+				default:
+					response.InternalServerError(w, r, err)
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireScope lets through the requests of an authenticated caller granted all the scopes, no scopes only require
+// the caller to be authenticated. Anonymous requests get 401, callers lacking a scope 403.
+func RequireScope(scopes ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				unauthorized(w, r, auth.ErrNoCredentials)
+				return
+			}
+
+			for _, scope := range scopes {
+				if !principal.HasScope(scope) {
+					w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+strings.Join(scopes, " ")+`"`)
+					response.Forbidden(w, r, auth.ErrForbidden)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireOperator lets through the requests of a caller acting for every tenant, e.g. to the admin routes whose data
+// is not scoped to a tenant. Anonymous requests get 401, callers of a tenant 403 whatever their scopes.
+func RequireOperator() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				unauthorized(w, r, auth.ErrNoCredentials)
+				return
+			}
+
+			if !principal.Operator() {
+				response.Forbidden(w, r, auth.ErrOperatorOnly)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	response.Unauthorized(w, r, err)
+}
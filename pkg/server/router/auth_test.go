@@ -0,0 +1,99 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"payment-service/pkg/auth"
+	"payment-service/pkg/store"
+)
+
+// staticAuthenticator authenticates the requests carrying the token as the principal, the forged token is invalid
+// and the other ones are left to the next authenticator.
+type staticAuthenticator struct {
+	token     string
+	principal auth.Principal
+}
+
+func (a staticAuthenticator) Authenticate(r *http.Request) (auth.Principal, error) {
+	switch r.Header.Get("Authorization") {
+	case "Bearer " + a.token:
+		return a.principal, nil
+	case "Bearer forged":
+		return auth.Principal{}, auth.ErrInvalidCredentials
+	}
+
+	return auth.Principal{}, auth.ErrNoCredentials
+}
+
+func TestAuthenticate(t *testing.T) {
+	authenticate := Authenticate(
+		staticAuthenticator{token: "tenant", principal: auth.Principal{Subject: "alice", Tenant: "acme", Scopes: []string{"catalogue:read"}}},
+	)
+
+	var tenant, subject string
+	handler := authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, subject = store.TenantID(r.Context()), auth.Actor(r.Context())
+	}))
+
+	serve(handler, "tenant")
+	if tenant != "acme" || subject != "alice" {
+		t.Errorf("tenant = %q, subject = %q, want the context scoped to acme for alice", tenant, subject)
+	}
+
+	tenant, subject = "", ""
+	if code := serve(handler, ""); code != http.StatusOK || tenant != "" || subject != "" {
+		t.Errorf("anonymous request: %d, tenant = %q, subject = %q", code, tenant, subject)
+	}
+
+	if code := serve(handler, "forged"); code != http.StatusUnauthorized {
+		t.Errorf("invalid credentials: %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+func TestRequireOperator(t *testing.T) {
+	authenticate := Authenticate(
+		staticAuthenticator{token: "tenant", principal: auth.Principal{Subject: "alice", Tenant: "acme", Scopes: []string{"admin"}}},
+		staticAuthenticator{token: "operator", principal: auth.Principal{Subject: "ops", Scopes: []string{"admin"}}},
+	)
+	handler := authenticate(RequireScope("admin")(RequireOperator()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))))
+
+	for token, want := range map[string]int{
+		"":         http.StatusUnauthorized,
+		"tenant":   http.StatusForbidden,
+		"operator": http.StatusOK,
+	} {
+		if code := serve(handler, token); code != want {
+			t.Errorf("%q: %d, want %d", token, code, want)
+		}
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	authenticate := Authenticate(
+		staticAuthenticator{token: "reader", principal: auth.Principal{Subject: "alice", Tenant: "acme", Scopes: []string{"catalogue:read"}}},
+	)
+	handler := authenticate(RequireScope("catalogue:write")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	if code := serve(handler, "reader"); code != http.StatusForbidden {
+		t.Errorf("missing scope: %d, want %d", code, http.StatusForbidden)
+	}
+
+	if code := serve(handler, ""); code != http.StatusUnauthorized {
+		t.Errorf("anonymous request: %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+// serve sends a request with the bearer token, none when it is empty, and returns the status of the response.
+func serve(handler http.Handler, token string) int {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	return w.Code
+}
@@ -1,23 +1,35 @@
 package router
 
 import (
+	"fmt"
+	"log"
+	"net"
+	"os"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/go-chi/render"
+
+	"payment-service/pkg/pii"
 )
 
-func New() *chi.Mux {
+// New returns the router with the common middleware, browsers may call it cross-origin from the allowed origins.
+// Credentials are passed in headers rather than cookies, so CORS requests are never sent with credentials.
+// The client IP is only taken from the forwarding headers of the requests the trusted proxies pass on.
+func New(trustedProxies []*net.IPNet, allowedOrigins ...string) *chi.Mux {
 	// Init a new router instance
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
 
-	r.Use(middleware.RealIP)
+	r.Use(RealIP(trustedProxies))
 
-	r.Use(middleware.Logger)
+	// the request URIs may carry emails or phones in their queries
+	r.Use(middleware.RequestLogger(&middleware.DefaultLogFormatter{
+		Logger: redactedLogger{log.New(os.Stdout, "", log.LstdFlags)},
+	}))
 
 	r.Use(middleware.Recoverer)
 
@@ -27,17 +39,33 @@ func New() *chi.Mux {
 
 	r.Use(middleware.Timeout(time.Second * 60))
 
-	r.Use(middleware.AllowContentType("application/json"))
+	r.Use(middleware.AllowContentType(
+		"application/json",
+		"text/csv",
+		"application/x-ndjson",
+		"application/merge-patch+json",
+		"multipart/form-data",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"))
 
 	r.Use(render.SetContentType(render.ContentTypeJSON))
 
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "PUT", "POST", "DELETE", "HEAD", "OPTIONS"},
-		AllowedHeaders:   []string{"*"},
-		AllowCredentials: true,
+		AllowedOrigins:   allowedOrigins,
+		AllowedMethods:   []string{"GET", "PUT", "PATCH", "POST", "DELETE", "HEAD", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-Match", "X-API-Key", "X-Request-Id"},
+		ExposedHeaders:   []string{"ETag", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
+		AllowCredentials: false,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
 
 	return r
 }
+
+// redactedLogger prints the request log lines with the personal data redacted.
+type redactedLogger struct {
+	logger middleware.LoggerInterface
+}
+
+func (l redactedLogger) Print(v ...any) {
+	l.logger.Print(pii.Redact(fmt.Sprint(v...)))
+}
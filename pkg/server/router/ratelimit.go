@@ -0,0 +1,98 @@
+package router
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"payment-service/pkg/auth"
+	"payment-service/pkg/ratelimit"
+	"payment-service/pkg/server/response"
+)
+
+// RateLimit takes a token for the request from the bucket of its caller, the API key or token of an authenticated
+// caller and the IP of an anonymous one. A route matching one of the rules has buckets of its own, the others share
+// the buckets of the fallback limit, a zero fallback leaves them unlimited. The RateLimit-Limit, RateLimit-Remaining
+// and RateLimit-Reset headers describe the bucket, a request finding it empty gets 429 with Retry-After.
+// It has to follow Authenticate to tell the callers apart. The limits are not enforced while the store fails,
+// payments are not turned away when the counters are out of reach.
+func RateLimit(store ratelimit.Store, fallback ratelimit.Limit, rules ...ratelimit.Rule) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, limit := "*", fallback
+			for _, rule := range rules {
+				if rule.Match(r.Method, r.URL.Path) {
+					route, limit = rule.Route(), rule.Limit
+					break
+				}
+			}
+
+			if take(store, w, r, route+"|"+caller(r), limit) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// RateLimitIP takes a token for the request from the bucket of its IP whoever the caller is, a zero limit leaves
+// the requests unlimited. It has to precede Authenticate, so requests with invalid credentials are counted too and
+// API keys can't be guessed faster than the limit allows. The IP is the one RealIP found.
+func RateLimitIP(store ratelimit.Store, limit ratelimit.Limit) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if take(store, w, r, "ip|"+address(r), limit) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// take reports whether the request may go on, otherwise it has been answered with 429.
+func take(store ratelimit.Store, w http.ResponseWriter, r *http.Request, key string, limit ratelimit.Limit) bool {
+	if !limit.Enabled() {
+		return true
+	}
+
+	res, err := store.Take(r.Context(), key, limit)
+	if err != nil {
+		return true
+	}
+
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	w.Header().Set("RateLimit-Reset", seconds(res.Reset))
+
+	if !res.Allowed {
+		w.Header().Set("Retry-After", seconds(res.RetryAfter))
+		response.TooManyRequests(w, r, ratelimit.ErrLimited)
+		return false
+	}
+
+	return true
+}
+
+// caller names the bucket owner, the subject is qualified by the tenant as subjects are only unique within one.
+func caller(r *http.Request) string {
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		return "key:" + principal.Tenant + "/" + principal.Subject
+	}
+
+	return "ip:" + address(r)
+}
+
+// address returns the IP of the request without its port.
+func address(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return ip
+}
+
+// seconds rounds the duration up to whole seconds as the headers carry them.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,77 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"payment-service/pkg/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	store := ratelimit.NewMemory()
+	rules, err := ratelimit.ParseRules(map[string]string{"POST /api/v1/billings": "1/1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	limit := ratelimit.Limit{Count: 2, Period: time.Minute}
+	handler := RateLimit(store, limit, rules...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	send := func(method, remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/api/v1/billings", nil)
+		r.RemoteAddr = remoteAddr
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		return w
+	}
+
+	if w := send(http.MethodPost, "203.0.113.5:1"); w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "1" {
+		t.Errorf("got %d %v", w.Code, w.Header())
+	}
+
+	w := send(http.MethodPost, "203.0.113.5:2")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "60" || w.Header().Get("RateLimit-Remaining") != "0" {
+		t.Errorf("got %d %v, want 429 with a retry after a minute", w.Code, w.Header())
+	}
+
+	// the routes without a rule of their own share the fallback buckets, by IP without the port
+	for i := 0; i < limit.Count; i++ {
+		if w = send(http.MethodGet, "203.0.113.5:3"); w.Code != http.StatusOK {
+			t.Errorf("request %d: got %d", i, w.Code)
+		}
+	}
+
+	if w = send(http.MethodGet, "203.0.113.5:4"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("got %d, want 429", w.Code)
+	}
+
+	if w = send(http.MethodGet, "203.0.113.6:4"); w.Code != http.StatusOK {
+		t.Errorf("another IP got %d", w.Code)
+	}
+}
+
+func TestRateLimitIP(t *testing.T) {
+	handler := RateLimitIP(ratelimit.NewMemory(), ratelimit.Limit{Count: 1, Period: time.Minute})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	// the requests of an IP are counted whatever credentials they carry
+	codes := make([]int, 0, 2)
+	for _, key := range []string{"first", "second"} {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/products", nil)
+		r.RemoteAddr = "203.0.113.5:4711"
+		r.Header.Set("X-API-Key", key)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		codes = append(codes, w.Code)
+	}
+
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("got %v, want the second key of the IP turned away", codes)
+	}
+}
@@ -0,0 +1,103 @@
+package router
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ParseProxies parses the addresses and CIDR ranges of the proxies in front of the service, e.g. 10.0.0.0/8.
+func ParseProxies(values []string) (dest []*net.IPNet, err error) {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: value}
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			dest = append(dest, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, err
+		}
+		dest = append(dest, network)
+	}
+
+	return
+}
+
+// RealIP sets the RemoteAddr of a request passed on by one of the proxies to the IP of the client they recorded,
+// the last address of X-Forwarded-For that isn't one of the proxies, or X-Real-IP. The headers of the requests
+// that come from anywhere else are ignored, as anyone can send them.
+func RealIP(proxies []*net.IPNet) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if trusted(proxies, remoteIP(r)) {
+				if ip := forwardedIP(proxies, r.Header); ip != "" {
+					r.RemoteAddr = ip
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedIP walks X-Forwarded-For from the right, the addresses left of the first client one can be forged.
+func forwardedIP(proxies []*net.IPNet, header http.Header) string {
+	if forwarded := header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				break
+			}
+
+			if !trusted(proxies, ip) {
+				return ip.String()
+			}
+		}
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+
+	return ""
+}
+
+// remoteIP returns the IP the request came from, RealIP may have left it without a port.
+func remoteIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return net.ParseIP(host)
+}
+
+func trusted(proxies []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range proxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,67 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseProxies(t *testing.T) {
+	proxies, err := ParseProxies([]string{"10.0.0.0/8", " 192.168.1.7 ", "", "2001:db8::/32", "::1"})
+	if err != nil || len(proxies) != 4 {
+		t.Fatalf("got %v, err = %v", proxies, err)
+	}
+
+	if got := proxies[1].String(); got != "192.168.1.7/32" {
+		t.Errorf("single address = %s, want 192.168.1.7/32", got)
+	}
+
+	for _, value := range []string{"10.0.0.300", "10.0.0.0/33", "proxy.internal"} {
+		if _, err = ParseProxies([]string{value}); err == nil {
+			t.Errorf("%q is accepted", value)
+		}
+	}
+}
+
+func TestRealIP(t *testing.T) {
+	proxies, err := ParseProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		realIP     string
+		want       string
+	}{
+		{"direct", "203.0.113.5:4711", "", "", "203.0.113.5:4711"},
+		{"headers of a client are ignored", "203.0.113.5:4711", "198.51.100.1", "198.51.100.2", "203.0.113.5:4711"},
+		{"proxy", "10.0.0.2:4711", "198.51.100.1", "", "198.51.100.1"},
+		{"proxies", "10.0.0.2:4711", "198.51.100.1, 10.0.0.3", "", "198.51.100.1"},
+		{"forged hops are left of the client", "10.0.0.2:4711", "192.0.2.66, 198.51.100.1, 10.0.0.3", "", "198.51.100.1"},
+		{"invalid hop", "10.0.0.2:4711", "198.51.100.1, garbage", "198.51.100.2", "198.51.100.2"},
+		{"real ip", "10.0.0.2:4711", "", "198.51.100.2", "198.51.100.2"},
+		{"no headers", "10.0.0.2:4711", "", "", "10.0.0.2:4711"},
+	} {
+		var got string
+		handler := RealIP(proxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.RemoteAddr
+		}))
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tt.remoteAddr
+		if tt.forwarded != "" {
+			r.Header.Set("X-Forwarded-For", tt.forwarded)
+		}
+		if tt.realIP != "" {
+			r.Header.Set("X-Real-IP", tt.realIP)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		if got != tt.want {
+			t.Errorf("%s: remote address = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+// ReadCSV returns the records of a comma or semicolon separated file, the separator is taken from the first line.
+func ReadCSV(r io.Reader) (records [][]string, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectComma(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	return reader.ReadAll()
+}
+
+func detectComma(data []byte) rune {
+	for _, b := range data {
+		switch b {
+		case ';':
+			return ';'
+		case ',', '\n':
+			return ','
+		}
+	}
+
+	return ','
+}
@@ -0,0 +1,129 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	t.Run("comma", func(t *testing.T) {
+		got, err := ReadCSV(strings.NewReader("sku,name\n1, Juice\n"))
+		want := [][]string{{"sku", "name"}, {"1", "Juice"}}
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("got %q, err = %v, want %q", got, err, want)
+		}
+	})
+
+	t.Run("semicolon", func(t *testing.T) {
+		got, err := ReadCSV(strings.NewReader("sku;name;price\n1;Juice, apple;1,5\n2;Water\n"))
+		want := [][]string{{"sku", "name", "price"}, {"1", "Juice, apple", "1,5"}, {"2", "Water"}}
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("got %q, err = %v, want %q", got, err, want)
+		}
+	})
+}
+
+func TestReadXLSX(t *testing.T) {
+	strs := `<sst><si><t>sku</t></si><si><r><t>Ju</t></r><r><t>ice</t></r></si></sst>`
+
+	t.Run("cells", func(t *testing.T) {
+		sheet := `<worksheet><sheetData>
+			<row><c r="A1" t="s"><v>0</v></c><c r="C1" t="inlineStr"><is><t>name</t></is></c></row>
+			<row><c r="A2"><v>1.5</v></c><c t="s"><v>1</v></c></row>
+		</sheetData></worksheet>`
+
+		got, err := ReadXLSX(workbook(t, map[string]string{"xl/sharedStrings.xml": strs, "xl/worksheets/sheet1.xml": sheet}))
+		want := [][]string{{"sku", "", "name"}, {"1.5", "Juice"}}
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("got %q, err = %v, want %q", got, err, want)
+		}
+	})
+
+	t.Run("first worksheet", func(t *testing.T) {
+		got, err := ReadXLSX(workbook(t, map[string]string{
+			"xl/worksheets/sheet2.xml": `<worksheet><sheetData><row><c r="A1"><v>2</v></c></row></sheetData></worksheet>`,
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row><c r="A1"><v>1</v></c></row></sheetData></worksheet>`,
+		}))
+		if err != nil || len(got) != 1 || got[0][0] != "1" {
+			t.Errorf("got %q, err = %v, want the cells of sheet1", got, err)
+		}
+	})
+
+	t.Run("no worksheet", func(t *testing.T) {
+		if _, err := ReadXLSX(workbook(t, map[string]string{"xl/sharedStrings.xml": strs})); err != ErrNoSheet {
+			t.Errorf("err = %v, want %v", err, ErrNoSheet)
+		}
+	})
+
+	t.Run("invalid cells", func(t *testing.T) {
+		for _, cell := range []string{
+			`<c r="XFE1"><v>1</v></c>`,
+			`<c r="A-1"><v>1</v></c>`,
+			`<c r="A1" t="s"><v>2</v></c>`,
+		} {
+			sheet := `<worksheet><sheetData><row>` + cell + `</row></sheetData></worksheet>`
+			files := map[string]string{"xl/sharedStrings.xml": strs, "xl/worksheets/sheet1.xml": sheet}
+			if got, err := ReadXLSX(workbook(t, files)); err == nil {
+				t.Errorf("%s: got %q, want an error", cell, got)
+			}
+		}
+	})
+
+	t.Run("not a workbook", func(t *testing.T) {
+		if _, err := ReadXLSX([]byte("sku,name\n")); err == nil {
+			t.Error("want an error")
+		}
+	})
+}
+
+func TestColumnIndex(t *testing.T) {
+	for _, tt := range []struct {
+		ref    string
+		column int
+		ok     bool
+	}{
+		{"A1", 0, true},
+		{"z7", 25, true},
+		{"AB12", 27, true},
+		{"XFD1", maxColumns - 1, true},
+		{"XFE1", 0, false},
+		{"AAAAAAAAAAAAAAAA1", 0, false},
+		{"É1", 0, false},
+		{"", 3, true},
+	} {
+		if column, ok := columnIndex(tt.ref, 3); column != tt.column || ok != tt.ok {
+			t.Errorf("columnIndex(%q) = %d, %v, want %d, %v", tt.ref, column, ok, tt.column, tt.ok)
+		}
+	}
+
+	if _, ok := columnIndex("", maxColumns); ok {
+		t.Error("a cell past the last column without a reference is accepted")
+	}
+}
+
+// workbook zips the parts into a workbook, the parts the reader doesn't read are left out.
+func workbook(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
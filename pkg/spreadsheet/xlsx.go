@@ -0,0 +1,153 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxColumns is the number of columns a worksheet has, XFD is the last one
+	maxColumns = 16384
+	// maxPartSize bounds the XML a part of the workbook unpacks to, a small archive can unpack to gigabytes
+	maxPartSize = 256 << 20
+)
+
+var ErrNoSheet = errors.New("spreadsheet: workbook has no worksheets")
+
+type sharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+type worksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline struct {
+				Text string `xml:"t"`
+			} `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadXLSX returns the cell values of the first worksheet of an Office Open XML workbook.
+// Numbers are returned as they are stored, formatting and formulas are not evaluated.
+func ReadXLSX(data []byte) (records [][]string, err error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return
+	}
+
+	var strs []string
+	var sheet *zip.File
+	for _, file := range archive.File {
+		switch {
+		case file.Name == "xl/sharedStrings.xml":
+			if strs, err = readSharedStrings(file); err != nil {
+				return
+			}
+		case strings.HasPrefix(file.Name, "xl/worksheets/sheet") && (sheet == nil || file.Name < sheet.Name):
+			sheet = file
+		}
+	}
+
+	if sheet == nil {
+		return nil, ErrNoSheet
+	}
+
+	var ws worksheet
+	if err = decode(sheet, &ws); err != nil {
+		return
+	}
+
+	for _, row := range ws.Rows {
+		var record []string
+		for _, cell := range row.Cells {
+			column, ok := columnIndex(cell.Ref, len(record))
+			if !ok {
+				return nil, errors.New("spreadsheet: invalid reference of cell " + cell.Ref)
+			}
+
+			for len(record) < column {
+				record = append(record, "")
+			}
+
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(value)
+				if err != nil || index >= len(strs) {
+					return nil, errors.New("spreadsheet: invalid shared string in cell " + cell.Ref)
+				}
+				value = strs[index]
+			case "inlineStr":
+				value = cell.Inline.Text
+			}
+			record = append(record, value)
+		}
+		records = append(records, record)
+	}
+
+	return
+}
+
+func readSharedStrings(file *zip.File) (strs []string, err error) {
+	var sst sharedStrings
+	if err = decode(file, &sst); err != nil {
+		return
+	}
+
+	for _, item := range sst.Items {
+		text := item.Text
+		for _, run := range item.Runs {
+			text += run.Text
+		}
+		strs = append(strs, text)
+	}
+
+	return
+}
+
+func decode(file *zip.File, v any) (err error) {
+	rc, err := file.Open()
+	if err != nil {
+		return
+	}
+	defer rc.Close()
+
+	// a part cut short by the limit fails to decode
+	return xml.NewDecoder(io.LimitReader(rc, maxPartSize)).Decode(v)
+}
+
+// columnIndex converts the letters of a cell reference such as "AB12" to a zero based column, cells without
+// a reference are placed after the previous one. It fails for letters past the last column of a worksheet.
+func columnIndex(ref string, fallback int) (column int, ok bool) {
+	letters := strings.TrimRightFunc(ref, func(r rune) bool { return r >= '0' && r <= '9' })
+	if letters == "" {
+		return fallback, fallback < maxColumns
+	}
+
+	for _, r := range strings.ToUpper(letters) {
+		if r < 'A' || r > 'Z' {
+			return 0, false
+		}
+
+		column = column*26 + int(r-'A'+1)
+		if column > maxColumns {
+			return 0, false
+		}
+	}
+
+	return column - 1, true
+}
@@ -1,7 +1,7 @@
 package store
 
 import (
-	_ "database/sql"
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/url"
@@ -12,6 +12,7 @@ import (
 	// _ "github.com/golang-migrate/migrate/v4/database/mongodb"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	//_ "github.com/sijms/go-ora/v2"
 )
 
@@ -62,9 +63,24 @@ func (s *Database) connection() (client *sqlx.DB, err error) {
 		return
 	}
 
-	client, err = sqlx.Connect(s.driverName, s.dataSourceName)
-	if err != nil {
-		return
+	switch s.driverName {
+	case "postgres":
+		// the connections scope themselves to the tenant of the context, see OperatorTenant
+		var connector *pq.Connector
+		if connector, err = pq.NewConnector(s.dataSourceName); err != nil {
+			return
+		}
+
+		client = sqlx.NewDb(sql.OpenDB(scopedConnector{connector}), s.driverName)
+		if err = client.Ping(); err != nil {
+			client.Close()
+			return
+		}
+	default:
+		client, err = sqlx.Connect(s.driverName, s.dataSourceName)
+		if err != nil {
+			return
+		}
 	}
 	client.SetMaxOpenConns(20)
 
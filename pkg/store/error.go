@@ -5,3 +5,8 @@ import (
 )
 
 var ErrorNotFound = errors.New("store: no rows in result set")
+
+var ErrorAlreadyExists = errors.New("store: row with the same unique key already exists")
+
+// ErrorStaleVersion tells the row was changed since the version the write expected was read.
+var ErrorStaleVersion = errors.New("store: row was changed by another request")
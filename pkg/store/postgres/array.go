@@ -1,10 +1,13 @@
 package postgres
 
 import (
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/lib/pq"
 )
 
 var (
@@ -46,17 +49,31 @@ func (s *Array) String() string {
 // Scan implements sql.Scanner for the String slice type
 // Scanners take the database value (in this case as a byte slice)
 // and sets the value of the type.  Here we cast to a string and
-// do a regexp based parse
+// do a regexp based parse. NULL scans to a nil Array, an empty array to an empty one.
 func (s *Array) Scan(src interface{}) error {
+	if src == nil {
+		*s = nil
+		return nil
+	}
+
 	bytes, ok := src.([]byte)
 	if !ok {
 		return errors.New("scan source was not []bytes")
 	}
-	*s = parseArray(string(bytes))
+	*s = append(Array{}, parseArray(string(bytes))...)
 
 	return nil
 }
 
+// Value implements driver.Valuer, a nil Array is stored as NULL.
+func (s Array) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	return pq.StringArray(s).Value()
+}
+
 // parseArray parse the output string from the array type.
 // Regex used: (((?P<value>(([^",\\{}\s(NULL)])+|"([^"\\]|\\"|\\\\)*")))(,)?)
 func parseArray(array string) (results []string) {
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+// OperatorTenant is the tenant a connection is scoped to for a context of WithOperator, the row-level security
+// of the database lets it see every tenant. A context scoped to none, like a session that never set its tenant,
+// sees no rows at all.
+const OperatorTenant = "*"
+
+// scopedConnector opens connections that scope themselves to the tenant of the context before every statement,
+// so row-level security backs the queries up inside and outside of transactions alike.
+type scopedConnector struct {
+	driver.Connector
+}
+
+func (c scopedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &scopedConn{conn: conn}, nil
+}
+
+// scopedConn sets app.tenant_id for the session only when the tenant differs from the one it set last. A rolled back
+// transaction reverts the setting, so the next statement sets it again.
+type scopedConn struct {
+	conn   driver.Conn
+	tenant string
+	scoped bool
+}
+
+func (c *scopedConn) scope(ctx context.Context) error {
+	tenant := TenantScope(ctx)
+	if c.scoped && c.tenant == tenant {
+		return nil
+	}
+
+	execer, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return driver.ErrBadConn
+	}
+
+	args := []driver.NamedValue{{Ordinal: 1, Value: tenant}}
+	if _, err := execer.ExecContext(ctx, `SELECT SET_CONFIG('app.tenant_id', $1, FALSE)`, args); err != nil {
+		c.scoped = false
+		return err
+	}
+	c.tenant, c.scoped = tenant, true
+
+	return nil
+}
+
+func (c *scopedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext scopes the session before the statement is prepared, e.g. a COPY that can't be interrupted
+// by another statement until it is done.
+func (c *scopedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := c.scope(ctx); err != nil {
+		return nil, err
+	}
+
+	if preparer, ok := c.conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+
+	return c.conn.Prepare(query)
+}
+
+func (c *scopedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	if err := c.scope(ctx); err != nil {
+		return nil, err
+	}
+
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *scopedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	if err := c.scope(ctx); err != nil {
+		return nil, err
+	}
+
+	return queryer.QueryContext(ctx, query, args)
+}
+
+func (c *scopedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *scopedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	var tx driver.Tx
+	var err error
+	if beginner, ok := c.conn.(driver.ConnBeginTx); ok {
+		tx, err = beginner.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.conn.Begin()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &scopedTx{Tx: tx, conn: c}, nil
+}
+
+func (c *scopedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c *scopedConn) Close() error {
+	return c.conn.Close()
+}
+
+// scopedTx forgets the tenant of its connection when it is rolled back, or fails to commit.
+type scopedTx struct {
+	driver.Tx
+	conn *scopedConn
+}
+
+func (tx *scopedTx) Commit() error {
+	err := tx.Tx.Commit()
+	if err != nil {
+		tx.conn.scoped = false
+	}
+
+	return err
+}
+
+func (tx *scopedTx) Rollback() error {
+	tx.conn.scoped = false
+	return tx.Tx.Rollback()
+}
@@ -0,0 +1,60 @@
+package store
+
+import "context"
+
+type tenantKey struct{}
+
+type operatorKey struct{}
+
+// WithTenant scopes the context to the tenant, the repositories only read and write its rows then.
+// An empty id leaves the context as it is, a context scoped to no tenant sees no rows.
+func WithTenant(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// WithOperator scopes the context to every tenant, for operators and the background work that serves them all.
+// A tenant the context is scoped to as well takes precedence.
+func WithOperator(ctx context.Context) context.Context {
+	return context.WithValue(ctx, operatorKey{}, true)
+}
+
+// TenantID returns the tenant the context is scoped to, it is empty for operators and contexts scoped to none.
+func TenantID(ctx context.Context) string {
+	id, _ := ctx.Value(tenantKey{}).(string)
+	return id
+}
+
+// TenantScope returns the tenant the context is scoped to, OperatorTenant for an operator and an empty string for
+// a context scoped to none, which sees no rows at all.
+func TenantScope(ctx context.Context) string {
+	if id := TenantID(ctx); id != "" {
+		return id
+	}
+
+	if operator, _ := ctx.Value(operatorKey{}).(bool); operator {
+		return OperatorTenant
+	}
+
+	return ""
+}
+
+// TenantAllows tells whether a row of the tenant is visible within the context, an operator sees every row
+// and a context scoped to none sees no rows.
+func TenantAllows(ctx context.Context, id string) bool {
+	scope := TenantScope(ctx)
+	return scope == OperatorTenant || (scope != "" && scope == id)
+}
+
+// ResolveTenant returns the tenant a row written within the context belongs to: the tenant of the context when it is
+// scoped, the id the row already carries otherwise.
+func ResolveTenant(ctx context.Context, id string) string {
+	if scope := TenantID(ctx); scope != "" {
+		return scope
+	}
+
+	return id
+}
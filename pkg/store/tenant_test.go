@@ -0,0 +1,120 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestTenant(t *testing.T) {
+	ctx := context.Background()
+	acme := WithTenant(ctx, "acme")
+
+	if WithTenant(ctx, "") != ctx || TenantID(ctx) != "" {
+		t.Error("an empty tenant scopes the context")
+	}
+
+	if TenantID(acme) != "acme" {
+		t.Errorf("tenant = %q, want acme", TenantID(acme))
+	}
+
+	if !TenantAllows(acme, "acme") || TenantAllows(acme, "globex") || TenantAllows(acme, "") {
+		t.Error("a scoped context sees the rows of another tenant")
+	}
+
+	// an unscoped context fails closed, only an operator sees every tenant
+	if TenantAllows(ctx, "acme") || TenantAllows(ctx, "") || TenantScope(ctx) != "" {
+		t.Error("an unscoped context sees rows")
+	}
+
+	operator := WithOperator(ctx)
+	if !TenantAllows(operator, "acme") || !TenantAllows(operator, "") || TenantScope(operator) != OperatorTenant {
+		t.Error("an operator misses rows")
+	}
+
+	// a tenant narrows an operator down to its rows
+	if scoped := WithTenant(operator, "acme"); TenantScope(scoped) != "acme" || TenantAllows(scoped, "globex") {
+		t.Error("a tenant doesn't narrow the operator down")
+	}
+
+	// a scoped write can't be made for another tenant, an unscoped one keeps the tenant of the row
+	if got := ResolveTenant(acme, "globex"); got != "acme" {
+		t.Errorf("resolved %q, want acme", got)
+	}
+
+	if got := ResolveTenant(ctx, "globex"); got != "globex" {
+		t.Errorf("resolved %q, want globex", got)
+	}
+}
+
+func TestScopedConn(t *testing.T) {
+	ctx := context.Background()
+	acme := WithTenant(ctx, "acme")
+
+	fake := &fakeConn{}
+	conn := &scopedConn{conn: fake}
+
+	exec := func(ctx context.Context) {
+		t.Helper()
+		if _, err := conn.ExecContext(ctx, "UPDATE products SET name=$1", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exec(acme)
+	exec(acme)
+	if _, err := conn.QueryContext(ctx, "SELECT 1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.QueryContext(WithOperator(ctx), "SELECT 1", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := conn.BeginTx(ctx, driver.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec(acme)
+
+	// the rollback reverts the setting, the connection sets it again
+	if err = tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	exec(acme)
+
+	// an unscoped context is scoped to no tenant rather than left with the last one
+	want := []string{"acme", "UPDATE", "UPDATE", "", "SELECT", OperatorTenant, "SELECT", "acme", "UPDATE", "acme", "UPDATE"}
+	if !reflect.DeepEqual(fake.statements, want) {
+		t.Errorf("statements = %q, want %q", fake.statements, want)
+	}
+}
+
+// fakeConn records the tenants the connection is scoped to and the first word of the other statements.
+type fakeConn struct {
+	statements []string
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if len(args) == 1 && query == `SELECT SET_CONFIG('app.tenant_id', $1, FALSE)` {
+		c.statements = append(c.statements, args[0].Value.(string))
+	} else {
+		c.statements = append(c.statements, query[:6])
+	}
+
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.statements = append(c.statements, query[:6])
+	return nil, nil
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
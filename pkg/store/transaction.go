@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	txKey    struct{}
+	hooksKey struct{}
+)
+
+// Transactor runs a function inside a single unit of work.
+type Transactor interface {
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Transact begins a transaction, puts it into the context passed to fn and commits it when fn succeeds.
+// If the context already carries a transaction, fn joins it instead of starting a new one.
+func (s *Database) Transact(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := s.Client.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	ctx, committed := DeferAfterCommit(context.WithValue(ctx, txKey{}, tx))
+	if err = fn(ctx); err != nil {
+		tx.Rollback()
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	committed()
+	return
+}
+
+// DeferAfterCommit returns a context that keeps the functions given to AfterCommit, committed runs them once the unit
+// of work commits. Within a context that keeps them already, committed does nothing: the outer unit of work runs them.
+func DeferAfterCommit(ctx context.Context) (_ context.Context, committed func()) {
+	if _, ok := ctx.Value(hooksKey{}).(*[]func()); ok {
+		return ctx, func() {}
+	}
+
+	hooks := new([]func())
+	return context.WithValue(ctx, hooksKey{}, hooks), func() {
+		for _, hook := range *hooks {
+			hook()
+		}
+	}
+}
+
+// AfterCommit runs fn once the transaction carried by the context commits, or right away outside of a transaction.
+// A rolled back transaction drops fn.
+func AfterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(hooksKey{}).(*[]func()); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+
+	fn()
+}
+
+// Executor returns the transaction carried by the context or the database itself.
+func Executor(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+
+	return db
+}